@@ -0,0 +1,1288 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: api/gossip/v1/admin.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EndpointStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	State           *EndpointState         `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Alive           bool                   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`
+	Phi             float64                `protobuf:"fixed64,3,opt,name=phi,proto3" json:"phi,omitempty"`
+	UpdateTimestamp int64                  `protobuf:"varint,4,opt,name=update_timestamp,json=updateTimestamp,proto3" json:"update_timestamp,omitempty"` // unix seconds we last heard from the endpoint
+	Local           bool                   `protobuf:"varint,5,opt,name=local,proto3" json:"local,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EndpointStatus) Reset() {
+	*x = EndpointStatus{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndpointStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointStatus) ProtoMessage() {}
+
+func (x *EndpointStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointStatus.ProtoReflect.Descriptor instead.
+func (*EndpointStatus) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EndpointStatus) GetState() *EndpointState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *EndpointStatus) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *EndpointStatus) GetPhi() float64 {
+	if x != nil {
+		return x.Phi
+	}
+	return 0
+}
+
+func (x *EndpointStatus) GetUpdateTimestamp() int64 {
+	if x != nil {
+		return x.UpdateTimestamp
+	}
+	return 0
+}
+
+func (x *EndpointStatus) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+type GetClusterStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClusterStateRequest) Reset() {
+	*x = GetClusterStateRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterStateRequest) ProtoMessage() {}
+
+func (x *GetClusterStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterStateRequest.ProtoReflect.Descriptor instead.
+func (*GetClusterStateRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+// Conflict is an identity conflict: one node ID gossiped from several addresses,
+// or one address gossiped by several live node IDs.
+type Conflict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // duplicate-node-id or duplicate-address
+	NodeIds       []string               `protobuf:"bytes,2,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	FirstSeenMs   int64                  `protobuf:"varint,4,opt,name=first_seen_ms,json=firstSeenMs,proto3" json:"first_seen_ms,omitempty"` // unix milliseconds
+	LastSeenMs    int64                  `protobuf:"varint,5,opt,name=last_seen_ms,json=lastSeenMs,proto3" json:"last_seen_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conflict) Reset() {
+	*x = Conflict{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conflict) ProtoMessage() {}
+
+func (x *Conflict) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conflict.ProtoReflect.Descriptor instead.
+func (*Conflict) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Conflict) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Conflict) GetNodeIds() []string {
+	if x != nil {
+		return x.NodeIds
+	}
+	return nil
+}
+
+func (x *Conflict) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Conflict) GetFirstSeenMs() int64 {
+	if x != nil {
+		return x.FirstSeenMs
+	}
+	return 0
+}
+
+func (x *Conflict) GetLastSeenMs() int64 {
+	if x != nil {
+		return x.LastSeenMs
+	}
+	return 0
+}
+
+// PeerLatency is the round-trip latency histogram of the gossip RPCs to a peer.
+type PeerLatency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Buckets       []int64                `protobuf:"varint,2,rep,packed,name=buckets,proto3" json:"buckets,omitempty"` // round trips per bucket, see gossip.LatencyBuckets, slower ones last
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	SumUs         int64                  `protobuf:"varint,4,opt,name=sum_us,json=sumUs,proto3" json:"sum_us,omitempty"` // microseconds
+	MaxUs         int64                  `protobuf:"varint,5,opt,name=max_us,json=maxUs,proto3" json:"max_us,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerLatency) Reset() {
+	*x = PeerLatency{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerLatency) ProtoMessage() {}
+
+func (x *PeerLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerLatency.ProtoReflect.Descriptor instead.
+func (*PeerLatency) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PeerLatency) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *PeerLatency) GetBuckets() []int64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *PeerLatency) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PeerLatency) GetSumUs() int64 {
+	if x != nil {
+		return x.SumUs
+	}
+	return 0
+}
+
+func (x *PeerLatency) GetMaxUs() int64 {
+	if x != nil {
+		return x.MaxUs
+	}
+	return 0
+}
+
+// GossipStats are the gossip statistics of a node since it started.
+type GossipStats struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Rounds              int64                  `protobuf:"varint,1,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	DigestsSent         int64                  `protobuf:"varint,2,opt,name=digests_sent,json=digestsSent,proto3" json:"digests_sent,omitempty"`
+	DigestsReceived     int64                  `protobuf:"varint,3,opt,name=digests_received,json=digestsReceived,proto3" json:"digests_received,omitempty"`
+	StatesPushed        int64                  `protobuf:"varint,4,opt,name=states_pushed,json=statesPushed,proto3" json:"states_pushed,omitempty"`                          // sent to peers, in ACKs and ACK2s
+	StatesPulled        int64                  `protobuf:"varint,5,opt,name=states_pulled,json=statesPulled,proto3" json:"states_pulled,omitempty"`                          // received from peers, in ACKs and ACK2s
+	LastRoundDurationUs int64                  `protobuf:"varint,6,opt,name=last_round_duration_us,json=lastRoundDurationUs,proto3" json:"last_round_duration_us,omitempty"` // microseconds
+	Convergence         float64                `protobuf:"fixed64,7,opt,name=convergence,proto3" json:"convergence,omitempty"`                                               // fraction of endpoints the last exchange found both nodes agreeing on
+	PeerLatency         []*PeerLatency         `protobuf:"bytes,8,rep,name=peer_latency,json=peerLatency,proto3" json:"peer_latency,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GossipStats) Reset() {
+	*x = GossipStats{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipStats) ProtoMessage() {}
+
+func (x *GossipStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipStats.ProtoReflect.Descriptor instead.
+func (*GossipStats) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GossipStats) GetRounds() int64 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *GossipStats) GetDigestsSent() int64 {
+	if x != nil {
+		return x.DigestsSent
+	}
+	return 0
+}
+
+func (x *GossipStats) GetDigestsReceived() int64 {
+	if x != nil {
+		return x.DigestsReceived
+	}
+	return 0
+}
+
+func (x *GossipStats) GetStatesPushed() int64 {
+	if x != nil {
+		return x.StatesPushed
+	}
+	return 0
+}
+
+func (x *GossipStats) GetStatesPulled() int64 {
+	if x != nil {
+		return x.StatesPulled
+	}
+	return 0
+}
+
+func (x *GossipStats) GetLastRoundDurationUs() int64 {
+	if x != nil {
+		return x.LastRoundDurationUs
+	}
+	return 0
+}
+
+func (x *GossipStats) GetConvergence() float64 {
+	if x != nil {
+		return x.Convergence
+	}
+	return 0
+}
+
+func (x *GossipStats) GetPeerLatency() []*PeerLatency {
+	if x != nil {
+		return x.PeerLatency
+	}
+	return nil
+}
+
+// ReadRepairStats are the read repair counters of a node's KV coordinator since it started.
+type ReadRepairStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reads         int64                  `protobuf:"varint,1,opt,name=reads,proto3" json:"reads,omitempty"`           // reads coordinated
+	Checked       int64                  `protobuf:"varint,2,opt,name=checked,proto3" json:"checked,omitempty"`       // reads compared on every replica, per read_repair_chance
+	Mismatches    int64                  `protobuf:"varint,3,opt,name=mismatches,proto3" json:"mismatches,omitempty"` // reads whose replicas disagreed
+	Repaired      int64                  `protobuf:"varint,4,opt,name=repaired,proto3" json:"repaired,omitempty"`     // stale replicas written the newest value
+	Failed        int64                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`         // write-backs that failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadRepairStats) Reset() {
+	*x = ReadRepairStats{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadRepairStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadRepairStats) ProtoMessage() {}
+
+func (x *ReadRepairStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadRepairStats.ProtoReflect.Descriptor instead.
+func (*ReadRepairStats) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReadRepairStats) GetReads() int64 {
+	if x != nil {
+		return x.Reads
+	}
+	return 0
+}
+
+func (x *ReadRepairStats) GetChecked() int64 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *ReadRepairStats) GetMismatches() int64 {
+	if x != nil {
+		return x.Mismatches
+	}
+	return 0
+}
+
+func (x *ReadRepairStats) GetRepaired() int64 {
+	if x != nil {
+		return x.Repaired
+	}
+	return 0
+}
+
+func (x *ReadRepairStats) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// StorageStats describe a node's KV store: its memtable, its SSTables, and the flushes and
+// compactions since it was opened.
+type StorageStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemtableKeys  int64                  `protobuf:"varint,1,opt,name=memtable_keys,json=memtableKeys,proto3" json:"memtable_keys,omitempty"`
+	MemtableBytes int64                  `protobuf:"varint,2,opt,name=memtable_bytes,json=memtableBytes,proto3" json:"memtable_bytes,omitempty"`
+	Sstables      int64                  `protobuf:"varint,3,opt,name=sstables,proto3" json:"sstables,omitempty"`
+	SstableBytes  int64                  `protobuf:"varint,4,opt,name=sstable_bytes,json=sstableBytes,proto3" json:"sstable_bytes,omitempty"`
+	Flushes       int64                  `protobuf:"varint,5,opt,name=flushes,proto3" json:"flushes,omitempty"`
+	Compactions   int64                  `protobuf:"varint,6,opt,name=compactions,proto3" json:"compactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageStats) Reset() {
+	*x = StorageStats{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageStats) ProtoMessage() {}
+
+func (x *StorageStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageStats.ProtoReflect.Descriptor instead.
+func (*StorageStats) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StorageStats) GetMemtableKeys() int64 {
+	if x != nil {
+		return x.MemtableKeys
+	}
+	return 0
+}
+
+func (x *StorageStats) GetMemtableBytes() int64 {
+	if x != nil {
+		return x.MemtableBytes
+	}
+	return 0
+}
+
+func (x *StorageStats) GetSstables() int64 {
+	if x != nil {
+		return x.Sstables
+	}
+	return 0
+}
+
+func (x *StorageStats) GetSstableBytes() int64 {
+	if x != nil {
+		return x.SstableBytes
+	}
+	return 0
+}
+
+func (x *StorageStats) GetFlushes() int64 {
+	if x != nil {
+		return x.Flushes
+	}
+	return 0
+}
+
+func (x *StorageStats) GetCompactions() int64 {
+	if x != nil {
+		return x.Compactions
+	}
+	return 0
+}
+
+type GetClusterStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	ClusterId     string                 `protobuf:"bytes,2,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	Endpoints     []*EndpointStatus      `protobuf:"bytes,3,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	Conflicts     []*Conflict            `protobuf:"bytes,4,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Stats         *GossipStats           `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	ReadRepair    *ReadRepairStats       `protobuf:"bytes,6,opt,name=read_repair,json=readRepair,proto3" json:"read_repair,omitempty"`
+	Storage       *StorageStats          `protobuf:"bytes,7,opt,name=storage,proto3" json:"storage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClusterStateResponse) Reset() {
+	*x = GetClusterStateResponse{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterStateResponse) ProtoMessage() {}
+
+func (x *GetClusterStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterStateResponse.ProtoReflect.Descriptor instead.
+func (*GetClusterStateResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetClusterStateResponse) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *GetClusterStateResponse) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetClusterStateResponse) GetEndpoints() []*EndpointStatus {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *GetClusterStateResponse) GetConflicts() []*Conflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *GetClusterStateResponse) GetStats() *GossipStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetClusterStateResponse) GetReadRepair() *ReadRepairStats {
+	if x != nil {
+		return x.ReadRepair
+	}
+	return nil
+}
+
+func (x *GetClusterStateResponse) GetStorage() *StorageStats {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+type RemoveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeRequest) Reset() {
+	*x = RemoveNodeRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeRequest) ProtoMessage() {}
+
+func (x *RemoveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemoveNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type RemoveNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeResponse) Reset() {
+	*x = RemoveNodeResponse{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeResponse) ProtoMessage() {}
+
+func (x *RemoveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+type SetAppStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAppStateRequest) Reset() {
+	*x = SetAppStateRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAppStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAppStateRequest) ProtoMessage() {}
+
+func (x *SetAppStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAppStateRequest.ProtoReflect.Descriptor instead.
+func (*SetAppStateRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetAppStateRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SetAppStateRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type SetAppStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *VersionedValue        `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAppStateResponse) Reset() {
+	*x = SetAppStateResponse{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAppStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAppStateResponse) ProtoMessage() {}
+
+func (x *SetAppStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAppStateResponse.ProtoReflect.Descriptor instead.
+func (*SetAppStateResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetAppStateResponse) GetState() *VersionedValue {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type TriggerGossipRoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerGossipRoundRequest) Reset() {
+	*x = TriggerGossipRoundRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerGossipRoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerGossipRoundRequest) ProtoMessage() {}
+
+func (x *TriggerGossipRoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerGossipRoundRequest.ProtoReflect.Descriptor instead.
+func (*TriggerGossipRoundRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+type TriggerGossipRoundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerGossipRoundResponse) Reset() {
+	*x = TriggerGossipRoundResponse{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerGossipRoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerGossipRoundResponse) ProtoMessage() {}
+
+func (x *TriggerGossipRoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerGossipRoundResponse.ProtoReflect.Descriptor instead.
+func (*TriggerGossipRoundResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+type DecommissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionRequest) Reset() {
+	*x = DecommissionRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionRequest) ProtoMessage() {}
+
+func (x *DecommissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionRequest.ProtoReflect.Descriptor instead.
+func (*DecommissionRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+type DecommissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DrainMs       int64                  `protobuf:"varint,1,opt,name=drain_ms,json=drainMs,proto3" json:"drain_ms,omitempty"` // how long each of LEAVING and LEFT is gossiped before the node stops
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionResponse) Reset() {
+	*x = DecommissionResponse{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionResponse) ProtoMessage() {}
+
+func (x *DecommissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionResponse.ProtoReflect.Descriptor instead.
+func (*DecommissionResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DecommissionResponse) GetDrainMs() int64 {
+	if x != nil {
+		return x.DrainMs
+	}
+	return 0
+}
+
+type LogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"` // only entries logged by this node, empty for every entry
+	Tail          int32                  `protobuf:"varint,2,opt,name=tail,proto3" json:"tail,omitempty"`                  // how many of the latest entries to send first, negative for all
+	Follow        bool                   `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`              // keep streaming new entries
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogsRequest) Reset() {
+	*x = LogsRequest{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogsRequest) ProtoMessage() {}
+
+func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LogsRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *LogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *LogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type LogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampMs   int64                  `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // unix milliseconds
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                 // "system" for entries not logged by a node
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Level         string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`         // "debug", "info", "warn" or "error"
+	Component     string                 `protobuf:"bytes,5,opt,name=component,proto3" json:"component,omitempty"` // package that logged it, e.g. "gossip"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LogEntry) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *LogEntry) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogEntry) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+var File_api_gossip_v1_admin_proto protoreflect.FileDescriptor
+
+const file_api_gossip_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x19api/gossip/v1/admin.proto\x121github.adamgarcia4.golearning.cassandra.gossip.v1\x1a\x1aapi/gossip/v1/gossip.proto\"\xd1\x01\n" +
+	"\x0eEndpointStatus\x12V\n" +
+	"\x05state\x18\x01 \x01(\v2@.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStateR\x05state\x12\x14\n" +
+	"\x05alive\x18\x02 \x01(\bR\x05alive\x12\x10\n" +
+	"\x03phi\x18\x03 \x01(\x01R\x03phi\x12)\n" +
+	"\x10update_timestamp\x18\x04 \x01(\x03R\x0fupdateTimestamp\x12\x14\n" +
+	"\x05local\x18\x05 \x01(\bR\x05local\"\x18\n" +
+	"\x16GetClusterStateRequest\"\x9d\x01\n" +
+	"\bConflict\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x19\n" +
+	"\bnode_ids\x18\x02 \x03(\tR\anodeIds\x12\x1c\n" +
+	"\taddresses\x18\x03 \x03(\tR\taddresses\x12\"\n" +
+	"\rfirst_seen_ms\x18\x04 \x01(\x03R\vfirstSeenMs\x12 \n" +
+	"\flast_seen_ms\x18\x05 \x01(\x03R\n" +
+	"lastSeenMs\"\x84\x01\n" +
+	"\vPeerLatency\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\abuckets\x18\x02 \x03(\x03R\abuckets\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x12\x15\n" +
+	"\x06sum_us\x18\x04 \x01(\x03R\x05sumUs\x12\x15\n" +
+	"\x06max_us\x18\x05 \x01(\x03R\x05maxUs\"\xf7\x02\n" +
+	"\vGossipStats\x12\x16\n" +
+	"\x06rounds\x18\x01 \x01(\x03R\x06rounds\x12!\n" +
+	"\fdigests_sent\x18\x02 \x01(\x03R\vdigestsSent\x12)\n" +
+	"\x10digests_received\x18\x03 \x01(\x03R\x0fdigestsReceived\x12#\n" +
+	"\rstates_pushed\x18\x04 \x01(\x03R\fstatesPushed\x12#\n" +
+	"\rstates_pulled\x18\x05 \x01(\x03R\fstatesPulled\x123\n" +
+	"\x16last_round_duration_us\x18\x06 \x01(\x03R\x13lastRoundDurationUs\x12 \n" +
+	"\vconvergence\x18\a \x01(\x01R\vconvergence\x12a\n" +
+	"\fpeer_latency\x18\b \x03(\v2>.github.adamgarcia4.golearning.cassandra.gossip.v1.PeerLatencyR\vpeerLatency\"\x95\x01\n" +
+	"\x0fReadRepairStats\x12\x14\n" +
+	"\x05reads\x18\x01 \x01(\x03R\x05reads\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\x03R\achecked\x12\x1e\n" +
+	"\n" +
+	"mismatches\x18\x03 \x01(\x03R\n" +
+	"mismatches\x12\x1a\n" +
+	"\brepaired\x18\x04 \x01(\x03R\brepaired\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x03R\x06failed\"\xd7\x01\n" +
+	"\fStorageStats\x12#\n" +
+	"\rmemtable_keys\x18\x01 \x01(\x03R\fmemtableKeys\x12%\n" +
+	"\x0ememtable_bytes\x18\x02 \x01(\x03R\rmemtableBytes\x12\x1a\n" +
+	"\bsstables\x18\x03 \x01(\x03R\bsstables\x12#\n" +
+	"\rsstable_bytes\x18\x04 \x01(\x03R\fsstableBytes\x12\x18\n" +
+	"\aflushes\x18\x05 \x01(\x03R\aflushes\x12 \n" +
+	"\vcompactions\x18\x06 \x01(\x03R\vcompactions\"\xa3\x04\n" +
+	"\x17GetClusterStateResponse\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x02 \x01(\tR\tclusterId\x12_\n" +
+	"\tendpoints\x18\x03 \x03(\v2A.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStatusR\tendpoints\x12Y\n" +
+	"\tconflicts\x18\x04 \x03(\v2;.github.adamgarcia4.golearning.cassandra.gossip.v1.ConflictR\tconflicts\x12T\n" +
+	"\x05stats\x18\x05 \x01(\v2>.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipStatsR\x05stats\x12c\n" +
+	"\vread_repair\x18\x06 \x01(\v2B.github.adamgarcia4.golearning.cassandra.gossip.v1.ReadRepairStatsR\n" +
+	"readRepair\x12Y\n" +
+	"\astorage\x18\a \x01(\v2?.github.adamgarcia4.golearning.cassandra.gossip.v1.StorageStatsR\astorage\",\n" +
+	"\x11RemoveNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"\x14\n" +
+	"\x12RemoveNodeResponse\"<\n" +
+	"\x12SetAppStateRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"n\n" +
+	"\x13SetAppStateResponse\x12W\n" +
+	"\x05state\x18\x01 \x01(\v2A.github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValueR\x05state\"\x1b\n" +
+	"\x19TriggerGossipRoundRequest\"\x1c\n" +
+	"\x1aTriggerGossipRoundResponse\"\x15\n" +
+	"\x13DecommissionRequest\"1\n" +
+	"\x14DecommissionResponse\x12\x19\n" +
+	"\bdrain_ms\x18\x01 \x01(\x03R\adrainMs\"R\n" +
+	"\vLogsRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x12\n" +
+	"\x04tail\x18\x02 \x01(\x05R\x04tail\x12\x16\n" +
+	"\x06follow\x18\x03 \x01(\bR\x06follow\"\x94\x01\n" +
+	"\bLogEntry\x12!\n" +
+	"\ftimestamp_ms\x18\x01 \x01(\x03R\vtimestampMs\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\x12\x1c\n" +
+	"\tcomponent\x18\x05 \x01(\tR\tcomponent2\xd2\a\n" +
+	"\fAdminService\x12\xa8\x01\n" +
+	"\x0fGetClusterState\x12I.github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateRequest\x1aJ.github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse\x12\x99\x01\n" +
+	"\n" +
+	"RemoveNode\x12D.github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeRequest\x1aE.github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeResponse\x12\x9c\x01\n" +
+	"\vSetAppState\x12E.github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateRequest\x1aF.github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateResponse\x12\xb1\x01\n" +
+	"\x12TriggerGossipRound\x12L.github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundRequest\x1aM.github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundResponse\x12\x9f\x01\n" +
+	"\fDecommission\x12F.github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionRequest\x1aG.github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionResponse\x12\x85\x01\n" +
+	"\x04Logs\x12>.github.adamgarcia4.golearning.cassandra.gossip.v1.LogsRequest\x1a;.github.adamgarcia4.golearning.cassandra.gossip.v1.LogEntry0\x01B;Z9github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1b\x06proto3"
+
+var (
+	file_api_gossip_v1_admin_proto_rawDescOnce sync.Once
+	file_api_gossip_v1_admin_proto_rawDescData []byte
+)
+
+func file_api_gossip_v1_admin_proto_rawDescGZIP() []byte {
+	file_api_gossip_v1_admin_proto_rawDescOnce.Do(func() {
+		file_api_gossip_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_gossip_v1_admin_proto_rawDesc), len(file_api_gossip_v1_admin_proto_rawDesc)))
+	})
+	return file_api_gossip_v1_admin_proto_rawDescData
+}
+
+var file_api_gossip_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_api_gossip_v1_admin_proto_goTypes = []any{
+	(*EndpointStatus)(nil),             // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStatus
+	(*GetClusterStateRequest)(nil),     // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateRequest
+	(*Conflict)(nil),                   // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.Conflict
+	(*PeerLatency)(nil),                // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.PeerLatency
+	(*GossipStats)(nil),                // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipStats
+	(*ReadRepairStats)(nil),            // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.ReadRepairStats
+	(*StorageStats)(nil),               // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.StorageStats
+	(*GetClusterStateResponse)(nil),    // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse
+	(*RemoveNodeRequest)(nil),          // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeRequest
+	(*RemoveNodeResponse)(nil),         // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeResponse
+	(*SetAppStateRequest)(nil),         // 10: github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateRequest
+	(*SetAppStateResponse)(nil),        // 11: github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateResponse
+	(*TriggerGossipRoundRequest)(nil),  // 12: github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundRequest
+	(*TriggerGossipRoundResponse)(nil), // 13: github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundResponse
+	(*DecommissionRequest)(nil),        // 14: github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionRequest
+	(*DecommissionResponse)(nil),       // 15: github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionResponse
+	(*LogsRequest)(nil),                // 16: github.adamgarcia4.golearning.cassandra.gossip.v1.LogsRequest
+	(*LogEntry)(nil),                   // 17: github.adamgarcia4.golearning.cassandra.gossip.v1.LogEntry
+	(*EndpointState)(nil),              // 18: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	(*VersionedValue)(nil),             // 19: github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValue
+}
+var file_api_gossip_v1_admin_proto_depIdxs = []int32{
+	18, // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStatus.state:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	3,  // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipStats.peer_latency:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.PeerLatency
+	0,  // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse.endpoints:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStatus
+	2,  // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse.conflicts:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.Conflict
+	4,  // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse.stats:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipStats
+	5,  // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse.read_repair:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.ReadRepairStats
+	6,  // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse.storage:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.StorageStats
+	19, // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateResponse.state:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValue
+	1,  // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.GetClusterState:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateRequest
+	8,  // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.RemoveNode:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeRequest
+	10, // 10: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.SetAppState:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateRequest
+	12, // 11: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.TriggerGossipRound:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundRequest
+	14, // 12: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.Decommission:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionRequest
+	16, // 13: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.Logs:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.LogsRequest
+	7,  // 14: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.GetClusterState:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetClusterStateResponse
+	9,  // 15: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.RemoveNode:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.RemoveNodeResponse
+	11, // 16: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.SetAppState:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.SetAppStateResponse
+	13, // 17: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.TriggerGossipRound:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.TriggerGossipRoundResponse
+	15, // 18: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.Decommission:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.DecommissionResponse
+	17, // 19: github.adamgarcia4.golearning.cassandra.gossip.v1.AdminService.Logs:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.LogEntry
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_gossip_v1_admin_proto_init() }
+func file_api_gossip_v1_admin_proto_init() {
+	if File_api_gossip_v1_admin_proto != nil {
+		return
+	}
+	file_api_gossip_v1_gossip_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gossip_v1_admin_proto_rawDesc), len(file_api_gossip_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_gossip_v1_admin_proto_goTypes,
+		DependencyIndexes: file_api_gossip_v1_admin_proto_depIdxs,
+		MessageInfos:      file_api_gossip_v1_admin_proto_msgTypes,
+	}.Build()
+	File_api_gossip_v1_admin_proto = out.File
+	file_api_gossip_v1_admin_proto_goTypes = nil
+	file_api_gossip_v1_admin_proto_depIdxs = nil
+}
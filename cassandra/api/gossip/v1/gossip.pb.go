@@ -0,0 +1,639 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: api/gossip/v1/gossip.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GossipDigest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Generation    int64                  `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	MaxVersion    int64                  `protobuf:"varint,3,opt,name=max_version,json=maxVersion,proto3" json:"max_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipDigest) Reset() {
+	*x = GossipDigest{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipDigest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipDigest) ProtoMessage() {}
+
+func (x *GossipDigest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipDigest.ProtoReflect.Descriptor instead.
+func (*GossipDigest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GossipDigest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *GossipDigest) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *GossipDigest) GetMaxVersion() int64 {
+	if x != nil {
+		return x.MaxVersion
+	}
+	return 0
+}
+
+type VersionedValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionedValue) Reset() {
+	*x = VersionedValue{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionedValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionedValue) ProtoMessage() {}
+
+func (x *VersionedValue) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionedValue.ProtoReflect.Descriptor instead.
+func (*VersionedValue) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VersionedValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *VersionedValue) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type EndpointState struct {
+	state             protoimpl.MessageState     `protogen:"open.v1"`
+	NodeId            string                     `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Generation        int64                      `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	HeartbeatVersion  int64                      `protobuf:"varint,3,opt,name=heartbeat_version,json=heartbeatVersion,proto3" json:"heartbeat_version,omitempty"`
+	ApplicationStates map[string]*VersionedValue `protobuf:"bytes,4,rep,name=application_states,json=applicationStates,proto3" json:"application_states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *EndpointState) Reset() {
+	*x = EndpointState{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndpointState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointState) ProtoMessage() {}
+
+func (x *EndpointState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointState.ProtoReflect.Descriptor instead.
+func (*EndpointState) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EndpointState) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *EndpointState) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *EndpointState) GetHeartbeatVersion() int64 {
+	if x != nil {
+		return x.HeartbeatVersion
+	}
+	return 0
+}
+
+func (x *EndpointState) GetApplicationStates() map[string]*VersionedValue {
+	if x != nil {
+		return x.ApplicationStates
+	}
+	return nil
+}
+
+// Compatibility policy: a node speaks every protocol version from the minimum it supports up
+// to its own. The initiator sends its version in the SYN; the responder answers in the lower
+// of the two versions and says which in the ACK, and the initiator uses that version for the
+// ACK2. A SYN below the responder's minimum is rejected with FAILED_PRECONDITION.
+// New fields must be optional to older versions (proto3 skips unknown fields); a change that
+// older nodes can't ignore needs a new version. 0 means the sender predates versioning (1).
+type GossipDigestSyn struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId       string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	SenderId        string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	SenderAddress   string                 `protobuf:"bytes,3,opt,name=sender_address,json=senderAddress,proto3" json:"sender_address,omitempty"`
+	Digests         []*GossipDigest        `protobuf:"bytes,4,rep,name=digests,proto3" json:"digests,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,5,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GossipDigestSyn) Reset() {
+	*x = GossipDigestSyn{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipDigestSyn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipDigestSyn) ProtoMessage() {}
+
+func (x *GossipDigestSyn) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipDigestSyn.ProtoReflect.Descriptor instead.
+func (*GossipDigestSyn) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GossipDigestSyn) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GossipDigestSyn) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *GossipDigestSyn) GetSenderAddress() string {
+	if x != nil {
+		return x.SenderAddress
+	}
+	return ""
+}
+
+func (x *GossipDigestSyn) GetDigests() []*GossipDigest {
+	if x != nil {
+		return x.Digests
+	}
+	return nil
+}
+
+func (x *GossipDigestSyn) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+type GossipDigestAck struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Requests        []*GossipDigest        `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	States          []*EndpointState       `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,3,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"` // version the rest of the round uses
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GossipDigestAck) Reset() {
+	*x = GossipDigestAck{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipDigestAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipDigestAck) ProtoMessage() {}
+
+func (x *GossipDigestAck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipDigestAck.ProtoReflect.Descriptor instead.
+func (*GossipDigestAck) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GossipDigestAck) GetRequests() []*GossipDigest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *GossipDigestAck) GetStates() []*EndpointState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *GossipDigestAck) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+type GossipDigestAck2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SenderId      string                 `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	States        []*EndpointState       `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipDigestAck2) Reset() {
+	*x = GossipDigestAck2{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipDigestAck2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipDigestAck2) ProtoMessage() {}
+
+func (x *GossipDigestAck2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipDigestAck2.ProtoReflect.Descriptor instead.
+func (*GossipDigestAck2) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GossipDigestAck2) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *GossipDigestAck2) GetStates() []*EndpointState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type GossipDigestAck2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipDigestAck2Response) Reset() {
+	*x = GossipDigestAck2Response{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipDigestAck2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipDigestAck2Response) ProtoMessage() {}
+
+func (x *GossipDigestAck2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipDigestAck2Response.ProtoReflect.Descriptor instead.
+func (*GossipDigestAck2Response) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{6}
+}
+
+type WatchClusterStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchClusterStateRequest) Reset() {
+	*x = WatchClusterStateRequest{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchClusterStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchClusterStateRequest) ProtoMessage() {}
+
+func (x *WatchClusterStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchClusterStateRequest.ProtoReflect.Descriptor instead.
+func (*WatchClusterStateRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{7}
+}
+
+type ClusterStateUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *EndpointState         `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Alive         bool                   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterStateUpdate) Reset() {
+	*x = ClusterStateUpdate{}
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStateUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterStateUpdate) ProtoMessage() {}
+
+func (x *ClusterStateUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_gossip_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterStateUpdate.ProtoReflect.Descriptor instead.
+func (*ClusterStateUpdate) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_gossip_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClusterStateUpdate) GetState() *EndpointState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *ClusterStateUpdate) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+var File_api_gossip_v1_gossip_proto protoreflect.FileDescriptor
+
+const file_api_gossip_v1_gossip_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapi/gossip/v1/gossip.proto\x121github.adamgarcia4.golearning.cassandra.gossip.v1\"h\n" +
+	"\fGossipDigest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x02 \x01(\x03R\n" +
+	"generation\x12\x1f\n" +
+	"\vmax_version\x18\x03 \x01(\x03R\n" +
+	"maxVersion\"@\n" +
+	"\x0eVersionedValue\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\x88\x03\n" +
+	"\rEndpointState\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x02 \x01(\x03R\n" +
+	"generation\x12+\n" +
+	"\x11heartbeat_version\x18\x03 \x01(\x03R\x10heartbeatVersion\x12\x86\x01\n" +
+	"\x12application_states\x18\x04 \x03(\v2W.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState.ApplicationStatesEntryR\x11applicationStates\x1a\x87\x01\n" +
+	"\x16ApplicationStatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12W\n" +
+	"\x05value\x18\x02 \x01(\v2A.github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValueR\x05value:\x028\x01\"\xfa\x01\n" +
+	"\x0fGossipDigestSyn\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12%\n" +
+	"\x0esender_address\x18\x03 \x01(\tR\rsenderAddress\x12Y\n" +
+	"\adigests\x18\x04 \x03(\v2?.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestR\adigests\x12)\n" +
+	"\x10protocol_version\x18\x05 \x01(\rR\x0fprotocolVersion\"\xf3\x01\n" +
+	"\x0fGossipDigestAck\x12[\n" +
+	"\brequests\x18\x01 \x03(\v2?.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestR\brequests\x12X\n" +
+	"\x06states\x18\x02 \x03(\v2@.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStateR\x06states\x12)\n" +
+	"\x10protocol_version\x18\x03 \x01(\rR\x0fprotocolVersion\"\x89\x01\n" +
+	"\x10GossipDigestAck2\x12\x1b\n" +
+	"\tsender_id\x18\x01 \x01(\tR\bsenderId\x12X\n" +
+	"\x06states\x18\x02 \x03(\v2@.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStateR\x06states\"\x1a\n" +
+	"\x18GossipDigestAck2Response\"\x1a\n" +
+	"\x18WatchClusterStateRequest\"\x82\x01\n" +
+	"\x12ClusterStateUpdate\x12V\n" +
+	"\x05state\x18\x01 \x01(\v2@.github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointStateR\x05state\x12\x14\n" +
+	"\x05alive\x18\x02 \x01(\bR\x05alive2\xe6\x03\n" +
+	"\rGossipService\x12\x8d\x01\n" +
+	"\x03Syn\x12B.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestSyn\x1aB.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck\x12\x98\x01\n" +
+	"\x04Ack2\x12C.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2\x1aK.github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2Response\x12\xa9\x01\n" +
+	"\x11WatchClusterState\x12K.github.adamgarcia4.golearning.cassandra.gossip.v1.WatchClusterStateRequest\x1aE.github.adamgarcia4.golearning.cassandra.gossip.v1.ClusterStateUpdate0\x01B;Z9github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1b\x06proto3"
+
+var (
+	file_api_gossip_v1_gossip_proto_rawDescOnce sync.Once
+	file_api_gossip_v1_gossip_proto_rawDescData []byte
+)
+
+func file_api_gossip_v1_gossip_proto_rawDescGZIP() []byte {
+	file_api_gossip_v1_gossip_proto_rawDescOnce.Do(func() {
+		file_api_gossip_v1_gossip_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_gossip_v1_gossip_proto_rawDesc), len(file_api_gossip_v1_gossip_proto_rawDesc)))
+	})
+	return file_api_gossip_v1_gossip_proto_rawDescData
+}
+
+var file_api_gossip_v1_gossip_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_gossip_v1_gossip_proto_goTypes = []any{
+	(*GossipDigest)(nil),             // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigest
+	(*VersionedValue)(nil),           // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValue
+	(*EndpointState)(nil),            // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	(*GossipDigestSyn)(nil),          // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestSyn
+	(*GossipDigestAck)(nil),          // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck
+	(*GossipDigestAck2)(nil),         // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2
+	(*GossipDigestAck2Response)(nil), // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2Response
+	(*WatchClusterStateRequest)(nil), // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.WatchClusterStateRequest
+	(*ClusterStateUpdate)(nil),       // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.ClusterStateUpdate
+	nil,                              // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState.ApplicationStatesEntry
+}
+var file_api_gossip_v1_gossip_proto_depIdxs = []int32{
+	9,  // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState.application_states:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState.ApplicationStatesEntry
+	0,  // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestSyn.digests:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigest
+	0,  // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck.requests:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigest
+	2,  // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck.states:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	2,  // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2.states:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	2,  // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.ClusterStateUpdate.state:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState
+	1,  // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.EndpointState.ApplicationStatesEntry.value:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.VersionedValue
+	3,  // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.Syn:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestSyn
+	5,  // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.Ack2:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2
+	7,  // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.WatchClusterState:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.WatchClusterStateRequest
+	4,  // 10: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.Syn:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck
+	6,  // 11: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.Ack2:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GossipDigestAck2Response
+	8,  // 12: github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService.WatchClusterState:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.ClusterStateUpdate
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_api_gossip_v1_gossip_proto_init() }
+func file_api_gossip_v1_gossip_proto_init() {
+	if File_api_gossip_v1_gossip_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gossip_v1_gossip_proto_rawDesc), len(file_api_gossip_v1_gossip_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_gossip_v1_gossip_proto_goTypes,
+		DependencyIndexes: file_api_gossip_v1_gossip_proto_depIdxs,
+		MessageInfos:      file_api_gossip_v1_gossip_proto_msgTypes,
+	}.Build()
+	File_api_gossip_v1_gossip_proto = out.File
+	file_api_gossip_v1_gossip_proto_goTypes = nil
+	file_api_gossip_v1_gossip_proto_depIdxs = nil
+}
@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: api/gossip/v1/gossip.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GossipService_Syn_FullMethodName               = "/github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService/Syn"
+	GossipService_Ack2_FullMethodName              = "/github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService/Ack2"
+	GossipService_WatchClusterState_FullMethodName = "/github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService/WatchClusterState"
+)
+
+// GossipServiceClient is the client API for GossipService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GossipService implements Cassandra's three-way gossip exchange.
+// The initiator sends a SYN with digests of everything it knows, the responder
+// answers with an ACK carrying newer states plus the digests it wants, and the
+// initiator completes the round with an ACK2 carrying the requested states.
+type GossipServiceClient interface {
+	Syn(ctx context.Context, in *GossipDigestSyn, opts ...grpc.CallOption) (*GossipDigestAck, error)
+	Ack2(ctx context.Context, in *GossipDigestAck2, opts ...grpc.CallOption) (*GossipDigestAck2Response, error)
+	// WatchClusterState streams the responder's view of the cluster: first the current
+	// state of every endpoint, then an update whenever an endpoint's state or liveness changes.
+	WatchClusterState(ctx context.Context, in *WatchClusterStateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClusterStateUpdate], error)
+}
+
+type gossipServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGossipServiceClient(cc grpc.ClientConnInterface) GossipServiceClient {
+	return &gossipServiceClient{cc}
+}
+
+func (c *gossipServiceClient) Syn(ctx context.Context, in *GossipDigestSyn, opts ...grpc.CallOption) (*GossipDigestAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GossipDigestAck)
+	err := c.cc.Invoke(ctx, GossipService_Syn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gossipServiceClient) Ack2(ctx context.Context, in *GossipDigestAck2, opts ...grpc.CallOption) (*GossipDigestAck2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GossipDigestAck2Response)
+	err := c.cc.Invoke(ctx, GossipService_Ack2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gossipServiceClient) WatchClusterState(ctx context.Context, in *WatchClusterStateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClusterStateUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GossipService_ServiceDesc.Streams[0], GossipService_WatchClusterState_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchClusterStateRequest, ClusterStateUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GossipService_WatchClusterStateClient = grpc.ServerStreamingClient[ClusterStateUpdate]
+
+// GossipServiceServer is the server API for GossipService service.
+// All implementations must embed UnimplementedGossipServiceServer
+// for forward compatibility.
+//
+// GossipService implements Cassandra's three-way gossip exchange.
+// The initiator sends a SYN with digests of everything it knows, the responder
+// answers with an ACK carrying newer states plus the digests it wants, and the
+// initiator completes the round with an ACK2 carrying the requested states.
+type GossipServiceServer interface {
+	Syn(context.Context, *GossipDigestSyn) (*GossipDigestAck, error)
+	Ack2(context.Context, *GossipDigestAck2) (*GossipDigestAck2Response, error)
+	// WatchClusterState streams the responder's view of the cluster: first the current
+	// state of every endpoint, then an update whenever an endpoint's state or liveness changes.
+	WatchClusterState(*WatchClusterStateRequest, grpc.ServerStreamingServer[ClusterStateUpdate]) error
+	mustEmbedUnimplementedGossipServiceServer()
+}
+
+// UnimplementedGossipServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGossipServiceServer struct{}
+
+func (UnimplementedGossipServiceServer) Syn(context.Context, *GossipDigestSyn) (*GossipDigestAck, error) {
+	return nil, status.Error(codes.Unimplemented, "method Syn not implemented")
+}
+func (UnimplementedGossipServiceServer) Ack2(context.Context, *GossipDigestAck2) (*GossipDigestAck2Response, error) {
+	return nil, status.Error(codes.Unimplemented, "method Ack2 not implemented")
+}
+func (UnimplementedGossipServiceServer) WatchClusterState(*WatchClusterStateRequest, grpc.ServerStreamingServer[ClusterStateUpdate]) error {
+	return status.Error(codes.Unimplemented, "method WatchClusterState not implemented")
+}
+func (UnimplementedGossipServiceServer) mustEmbedUnimplementedGossipServiceServer() {}
+func (UnimplementedGossipServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeGossipServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GossipServiceServer will
+// result in compilation errors.
+type UnsafeGossipServiceServer interface {
+	mustEmbedUnimplementedGossipServiceServer()
+}
+
+func RegisterGossipServiceServer(s grpc.ServiceRegistrar, srv GossipServiceServer) {
+	// If the following call panics, it indicates UnimplementedGossipServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GossipService_ServiceDesc, srv)
+}
+
+func _GossipService_Syn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipDigestSyn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServiceServer).Syn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GossipService_Syn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServiceServer).Syn(ctx, req.(*GossipDigestSyn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GossipService_Ack2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipDigestAck2)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServiceServer).Ack2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GossipService_Ack2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServiceServer).Ack2(ctx, req.(*GossipDigestAck2))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GossipService_WatchClusterState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchClusterStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GossipServiceServer).WatchClusterState(m, &grpc.GenericServerStream[WatchClusterStateRequest, ClusterStateUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GossipService_WatchClusterStateServer = grpc.ServerStreamingServer[ClusterStateUpdate]
+
+// GossipService_ServiceDesc is the grpc.ServiceDesc for GossipService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GossipService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "github.adamgarcia4.golearning.cassandra.gossip.v1.GossipService",
+	HandlerType: (*GossipServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Syn",
+			Handler:    _GossipService_Syn_Handler,
+		},
+		{
+			MethodName: "Ack2",
+			Handler:    _GossipService_Ack2_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchClusterState",
+			Handler:       _GossipService_WatchClusterState_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/gossip/v1/gossip.proto",
+}
@@ -25,6 +25,8 @@ type HeartbeatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Generation    int64                  `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,10 +75,26 @@ func (x *HeartbeatRequest) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *HeartbeatRequest) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type HeartbeatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Generation    int64                  `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,17 +143,39 @@ func (x *HeartbeatResponse) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *HeartbeatResponse) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *HeartbeatResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 var File_api_gossip_v1_heartbeat_proto protoreflect.FileDescriptor
 
 const file_api_gossip_v1_heartbeat_proto_rawDesc = "" +
 	"\n" +
-	"\x1dapi/gossip/v1/heartbeat.proto\x121github.adamgarcia4.golearning.cassandra.gossip.v1\"I\n" +
+	"\x1dapi/gossip/v1/heartbeat.proto\x121github.adamgarcia4.golearning.cassandra.gossip.v1\"\x83\x01\n" +
 	"\x10HeartbeatRequest\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"J\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x03 \x01(\x03R\n" +
+	"generation\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\"\x84\x01\n" +
 	"\x11HeartbeatResponse\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp2\xab\x01\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x03 \x01(\x03R\n" +
+	"generation\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion2\xab\x01\n" +
 	"\x10HeartbeatService\x12\x96\x01\n" +
 	"\tHeartbeat\x12C.github.adamgarcia4.golearning.cassandra.gossip.v1.HeartbeatRequest\x1aD.github.adamgarcia4.golearning.cassandra.gossip.v1.HeartbeatResponseB;Z9github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1b\x06proto3"
 
@@ -0,0 +1,1247 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: api/gossip/v1/kv.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ConsistencyLevel is how many replicas must answer before the coordinator answers the client.
+type ConsistencyLevel int32
+
+const (
+	ConsistencyLevel_CONSISTENCY_LEVEL_UNSPECIFIED ConsistencyLevel = 0 // ONE
+	ConsistencyLevel_CONSISTENCY_LEVEL_ONE         ConsistencyLevel = 1
+	ConsistencyLevel_CONSISTENCY_LEVEL_QUORUM      ConsistencyLevel = 2 // a majority of the replication factor
+	ConsistencyLevel_CONSISTENCY_LEVEL_ALL         ConsistencyLevel = 3
+)
+
+// Enum value maps for ConsistencyLevel.
+var (
+	ConsistencyLevel_name = map[int32]string{
+		0: "CONSISTENCY_LEVEL_UNSPECIFIED",
+		1: "CONSISTENCY_LEVEL_ONE",
+		2: "CONSISTENCY_LEVEL_QUORUM",
+		3: "CONSISTENCY_LEVEL_ALL",
+	}
+	ConsistencyLevel_value = map[string]int32{
+		"CONSISTENCY_LEVEL_UNSPECIFIED": 0,
+		"CONSISTENCY_LEVEL_ONE":         1,
+		"CONSISTENCY_LEVEL_QUORUM":      2,
+		"CONSISTENCY_LEVEL_ALL":         3,
+	}
+)
+
+func (x ConsistencyLevel) Enum() *ConsistencyLevel {
+	p := new(ConsistencyLevel)
+	*p = x
+	return p
+}
+
+func (x ConsistencyLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConsistencyLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_gossip_v1_kv_proto_enumTypes[0].Descriptor()
+}
+
+func (ConsistencyLevel) Type() protoreflect.EnumType {
+	return &file_api_gossip_v1_kv_proto_enumTypes[0]
+}
+
+func (x ConsistencyLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConsistencyLevel.Descriptor instead.
+func (ConsistencyLevel) EnumDescriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{0}
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Forwarded     bool                   `protobuf:"varint,2,opt,name=forwarded,proto3" json:"forwarded,omitempty"` // sent by the coordinator to a replica, to be served locally
+	Consistency   ConsistencyLevel       `protobuf:"varint,3,opt,name=consistency,proto3,enum=github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel" json:"consistency,omitempty"`
+	Trace         bool                   `protobuf:"varint,4,opt,name=trace,proto3" json:"trace,omitempty"` // record the request's events, see GetTrace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetRequest) GetForwarded() bool {
+	if x != nil {
+		return x.Forwarded
+	}
+	return false
+}
+
+func (x *GetRequest) GetConsistency() ConsistencyLevel {
+	if x != nil {
+		return x.Consistency
+	}
+	return ConsistencyLevel_CONSISTENCY_LEVEL_UNSPECIFIED
+}
+
+func (x *GetRequest) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+type GetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`                    // node that served the request, the key's first replica for a coordinator
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`           // when the value was written, in microseconds since the epoch
+	Replicas      []string               `protobuf:"bytes,5,rep,name=replicas,proto3" json:"replicas,omitempty"`              // replicas that answered the coordinator in time
+	TtlMs         int64                  `protobuf:"varint,6,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`      // how long after timestamp the value expires, 0 for never
+	Deleted       bool                   `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`               // the replica holds a tombstone, on forwarded requests
+	TraceId       string                 `protobuf:"bytes,8,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"` // of the request's trace, when traced
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetResponse) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *GetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetResponse) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *GetResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GetResponse) GetReplicas() []string {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *GetResponse) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *GetResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *GetResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type PutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Forwarded     bool                   `protobuf:"varint,3,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	Consistency   ConsistencyLevel       `protobuf:"varint,4,opt,name=consistency,proto3,enum=github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel" json:"consistency,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`      // set by the coordinator on forwarded writes, the newest write wins
+	TtlMs         int64                  `protobuf:"varint,6,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"` // how long after timestamp the value expires, 0 for never
+	Trace         bool                   `protobuf:"varint,7,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutRequest) Reset() {
+	*x = PutRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutRequest) ProtoMessage() {}
+
+func (x *PutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutRequest.ProtoReflect.Descriptor instead.
+func (*PutRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PutRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PutRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *PutRequest) GetForwarded() bool {
+	if x != nil {
+		return x.Forwarded
+	}
+	return false
+}
+
+func (x *PutRequest) GetConsistency() ConsistencyLevel {
+	if x != nil {
+		return x.Consistency
+	}
+	return ConsistencyLevel_CONSISTENCY_LEVEL_UNSPECIFIED
+}
+
+func (x *PutRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PutRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *PutRequest) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+type PutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Owner         string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Replicas      []string               `protobuf:"bytes,2,rep,name=replicas,proto3" json:"replicas,omitempty"`
+	TraceId       string                 `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutResponse) Reset() {
+	*x = PutResponse{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutResponse) ProtoMessage() {}
+
+func (x *PutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
+func (*PutResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PutResponse) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *PutResponse) GetReplicas() []string {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *PutResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Forwarded     bool                   `protobuf:"varint,2,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	Consistency   ConsistencyLevel       `protobuf:"varint,3,opt,name=consistency,proto3,enum=github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel" json:"consistency,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // of the tombstone, set by the coordinator on forwarded deletes
+	Trace         bool                   `protobuf:"varint,5,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetForwarded() bool {
+	if x != nil {
+		return x.Forwarded
+	}
+	return false
+}
+
+func (x *DeleteRequest) GetConsistency() ConsistencyLevel {
+	if x != nil {
+		return x.Consistency
+	}
+	return ConsistencyLevel_CONSISTENCY_LEVEL_UNSPECIFIED
+}
+
+func (x *DeleteRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"` // whether the key was set, a live value deleted
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Replicas      []string               `protobuf:"bytes,3,rep,name=replicas,proto3" json:"replicas,omitempty"`
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *DeleteResponse) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *DeleteResponse) GetReplicas() []string {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *DeleteResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type ScanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consistency   ConsistencyLevel       `protobuf:"varint,1,opt,name=consistency,proto3,enum=github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel" json:"consistency,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // most keys returned, 0 for all of them
+	Trace         bool                   `protobuf:"varint,3,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanRequest) ProtoMessage() {}
+
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScanRequest) GetConsistency() ConsistencyLevel {
+	if x != nil {
+		return x.Consistency
+	}
+	return ConsistencyLevel_CONSISTENCY_LEVEL_UNSPECIFIED
+}
+
+func (x *ScanRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ScanRequest) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+type ScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*KeyVersion          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`      // live keys only, never tombstones
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // the limit left keys out
+	Replicas      []string               `protobuf:"bytes,3,rep,name=replicas,proto3" json:"replicas,omitempty"`    // replicas that answered the coordinator in time
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanResponse) ProtoMessage() {}
+
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScanResponse) GetEntries() []*KeyVersion {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ScanResponse) GetReplicas() []string {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type GetTraceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTraceRequest) Reset() {
+	*x = GetTraceRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTraceRequest) ProtoMessage() {}
+
+func (x *GetTraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTraceRequest.ProtoReflect.Descriptor instead.
+func (*GetTraceRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTraceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// TraceEvent is one step of a traced request
+type TraceEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ElapsedUs     int64                  `protobuf:"varint,1,opt,name=elapsed_us,json=elapsedUs,proto3" json:"elapsed_us,omitempty"` // since the coordinator received the request
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`                         // the node the event is about
+	Activity      string                 `protobuf:"bytes,3,opt,name=activity,proto3" json:"activity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceEvent) Reset() {
+	*x = TraceEvent{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceEvent) ProtoMessage() {}
+
+func (x *TraceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceEvent.ProtoReflect.Descriptor instead.
+func (*TraceEvent) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TraceEvent) GetElapsedUs() int64 {
+	if x != nil {
+		return x.ElapsedUs
+	}
+	return 0
+}
+
+func (x *TraceEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TraceEvent) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+type GetTraceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Request       string                 `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"` // what was asked, e.g. "GET greeting at QUORUM"
+	Coordinator   string                 `protobuf:"bytes,3,opt,name=coordinator,proto3" json:"coordinator,omitempty"`
+	StartedAtMs   int64                  `protobuf:"varint,4,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"` // unix milliseconds
+	Events        []*TraceEvent          `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTraceResponse) Reset() {
+	*x = GetTraceResponse{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTraceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTraceResponse) ProtoMessage() {}
+
+func (x *GetTraceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTraceResponse.ProtoReflect.Descriptor instead.
+func (*GetTraceResponse) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetTraceResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetTraceResponse) GetRequest() string {
+	if x != nil {
+		return x.Request
+	}
+	return ""
+}
+
+func (x *GetTraceResponse) GetCoordinator() string {
+	if x != nil {
+		return x.Coordinator
+	}
+	return ""
+}
+
+func (x *GetTraceResponse) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+func (x *GetTraceResponse) GetEvents() []*TraceEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// TokenRange is the tokens after start, up to and including end. It wraps around the ring if
+// start is not below end.
+type TokenRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenRange) Reset() {
+	*x = TokenRange{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenRange) ProtoMessage() {}
+
+func (x *TokenRange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenRange.ProtoReflect.Descriptor instead.
+func (*TokenRange) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TokenRange) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TokenRange) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type StreamRangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ranges        []*TokenRange          `protobuf:"bytes,1,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRangesRequest) Reset() {
+	*x = StreamRangesRequest{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRangesRequest) ProtoMessage() {}
+
+func (x *StreamRangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRangesRequest.ProtoReflect.Descriptor instead.
+func (*StreamRangesRequest) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StreamRangesRequest) GetRanges() []*TokenRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+// KeyVersion is the version of a key a replica holds: a value, or a tombstone
+type KeyVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TtlMs         int64                  `protobuf:"varint,4,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	Deleted       bool                   `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyVersion) Reset() {
+	*x = KeyVersion{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyVersion) ProtoMessage() {}
+
+func (x *KeyVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyVersion.ProtoReflect.Descriptor instead.
+func (*KeyVersion) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *KeyVersion) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyVersion) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *KeyVersion) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *KeyVersion) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *KeyVersion) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type StreamRangesChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*KeyVersion          `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRangesChunk) Reset() {
+	*x = StreamRangesChunk{}
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRangesChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRangesChunk) ProtoMessage() {}
+
+func (x *StreamRangesChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gossip_v1_kv_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRangesChunk.ProtoReflect.Descriptor instead.
+func (*StreamRangesChunk) Descriptor() ([]byte, []int) {
+	return file_api_gossip_v1_kv_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamRangesChunk) GetVersions() []*KeyVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+var File_api_gossip_v1_kv_proto protoreflect.FileDescriptor
+
+const file_api_gossip_v1_kv_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/gossip/v1/kv.proto\x121github.adamgarcia4.golearning.cassandra.gossip.v1\"\xb9\x01\n" +
+	"\n" +
+	"GetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tforwarded\x18\x02 \x01(\bR\tforwarded\x12e\n" +
+	"\vconsistency\x18\x03 \x01(\x0e2C.github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevelR\vconsistency\x12\x14\n" +
+	"\x05trace\x18\x04 \x01(\bR\x05trace\"\xd5\x01\n" +
+	"\vGetResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1a\n" +
+	"\breplicas\x18\x05 \x03(\tR\breplicas\x12\x15\n" +
+	"\x06ttl_ms\x18\x06 \x01(\x03R\x05ttlMs\x12\x18\n" +
+	"\adeleted\x18\a \x01(\bR\adeleted\x12\x19\n" +
+	"\btrace_id\x18\b \x01(\tR\atraceId\"\x84\x02\n" +
+	"\n" +
+	"PutRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x1c\n" +
+	"\tforwarded\x18\x03 \x01(\bR\tforwarded\x12e\n" +
+	"\vconsistency\x18\x04 \x01(\x0e2C.github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevelR\vconsistency\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x15\n" +
+	"\x06ttl_ms\x18\x06 \x01(\x03R\x05ttlMs\x12\x14\n" +
+	"\x05trace\x18\a \x01(\bR\x05trace\"Z\n" +
+	"\vPutResponse\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x1a\n" +
+	"\breplicas\x18\x02 \x03(\tR\breplicas\x12\x19\n" +
+	"\btrace_id\x18\x03 \x01(\tR\atraceId\"\xda\x01\n" +
+	"\rDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tforwarded\x18\x02 \x01(\bR\tforwarded\x12e\n" +
+	"\vconsistency\x18\x03 \x01(\x0e2C.github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevelR\vconsistency\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05trace\x18\x05 \x01(\bR\x05trace\"s\n" +
+	"\x0eDeleteResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x1a\n" +
+	"\breplicas\x18\x03 \x03(\tR\breplicas\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\"\xa0\x01\n" +
+	"\vScanRequest\x12e\n" +
+	"\vconsistency\x18\x01 \x01(\x0e2C.github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevelR\vconsistency\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05trace\x18\x03 \x01(\bR\x05trace\"\xbc\x01\n" +
+	"\fScanResponse\x12W\n" +
+	"\aentries\x18\x01 \x03(\v2=.github.adamgarcia4.golearning.cassandra.gossip.v1.KeyVersionR\aentries\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x1a\n" +
+	"\breplicas\x18\x03 \x03(\tR\breplicas\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\"!\n" +
+	"\x0fGetTraceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"_\n" +
+	"\n" +
+	"TraceEvent\x12\x1d\n" +
+	"\n" +
+	"elapsed_us\x18\x01 \x01(\x03R\telapsedUs\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1a\n" +
+	"\bactivity\x18\x03 \x01(\tR\bactivity\"\xd9\x01\n" +
+	"\x10GetTraceResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\arequest\x18\x02 \x01(\tR\arequest\x12 \n" +
+	"\vcoordinator\x18\x03 \x01(\tR\vcoordinator\x12\"\n" +
+	"\rstarted_at_ms\x18\x04 \x01(\x03R\vstartedAtMs\x12U\n" +
+	"\x06events\x18\x05 \x03(\v2=.github.adamgarcia4.golearning.cassandra.gossip.v1.TraceEventR\x06events\"4\n" +
+	"\n" +
+	"TokenRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\"l\n" +
+	"\x13StreamRangesRequest\x12U\n" +
+	"\x06ranges\x18\x01 \x03(\v2=.github.adamgarcia4.golearning.cassandra.gossip.v1.TokenRangeR\x06ranges\"\x83\x01\n" +
+	"\n" +
+	"KeyVersion\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x15\n" +
+	"\x06ttl_ms\x18\x04 \x01(\x03R\x05ttlMs\x12\x18\n" +
+	"\adeleted\x18\x05 \x01(\bR\adeleted\"n\n" +
+	"\x11StreamRangesChunk\x12Y\n" +
+	"\bversions\x18\x01 \x03(\v2=.github.adamgarcia4.golearning.cassandra.gossip.v1.KeyVersionR\bversions*\x89\x01\n" +
+	"\x10ConsistencyLevel\x12!\n" +
+	"\x1dCONSISTENCY_LEVEL_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15CONSISTENCY_LEVEL_ONE\x10\x01\x12\x1c\n" +
+	"\x18CONSISTENCY_LEVEL_QUORUM\x10\x02\x12\x19\n" +
+	"\x15CONSISTENCY_LEVEL_ALL\x10\x032\xea\x06\n" +
+	"\tKVService\x12\x84\x01\n" +
+	"\x03Get\x12=.github.adamgarcia4.golearning.cassandra.gossip.v1.GetRequest\x1a>.github.adamgarcia4.golearning.cassandra.gossip.v1.GetResponse\x12\x84\x01\n" +
+	"\x03Put\x12=.github.adamgarcia4.golearning.cassandra.gossip.v1.PutRequest\x1a>.github.adamgarcia4.golearning.cassandra.gossip.v1.PutResponse\x12\x8d\x01\n" +
+	"\x06Delete\x12@.github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteRequest\x1aA.github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteResponse\x12\x87\x01\n" +
+	"\x04Scan\x12>.github.adamgarcia4.golearning.cassandra.gossip.v1.ScanRequest\x1a?.github.adamgarcia4.golearning.cassandra.gossip.v1.ScanResponse\x12\x93\x01\n" +
+	"\bGetTrace\x12B.github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceRequest\x1aC.github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceResponse\x12\x9e\x01\n" +
+	"\fStreamRanges\x12F.github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesRequest\x1aD.github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesChunk0\x01B;Z9github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1b\x06proto3"
+
+var (
+	file_api_gossip_v1_kv_proto_rawDescOnce sync.Once
+	file_api_gossip_v1_kv_proto_rawDescData []byte
+)
+
+func file_api_gossip_v1_kv_proto_rawDescGZIP() []byte {
+	file_api_gossip_v1_kv_proto_rawDescOnce.Do(func() {
+		file_api_gossip_v1_kv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_gossip_v1_kv_proto_rawDesc), len(file_api_gossip_v1_kv_proto_rawDesc)))
+	})
+	return file_api_gossip_v1_kv_proto_rawDescData
+}
+
+var file_api_gossip_v1_kv_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_gossip_v1_kv_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_api_gossip_v1_kv_proto_goTypes = []any{
+	(ConsistencyLevel)(0),       // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel
+	(*GetRequest)(nil),          // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.GetRequest
+	(*GetResponse)(nil),         // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.GetResponse
+	(*PutRequest)(nil),          // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.PutRequest
+	(*PutResponse)(nil),         // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.PutResponse
+	(*DeleteRequest)(nil),       // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteRequest
+	(*DeleteResponse)(nil),      // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteResponse
+	(*ScanRequest)(nil),         // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.ScanRequest
+	(*ScanResponse)(nil),        // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.ScanResponse
+	(*GetTraceRequest)(nil),     // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceRequest
+	(*TraceEvent)(nil),          // 10: github.adamgarcia4.golearning.cassandra.gossip.v1.TraceEvent
+	(*GetTraceResponse)(nil),    // 11: github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceResponse
+	(*TokenRange)(nil),          // 12: github.adamgarcia4.golearning.cassandra.gossip.v1.TokenRange
+	(*StreamRangesRequest)(nil), // 13: github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesRequest
+	(*KeyVersion)(nil),          // 14: github.adamgarcia4.golearning.cassandra.gossip.v1.KeyVersion
+	(*StreamRangesChunk)(nil),   // 15: github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesChunk
+}
+var file_api_gossip_v1_kv_proto_depIdxs = []int32{
+	0,  // 0: github.adamgarcia4.golearning.cassandra.gossip.v1.GetRequest.consistency:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel
+	0,  // 1: github.adamgarcia4.golearning.cassandra.gossip.v1.PutRequest.consistency:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel
+	0,  // 2: github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteRequest.consistency:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel
+	0,  // 3: github.adamgarcia4.golearning.cassandra.gossip.v1.ScanRequest.consistency:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.ConsistencyLevel
+	14, // 4: github.adamgarcia4.golearning.cassandra.gossip.v1.ScanResponse.entries:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.KeyVersion
+	10, // 5: github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceResponse.events:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.TraceEvent
+	12, // 6: github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesRequest.ranges:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.TokenRange
+	14, // 7: github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesChunk.versions:type_name -> github.adamgarcia4.golearning.cassandra.gossip.v1.KeyVersion
+	1,  // 8: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Get:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetRequest
+	3,  // 9: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Put:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.PutRequest
+	5,  // 10: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Delete:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteRequest
+	7,  // 11: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Scan:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.ScanRequest
+	9,  // 12: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.GetTrace:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceRequest
+	13, // 13: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.StreamRanges:input_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesRequest
+	2,  // 14: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Get:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetResponse
+	4,  // 15: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Put:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.PutResponse
+	6,  // 16: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Delete:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.DeleteResponse
+	8,  // 17: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.Scan:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.ScanResponse
+	11, // 18: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.GetTrace:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.GetTraceResponse
+	15, // 19: github.adamgarcia4.golearning.cassandra.gossip.v1.KVService.StreamRanges:output_type -> github.adamgarcia4.golearning.cassandra.gossip.v1.StreamRangesChunk
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_gossip_v1_kv_proto_init() }
+func file_api_gossip_v1_kv_proto_init() {
+	if File_api_gossip_v1_kv_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gossip_v1_kv_proto_rawDesc), len(file_api_gossip_v1_kv_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_gossip_v1_kv_proto_goTypes,
+		DependencyIndexes: file_api_gossip_v1_kv_proto_depIdxs,
+		EnumInfos:         file_api_gossip_v1_kv_proto_enumTypes,
+		MessageInfos:      file_api_gossip_v1_kv_proto_msgTypes,
+	}.Build()
+	File_api_gossip_v1_kv_proto = out.File
+	file_api_gossip_v1_kv_proto_goTypes = nil
+	file_api_gossip_v1_kv_proto_depIdxs = nil
+}
@@ -0,0 +1,343 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: api/gossip/v1/kv.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	KVService_Get_FullMethodName          = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/Get"
+	KVService_Put_FullMethodName          = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/Put"
+	KVService_Delete_FullMethodName       = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/Delete"
+	KVService_Scan_FullMethodName         = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/Scan"
+	KVService_GetTrace_FullMethodName     = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/GetTrace"
+	KVService_StreamRanges_FullMethodName = "/github.adamgarcia4.golearning.cassandra.gossip.v1.KVService/StreamRanges"
+)
+
+// KVServiceClient is the client API for KVService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// KVService is the key-value store of the cluster. Any node takes requests for any key and
+// coordinates them: it sends them to every replica of the key, as decided from the token
+// ring, and answers once as many replicas as the consistency level asks for have.
+type KVServiceClient interface {
+	// Get returns the value of a key.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	// Put sets a key to a value.
+	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	// Delete removes a key, writing a tombstone that hides the older values of its replicas.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Scan returns the live keys of the cluster and their values, in token order, reading every
+	// token range from as many of its replicas as the consistency level asks for.
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	// GetTrace returns the events of a request the node coordinated with tracing on, while the
+	// node still keeps its trace.
+	GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (*GetTraceResponse, error)
+	// StreamRanges streams the version of every key the node holds in token ranges, tombstones
+	// included, in chunks. A node joining the ring streams the ranges it takes over from their
+	// replicas before it starts serving them.
+	StreamRanges(ctx context.Context, in *StreamRangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamRangesChunk], error)
+}
+
+type kVServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKVServiceClient(cc grpc.ClientConnInterface) KVServiceClient {
+	return &kVServiceClient{cc}
+}
+
+func (c *kVServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, KVService_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVServiceClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, KVService_Put_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, KVService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVServiceClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, KVService_Scan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVServiceClient) GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (*GetTraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTraceResponse)
+	err := c.cc.Invoke(ctx, KVService_GetTrace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVServiceClient) StreamRanges(ctx context.Context, in *StreamRangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamRangesChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &KVService_ServiceDesc.Streams[0], KVService_StreamRanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRangesRequest, StreamRangesChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KVService_StreamRangesClient = grpc.ServerStreamingClient[StreamRangesChunk]
+
+// KVServiceServer is the server API for KVService service.
+// All implementations must embed UnimplementedKVServiceServer
+// for forward compatibility.
+//
+// KVService is the key-value store of the cluster. Any node takes requests for any key and
+// coordinates them: it sends them to every replica of the key, as decided from the token
+// ring, and answers once as many replicas as the consistency level asks for have.
+type KVServiceServer interface {
+	// Get returns the value of a key.
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	// Put sets a key to a value.
+	Put(context.Context, *PutRequest) (*PutResponse, error)
+	// Delete removes a key, writing a tombstone that hides the older values of its replicas.
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Scan returns the live keys of the cluster and their values, in token order, reading every
+	// token range from as many of its replicas as the consistency level asks for.
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	// GetTrace returns the events of a request the node coordinated with tracing on, while the
+	// node still keeps its trace.
+	GetTrace(context.Context, *GetTraceRequest) (*GetTraceResponse, error)
+	// StreamRanges streams the version of every key the node holds in token ranges, tombstones
+	// included, in chunks. A node joining the ring streams the ranges it takes over from their
+	// replicas before it starts serving them.
+	StreamRanges(*StreamRangesRequest, grpc.ServerStreamingServer[StreamRangesChunk]) error
+	mustEmbedUnimplementedKVServiceServer()
+}
+
+// UnimplementedKVServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKVServiceServer struct{}
+
+func (UnimplementedKVServiceServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedKVServiceServer) Put(context.Context, *PutRequest) (*PutResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedKVServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedKVServiceServer) Scan(context.Context, *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedKVServiceServer) GetTrace(context.Context, *GetTraceRequest) (*GetTraceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTrace not implemented")
+}
+func (UnimplementedKVServiceServer) StreamRanges(*StreamRangesRequest, grpc.ServerStreamingServer[StreamRangesChunk]) error {
+	return status.Error(codes.Unimplemented, "method StreamRanges not implemented")
+}
+func (UnimplementedKVServiceServer) mustEmbedUnimplementedKVServiceServer() {}
+func (UnimplementedKVServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeKVServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KVServiceServer will
+// result in compilation errors.
+type UnsafeKVServiceServer interface {
+	mustEmbedUnimplementedKVServiceServer()
+}
+
+func RegisterKVServiceServer(s grpc.ServiceRegistrar, srv KVServiceServer) {
+	// If the following call panics, it indicates UnimplementedKVServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&KVService_ServiceDesc, srv)
+}
+
+func _KVService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVService_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVServiceServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVService_Put_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVServiceServer).Put(ctx, req.(*PutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVService_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVServiceServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVService_Scan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVServiceServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVService_GetTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVServiceServer).GetTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KVService_GetTrace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVServiceServer).GetTrace(ctx, req.(*GetTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVService_StreamRanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KVServiceServer).StreamRanges(m, &grpc.GenericServerStream[StreamRangesRequest, StreamRangesChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KVService_StreamRangesServer = grpc.ServerStreamingServer[StreamRangesChunk]
+
+// KVService_ServiceDesc is the grpc.ServiceDesc for KVService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KVService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "github.adamgarcia4.golearning.cassandra.gossip.v1.KVService",
+	HandlerType: (*KVServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _KVService_Get_Handler,
+		},
+		{
+			MethodName: "Put",
+			Handler:    _KVService_Put_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _KVService_Delete_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _KVService_Scan_Handler,
+		},
+		{
+			MethodName: "GetTrace",
+			Handler:    _KVService_GetTrace_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRanges",
+			Handler:       _KVService_StreamRanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/gossip/v1/kv.proto",
+}
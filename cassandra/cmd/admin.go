@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// Flags shared by the commands that talk to a running node's AdminService
+var (
+	adminTarget  string
+	adminTLSCert string
+	adminTLSKey  string
+	adminTLSCA   string
+)
+
+// addAdminFlags registers the flags used to reach a node's AdminService
+func addAdminFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&adminTarget, "target", "t", node.DefaultTarget, "Address of the node (host:port or unix:///path)")
+	cmd.Flags().StringVar(&adminTLSCert, "tls-cert", "", "PEM client certificate, for nodes that require one")
+	cmd.Flags().StringVar(&adminTLSKey, "tls-key", "", "PEM private key file for --tls-cert")
+	cmd.Flags().StringVar(&adminTLSCA, "tls-ca", "", "PEM CA bundle used to verify the node (enables TLS)")
+}
+
+// adminTLSConfig returns the TLS config given by the --tls-* flags, nil for plaintext
+func adminTLSConfig() *transport.TLSConfig {
+	if adminTLSCert == "" && adminTLSKey == "" && adminTLSCA == "" {
+		return nil
+	}
+	return &transport.TLSConfig{
+		CertFile: adminTLSCert,
+		KeyFile:  adminTLSKey,
+		CAFile:   adminTLSCA,
+	}
+}
+
+// dialAdmin connects to the AdminService at --target, exiting on failure
+func dialAdmin() *transport.AdminClient {
+	client, err := transport.DialAdmin(adminTarget, adminTLSConfig())
+	if err != nil {
+		fatalf(exitConfig, "failed to connect to %s: %v", adminTarget, err)
+	}
+	return client
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport/inmem"
+)
+
+// benchAppState is the application state bench changes to measure convergence
+const benchAppState gossip.AppStateKey = "BENCH"
+
+var (
+	benchNodes    int
+	benchRounds   int
+	benchRuns     int
+	benchSeeds    int
+	benchInterval time.Duration
+	benchCSV      bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure how fast a state change spreads through a cluster",
+	Long: `Start a cluster of in-memory nodes (no sockets), wait until every node knows every other,
+then change an application state on a random node and count the gossip rounds, and the time,
+until every node has the new value. In every round all nodes gossip once, at the same time.
+
+Repeat it with --runs to average out the randomness of gossip, and use --csv to compare
+cluster sizes, seed counts and intervals.
+
+Examples:
+  cassandra bench --nodes=50 --runs=20
+
+  # Rounds of 100ms instead of back to back, as CSV
+  cassandra bench --nodes=50 --runs=20 --interval=100ms --csv > bench.csv`,
+	Run: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().IntVar(&benchNodes, "nodes", 10, "Number of nodes")
+	benchCmd.Flags().IntVar(&benchRounds, "rounds", 100, "Give up on a run after this many rounds")
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 1, "Number of state changes to measure")
+	benchCmd.Flags().IntVar(&benchSeeds, "seeds", 3, "Number of nodes the others use as seeds")
+	benchCmd.Flags().DurationVar(&benchInterval, "interval", 0, "Wait between rounds (0 runs them back to back)")
+	benchCmd.Flags().BoolVar(&benchCSV, "csv", false, "Print one CSV line per run")
+}
+
+// benchResult is the outcome of one run
+type benchResult struct {
+	rounds    int
+	duration  time.Duration
+	converged bool
+}
+
+// benchOutput is the JSON output of bench
+type benchOutput struct {
+	Nodes      int   `json:"nodes"`
+	Seeds      int   `json:"seeds"`
+	IntervalMs int64 `json:"interval_ms"`
+
+	// How long the nodes took to discover each other, before the first run
+	SetupRounds     int     `json:"setup_rounds"`
+	SetupDurationMs float64 `json:"setup_duration_ms"`
+
+	Runs []benchRunOutput `json:"runs"`
+}
+
+// benchRunOutput is a benchResult in the JSON output of bench
+type benchRunOutput struct {
+	Run        int           `json:"run"`
+	Source     gossip.NodeID `json:"source"` // the node whose state changed
+	Rounds     int           `json:"rounds"`
+	DurationMs float64       `json:"duration_ms"`
+	Converged  bool          `json:"converged"`
+}
+
+func runBench(cmd *cobra.Command, args []string) {
+	if benchNodes < 2 {
+		fatalf(exitConfig, "--nodes must be at least 2, got %d", benchNodes)
+	}
+	if benchSeeds < 1 {
+		fatalf(exitConfig, "--seeds must be at least 1, got %d", benchSeeds)
+	}
+	// Nodes log every round, keep the output to the results
+	initLogger(false)
+	initTracing()
+
+	nodes, err := startBenchCluster()
+	defer stopBenchCluster(nodes)
+	if err != nil {
+		fatalf(exitFailure, "failed to start cluster: %v", err)
+	}
+
+	// Every node must know every other before the first change, or we'd measure discovery
+	setup := runUntil(nodes, func(n *node.Node) bool {
+		return len(n.GetGossipState().GetStateByNode()) == len(nodes)-1
+	})
+	if !setup.converged {
+		fatalf(exitFailure, "nodes did not discover each other within %d rounds", benchRounds)
+	}
+
+	output := benchOutput{Nodes: len(nodes), Seeds: min(benchSeeds, len(nodes)),
+		IntervalMs: benchInterval.Milliseconds(), SetupRounds: setup.rounds,
+		SetupDurationMs: float64(setup.duration.Microseconds()) / 1000, Runs: []benchRunOutput{}}
+	switch {
+	case jsonOutput():
+		// Printed once every run is done
+	case benchCSV:
+		fmt.Println("run,nodes,seeds,interval_ms,rounds,duration_ms,converged")
+	default:
+		fmt.Printf("%d nodes discovered each other in %d rounds (%v)\n\n", len(nodes), setup.rounds, setup.duration.Round(time.Microsecond))
+	}
+
+	var results []benchResult
+	for run := 1; run <= benchRuns; run++ {
+		source := nodes[rand.Intn(len(nodes))]
+		sourceID := source.GetConfig().NodeID
+		value := strconv.Itoa(run)
+		if _, err := source.SetAppState(benchAppState, value); err != nil {
+			fatalf(exitFailure, "failed to change state on %s: %v", sourceID, err)
+		}
+
+		result := runUntil(nodes, func(n *node.Node) bool {
+			if n == source {
+				return true
+			}
+			state, ok := n.GetGossipState().GetEndpointState(sourceID)
+			if !ok {
+				return false
+			}
+			appState, ok := state.GetApplicationState(benchAppState)
+			return ok && appState.Value == value
+		})
+		results = append(results, result)
+		output.Runs = append(output.Runs, benchRunOutput{Run: run, Source: sourceID, Rounds: result.rounds,
+			DurationMs: float64(result.duration.Microseconds()) / 1000, Converged: result.converged})
+
+		switch {
+		case jsonOutput():
+		case benchCSV:
+			fmt.Printf("%d,%d,%d,%d,%d,%.3f,%t\n", run, len(nodes), min(benchSeeds, len(nodes)),
+				benchInterval.Milliseconds(), result.rounds, float64(result.duration.Microseconds())/1000, result.converged)
+		case result.converged:
+			fmt.Printf("Run %d: change on %s reached every node in %d rounds (%v)\n", run, sourceID, result.rounds, result.duration.Round(time.Microsecond))
+		default:
+			fmt.Printf("Run %d: change on %s did not reach every node within %d rounds\n", run, sourceID, benchRounds)
+		}
+	}
+
+	switch {
+	case jsonOutput():
+		printJSON(output)
+	case !benchCSV:
+		printBenchSummary(results)
+	}
+}
+
+// startBenchCluster starts --nodes nodes on one in-memory network, the first --seeds being
+// everyone's seeds. Rounds only run when runUntil triggers them.
+func startBenchCluster() ([]*node.Node, error) {
+	network := inmem.NewNetwork()
+	var seeds []string
+	var nodes []*node.Node
+	for i := 0; i < benchNodes; i++ {
+		config := node.DefaultConfig(gossip.NodeID(fmt.Sprintf("node-%d", i+1)))
+		config.Address = "127.0.0.1"
+		config.Port = strconv.Itoa(50051 + i)
+		config.Transport = node.InMemoryTransport(network)
+		config.ManualHeartbeat = true
+		if i < benchSeeds {
+			seeds = append(seeds, config.GetAddress())
+		}
+		config.Seeds = seeds
+
+		n, err := node.New(config)
+		if err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, n)
+		if err := n.Start(); err != nil {
+			return nodes, err
+		}
+	}
+	return nodes, nil
+}
+
+func stopBenchCluster(nodes []*node.Node) {
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Go(func() {
+			n.Stop()
+		})
+	}
+	wg.Wait()
+}
+
+// runUntil runs gossip rounds, on every node at once, until done holds for every node or
+// --rounds rounds ran
+func runUntil(nodes []*node.Node, done func(*node.Node) bool) benchResult {
+	converged := func() bool {
+		for _, n := range nodes {
+			if !done(n) {
+				return false
+			}
+		}
+		return true
+	}
+
+	start := time.Now()
+	for round := 1; round <= benchRounds; round++ {
+		var wg sync.WaitGroup
+		for _, n := range nodes {
+			wg.Go(func() {
+				if err := n.SendGossipRound(); err != nil {
+					warnf("%s: %v", n.GetConfig().NodeID, err)
+				}
+			})
+		}
+		wg.Wait()
+
+		if converged() {
+			return benchResult{rounds: round, duration: time.Since(start), converged: true}
+		}
+		time.Sleep(benchInterval)
+	}
+	return benchResult{rounds: benchRounds, duration: time.Since(start)}
+}
+
+func printBenchSummary(results []benchResult) {
+	var converged []benchResult
+	for _, result := range results {
+		if result.converged {
+			converged = append(converged, result)
+		}
+	}
+	fmt.Printf("\n%d of %d runs converged", len(converged), len(results))
+	if len(converged) == 0 {
+		fmt.Println()
+		return
+	}
+
+	minRounds, maxRounds, totalRounds := converged[0].rounds, converged[0].rounds, 0
+	var totalDuration time.Duration
+	for _, result := range converged {
+		minRounds = min(minRounds, result.rounds)
+		maxRounds = max(maxRounds, result.rounds)
+		totalRounds += result.rounds
+		totalDuration += result.duration
+	}
+	fmt.Printf(": rounds min %d, avg %.1f, max %d; avg time %v\n", minRounds,
+		float64(totalRounds)/float64(len(converged)), maxRounds, (totalDuration / time.Duration(len(converged))).Round(time.Microsecond))
+}
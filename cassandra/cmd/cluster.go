@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+var (
+	clusterNodes       int
+	clusterSave        string
+	clusterAutoRestart bool
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Run a local cluster of gossiping nodes in one process",
+	Long: `Run a local cluster in one process, on consecutive ports from 50051. The first nodes
+are the seeds of the others, so the nodes find each other within a few gossip rounds.
+Stop it with Ctrl+C.
+
+Examples:
+  cassandra cluster --nodes=5
+
+  # Save the topology, to start the same cluster again later
+  cassandra cluster --nodes=5 --save=topology.yaml
+  cassandra cluster restore topology.yaml`,
+	Run: runCluster,
+}
+
+var clusterRestoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Run the cluster saved in a topology file",
+	Long: `Run the nodes of a topology file in one process, with the IDs, ports, seeds and
+settings they were saved with (by 'cassandra cluster --save' or W in interactive mode).
+Stop it with Ctrl+C.
+
+Examples:
+  cassandra cluster restore topology.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run:  runClusterRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(clusterCmd)
+	clusterCmd.AddCommand(clusterRestoreCmd)
+	clusterCmd.Flags().IntVar(&clusterNodes, "nodes", 3, "Number of nodes to start")
+	clusterCmd.Flags().StringVar(&clusterSave, "save", "", "Write the cluster's topology to this file once it is running")
+	clusterCmd.PersistentFlags().BoolVar(&clusterAutoRestart, "auto-restart", false, "Restart nodes that fail, with backoff")
+}
+
+func runCluster(cmd *cobra.Command, args []string) {
+	initLogger(true)
+	initTracing()
+
+	manager := node.NewManager()
+	manager.SetAutoRestart(clusterAutoRestart)
+	nodes, err := manager.CreateCluster(clusterNodes)
+	if err != nil {
+		stopCluster(manager)
+		fatalf(exitFailure, "failed to create cluster: %v", err)
+	}
+	logger.Infof("Cluster of %d nodes running", len(nodes))
+
+	if clusterSave != "" {
+		if err := manager.SaveTopology(clusterSave); err != nil {
+			stopCluster(manager)
+			fatalf(exitFailure, "failed to save topology: %v", err)
+		}
+		logger.Infof("Topology saved to %s", clusterSave)
+	}
+
+	waitForSignal()
+	stopCluster(manager)
+}
+
+func runClusterRestore(cmd *cobra.Command, args []string) {
+	initLogger(true)
+	initTracing()
+
+	manager := node.NewManager()
+	manager.SetAutoRestart(clusterAutoRestart)
+	nodes, err := manager.LoadTopology(args[0])
+	if err != nil {
+		stopCluster(manager)
+		fatalf(exitFailure, "failed to restore cluster: %v", err)
+	}
+	logger.Infof("Cluster of %d nodes restored from %s", len(nodes), args[0])
+
+	waitForSignal()
+	stopCluster(manager)
+}
+
+// waitForSignal blocks until the process is interrupted
+func waitForSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+}
+
+func stopCluster(manager *node.Manager) {
+	logger.Info("Shutting down...")
+	if err := manager.StopAll(); err != nil {
+		logger.Errorf("Error during shutdown: %v", err)
+	}
+}
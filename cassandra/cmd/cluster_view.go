@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var clusterViewCmd = &cobra.Command{
+	Use:   "cluster-view",
+	Short: "Show what every node believes about every other node",
+	Long: `Ask a running node for its cluster state, then ask every node it knows about, and
+print a matrix of what each node (row) believes about each node (column): UP or DOWN and the
+highest version of its state it has seen. A state from an older generation is marked "old gen".
+
+Nodes that can't be reached are reported and left out of the rows.
+
+Examples:
+  cassandra cluster-view --target=127.0.0.1:50051`,
+	Run: runClusterView,
+}
+
+func init() {
+	rootCmd.AddCommand(clusterViewCmd)
+	addAdminFlags(clusterViewCmd)
+}
+
+func runClusterView(cmd *cobra.Command, args []string) {
+	first, err := fetchClusterState(adminTarget)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get cluster state from %s: %v", adminTarget, err)
+	}
+
+	states := []transport.ClusterState{first}
+	for _, endpoint := range first.Endpoints {
+		if endpoint.Local {
+			continue
+		}
+		nodeID := endpoint.State.HeartbeatState.NodeID
+		addr, ok := endpoint.State.ApplicationStates[gossip.AppHeartbeat]
+		if !ok {
+			warnf("%s: address unknown, skipped", nodeID)
+			continue
+		}
+		state, err := fetchClusterState(addr.Value)
+		if err != nil {
+			warnf("%s (%s): %v", nodeID, addr.Value, err)
+			continue
+		}
+		states = append(states, state)
+	}
+
+	view := node.NewClusterView(states)
+	if jsonOutput() {
+		printJSON(newClusterViewOutput(view))
+		return
+	}
+	printClusterView(view)
+}
+
+// clusterViewOutput is the cluster-view JSON output: beliefs[i][j] is what observers[i]
+// believes about nodes[j], nil if it doesn't know it
+type clusterViewOutput struct {
+	Observers []gossip.NodeID   `json:"observers"`
+	Nodes     []gossip.NodeID   `json:"nodes"`
+	Beliefs   [][]*beliefOutput `json:"beliefs"`
+	Converged bool              `json:"converged"`
+}
+
+type beliefOutput struct {
+	Alive      bool  `json:"alive"`
+	Generation int64 `json:"generation"`
+	Version    int64 `json:"version"`
+}
+
+func newClusterViewOutput(view node.ClusterView) clusterViewOutput {
+	out := clusterViewOutput{
+		Observers: view.Observers,
+		Nodes:     view.Nodes,
+		Beliefs:   make([][]*beliefOutput, len(view.Beliefs)),
+		Converged: view.Converged(),
+	}
+	for i, row := range view.Beliefs {
+		out.Beliefs[i] = make([]*beliefOutput, len(row))
+		for j, belief := range row {
+			if belief.Known {
+				out.Beliefs[i][j] = &beliefOutput{Alive: belief.Alive, Generation: belief.Generation, Version: belief.Version}
+			}
+		}
+	}
+	return out
+}
+
+// fetchClusterState asks the node at target for its cluster state
+func fetchClusterState(target string) (transport.ClusterState, error) {
+	client, err := transport.DialAdmin(target, adminTLSConfig())
+	if err != nil {
+		return transport.ClusterState{}, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.GetClusterState(ctx)
+}
+
+func printClusterView(view node.ClusterView) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := []string{"OBSERVER"}
+	for _, nodeID := range view.Nodes {
+		header = append(header, string(nodeID))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for i, observer := range view.Observers {
+		row := []string{string(observer)}
+		for j := range view.Nodes {
+			row = append(row, formatBelief(view.Beliefs[i][j], view.Latest(j)))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	if view.Converged() {
+		fmt.Println("\nConverged: every node has the same state of every node")
+	} else {
+		fmt.Println("\nNot converged")
+	}
+}
+
+// formatBelief renders one cell of the matrix, e.g. "UP v12"
+func formatBelief(belief, latest node.Belief) string {
+	if !belief.Known {
+		return "-"
+	}
+	liveness := "DOWN"
+	if belief.Alive {
+		liveness = "UP"
+	}
+	cell := fmt.Sprintf("%s v%d", liveness, belief.Version)
+	if belief.Generation < latest.Generation {
+		cell += " (old gen)"
+	}
+	return cell
+}
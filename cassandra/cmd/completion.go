@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Print a script that completes cassandra's commands and flags in your shell.
+
+Bash (needs the bash-completion package):
+  source <(cassandra completion bash)
+  # For every session, on Linux:
+  cassandra completion bash > /etc/bash_completion.d/cassandra
+
+Zsh:
+  # If completion isn't enabled yet:
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  cassandra completion zsh > "${fpath[1]}/_cassandra"
+
+Fish:
+  cassandra completion fish > ~/.config/fish/completions/cassandra.fish
+
+PowerShell:
+  cassandra completion powershell | Out-String | Invoke-Expression`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+var exampleFile string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with node config files",
+}
+
+var configExampleCmd = &cobra.Command{
+	Use:   "example",
+	Short: "Print an example config file with every setting at its default",
+	Long: `Print an example YAML config file for 'cassandra start --config', with every
+setting at its default value.
+
+Examples:
+  # Write an example config and start a node from it
+  cassandra config example --file node.yaml
+  cassandra start --config node.yaml`,
+	Run: runConfigExample,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configExampleCmd)
+
+	configExampleCmd.Flags().StringVar(&exampleFile, "file", "", "Write to this file instead of stdout")
+}
+
+func runConfigExample(cmd *cobra.Command, args []string) {
+	config := node.DefaultConfig(gossip.NodeID(node.DefaultNodeID))
+	config.Seeds = []string{node.DefaultTarget}
+
+	data, err := node.MarshalConfig(config, node.TransportGRPC)
+	if err != nil {
+		fatalf(exitFailure, "failed to render config: %v", err)
+	}
+
+	if exampleFile == "" {
+		fmt.Print(string(data))
+		return
+	}
+	if err := os.WriteFile(exampleFile, data, 0o644); err != nil {
+		fatalf(exitFailure, "failed to write config: %v", err)
+	}
+	fmt.Printf("Wrote %s\n", exampleFile)
+}
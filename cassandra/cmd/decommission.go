@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var decommissionCmd = &cobra.Command{
+	Use:   "decommission",
+	Short: "Make a running node leave the cluster gracefully",
+	Long: `Make a running node leave the cluster. The node gossips STATUS LEAVING and then LEFT,
+each for its decommission drain, and then stops. The other nodes remove it once they have
+seen it LEFT for their own drain.
+
+Examples:
+  cassandra decommission --target=127.0.0.1:50052`,
+	Run: runDecommission,
+}
+
+func init() {
+	rootCmd.AddCommand(decommissionCmd)
+	addAdminFlags(decommissionCmd)
+}
+
+func runDecommission(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	drain, err := client.Decommission(ctx)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to decommission %s: %v", adminTarget, err)
+	}
+	if jsonOutput() {
+		printJSON(struct {
+			Target  string `json:"target"`
+			DrainMs int64  `json:"drain_ms"`
+		}{adminTarget, drain.Milliseconds()})
+		return
+	}
+	fmt.Printf("Decommissioning %s: LEAVING for %v, then LEFT for %v, then it stops\n", adminTarget, drain, drain)
+}
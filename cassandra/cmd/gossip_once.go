@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var gossipOnceCmd = &cobra.Command{
+	Use:   "gossip-once",
+	Short: "Make a running node run one gossip round",
+	Long: `Make a running node run one gossip round now: bump its heartbeat and exchange
+SYN/ACK/ACK2 with a random live peer and, like every round, maybe a seed. Together with
+'cassandra start --manual-gossip' this steps through the protocol one round at a time.
+
+Examples:
+  cassandra start --node-id=node-1 --port=50051 --manual-gossip
+  cassandra gossip-once --target=127.0.0.1:50051`,
+	Run: runGossipOnce,
+}
+
+func init() {
+	rootCmd.AddCommand(gossipOnceCmd)
+	addAdminFlags(gossipOnceCmd)
+}
+
+func runGossipOnce(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.TriggerGossipRound(ctx); err != nil {
+		fatalf(rpcExitCode(err), "failed to trigger a gossip round on %s: %v", adminTarget, err)
+	}
+	if jsonOutput() {
+		printJSON(struct {
+			Target string `json:"target"`
+		}{adminTarget})
+		return
+	}
+	fmt.Printf("Gossip round done on %s\n", adminTarget)
+}
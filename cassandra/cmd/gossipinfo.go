@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var gossipInfoCmd = &cobra.Command{
+	Use:   "gossipinfo",
+	Short: "Dump the gossip state a running node holds for every endpoint",
+	Long: `Dump every endpoint state a running node holds, like nodetool gossipinfo: per endpoint
+its generation, heartbeat version and every application state as KEY:version:value, to see
+exactly what the node believes.
+
+Examples:
+  cassandra gossipinfo --target=127.0.0.1:50051`,
+	Run: runGossipInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(gossipInfoCmd)
+	addAdminFlags(gossipInfoCmd)
+}
+
+func runGossipInfo(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := client.GetClusterState(ctx)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get cluster state from %s: %v", adminTarget, err)
+	}
+
+	if jsonOutput() {
+		printJSON(newGossipInfo(state))
+		return
+	}
+	for _, endpoint := range state.Endpoints {
+		heartbeat := endpoint.State.HeartbeatState
+		header := string(heartbeat.NodeID)
+		if addr, ok := endpoint.State.ApplicationStates[gossip.AppHeartbeat]; ok {
+			header += "/" + addr.Value
+		}
+		fmt.Println(header)
+		fmt.Printf("  generation:%d\n", heartbeat.Generation)
+		fmt.Printf("  heartbeat:%d\n", heartbeat.Version)
+
+		keys := make([]gossip.AppStateKey, 0, len(endpoint.State.ApplicationStates))
+		for key := range endpoint.State.ApplicationStates {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			appState := endpoint.State.ApplicationStates[key]
+			fmt.Printf("  %s:%d:%s\n", key, appState.Version, appState.Value)
+		}
+	}
+}
+
+// endpointGossipInfo is one endpoint of the gossipinfo JSON output
+type endpointGossipInfo struct {
+	NodeID     gossip.NodeID                       `json:"node_id"`
+	Generation int64                               `json:"generation"`
+	Heartbeat  int64                               `json:"heartbeat"`
+	AppStates  map[gossip.AppStateKey]appStateInfo `json:"app_states"`
+}
+
+type appStateInfo struct {
+	Value   string `json:"value"`
+	Version int64  `json:"version"`
+}
+
+func newGossipInfo(state transport.ClusterState) []endpointGossipInfo {
+	endpoints := make([]endpointGossipInfo, 0, len(state.Endpoints))
+	for _, endpoint := range state.Endpoints {
+		info := endpointGossipInfo{
+			NodeID:     endpoint.State.HeartbeatState.NodeID,
+			Generation: endpoint.State.HeartbeatState.Generation,
+			Heartbeat:  endpoint.State.HeartbeatState.Version,
+			AppStates:  make(map[gossip.AppStateKey]appStateInfo, len(endpoint.State.ApplicationStates)),
+		}
+		for key, appState := range endpoint.State.ApplicationStates {
+			info.AppStates[key] = appStateInfo{Value: appState.Value, Version: appState.Version}
+		}
+		endpoints = append(endpoints, info)
+	}
+	return endpoints
+}
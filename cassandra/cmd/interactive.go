@@ -1,17 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/spf13/cobra"
 
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
 	"github.com/adamgarcia4/goLearning/cassandra/logger"
 	"github.com/adamgarcia4/goLearning/cassandra/node"
 )
@@ -22,18 +27,106 @@ var interactiveCmd = &cobra.Command{
 	Long: `Start an interactive terminal UI for managing nodes.
 
 Keyboard shortcuts:
-  C - Create a new node
+  c - Create a new node
+  C - Create a node with the ID, port, seeds, heartbeat interval and manual gossip of your choice
   D - Delete a node (shows selection menu)
   DD - Delete the first active node
+  R - Restart a node (shows selection menu)
+  X - Decommission a node (shows selection menu)
+  G - Run one gossip round on a node (shows selection menu, Enter repeats it)
+  P - Pause or resume a node (shows selection menu)
+  p - Build a partition: put nodes in groups A and B, Enter partitions them; U heals it
+  I - Show a node's heartbeat, application states, peers and recent events (shows selection menu)
+  M - Show or hide the convergence matrix of what every node believes about every node
+  H - Show or hide sparklines of every node's gossip rounds, SYN failures, merges and peers' phi, and its gossip totals
+  V - Show or hide the failure detector: each peer's phi against the threshold and its last heartbeat
+  T - Show a timeline of membership events (joins, up/down, restarts, partitions) in place of the logs
+  / - Search the logs (n/N jump to the next older/newer match, Esc clears the search)
+  F - Pause the log panel to read it while logs keep coming, or follow the newest logs again
+  e - Export the logs shown (filtered) to a timestamped file, E exports every buffered log
+  A - Open a node's action menu: restart, pause/resume, decommission, gossip round, set app state
+  Z - Bring back the last deleted node, with its ID, port and seeds
+  W - Save the cluster's topology to --topology
   Q - Quit
 
+Mouse (unless --mouse=false):
+  Click a node to pick it, a split view panel's header to hide it; the wheel scrolls
+
 Examples:
-  cassandra interactive`,
+  cassandra interactive
+
+  # Nodes only gossip when G is pressed, to follow the protocol step by step
+  cassandra interactive --manual-gossip
+
+  # Recreate the cluster saved with W
+  cassandra interactive --restore
+
+  # Record a demo, then replay it (see INTERACTIVE.md for the scenario format)
+  cassandra interactive --record=demo.yaml
+  cassandra interactive --scenario=demo.yaml
+
+  # Start with the default layout instead of the last session's
+  cassandra interactive --reset-prefs
+
+  # Bright colors for dim screens, or no colors at all (like NO_COLOR=1)
+  cassandra interactive --theme=high-contrast
+  cassandra interactive --theme=monochrome
+
+  # Select text with the mouse instead of clicking nodes
+  cassandra interactive --mouse=false
+
+  # Delete and decommission nodes without asking first
+  cassandra interactive --confirm=false
+
+  # Restart nodes that fail, showing how often each was restarted
+  cassandra interactive --auto-restart
+
+  # Show a node started with 'cassandra start' alongside the nodes created here,
+  # which join its cluster
+  cassandra interactive --attach=127.0.0.1:50051
+
+  # Watch a running cluster's state and logs without creating nodes
+  cassandra interactive --monitor --attach=127.0.0.1:50051`,
 	Run: runInteractive,
 }
 
+// Settings of the nodes created with C
+var (
+	interactiveManualGossip bool
+	interactiveHeartbeat    time.Duration
+
+	interactiveTopology    string // file W saves the topology to
+	interactiveRestore     bool   // start the nodes of interactiveTopology
+	interactiveAutoRestart bool   // restart nodes that fail
+
+	interactiveAttach  []string // addresses of nodes of other processes to show alongside ours
+	interactiveMonitor bool     // only show the attached nodes, without nodes of our own
+
+	interactiveConfirm bool   // ask before deleting or decommissioning a node
+	interactiveMouse   bool   // click to select nodes, wheel to scroll
+	interactiveTheme   string // colors of the TUI, the saved one if not set
+
+	interactiveResetPrefs bool // start from the default layout rather than the last session's
+
+	interactiveScenario string // scenario file run once the TUI starts
+	interactiveRecord   string // file the session's actions are saved to as a scenario
+)
+
 func init() {
 	rootCmd.AddCommand(interactiveCmd)
+	interactiveCmd.Flags().BoolVar(&interactiveManualGossip, "manual-gossip", false, "Create nodes that only gossip when triggered with G")
+	interactiveCmd.Flags().DurationVar(&interactiveHeartbeat, "heartbeat-interval", 0, "Heartbeat interval of the nodes created (default 5s)")
+	interactiveCmd.Flags().StringVar(&interactiveTopology, "topology", "topology.yaml", "File W saves the cluster's topology to")
+	interactiveCmd.Flags().BoolVar(&interactiveRestore, "restore", false, "Start with the nodes saved in --topology")
+	interactiveCmd.Flags().BoolVar(&interactiveAutoRestart, "auto-restart", false, "Restart nodes that fail, with backoff")
+	interactiveCmd.Flags().BoolVar(&interactiveConfirm, "confirm", true, "Ask before deleting or decommissioning a node (--confirm=false to skip)")
+	interactiveCmd.Flags().BoolVar(&interactiveMouse, "mouse", true, "Click to select nodes and scroll with the wheel (--mouse=false leaves the mouse to the terminal, to select text)")
+	interactiveCmd.Flags().StringVar(&interactiveTheme, "theme", "", "Colors of the TUI: default, high-contrast or monochrome (default: the last session's, monochrome if NO_COLOR is set)")
+	interactiveCmd.Flags().BoolVar(&interactiveResetPrefs, "reset-prefs", false, "Forget the layout saved by the last session (split view, filters, follow mode, shown panels)")
+	interactiveCmd.Flags().StringVar(&interactiveScenario, "scenario", "", "Run the timed actions of this scenario file (YAML) while you watch")
+	interactiveCmd.Flags().StringVar(&interactiveRecord, "record", "", "Save the session's actions to this file as a scenario, when quitting")
+	interactiveCmd.Flags().StringSliceVar(&interactiveAttach, "attach", nil, "Addresses of nodes started with 'cassandra start' to attach to (repeatable)")
+	interactiveCmd.Flags().BoolVar(&interactiveMonitor, "monitor", false, "Only watch the --attach nodes' cluster and logs, without creating nodes in this process")
 }
 
 // State represents the current state of the interactive UI
@@ -42,47 +135,138 @@ type State int
 const (
 	StateNormal State = iota
 	StateDeleteSelect
+	StateRestartSelect
+	StateDecommissionSelect
+	StateGossipSelect
+	StatePauseSelect
 	StateWaitingForSecondD
 	StateLogFilter
+	StateDetailSelect
+	StateNodeDetail
+	StateLogSearch
+	StateActionSelect
+	StateActionMenu
+	StateSetAppState
+	StatePartition
+	StateHelp
+	StateCreateForm
+	StateConfirm
 )
 
 type model struct {
 	manager      *node.Manager
+	newNode      node.NodeOverrides // settings of nodes created with C
+	membership   <-chan node.Event  // peers of any node coming, going up or down, and connections failing
 	nodes        []*node.Node
 	state        State
 	selected     int
 	err          error
 	logBuffer    *logger.LogBuffer
-	logScroll    int // for scrolling logs
+	logEntries   []logger.LogEntry      // entries of logBuffer, kept up to date from logUpdates
+	logUpdates   <-chan logger.LogEntry // entries added to logBuffer, see waitForLogEntries
+	logScroll    int                    // for scrolling logs
 	width        int
 	height       int
 	lastCommand  string // Track last command for repeat (Enter key)
 	numericInput string // Buffer for multi-digit numeric input in delete mode
 
+	// Detail view state
+	events     []node.Event  // recent events of every node, oldest first
+	detailNode gossip.NodeID // node shown by the detail view
+
+	showMatrix bool // whether the convergence matrix is shown, toggled with M
+	helpScroll int  // lines the help screen is scrolled down
+
+	showSparklines bool                           // whether the metrics sparklines are shown, toggled with H
+	samples        map[gossip.NodeID]*nodeSamples // recent metrics of every node, see sampleMetrics
+
+	showDetector bool // whether the failure detector panel is shown, toggled with V
+
+	showTimeline   bool // whether the event timeline replaces the logs, toggled with T
+	timelineScroll int  // events the timeline is scrolled back
+
+	theme string // theme saved in the preferences, see selectTheme
+
+	// Log search state
+	searchInput     string         // pattern being typed after /
+	search          *regexp.Regexp // compiled pattern of the active search, nil if none
+	searchText      string         // the active search as typed
+	currentMatch    logger.LogEntry
+	hasCurrentMatch bool // whether currentMatch is set
+
+	// Log follow state
+	logPaused   bool // whether the log panel stopped following the newest entries
+	logNewLines int  // entries logged since the panel was paused
+
+	toast        string    // confirmation shown below the title, see showToast
+	toastExpires time.Time // when the toast disappears
+
+	// Action menu state
+	actionNode    int    // index of the node the action menu is open for
+	actionChoice  int    // highlighted entry of the action menu
+	appStateInput string // KEY=value being typed for Set app state
+
+	// Confirmation and undo state
+	monitor     bool                           // whether the session only watches attached nodes, see --monitor
+	confirm     bool                           // whether deleting and decommissioning ask for confirmation
+	confirmNode gossip.NodeID                  // node of the action waiting for confirmation
+	confirmVerb string                         // what the action does, for the dialog
+	confirmRun  func(*model, int) actionResult // the action, run with the node's index once confirmed
+	deleted     []node.NodeOverrides           // settings of the last deleted nodes, newest last, for Z
+
+	// Scenario state
+	scenario     []scenarioStep // steps of --scenario, nil once finished
+	scenarioNext int            // index of the scenario's next step
+	recordPath   string         // file --record saves the session's actions to, empty if not recording
+	recorded     []scenarioStep // actions of the session, with waits between them
+	lastRecorded time.Time      // when the last action was recorded
+
+	// Create form state
+	createInputs []textinput.Model // text fields of the create form, see createFieldNodeID
+	createFocus  int               // field of the create form the cursor is in
+	createManual bool              // whether the create form's node gossips manually
+
+	// Partition state
+	partitionGroups map[gossip.NodeID]partitionGroup // groups of the partition being built with p
+	partitions      []string                         // partitions applied since the last heal, for the banner
+
 	// Log filter state
-	logFilter      map[int]bool // tracks which nodes are filtered (key: node index 0-based)
-	logFilterMode  bool         // whether filter mode is active
-	logSplitView   string       // "none", "columns", or "rows"
-	logFilterInput string       // buffer for numeric input in filter mode
-	hiddenNodes    map[int]bool // tracks which nodes are hidden in split view (key: node index 0-based)
-	splitInput     string       // buffer for numeric input in split view mode
+	logFilter      map[int]bool          // tracks which nodes are filtered (key: node index 0-based)
+	logFilterMode  bool                  // whether filter mode is active
+	logSplitView   string                // "none", "columns", or "rows"
+	logFilterInput string                // buffer for numeric input in filter mode
+	hiddenNodes    map[int]bool          // tracks which nodes are hidden in split view (key: node index 0-based)
+	splitInput     string                // buffer for numeric input in split view mode
+	splitPageIndex int                   // page of panels shown in split view, see splitPage
+	logLevels      map[logger.Level]bool // levels shown, every level if none is set
+	logComponents  map[string]bool       // components shown, every component if none is set
 }
 
-func initialModel() model {
+func initialModel(newNode node.NodeOverrides) model {
 	// Initialize logger for interactive mode (no stdout, only log buffer)
-	logger.Init("", false) // No prefix, no stdout
+	initLogger(false)
+	initTracing()
 	logBuffer := logger.GetGlobalLogBuffer()
-	if err := logger.AddOutput(logger.NewLogBufferWriter(logBuffer)); err != nil {
-		// Use standard log since logger might not be fully initialized
-		log.Fatalf("Failed to add log buffer output: %v", err)
-	}
+	// Subscribed for the whole session, before taking the entries so none are missed: no node
+	// runs yet to log in between
+	logUpdates, _ := logBuffer.Subscribe()
+
+	manager := node.NewManager()
+	membership := manager.Bus().Subscribe(context.Background(),
+		node.EventPeerDiscovered, node.EventPeerUp, node.EventPeerDown, node.EventPeerRemoved,
+		node.EventNodeStarted, node.EventConnectionDown, node.EventConnectionUp, node.EventNodePaused, node.EventNodeResumed,
+		node.EventPartitioned, node.EventHealed)
 
 	return model{
-		manager:        node.NewManager(),
+		manager:        manager,
+		newNode:        newNode,
+		membership:     membership,
 		nodes:          []*node.Node{},
 		state:          StateNormal,
 		selected:       0,
 		logBuffer:      logBuffer,
+		logEntries:     logBuffer.GetAll(),
+		logUpdates:     logUpdates,
 		logScroll:      0,
 		numericInput:   "",
 		logFilter:      make(map[int]bool),
@@ -91,12 +275,20 @@ func initialModel() model {
 		logFilterInput: "",
 		hiddenNodes:    make(map[int]bool),
 		splitInput:     "",
+		logLevels:      make(map[logger.Level]bool),
+		logComponents:  make(map[string]bool),
+		confirm:        true,
 	}
 }
 
 func (m model) Init() tea.Cmd {
 	// Refresh nodes list periodically
-	return tea.Batch(tick(), refreshNodes(m.manager))
+	cmds := []tea.Cmd{tick(), refreshNodes(m.manager), waitForStatusEvent(m.manager), waitForMembershipEvent(m.membership),
+		waitForLogEntries(m.logUpdates)}
+	if len(m.scenario) > 0 {
+		cmds = append(cmds, nextScenarioStep(0))
+	}
+	return tea.Batch(cmds...)
 }
 
 func tick() tea.Cmd {
@@ -117,6 +309,48 @@ type nodesUpdatedMsg struct {
 	nodes []*node.Node
 }
 
+// waitForStatusEvent waits for the next node status transition, so the list updates right away
+func waitForStatusEvent(manager *node.Manager) tea.Cmd {
+	return func() tea.Msg {
+		return statusEventMsg{event: <-manager.Events()}
+	}
+}
+
+type statusEventMsg struct {
+	event node.StatusEvent
+}
+
+// waitForMembershipEvent waits for the next change in any node's view of the cluster
+func waitForMembershipEvent(events <-chan node.Event) tea.Cmd {
+	return func() tea.Msg {
+		return membershipEventMsg{event: <-events}
+	}
+}
+
+type membershipEventMsg struct {
+	event node.Event
+}
+
+// waitForLogEntries waits for the next entry added to the log buffer, and takes those added
+// with it, so a burst of logs makes one update
+func waitForLogEntries(updates <-chan logger.LogEntry) tea.Cmd {
+	return func() tea.Msg {
+		entries := []logger.LogEntry{<-updates}
+		for {
+			select {
+			case entry := <-updates:
+				entries = append(entries, entry)
+			default:
+				return logEntriesMsg{entries: entries}
+			}
+		}
+	}
+}
+
+type logEntriesMsg struct {
+	entries []logger.LogEntry
+}
+
 type quitMsg struct{}
 
 type shutdownCompleteMsg struct {
@@ -145,26 +379,134 @@ type actionResult struct {
 
 // handleCreateNode creates a new node
 func handleCreateNode(m *model) actionResult {
-	_, err := m.manager.CreateNode()
-	if err != nil {
+	if _, err := createNode(m, m.newNode); err != nil {
 		return actionResult{state: m.state, err: err}
 	}
-	m.nodes = m.manager.GetNodes()
 	return actionResult{state: m.state, lastCommand: "create"}
 }
 
-// handleDeleteNode deletes a node at the given index
+// createNode creates a node with overrides, the settings of c, C, Z and scenarios
+func createNode(m *model, overrides node.NodeOverrides) (*node.Node, error) {
+	if m.monitor {
+		return nil, errMonitor
+	}
+	n, err := m.manager.CreateNodeWith(overrides)
+	if err != nil {
+		return nil, err
+	}
+	m.nodes = m.manager.GetNodes()
+	m.record(createStep(overrides))
+	return n, nil
+}
+
+// handleDeleteNode deletes a node at the given index, once confirmed
 func handleDeleteNode(m *model, index int) actionResult {
+	return confirmAction(m, index, "Delete", deleteNode)
+}
+
+// deleteNode deletes a node at the given index, remembering it for undo
+func deleteNode(m *model, index int) actionResult {
+	overrides := m.nodes[index].Overrides()
 	if err := m.manager.DeleteNode(index); err != nil {
 		return actionResult{state: m.state, err: err}
 	}
+	m.rememberDeleted(overrides)
 	m.nodes = m.manager.GetNodes()
+	m.record(scenarioStep{Action: "delete", Node: overrides.NodeID})
 	return actionResult{
 		state:       StateNormal,
 		lastCommand: fmt.Sprintf("delete:%d", index),
 	}
 }
 
+// handleRestartNode restarts the node at the given index with a new generation
+func handleRestartNode(m *model, index int) actionResult {
+	if err := m.manager.RestartNode(index); err != nil {
+		return actionResult{state: StateNormal, err: err}
+	}
+	m.record(scenarioStep{Action: "restart", Node: m.nodes[index].GetConfig().NodeID})
+	m.nodes = m.manager.GetNodes()
+	return actionResult{
+		state:       StateNormal,
+		lastCommand: fmt.Sprintf("restart:%d", index),
+	}
+}
+
+// handleDecommissionNode starts decommissioning the node at the given index
+func handleDecommissionNode(m *model, index int) actionResult {
+	return confirmAction(m, index, "Decommission", decommissionNode)
+}
+
+// decommissionNode decommissions the node at the given index
+func decommissionNode(m *model, index int) actionResult {
+	if err := m.manager.DecommissionNode(index); err != nil {
+		return actionResult{state: StateNormal, err: err}
+	}
+	m.record(scenarioStep{Action: "decommission", Node: m.nodes[index].GetConfig().NodeID})
+	return actionResult{state: StateNormal}
+}
+
+// handleGossipNode runs one gossip round on the node at the given index
+func handleGossipNode(m *model, index int) actionResult {
+	if err := m.manager.SendGossipRound(index); err != nil {
+		return actionResult{state: StateNormal, err: err}
+	}
+	m.record(scenarioStep{Action: "gossip", Node: m.nodes[index].GetConfig().NodeID})
+	return actionResult{
+		state:       StateNormal,
+		lastCommand: fmt.Sprintf("gossip:%d", index),
+	}
+}
+
+// handlePauseNode pauses the node at the given index, or resumes it if it is paused
+func handlePauseNode(m *model, index int) actionResult {
+	if index < 0 || index >= len(m.nodes) {
+		return actionResult{state: StateNormal, err: fmt.Errorf("invalid node index: %d", index+1)}
+	}
+	nodeID := m.nodes[index].GetConfig().NodeID
+	pause, action := m.manager.PauseNode, "pause"
+	if m.nodes[index].Paused() {
+		pause, action = m.manager.ResumeNode, "resume"
+	}
+	if err := pause(nodeID); err != nil {
+		return actionResult{state: StateNormal, err: err}
+	}
+	m.record(scenarioStep{Action: action, Node: nodeID})
+	return actionResult{
+		state:       StateNormal,
+		lastCommand: fmt.Sprintf("pause:%d", index),
+	}
+}
+
+// selecting reports whether a node selection menu (delete, restart, decommission, gossip, pause, detail
+// or action) is open
+func (m *model) selecting() bool {
+	return m.state == StateDeleteSelect || m.state == StateRestartSelect ||
+		m.state == StateDecommissionSelect || m.state == StateGossipSelect ||
+		m.state == StatePauseSelect || m.state == StateDetailSelect ||
+		m.state == StateActionSelect
+}
+
+// handleSelected runs the selection menu's action on the node at index
+func handleSelected(m *model, index int) actionResult {
+	switch m.state {
+	case StateRestartSelect:
+		return handleRestartNode(m, index)
+	case StateDecommissionSelect:
+		return handleDecommissionNode(m, index)
+	case StateGossipSelect:
+		return handleGossipNode(m, index)
+	case StatePauseSelect:
+		return handlePauseNode(m, index)
+	case StateDetailSelect:
+		return handleShowDetail(m, index)
+	case StateActionSelect:
+		return handleOpenActions(m, index)
+	default:
+		return handleDeleteNode(m, index)
+	}
+}
+
 // handleEnterDeleteMode transitions to delete selection mode
 func handleEnterDeleteMode(m *model) State {
 	if len(m.nodes) == 0 {
@@ -176,24 +518,36 @@ func handleEnterDeleteMode(m *model) State {
 	return StateDeleteSelect
 }
 
-// handleCancelDelete cancels delete mode
-func handleCancelDelete(m *model) State {
+// handleCancelSelect cancels delete, restart, decommission, gossip, pause, detail or action mode
+func handleCancelSelect(m *model) State {
 	m.selected = 0
 	m.numericInput = ""
 	m.err = nil
 	return StateNormal
 }
 
+// scrollLogSection scrolls what the log section shows, the timeline or the logs
+func scrollLogSection(m *model, direction string) {
+	if !m.showTimeline {
+		handleScrollLogs(m, direction)
+	} else if direction == "up" {
+		m.scrollTimeline(1)
+	} else {
+		m.scrollTimeline(-1)
+	}
+}
+
 // handleScrollLogs scrolls the log view
 func handleScrollLogs(m *model, direction string) {
-	allEntries := m.logBuffer.GetAll()
-	maxScroll := len(allEntries) - 15
+	maxScroll := m.countShownLogs(m.logEntries) - m.logPanelLines()
 	if maxScroll < 0 {
 		maxScroll = 0
 	}
 
 	switch direction {
 	case "up":
+		// Reading older logs, keep them in place while new ones come
+		pauseLogs(m)
 		if m.logScroll < maxScroll {
 			m.logScroll++
 		}
@@ -204,6 +558,17 @@ func handleScrollLogs(m *model, direction string) {
 	}
 }
 
+// countShownLogs counts the entries the log panel shows
+func (m *model) countShownLogs(entries []logger.LogEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if m.shouldShowLogEntry(entry) {
+			count++
+		}
+	}
+	return count
+}
+
 // handleRepeatLastCommand repeats the last command
 func handleRepeatLastCommand(m *model) actionResult {
 	if m.lastCommand == "" {
@@ -223,6 +588,36 @@ func handleRepeatLastCommand(m *model) actionResult {
 				return actionResult{state: m.state, err: fmt.Errorf("node index %d no longer exists", index+1)}
 			}
 		}
+	} else if strings.HasPrefix(m.lastCommand, "restart:") {
+		parts := strings.Split(m.lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				if index >= 0 && index < len(m.nodes) {
+					return handleRestartNode(m, index)
+				}
+				return actionResult{state: m.state, err: fmt.Errorf("node index %d no longer exists", index+1)}
+			}
+		}
+	} else if strings.HasPrefix(m.lastCommand, "gossip:") {
+		parts := strings.Split(m.lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				if index >= 0 && index < len(m.nodes) {
+					return handleGossipNode(m, index)
+				}
+				return actionResult{state: m.state, err: fmt.Errorf("node index %d no longer exists", index+1)}
+			}
+		}
+	} else if strings.HasPrefix(m.lastCommand, "pause:") {
+		parts := strings.Split(m.lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				if index >= 0 && index < len(m.nodes) {
+					return handlePauseNode(m, index)
+				}
+				return actionResult{state: m.state, err: fmt.Errorf("node index %d no longer exists", index+1)}
+			}
+		}
 	} else if m.lastCommand == "create" {
 		return handleCreateNode(m)
 	}
@@ -232,7 +627,7 @@ func handleRepeatLastCommand(m *model) actionResult {
 
 // Key handler functions
 
-// handleCreateNodeKey handles C key press
+// handleCreateNodeKey handles c key press (creates a node with the default settings)
 func handleCreateNodeKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 	result := handleCreateNode(m)
 	m.err = result.err
@@ -263,6 +658,61 @@ func handleFirstD(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 	return StateWaitingForSecondD, nil
 }
 
+// handleRestartKey handles R key press (enters restart mode)
+func handleRestartKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to restart")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StateRestartSelect, nil
+}
+
+// handleDecommissionKey handles X key press (enters decommission mode)
+func handleDecommissionKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to decommission")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StateDecommissionSelect, nil
+}
+
+// handleGossipKey handles G key press (enters gossip mode)
+func handleGossipKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to gossip from")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StateGossipSelect, nil
+}
+
+// handlePauseKey handles P key press (enters pause mode)
+func handlePauseKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to pause")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StatePauseSelect, nil
+}
+
+// handleSaveTopologyKey handles W key press (saves the topology for --restore)
+func handleSaveTopologyKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if err := m.manager.SaveTopology(interactiveTopology); err != nil {
+		m.err = err
+		return m.state, nil
+	}
+	m.err = nil
+	logger.Infof("Topology of %d nodes saved to %s", len(m.nodes), interactiveTopology)
+	return m.state, nil
+}
+
 // handleQuit handles quit commands
 func handleQuit(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 	return m.state, shutdownNodes(m.manager)
@@ -270,13 +720,13 @@ func handleQuit(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 
 // handleEnter handles Enter key
 func handleEnter(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
-		// Handle delete confirmation
+	if m.selecting() {
+		// Handle delete/restart confirmation
 		if m.numericInput != "" {
 			if num, err := strconv.Atoi(m.numericInput); err == nil {
 				if num >= 1 && num <= len(m.nodes) {
 					index := num - 1
-					result := handleDeleteNode(m, index)
+					result := handleSelected(m, index)
 					m.err = result.err
 					m.numericInput = ""
 					if result.lastCommand != "" {
@@ -292,14 +742,29 @@ func handleEnter(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 			m.numericInput = ""
 			return m.state, nil
 		}
-		// Delete selected node
-		result := handleDeleteNode(m, m.selected)
+		// Delete/restart selected node
+		result := handleSelected(m, m.selected)
 		m.err = result.err
 		if result.lastCommand != "" {
 			m.lastCommand = result.lastCommand
 		}
 		return result.state, nil
 	}
+	if m.state == StateLogSearch {
+		return startSearch(m), nil
+	}
+	if m.state == StateActionMenu {
+		return runAction(m, m.actionChoice), nil
+	}
+	if m.state == StateSetAppState {
+		return setAppState(m), nil
+	}
+	if m.state == StatePartition {
+		return applyPartition(m), nil
+	}
+	if m.state == StateCreateForm {
+		return submitCreateForm(m), nil
+	}
 	if m.state == StateLogFilter {
 		// Confirm filter and return to normal mode
 		// Check if any filter is active
@@ -331,7 +796,7 @@ func handleEnter(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 
 // handleSpace handles Space key (same as Enter in delete mode)
 func handleSpace(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
+	if m.selecting() {
 		return handleEnter(m, msg)
 	}
 	return m.state, nil
@@ -339,8 +804,8 @@ func handleSpace(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 
 // handleEscape handles Escape key
 func handleEscape(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
-		return handleCancelDelete(m), nil
+	if m.selecting() {
+		return handleCancelSelect(m), nil
 	}
 	if m.state == StateWaitingForSecondD {
 		return StateNormal, nil
@@ -352,36 +817,79 @@ func handleEscape(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 		m.err = nil
 		return StateNormal, nil
 	}
+	if m.state == StateLogSearch {
+		// Cancel typing, the previous search stays
+		m.searchInput = ""
+		return StateNormal, nil
+	}
+	if m.state == StateActionMenu || m.state == StateSetAppState {
+		m.appStateInput = ""
+		return StateNormal, nil
+	}
+	if m.state == StatePartition {
+		m.partitionGroups = nil
+		m.err = nil
+		return StateNormal, nil
+	}
+	if m.state == StateCreateForm {
+		m.createInputs = nil
+		m.err = nil
+		return StateNormal, nil
+	}
+	if m.state == StateNormal && m.search != nil {
+		clearSearch(m)
+		m.err = nil
+	}
 	return m.state, nil
 }
 
 // handleUp handles Up/K keys
 func handleUp(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
+	if m.state == StateActionMenu {
+		if m.actionChoice > 0 {
+			m.actionChoice--
+		}
+		return m.state, nil
+	}
+	if m.selecting() || m.state == StatePartition {
 		if m.selected > 0 {
 			m.selected--
 		}
 		return m.state, nil
 	}
-	handleScrollLogs(m, "up")
+	scrollLogSection(m, "up")
 	return m.state, nil
 }
 
 // handleDown handles Down/J keys
 func handleDown(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
+	if m.state == StateActionMenu {
+		if m.actionChoice < len(nodeActions)-1 {
+			m.actionChoice++
+		}
+		return m.state, nil
+	}
+	if m.selecting() || m.state == StatePartition {
 		if m.selected < len(m.nodes)-1 {
 			m.selected++
 		}
 		return m.state, nil
 	}
-	handleScrollLogs(m, "down")
+	scrollLogSection(m, "down")
 	return m.state, nil
 }
 
 // handleNumeric handles numeric input (0-9)
 func handleNumeric(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
-	if m.state == StateDeleteSelect {
+	if m.state == StateActionMenu {
+		// Menu entries are single digits, run the entry right away
+		if num, err := strconv.Atoi(msg.String()); err == nil && num >= 1 && num <= len(nodeActions) {
+			m.actionChoice = num - 1
+			return runAction(m, m.actionChoice), nil
+		}
+		return m.state, nil
+	}
+	if m.selecting() {
 		keyStr := msg.String()
 		m.numericInput += keyStr
 		if m.err != nil && strings.Contains(m.err.Error(), "does not exist") {
@@ -461,6 +969,7 @@ func handleSplitViewKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 	if m.logSplitView == "none" || m.logSplitView == "colored" {
 		m.hiddenNodes = make(map[int]bool)
 		m.splitInput = ""
+		m.splitPageIndex = 0
 	}
 	return m.state, nil
 }
@@ -486,7 +995,18 @@ func handleOtherKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 	if m.state == StateWaitingForSecondD {
 		return handleEnterDeleteMode(m), nil
 	}
-	if m.state == StateDeleteSelect {
+	if m.state == StateLogSearch {
+		m.searchInput += typedText(msg)
+		return m.state, nil
+	}
+	if m.state == StateSetAppState {
+		m.appStateInput += typedText(msg)
+		return m.state, nil
+	}
+	if m.state == StateCreateForm {
+		return updateCreateInput(m, msg)
+	}
+	if m.selecting() {
 		// Clear numeric input on non-numeric keys
 		m.numericInput = ""
 	}
@@ -511,13 +1031,50 @@ func handleOtherKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
 var keyHandlers = map[State]map[string]keyHandler{
 	StateNormal: {
 		"c":      handleCreateNodeKey,
-		"C":      handleCreateNodeKey,
+		"C":      handleCreateFormKey,
 		"d":      handleFirstD,
 		"D":      handleFirstD,
+		"r":      handleRestartKey,
+		"R":      handleRestartKey,
+		"x":      handleDecommissionKey,
+		"X":      handleDecommissionKey,
+		"g":      handleGossipKey,
+		"G":      handleGossipKey,
+		"p":      handlePartitionKey,
+		"P":      handlePauseKey,
+		"u":      handleHealKey,
+		"U":      handleHealKey,
+		"i":      handleDetailKey,
+		"I":      handleDetailKey,
+		"m":      handleMatrixKey,
+		"M":      handleMatrixKey,
+		"/":      handleSearchKey,
+		"n":      handleNextMatch,
+		"N":      handlePrevMatch,
+		"f":      handleFollowKey,
+		"F":      handleFollowKey,
+		"e":      handleExportKey,
+		"E":      handleExportAllKey,
+		"a":      handleActionKey,
+		"A":      handleActionKey,
+		"esc":    handleEscape,
+		"w":      handleSaveTopologyKey,
+		"W":      handleSaveTopologyKey,
 		"l":      handleLogFilterKey,
 		"L":      handleLogFilterKey,
 		"s":      handleSplitViewKey,
 		"S":      handleSplitViewKey,
+		"[":      handleSplitPageKey,
+		"]":      handleSplitPageKey,
+		"?":      handleHelpKey,
+		"h":      handleSparklinesKey,
+		"H":      handleSparklinesKey,
+		"v":      handleDetectorKey,
+		"V":      handleDetectorKey,
+		"t":      handleTimelineKey,
+		"T":      handleTimelineKey,
+		"z":      handleUndoKey,
+		"Z":      handleUndoKey,
 		"q":      handleQuit,
 		"Q":      handleQuit,
 		"ctrl+c": handleQuit,
@@ -562,11 +1119,224 @@ var keyHandlers = map[State]map[string]keyHandler{
 		"8":     handleNumeric,
 		"9":     handleNumeric,
 	},
+	StateRestartSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StateDecommissionSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StateGossipSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StatePauseSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StateActionSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StateActionMenu: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleEnter,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StatePartition: {
+		"esc":    handleEscape,
+		"enter":  handleEnter,
+		" ":      handlePartitionCycleKey,
+		"a":      handlePartitionGroupKey,
+		"A":      handlePartitionGroupKey,
+		"b":      handlePartitionGroupKey,
+		"B":      handlePartitionGroupKey,
+		"up":     handleUp,
+		"k":      handleUp,
+		"down":   handleDown,
+		"j":      handleDown,
+		"ctrl+c": handleQuit,
+	},
+	StateSetAppState: {
+		"esc":       handleEscape,
+		"enter":     handleEnter,
+		"backspace": handleAppStateBackspace,
+		"ctrl+c":    handleQuit,
+	},
+	StateDetailSelect: {
+		"esc":   handleEscape,
+		"enter": handleEnter,
+		" ":     handleSpace,
+		"up":    handleUp,
+		"k":     handleUp,
+		"down":  handleDown,
+		"j":     handleDown,
+		"0":     handleNumeric,
+		"1":     handleNumeric,
+		"2":     handleNumeric,
+		"3":     handleNumeric,
+		"4":     handleNumeric,
+		"5":     handleNumeric,
+		"6":     handleNumeric,
+		"7":     handleNumeric,
+		"8":     handleNumeric,
+		"9":     handleNumeric,
+	},
+	StateLogSearch: {
+		"esc":       handleEscape,
+		"enter":     handleEnter,
+		"backspace": handleSearchBackspace,
+		"ctrl+c":    handleQuit,
+	},
+	StateCreateForm: {
+		"esc":       handleEscape,
+		"enter":     handleEnter,
+		"tab":       handleCreateFormMove,
+		"shift+tab": handleCreateFormMove,
+		"up":        handleCreateFormMove,
+		"down":      handleCreateFormMove,
+		" ":         handleCreateFormSpace,
+		"ctrl+c":    handleQuit,
+	},
+	StateConfirm: {
+		"y":      handleConfirmYes,
+		"Y":      handleConfirmYes,
+		"n":      handleConfirmNo,
+		"N":      handleConfirmNo,
+		"esc":    handleConfirmNo,
+		"ctrl+c": handleQuit,
+	},
+	StateHelp: {
+		"?":      handleCloseHelp,
+		"esc":    handleCloseHelp,
+		"q":      handleCloseHelp,
+		"Q":      handleCloseHelp,
+		"ctrl+c": handleQuit,
+		"up":     handleHelpScroll,
+		"k":      handleHelpScroll,
+		"down":   handleHelpScroll,
+		"j":      handleHelpScroll,
+	},
+	StateNodeDetail: {
+		"esc":    handleCloseDetail,
+		"i":      handleCloseDetail,
+		"I":      handleCloseDetail,
+		"q":      handleQuit,
+		"Q":      handleQuit,
+		"ctrl+c": handleQuit,
+		"up":     handleUp,
+		"k":      handleUp,
+		"down":   handleDown,
+		"j":      handleDown,
+	},
 	StateLogFilter: {
 		"esc":   handleEscape,
 		"enter": handleEnter,
 		"a":     handleFilterAllKey,
 		"A":     handleFilterAllKey,
+		"d":     handleFilterLevelKey,
+		"D":     handleFilterLevelKey,
+		"i":     handleFilterLevelKey,
+		"I":     handleFilterLevelKey,
+		"w":     handleFilterLevelKey,
+		"W":     handleFilterLevelKey,
+		"e":     handleFilterLevelKey,
+		"E":     handleFilterLevelKey,
+		"g":     handleFilterComponentKey,
+		"G":     handleFilterComponentKey,
+		"t":     handleFilterComponentKey,
+		"T":     handleFilterComponentKey,
+		"n":     handleFilterComponentKey,
+		"N":     handleFilterComponentKey,
 		"0":     handleNumeric,
 		"1":     handleNumeric,
 		"2":     handleNumeric,
@@ -604,6 +1374,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = newState
 		return m, cmd
 
+	case tea.MouseMsg:
+		newState, cmd := handleMouse(&m, msg)
+		m.state = newState
+		return m, cmd
+
+	case scenarioStepMsg:
+		return m, m.runScenarioStep()
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -615,12 +1393,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == StateWaitingForSecondD {
 			m.state = handleEnterDeleteMode(&m)
 		}
+		if m.toast != "" && time.Now().After(m.toastExpires) {
+			m.toast = ""
+		}
+		m.sampleMetrics(time.Now())
 		return m, tea.Batch(tick(), refreshNodes(m.manager))
 
 	case nodesUpdatedMsg:
 		m.nodes = msg.nodes
 		return m, nil
 
+	case statusEventMsg:
+		return m, tea.Batch(refreshNodes(m.manager), waitForStatusEvent(m.manager))
+
+	case membershipEventMsg:
+		m.recordEvent(msg.event)
+		return m, tea.Batch(refreshNodes(m.manager), waitForMembershipEvent(m.membership))
+
+	case logEntriesMsg:
+		m.addLogEntries(msg.entries)
+		return m, waitForLogEntries(m.logUpdates)
+
 	case shutdownCompleteMsg:
 		// Log any shutdown errors via the logger
 		if msg.err != nil {
@@ -636,12 +1429,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// getNodeColor returns a lipgloss color for a given node index
-func getNodeColor(nodeIndex int) lipgloss.Color {
-	colors := []string{"39", "46", "226", "201", "51"} // Blue, Green, Yellow, Magenta, Cyan
-	return lipgloss.Color(colors[nodeIndex%len(colors)])
-}
-
 // getNodeIndexByID returns the node index for a given NodeID string, or -1 if not found
 func (m *model) getNodeIndexByID(nodeID string) int {
 	for i, n := range m.nodes {
@@ -654,6 +1441,12 @@ func (m *model) getNodeIndexByID(nodeID string) int {
 
 // shouldShowLogEntry determines if a log entry should be shown based on the current filter
 func (m *model) shouldShowLogEntry(entry logger.LogEntry) bool {
+	if m.state == StateNodeDetail {
+		return entry.NodeID == string(m.detailNode) // only the logs of the node in the detail view
+	}
+	if !m.passesLevelAndComponent(entry) {
+		return false
+	}
 	if !m.logFilterMode {
 		return true // Show all if filter mode is not active
 	}
@@ -688,12 +1481,11 @@ func (m *model) getLogEntryColor(entry logger.LogEntry) lipgloss.Color {
 		return lipgloss.Color("") // No color in normal mode
 	}
 
-	nodeIndex := m.getNodeIndexByID(entry.NodeID)
-	if nodeIndex == -1 {
+	if m.getNodeIndexByID(entry.NodeID) == -1 {
 		return lipgloss.Color("") // No color for unknown nodes
 	}
 
-	return getNodeColor(nodeIndex)
+	return nodeColor(entry.NodeID)
 }
 
 // getNodesToDisplay returns the list of node indices to display in split view
@@ -811,28 +1603,26 @@ func wrapText(text string, width int) []string {
 	return lines
 }
 
-// renderLogPanel renders a single log panel for a specific node
+// renderLogPanel renders a single log panel for a specific node, width and height including the border
 func (m *model) renderLogPanel(nodeIndex int, width int, height int, isColumnMode bool) string {
-	allEntries := m.logBuffer.GetAll()
-	totalCount := len(allEntries)
-
-	logCount := height - 2 // Reserve space for title and border
-	if logCount < 1 {
-		logCount = 1
-	}
+	contentWidth := max(1, width-4) // Border and padding
+	logCount := max(1, height-3)    // Border and title
 
 	var logLines []string
-	if totalCount == 0 {
+	if len(m.logEntries) == 0 {
 		logLines = []string{"(no logs yet)"}
 	} else {
 		nodeID := string(m.nodes[nodeIndex].GetConfig().NodeID)
-		color := getNodeColor(nodeIndex)
+		color := nodeColor(nodeID)
 
-		// Collect all entries for this specific node
+		// Only the newest entries fit, unless the filters drop some of them
+		limit := 0
+		if len(m.selectedLevels()) == 0 && len(m.selectedComponents()) == 0 {
+			limit = logCount
+		}
 		var nodeEntries []logger.LogEntry
-
-		for _, entry := range allEntries {
-			if entry.NodeID == nodeID {
+		for _, entry := range m.logBuffer.Query(nodeID, logger.LevelDebug, time.Time{}, limit) {
+			if m.passesLevelAndComponent(entry) {
 				nodeEntries = append(nodeEntries, entry)
 			}
 		}
@@ -848,23 +1638,25 @@ func (m *model) renderLogPanel(nodeIndex int, width int, height int, isColumnMod
 			entry := nodeEntries[i]
 			// Local line number: most recent entry in this panel = 0
 			// We show entries in reverse order (newest first), so line 0 is the first one shown
-			localLineNumber := len(nodeEntries) - 1 - i - start
-			if localLineNumber < 0 {
-				localLineNumber = 0
-			}
+			localLineNumber := len(nodeEntries) - 1 - i
 			lineNum := fmt.Sprintf("%4d", localLineNumber)
 			formattedEntry := logger.FormatLogEntry(entry)
 
-			// Apply color
+			// Highlight search matches, or apply color
+			panelColor := lipgloss.Color("")
 			if m.logFilterMode {
+				panelColor = color
+			}
+			if highlighted, ok := m.highlightSearch(entry, formattedEntry, panelColor); ok {
+				formattedEntry = highlighted
+			} else if m.logFilterMode {
 				colorStyle := lipgloss.NewStyle().Foreground(color)
 				formattedEntry = colorStyle.Render(formattedEntry)
 			}
 
-			// Wrap text in column mode, truncate in row mode
-			maxLen := width - 10 // Reserve space for line number and padding
+			// Wrap text in column mode, the viewport cuts long lines in row mode
 			if isColumnMode {
-				// Wrap text for column mode
+				maxLen := max(1, contentWidth-7) // Reserve space for the line number
 				wrappedLines := wrapText(formattedEntry, maxLen)
 				for i, wrappedLine := range wrappedLines {
 					if i == 0 {
@@ -875,10 +1667,6 @@ func (m *model) renderLogPanel(nodeIndex int, width int, height int, isColumnMod
 					}
 				}
 			} else {
-				// Truncate for row mode (rows have more width, but we still truncate for safety)
-				if len(formattedEntry) > maxLen {
-					formattedEntry = formattedEntry[:maxLen-3] + "..."
-				}
 				logLines = append(logLines, fmt.Sprintf("%s | %s", lineNum, formattedEntry))
 			}
 		}
@@ -889,79 +1677,130 @@ func (m *model) renderLogPanel(nodeIndex int, width int, height int, isColumnMod
 	}
 
 	nodeID := string(m.nodes[nodeIndex].GetConfig().NodeID)
-	title := fmt.Sprintf("Node %d (%s)", nodeIndex+1, nodeID)
+	title := ansi.Truncate(fmt.Sprintf("Node %d (%s)", nodeIndex+1, nodeID), contentWidth, "")
 
-	content := title + "\n" + strings.Join(logLines, "\n")
+	// The viewport keeps the panel at its size, however many and long the lines
+	logView := viewport.New(contentWidth, logCount)
+	logView.SetContent(strings.Join(logLines, "\n"))
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(getNodeColor(nodeIndex)).
-		Padding(0, 1).
-		Height(height).
-		Width(width)
+		BorderForeground(nodeColor(nodeID)).
+		Padding(0, 1)
 
-	return boxStyle.Render(content)
+	return boxStyle.Render(title + "\n" + logView.View())
 }
 
-// renderSplitView renders logs in columns or rows layout
+// renderSplitView renders logs in columns or rows layout, a page of panels at a time
 func (m *model) renderSplitView() string {
-	nodeIndices := m.getNodesToDisplay()
+	nodeIndices, page, pages, height := m.splitPage(m.logSectionHeight())
 
 	if len(nodeIndices) == 0 {
 		return "(no nodes to display)"
 	}
 
-	// Limit to reasonable number of panels
-	maxPanels := 4
-	if len(nodeIndices) > maxPanels {
-		nodeIndices = nodeIndices[:maxPanels]
+	var panels []string
+	if m.logSplitView == "columns" {
+		// Split into columns, sharing the width
+		panelWidth := m.logBoxWidth() / len(nodeIndices)
+		for _, nodeIndex := range nodeIndices {
+			panels = append(panels, m.renderLogPanel(nodeIndex, panelWidth, height, true))
+		}
+	} else {
+		// Split into rows, sharing the height, the first rows get the lines left over
+		for i, nodeIndex := range nodeIndices {
+			panelHeight := height / len(nodeIndices)
+			if i < height%len(nodeIndices) {
+				panelHeight++
+			}
+			panels = append(panels, m.renderLogPanel(nodeIndex, m.logBoxWidth(), panelHeight, false))
+		}
 	}
 
-	boxWidth := 100
-	if m.width > 0 {
-		boxWidth = m.width - 4
+	var view string
+	if m.logSplitView == "columns" {
+		view = lipgloss.JoinHorizontal(lipgloss.Top, panels...)
+	} else {
+		view = lipgloss.JoinVertical(lipgloss.Left, panels...)
 	}
+	if pages > 1 {
+		view = splitPageLine(page, pages) + "\n" + view
+	}
+	return view
+}
 
-	panelHeight := 13
+// renderUnifiedLogs renders the log entries of every shown node in one box, newest first,
+// scrolled back by logScroll entries
+func (m *model) renderUnifiedLogs() string {
+	allEntries := m.logEntries
+	totalCount := len(allEntries)
+	contentWidth := m.logBoxWidth() - 4 // Border and padding
 
-	if m.logSplitView == "columns" {
-		// Split into columns
-		panelWidth := boxWidth / len(nodeIndices)
-		if panelWidth < 20 {
-			panelWidth = 20
-		}
+	// Show entries in reverse order (newest first) with line numbers
+	// Most recent = 0, older entries count up
+	// Line number is based on position in full buffer, so filtered entries leave gaps
+	var logLines []string
+	for i := totalCount - 1; i >= 0; i-- {
+		entry := allEntries[i]
 
-		var panels []string
-		for _, nodeIndex := range nodeIndices {
-			panels = append(panels, m.renderLogPanel(nodeIndex, panelWidth, panelHeight, true))
+		// Filter entries based on active filter
+		if !m.shouldShowLogEntry(entry) {
+			continue
 		}
 
-		return lipgloss.JoinHorizontal(lipgloss.Top, panels...)
-	} else if m.logSplitView == "rows" {
-		// Split into rows
-		panelHeight = panelHeight / len(nodeIndices)
-		if panelHeight < 3 {
-			panelHeight = 3
-		}
+		// Format with line number (right-aligned, 4 digits)
+		lineNum := fmt.Sprintf("%4d", totalCount-1-i)
+		formattedEntry := logger.FormatLogEntry(entry)
 
-		var panels []string
-		for _, nodeIndex := range nodeIndices {
-			panels = append(panels, m.renderLogPanel(nodeIndex, boxWidth, panelHeight, false))
+		// Highlight search matches, in the entry's color if it has one
+		if highlighted, ok := m.highlightSearch(entry, formattedEntry, m.getLogEntryColor(entry)); ok {
+			formattedEntry = highlighted
+		} else if m.logFilterMode || m.logSplitView == "colored" {
+			// Apply color if in filter mode or colored split view
+			color := m.getLogEntryColor(entry)
+			if color != "" {
+				colorStyle := lipgloss.NewStyle().Foreground(color)
+				formattedEntry = colorStyle.Render(formattedEntry)
+			}
 		}
 
-		return lipgloss.JoinVertical(lipgloss.Left, panels...)
+		logLines = append(logLines, fmt.Sprintf("%s | %s", lineNum, formattedEntry))
+	}
+	if totalCount == 0 {
+		logLines = []string{"     | (no logs yet)"}
+	}
+
+	logTitle := "Logs:"
+	if m.logPaused {
+		newLines := fmt.Sprintf("%d new lines", m.logNewLines)
+		if m.logNewLines == 1 {
+			newLines = "1 new line"
+		}
+		logTitle = fmt.Sprintf("Logs (paused, %s, F to follow):", newLines)
 	}
+	logTitle = ansi.Truncate(logTitle, contentWidth, "")
+
+	// logScroll=0 shows the most recent entries, logScroll=1 starts 1 shown entry back, etc.
+	logView := viewport.New(contentWidth, m.logPanelLines())
+	logView.SetContent(strings.Join(logLines, "\n"))
+	logView.SetYOffset(m.logScroll)
 
-	return ""
+	logStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.muted).
+		Padding(0, 1)
+
+	return logStyle.Render(logTitle + "\n" + logView.View())
 }
 
-func (m model) View() string {
+// renderStatus renders the top of the screen: the title, the error, the partition banner and the toast
+func (m *model) renderStatus() string {
 	var s strings.Builder
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(activeTheme.title).
 		Padding(1, 2)
 	s.WriteString(titleStyle.Render("Cassandra Node Manager"))
 	s.WriteString("\n\n")
@@ -969,17 +1808,34 @@ func (m model) View() string {
 	// Status
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(activeTheme.err).
 			Bold(true)
 		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
 		s.WriteString("\n\n")
 	}
+	s.WriteString(m.renderPartitionBanner())
+	if m.toast != "" {
+		toastStyle := lipgloss.NewStyle().
+			Foreground(activeTheme.ok).
+			Bold(true)
+		s.WriteString(toastStyle.Render(m.toast))
+		s.WriteString("\n\n")
+	}
+	return s.String()
+}
 
-	// Nodes list
-	if len(m.nodes) == 0 {
+// renderHeader renders everything above the log section
+func (m *model) renderHeader() string {
+	var s strings.Builder
+	s.WriteString(m.renderStatus())
+
+	// Nodes list, or the detail view of one node
+	if m.state == StateNodeDetail {
+		s.WriteString(m.renderNodeDetail())
+	} else if len(m.nodes) == 0 {
 		s.WriteString("No nodes running.\n\n")
 	} else {
-		s.WriteString("Running Nodes:\n\n")
+		s.WriteString("Nodes:\n\n")
 		for i, n := range m.nodes {
 			config := n.GetConfig()
 			// Check if logs are visible in split view
@@ -988,25 +1844,54 @@ func (m model) View() string {
 				logsVisible = !m.hiddenNodes[i]
 			}
 
-			baseInfo := fmt.Sprintf("%s (port: %s)", config.NodeID, config.Port)
+			generation := n.GetGossipState().LocalHeartbeat().Generation
+			baseInfo := fmt.Sprintf("%s (port: %s, generation: %d) [%s]", config.NodeID, config.Port, generation, n.Status())
+			if status, ok := n.GetGossipState().GetLocalAppState(gossip.AppStatus); ok && status.Value != gossip.StatusNormal {
+				// Leaving the cluster
+				baseInfo += fmt.Sprintf(" [%s]", status.Value)
+			}
+			if n.Paused() {
+				baseInfo += " [paused]"
+			}
+			if restarts := n.Restarts(); restarts > 0 {
+				baseInfo += fmt.Sprintf(" [restarts: %d]", restarts)
+			}
 			if logsVisible && (m.logSplitView == "columns" || m.logSplitView == "rows") {
 				baseInfo += " [logs enabled]"
 			}
+			group := groupNone
+			if m.state == StatePartition {
+				group = m.partitionGroups[config.NodeID]
+			}
+			if group != groupNone {
+				baseInfo += fmt.Sprintf(" [group %s]", group)
+			}
 
-			if m.state == StateDeleteSelect && i == m.selected {
-				// Highlight selected node in delete mode
+			if (m.selecting() || m.state == StatePartition) && i == m.selected {
+				// Highlight selected node, red in delete mode and orange in the other selection modes
+				selectColor := activeTheme.err
+				if m.state != StateDeleteSelect {
+					selectColor = activeTheme.selected
+				}
 				nodeStyle := lipgloss.NewStyle().
 					PaddingLeft(2).
-					Foreground(lipgloss.Color("196")).
+					Foreground(selectColor).
 					Bold(true)
 				s.WriteString(nodeStyle.Render(fmt.Sprintf("[%d] > %s", i+1, baseInfo)))
 				s.WriteString("\n")
+			} else if group != groupNone {
+				// Color the nodes of the partition being built by group
+				nodeStyle := lipgloss.NewStyle().
+					PaddingLeft(2).
+					Foreground(groupColor(group)).
+					Bold(true)
+				s.WriteString(nodeStyle.Render(fmt.Sprintf("[%d] %s %s", i+1, group, baseInfo)))
+				s.WriteString("\n")
 			} else if m.logFilterMode && m.logFilter[i] {
 				// Highlight filtered node with its color
-				nodeColor := getNodeColor(i)
 				nodeStyle := lipgloss.NewStyle().
 					PaddingLeft(2).
-					Foreground(nodeColor).
+					Foreground(nodeColor(string(config.NodeID))).
 					Bold(true)
 				s.WriteString(nodeStyle.Render(fmt.Sprintf("[%d] * %s", i+1, baseInfo)))
 				s.WriteString("\n")
@@ -1017,150 +1902,129 @@ func (m model) View() string {
 		s.WriteString("\n")
 	}
 
-	// Logs section - single unified box
-	s.WriteString("\n")
-
-	// Get all log entries once to avoid redundant buffer access
-	allEntries := m.logBuffer.GetAll()
-	totalCount := len(allEntries)
-
-	// Get recent logs (show last 15 entries, adjusted by scroll)
-	logCount := 15
-	maxScroll := 100 // Maximum scroll back
-
-	// Calculate how many entries we need to fetch
-	// We need logCount entries to display, plus logScroll to scroll back
-	entriesNeeded := logCount + m.logScroll
-	if entriesNeeded > maxScroll+logCount {
-		entriesNeeded = maxScroll + logCount
+	if m.state == StateActionMenu || m.state == StateSetAppState {
+		s.WriteString(m.renderActionMenu())
+	}
+	if m.state == StateCreateForm {
+		s.WriteString(m.renderCreateForm())
+	}
+	if m.state == StateConfirm {
+		s.WriteString(m.renderConfirm())
 	}
 
-	var logLines []string
-	if totalCount == 0 {
-		logLines = []string{"     | (no logs yet)"}
-	} else {
-		// Derive recent entries from allEntries (take last entriesNeeded entries)
-		// If entriesNeeded > totalCount, we'll use all entries
-		recentStart := totalCount - entriesNeeded
-		if recentStart < 0 {
-			recentStart = 0
-		}
-		logEntries := allEntries[recentStart:]
-
-		// Calculate the range to display from logEntries
-		// logScroll=0 means show most recent logCount entries
-		// logScroll=1 means show entries starting 1 position back, etc.
-		start := len(logEntries) - logCount - m.logScroll
-		if start < 0 {
-			start = 0
-		}
-		end := len(logEntries) - m.logScroll
-		if end > len(logEntries) {
-			end = len(logEntries)
-		}
-		if end <= start {
-			end = start + logCount
-			if end > len(logEntries) {
-				end = len(logEntries)
-				start = end - logCount
-				if start < 0 {
-					start = 0
-				}
-			}
-		}
-
-		// Show entries in reverse order (newest first) with line numbers
-		// Most recent = 0, older entries count up
-		// Line number is based on position in full buffer, not display position
-		// logEntries[i] corresponds to allEntries[recentStart + i]
-		// Position in full buffer = recentStart + i
-		// Line number: most recent (position totalCount-1) = 0
-		// So line number = totalCount - 1 - (recentStart + i)
-		for i := end - 1; i >= start; i-- {
-			entry := logEntries[i]
-
-			// Filter entries based on active filter
-			if !m.shouldShowLogEntry(entry) {
-				continue
-			}
-
-			// Calculate line number based on position in full buffer
-			positionInFullBuffer := recentStart + i
-			// Line number: most recent (position totalCount-1) = 0
-			// So line number = totalCount - 1 - positionInFullBuffer
-			lineNumber := totalCount - 1 - positionInFullBuffer
-			if lineNumber < 0 {
-				lineNumber = 0
-			}
-
-			// Format with line number (right-aligned, 4 digits)
-			lineNum := fmt.Sprintf("%4d", lineNumber)
-			formattedEntry := logger.FormatLogEntry(entry)
+	// Nodes of other processes, see --attach
+	s.WriteString(m.renderAttachedNodes())
 
-			// Apply color if in filter mode or colored split view
-			if m.logFilterMode || m.logSplitView == "colored" {
-				color := m.getLogEntryColor(entry)
-				if color != "" {
-					colorStyle := lipgloss.NewStyle().Foreground(color)
-					formattedEntry = colorStyle.Render(formattedEntry)
-				}
-			}
-
-			logLines = append(logLines, fmt.Sprintf("%s | %s", lineNum, formattedEntry))
-		}
+	if m.showMatrix && m.state != StateNodeDetail {
+		s.WriteString(m.renderMatrix())
+	}
+	if m.showSparklines && m.state != StateNodeDetail {
+		s.WriteString(m.renderSparklines())
+	}
+	if m.showDetector && m.state != StateNodeDetail {
+		s.WriteString(m.renderDetector())
 	}
 
-	// Check if split view is active (but not "colored" which uses unified view with colors)
-	if (m.logSplitView == "columns" || m.logSplitView == "rows") && len(m.nodes) > 0 {
-		splitViewContent := m.renderSplitView()
-		s.WriteString(splitViewContent)
-	} else {
-		// Create a single log box with title - use terminal width if available, otherwise default
-		boxWidth := 100
-		if m.width > 0 {
-			boxWidth = m.width - 4 // Leave some margin
-		}
+	return s.String()
+}
 
-		// Combine title and content
-		logContent := "Logs:\n" + strings.Join(logLines, "\n")
+func (m model) View() string {
+	if m.state == StateHelp {
+		return m.renderHelp()
+	}
 
-		logStyle := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			Padding(0, 1).
-			Height(13).
-			Width(boxWidth)
+	var s strings.Builder
+	s.WriteString(m.renderHeader())
 
-		s.WriteString(logStyle.Render(logContent))
+	// Logs section, sized to fill the terminal
+	s.WriteString("\n")
+	if m.showTimeline {
+		s.WriteString(m.renderTimeline())
+	} else if m.splitViewShown() {
+		// Split view, but not "colored" which uses the unified view with colors
+		s.WriteString(m.renderSplitView())
+	} else {
+		s.WriteString(m.renderUnifiedLogs())
 	}
 	s.WriteString("\n\n")
 
+	s.WriteString(m.renderInstructions())
+	return s.String()
+}
+
+// renderInstructions renders the status line below the log section
+func (m *model) renderInstructions() string {
+	var s strings.Builder
+
 	// Instructions
 	instructionsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(activeTheme.muted).
 		Italic(true).
 		PaddingTop(1)
+	if m.width > 0 {
+		// Wrap rather than have the renderer cut the line at the terminal's edge
+		instructionsStyle = instructionsStyle.Width(m.width)
+	}
 
-	if m.state == StateDeleteSelect {
+	if m.selecting() {
+		mode := "DELETE MODE"
+		switch m.state {
+		case StateRestartSelect:
+			mode = "RESTART MODE"
+		case StateDecommissionSelect:
+			mode = "DECOMMISSION MODE"
+		case StateGossipSelect:
+			mode = "GOSSIP MODE"
+		case StatePauseSelect:
+			mode = "PAUSE/RESUME MODE"
+		case StateDetailSelect:
+			mode = "DETAIL MODE"
+		case StateActionSelect:
+			mode = "ACTION MODE"
+		}
 		var helpText string
 		if m.numericInput != "" {
 			// Build fully formatted string when numeric input is present
-			helpText = fmt.Sprintf("DELETE MODE: Type node number (current: %s) or Enter to confirm, Esc to cancel", m.numericInput)
+			helpText = fmt.Sprintf("%s: Type node number (current: %s) or Enter to confirm, Esc to cancel", mode, m.numericInput)
 		} else {
 			// Format string with node count when no numeric input
-			helpText = fmt.Sprintf("DELETE MODE: Use ↑/↓/j/k or type node number (1-%d, multi-digit supported), Enter to confirm, Esc to cancel", len(m.nodes))
+			helpText = fmt.Sprintf("%s: Use ↑/↓/j/k or type node number (1-%d, multi-digit supported), Enter to confirm, Esc to cancel", mode, len(m.nodes))
 		}
 		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StateLogSearch {
+		helpText := fmt.Sprintf("SEARCH: /%s█ Enter to search (regular expression), Esc to cancel", m.searchInput)
+		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StatePartition {
+		helpText := "PARTITION MODE: Use ↑/↓/j/k to select a node, A or B to put it in a group (again to take it out), Space to cycle, Enter to partition A from B, Esc to cancel"
+		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StateActionMenu {
+		helpText := fmt.Sprintf("ACTION MENU: Use ↑/↓/j/k and Enter, or type the action's number (1-%d), Esc to cancel", len(nodeActions))
+		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StateSetAppState {
+		helpText := fmt.Sprintf("SET APP STATE: KEY=value: %s█ Enter to set, Esc to cancel", m.appStateInput)
+		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StateConfirm {
+		s.WriteString(instructionsStyle.Render("CONFIRM: Y to confirm, N or Esc to cancel"))
+	} else if m.state == StateCreateForm {
+		helpText := "CREATE NODE: Tab/Shift+Tab or ↑/↓ to move between fields, Space to toggle manual gossip, Enter to create, Esc to cancel"
+		s.WriteString(instructionsStyle.Render(helpText))
+	} else if m.state == StateNodeDetail {
+		s.WriteString(instructionsStyle.Render("DETAIL VIEW: Esc or I to go back | ↑/↓/j/k to scroll logs | Q to quit"))
 	} else if m.state == StateLogFilter {
 		var helpText string
 		if m.logFilterInput != "" {
 			helpText = fmt.Sprintf("FILTER MODE: Type node number (current: %s) or A for all, Enter to confirm, Esc to cancel", m.logFilterInput)
 		} else {
-			helpText = fmt.Sprintf("FILTER MODE: Type node number (1-%d, multi-digit supported) or A for all, Enter to confirm, Esc to cancel", len(m.nodes))
+			helpText = fmt.Sprintf("FILTER MODE: Type node number (1-%d, multi-digit supported) or A for all, D/I/W/E for levels, G/T/N for gossip/transport/node logs, Enter to confirm, Esc to cancel", len(m.nodes))
 		}
+		helpText += m.levelComponentFilterStatus()
 		s.WriteString(instructionsStyle.Render(helpText))
 	} else {
-		instructionText := "Press C to create a node | D to delete a node | DD to delete first node | L to filter logs | S to toggle split view"
+		// The essentials, ? lists every key
+		instructionText := "c create | C create with settings | D delete | R restart | A actions | I inspect | L filter logs | / search logs"
+		if m.monitor {
+			instructionText = "Monitoring the attached nodes | T timeline | L filter logs | / search logs"
+		}
 
 		// Add inline preview if there's a last command
 		if m.lastCommand != "" {
@@ -1170,7 +2034,15 @@ func (m model) View() string {
 			instructionText += " | Enter to repeat last command"
 		}
 
-		instructionText += " | ↑/↓/j/k to scroll logs | Q to quit"
+		instructionText += " | ↑/↓/j/k to scroll logs | ? for help | Q to quit"
+
+		instructionText += m.undoStatus()
+		instructionText += m.scenarioStatus()
+
+		// Add search status if active
+		if m.search != nil {
+			instructionText += fmt.Sprintf(" | Search: %s, n/N for next/previous, Esc to clear", m.searchStatus())
+		}
 
 		// Add filter status if active
 		if m.logFilterMode {
@@ -1185,6 +2057,9 @@ func (m model) View() string {
 			}
 		}
 
+		// Add level and component filter status if active
+		instructionText += m.levelComponentFilterStatus()
+
 		// Add split view status if active
 		if m.logSplitView != "none" {
 			instructionText += fmt.Sprintf(" | Split: %s", m.logSplitView)
@@ -1208,6 +2083,30 @@ func formatCommandPreview(lastCommand string) string {
 			}
 		}
 		return "D → [node]"
+	} else if strings.HasPrefix(lastCommand, "restart:") {
+		parts := strings.Split(lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				return fmt.Sprintf("R → %d", index+1)
+			}
+		}
+		return "R → [node]"
+	} else if strings.HasPrefix(lastCommand, "gossip:") {
+		parts := strings.Split(lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				return fmt.Sprintf("G → %d", index+1)
+			}
+		}
+		return "G → [node]"
+	} else if strings.HasPrefix(lastCommand, "pause:") {
+		parts := strings.Split(lastCommand, ":")
+		if len(parts) == 2 {
+			if index, err := strconv.Atoi(parts[1]); err == nil {
+				return fmt.Sprintf("P → %d", index+1)
+			}
+		}
+		return "P → [node]"
 	} else if lastCommand == "create" {
 		return "C"
 	}
@@ -1215,8 +2114,81 @@ func formatCommandPreview(lastCommand string) string {
 }
 
 func runInteractive(cmd *cobra.Command, args []string) {
-	p := tea.NewProgram(initialModel())
-	if _, err := p.Run(); err != nil {
+	if interactiveMonitor && len(interactiveAttach) == 0 {
+		fatalf(exitConfig, "--monitor needs the nodes to watch, give them with --attach")
+	}
+	if interactiveMonitor && interactiveRestore {
+		fatalf(exitConfig, "--monitor doesn't create nodes, it can't be used with --restore")
+	}
+	if interactiveTheme != "" {
+		if err := validateTheme(interactiveTheme); err != nil {
+			fatalf(exitConfig, "invalid --theme: %v", err)
+		}
+	}
+	var scenario []scenarioStep
+	if interactiveScenario != "" {
+		var err error
+		if scenario, err = loadScenario(interactiveScenario); err != nil {
+			fatalf(exitConfig, "%v", err)
+		}
+	}
+	m := initialModel(node.NodeOverrides{
+		HeartbeatInterval: interactiveHeartbeat,
+		ManualHeartbeat:   interactiveManualGossip,
+	})
+	m.manager.SetAutoRestart(interactiveAutoRestart)
+	m.confirm = interactiveConfirm
+	if interactiveResetPrefs {
+		if err := resetPreferences(); err != nil {
+			warnf("%v", err)
+		}
+	} else if prefs, err := loadPreferences(); err != nil {
+		warnf("%v, starting with the default layout", err)
+	} else if err := m.applyPreferences(prefs); err != nil {
+		warnf("%v, starting with the default layout", err)
+	}
+	activeTheme = selectTheme(interactiveTheme, m.theme)
+	if interactiveTheme != "" {
+		m.theme = interactiveTheme
+	}
+	m.monitor = interactiveMonitor
+	m.scenario = scenario
+	m.recordPath = interactiveRecord
+	for _, addr := range interactiveAttach {
+		remote, err := m.manager.Attach(addr)
+		if err != nil {
+			m.err = err
+			continue
+		}
+		remote.FollowLogs(m.logBuffer)
+	}
+	if interactiveRestore {
+		if _, err := m.manager.LoadTopology(interactiveTopology); err != nil {
+			m.err = err
+		}
+		m.nodes = m.manager.GetNodes()
+	}
+
+	var options []tea.ProgramOption
+	if interactiveMouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, options...)
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error running interactive mode: %v\n", err)
+		return
+	}
+	if final, ok := final.(model); ok {
+		if err := savePreferences(final.preferences()); err != nil {
+			warnf("%v", err)
+		}
+		if final.recordPath != "" {
+			if err := saveScenario(final.recordPath, final.recorded); err != nil {
+				warnf("%v", err)
+			} else {
+				fmt.Printf("Recorded %d steps to %s\n", len(final.recorded), final.recordPath)
+			}
+		}
 	}
 }
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// nodeAction is an entry of the action menu opened with A
+type nodeAction struct {
+	label func(m *model, index int) string
+	run   func(m *model, index int) actionResult
+}
+
+// nodeActions are the entries of the action menu, in order
+var nodeActions = []nodeAction{
+	{label: fixedLabel("Restart"), run: handleRestartNode},
+	{label: pauseLabel, run: handlePauseNode},
+	{label: fixedLabel("Decommission"), run: handleDecommissionNode},
+	{label: fixedLabel("Trigger gossip round"), run: handleGossipNode},
+	{label: fixedLabel("Set app state"), run: handleSetAppStatePrompt},
+}
+
+func fixedLabel(label string) func(*model, int) string {
+	return func(*model, int) string { return label }
+}
+
+// pauseLabel offers to resume a paused node and to pause the others
+func pauseLabel(m *model, index int) string {
+	if index < len(m.nodes) && m.nodes[index].Paused() {
+		return "Resume"
+	}
+	return "Pause"
+}
+
+// handleActionKey handles A key press (enters action mode)
+func handleActionKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to act on")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StateActionSelect, nil
+}
+
+// handleOpenActions opens the action menu of the node at the given index
+func handleOpenActions(m *model, index int) actionResult {
+	if index < 0 || index >= len(m.nodes) {
+		return actionResult{state: StateNormal, err: fmt.Errorf("invalid node index: %d", index+1)}
+	}
+	m.actionNode = index
+	m.actionChoice = 0
+	return actionResult{state: StateActionMenu}
+}
+
+// runAction runs the action menu entry at choice on the menu's node
+func runAction(m *model, choice int) State {
+	if m.actionNode >= len(m.nodes) {
+		m.err = fmt.Errorf("node %d no longer exists", m.actionNode+1)
+		return StateNormal
+	}
+	result := nodeActions[choice].run(m, m.actionNode)
+	m.err = result.err
+	if result.lastCommand != "" {
+		m.lastCommand = result.lastCommand
+	}
+	return result.state
+}
+
+// handleSetAppStatePrompt asks for the KEY=value to set on the node at index
+func handleSetAppStatePrompt(m *model, index int) actionResult {
+	m.appStateInput = ""
+	return actionResult{state: StateSetAppState}
+}
+
+// handleAppStateBackspace deletes the last character of the KEY=value being typed
+func handleAppStateBackspace(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.appStateInput = trimLastRune(m.appStateInput)
+	return m.state, nil
+}
+
+// setAppState sets the typed KEY=value on the action menu's node
+func setAppState(m *model) State {
+	input := m.appStateInput
+	m.appStateInput = ""
+	key, value, ok := strings.Cut(input, "=")
+	if !ok {
+		m.err = fmt.Errorf("expected KEY=value, got %q", input)
+		return StateNormal
+	}
+	state, err := setNodeAppState(m, m.actionNode, gossip.AppStateKey(strings.TrimSpace(key)), value)
+	if err != nil {
+		m.err = err
+		return StateNormal
+	}
+	m.err = nil
+	m.showToast(fmt.Sprintf("Set %s=%s (version %d) on %s", strings.TrimSpace(key), state.Value, state.Version,
+		m.nodes[m.actionNode].GetConfig().NodeID))
+	return StateNormal
+}
+
+// setNodeAppState sets the application state key to value on the node at index
+func setNodeAppState(m *model, index int, key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	state, err := m.manager.SetAppState(index, key, value)
+	if err != nil {
+		return gossip.AppState{}, err
+	}
+	m.record(scenarioStep{Action: "set-state", Node: m.nodes[index].GetConfig().NodeID, Key: key, Value: value})
+	return state, nil
+}
+
+// renderActionMenu renders the action menu of the node at m.actionNode
+func (m *model) renderActionMenu() string {
+	if m.actionNode >= len(m.nodes) {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Actions for %s:\n\n", m.nodes[m.actionNode].GetConfig().NodeID))
+	chosenStyle := lipgloss.NewStyle().
+		PaddingLeft(2).
+		Foreground(activeTheme.selected).
+		Bold(true)
+	for i, action := range nodeActions {
+		label := action.label(m, m.actionNode)
+		if i == m.actionChoice {
+			s.WriteString(chosenStyle.Render(fmt.Sprintf("[%d] > %s", i+1, label)))
+			s.WriteString("\n")
+		} else {
+			s.WriteString(fmt.Sprintf("  [%d]   %s\n", i+1, label))
+		}
+	}
+	s.WriteString("\n")
+	return s.String()
+}
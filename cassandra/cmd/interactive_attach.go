@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// errMonitor is the error of the keys creating nodes with --monitor
+var errMonitor = errors.New("--monitor only watches the attached nodes, it doesn't create nodes")
+
+// renderAttachedNodes renders the nodes of other processes (--attach), each with its view of the
+// cluster like cassandra status shows it
+func (m *model) renderAttachedNodes() string {
+	remotes := m.manager.RemoteNodes()
+	if len(remotes) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString("Attached nodes:\n\n")
+	for _, remote := range remotes {
+		endpoints := remote.Endpoints()
+		connection := "connected"
+		if !remote.Connected() {
+			connection = "disconnected"
+			if err := remote.Err(); err != nil {
+				connection += ": " + err.Error()
+			}
+		}
+		s.WriteString(fmt.Sprintf("  [remote] %s (%s) [%s] knows %d nodes\n",
+			remote.NodeID(), remote.Address(), connection, len(endpoints)))
+		if !remote.Connected() || len(endpoints) == 0 {
+			continue
+		}
+
+		w := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "    NODE\tADDRESS\tSTATE\tSTATUS\tGENERATION\tVERSION")
+		for _, endpoint := range endpoints {
+			nodeID := string(endpoint.State.HeartbeatState.NodeID)
+			if endpoint.Local {
+				nodeID += " (local)"
+			}
+			state := "DOWN"
+			if endpoint.Alive {
+				state = "UP"
+			}
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%s\t%d\t%d\n", nodeID,
+				endpoint.State.ApplicationStates[gossip.AppHeartbeat].Value, state,
+				endpoint.State.ApplicationStates[gossip.AppStatus].Value,
+				endpoint.State.HeartbeatState.Generation, endpoint.State.HeartbeatState.Version)
+		}
+		w.Flush()
+	}
+	s.WriteString("\n")
+	return s.String()
+}
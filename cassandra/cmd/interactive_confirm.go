@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+// undoLimit is how many deleted nodes Z can bring back
+const undoLimit = 10
+
+// confirmAction runs run on the node at index once confirmed with Y, or right away if
+// confirmation is off (--confirm=false). The node is remembered by ID, so the confirmation
+// applies to it even if the list changes meanwhile.
+func confirmAction(m *model, index int, verb string, run func(m *model, index int) actionResult) actionResult {
+	if index < 0 || index >= len(m.nodes) {
+		return actionResult{state: StateNormal, err: fmt.Errorf("invalid node index: %d", index+1)}
+	}
+	if !m.confirm {
+		return run(m, index)
+	}
+	m.confirmNode = m.nodes[index].GetConfig().NodeID
+	m.confirmVerb = verb
+	m.confirmRun = run
+	return actionResult{state: StateConfirm}
+}
+
+// handleConfirmYes handles Y key in the confirmation dialog (run the action)
+func handleConfirmYes(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	run := m.confirmRun
+	m.confirmRun = nil
+	index := m.getNodeIndexByID(string(m.confirmNode))
+	if run == nil || index < 0 {
+		m.err = fmt.Errorf("node %s no longer exists", m.confirmNode)
+		return StateNormal, nil
+	}
+	result := run(m, index)
+	m.err = result.err
+	if result.lastCommand != "" {
+		m.lastCommand = result.lastCommand
+	}
+	return result.state, nil
+}
+
+// handleConfirmNo handles N and Esc keys in the confirmation dialog (cancel the action)
+func handleConfirmNo(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.confirmRun = nil
+	return StateNormal, nil
+}
+
+// renderConfirm renders the confirmation dialog
+func (m *model) renderConfirm() string {
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.err).
+		Foreground(activeTheme.err).
+		Bold(true).
+		Padding(0, 1)
+	return dialogStyle.Render(fmt.Sprintf("%s %s? (y/n)", m.confirmVerb, m.confirmNode)) + "\n\n"
+}
+
+// rememberDeleted keeps the settings of a node about to be deleted, for Z
+func (m *model) rememberDeleted(overrides node.NodeOverrides) {
+	m.deleted = append(m.deleted, overrides)
+	if len(m.deleted) > undoLimit {
+		m.deleted = m.deleted[len(m.deleted)-undoLimit:]
+	}
+}
+
+// handleUndoKey handles Z key (recreate the last deleted node with its ID, port and seeds)
+func handleUndoKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.deleted) == 0 {
+		m.err = fmt.Errorf("no deleted node to bring back")
+		return m.state, nil
+	}
+	overrides := m.deleted[len(m.deleted)-1]
+	if _, err := createNode(m, overrides); err != nil {
+		// Kept, to try again once e.g. its port is free
+		m.err = fmt.Errorf("failed to recreate %s: %w", overrides.NodeID, err)
+		return m.state, nil
+	}
+	m.deleted = m.deleted[:len(m.deleted)-1]
+	m.err = nil
+	m.showToast(fmt.Sprintf("Recreated %s on port %d", overrides.NodeID, overrides.Port))
+	return m.state, nil
+}
+
+// undoStatus describes the undo buffer for the status line, e.g. " | Z to bring back node-2"
+func (m *model) undoStatus() string {
+	if len(m.deleted) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | Z to bring back %s", m.deleted[len(m.deleted)-1].NodeID)
+}
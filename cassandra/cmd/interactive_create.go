@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Fields of the create form, in order. The text fields index createInputs, manual gossip is a toggle.
+const (
+	createFieldNodeID = iota
+	createFieldPort
+	createFieldSeeds
+	createFieldHeartbeat
+	createFieldManual
+	createFieldCount
+)
+
+// createFieldLabels are the labels of the create form's fields, by field
+var createFieldLabels = [createFieldCount]string{
+	createFieldNodeID:    "Node ID",
+	createFieldPort:      "Port",
+	createFieldSeeds:     "Seeds",
+	createFieldHeartbeat: "Heartbeat interval",
+	createFieldManual:    "Manual gossip",
+}
+
+// handleCreateFormKey handles Shift+C key (opens the form to create a node with custom settings)
+func handleCreateFormKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.monitor {
+		m.err = errMonitor
+		return m.state, nil
+	}
+	placeholders := [createFieldManual]string{
+		createFieldNodeID:    "generated (node-1, node-2, ...)",
+		createFieldPort:      "next free port",
+		createFieldSeeds:     "the oldest nodes, or none; host:port, comma-separated",
+		createFieldHeartbeat: "default",
+	}
+
+	m.createInputs = make([]textinput.Model, createFieldManual)
+	for i := range m.createInputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = placeholders[i]
+		input.Cursor.SetMode(cursor.CursorStatic)
+		m.createInputs[i] = input
+	}
+	// Start from the settings of the nodes C creates
+	if m.newNode.HeartbeatInterval > 0 {
+		m.createInputs[createFieldHeartbeat].SetValue(m.newNode.HeartbeatInterval.String())
+	}
+	if m.newNode.Seeds != nil {
+		m.createInputs[createFieldSeeds].SetValue(strings.Join(m.newNode.Seeds, ","))
+	}
+	m.createManual = m.newNode.ManualHeartbeat
+	m.createFocus = createFieldNodeID
+	m.createInputs[m.createFocus].Focus()
+	m.err = nil
+	return StateCreateForm, nil
+}
+
+// handleCreateFormMove handles Tab, Shift+Tab, ↑ and ↓ in the create form (focus the next or previous field)
+func handleCreateFormMove(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	step := 1
+	if key := msg.String(); key == "shift+tab" || key == "up" {
+		step = -1
+	}
+	focusCreateField(m, (m.createFocus+step+createFieldCount)%createFieldCount)
+	return m.state, nil
+}
+
+// focusCreateField moves the cursor of the create form to field
+func focusCreateField(m *model, field int) {
+	if m.createFocus < createFieldManual {
+		m.createInputs[m.createFocus].Blur()
+	}
+	m.createFocus = field
+	if field < createFieldManual {
+		m.createInputs[field].Focus()
+	}
+}
+
+// handleCreateFormSpace handles Space in the create form, toggling manual gossip or typing a space
+func handleCreateFormSpace(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.createFocus == createFieldManual {
+		m.createManual = !m.createManual
+		return m.state, nil
+	}
+	return updateCreateInput(m, msg)
+}
+
+// updateCreateInput passes msg, a key typed in the create form, to the focused text field
+func updateCreateInput(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.createFocus == createFieldManual {
+		return m.state, nil
+	}
+	var cmd tea.Cmd
+	m.createInputs[m.createFocus], cmd = m.createInputs[m.createFocus].Update(msg)
+	return m.state, cmd
+}
+
+// submitCreateForm creates a node with the settings of the create form. The form stays open with
+// the error if a field is invalid or the node can't be created.
+func submitCreateForm(m *model) State {
+	overrides := m.newNode
+	overrides.NodeID = gossip.NodeID(strings.TrimSpace(m.createInputs[createFieldNodeID].Value()))
+	overrides.ManualHeartbeat = m.createManual
+
+	if port := strings.TrimSpace(m.createInputs[createFieldPort].Value()); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil || p < 1 || p > 65535 {
+			return createFormError(m, createFieldPort, fmt.Errorf("invalid port %q, expected 1-65535", port))
+		}
+		overrides.Port = p
+	}
+
+	switch seeds := strings.TrimSpace(m.createInputs[createFieldSeeds].Value()); seeds {
+	case "":
+		overrides.Seeds = nil // the oldest managed nodes
+	case "none":
+		overrides.Seeds = []string{}
+	default:
+		overrides.Seeds = nil
+		for _, seed := range strings.Split(seeds, ",") {
+			seed = strings.TrimSpace(seed)
+			if _, _, err := net.SplitHostPort(seed); err != nil {
+				return createFormError(m, createFieldSeeds, fmt.Errorf("invalid seed %q, expected host:port", seed))
+			}
+			overrides.Seeds = append(overrides.Seeds, seed)
+		}
+	}
+
+	overrides.HeartbeatInterval = 0
+	if interval := strings.TrimSpace(m.createInputs[createFieldHeartbeat].Value()); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			return createFormError(m, createFieldHeartbeat, fmt.Errorf("invalid heartbeat interval %q, expected a duration like 500ms or 2s", interval))
+		}
+		overrides.HeartbeatInterval = d
+	}
+
+	n, err := createNode(m, overrides)
+	if err != nil {
+		m.err = err
+		return m.state
+	}
+	m.createInputs = nil
+	m.err = nil
+	m.showToast(fmt.Sprintf("Created %s on port %s", n.GetConfig().NodeID, n.GetConfig().Port))
+	return StateNormal
+}
+
+// createFormError shows err and moves the cursor to the field at fault
+func createFormError(m *model, field int, err error) State {
+	m.err = err
+	focusCreateField(m, field)
+	return m.state
+}
+
+// renderCreateForm renders the create form, below the node list
+func (m *model) renderCreateForm() string {
+	labelWidth := 0
+	for _, label := range createFieldLabels {
+		labelWidth = max(labelWidth, lipgloss.Width(label))
+	}
+	labelStyle := lipgloss.NewStyle().Width(labelWidth + 4) // the marker and a space
+	focusedStyle := labelStyle.Foreground(activeTheme.selected).Bold(true)
+
+	var s strings.Builder
+	s.WriteString("Create node:\n\n")
+	for field, label := range createFieldLabels {
+		style, marker := labelStyle, "  "
+		if field == m.createFocus {
+			style, marker = focusedStyle, "> "
+		}
+		value := "[ ]"
+		if field == createFieldManual {
+			if m.createManual {
+				value = "[x]"
+			}
+		} else {
+			value = m.createInputs[field].View()
+		}
+		s.WriteString("  " + style.Render(marker+label) + value + "\n")
+	}
+	s.WriteString("\n")
+	return s.String()
+}
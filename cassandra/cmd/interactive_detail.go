@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+const (
+	// recentEventLimit is how many events of all nodes the interactive mode keeps
+	recentEventLimit = 500
+	// detailEventCount is how many of a node's recent events the detail view shows
+	detailEventCount = 10
+)
+
+// handleDetailKey handles I key press (enters detail mode)
+func handleDetailKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) == 0 {
+		m.err = fmt.Errorf("no nodes to inspect")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.numericInput = ""
+	return StateDetailSelect, nil
+}
+
+// handleShowDetail opens the detail view of the node at the given index
+func handleShowDetail(m *model, index int) actionResult {
+	if index < 0 || index >= len(m.nodes) {
+		return actionResult{state: StateNormal, err: fmt.Errorf("invalid node index: %d", index+1)}
+	}
+	// Follow the node by ID, its index changes when nodes before it are deleted
+	m.detailNode = m.nodes[index].GetConfig().NodeID
+	return actionResult{state: StateNodeDetail}
+}
+
+// handleCloseDetail closes the detail view
+func handleCloseDetail(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.detailNode = ""
+	return StateNormal, nil
+}
+
+// recordEvent keeps event for the detail view, forgetting the oldest past recentEventLimit
+func (m *model) recordEvent(event node.Event) {
+	m.events = append(m.events, event)
+	if len(m.events) > recentEventLimit {
+		m.events = slices.Clone(m.events[len(m.events)-recentEventLimit:])
+	}
+}
+
+// renderNodeDetail renders the detail view of the node with ID m.detailNode: its heartbeat,
+// application states, peers, round-trip latency to them and recent events
+func (m *model) renderNodeDetail() string {
+	index := m.getNodeIndexByID(string(m.detailNode))
+	if index < 0 {
+		return fmt.Sprintf("Node %s no longer exists.\n\n", m.detailNode)
+	}
+	n := m.nodes[index]
+	now := time.Now()
+	local := n.GetGossipState().LocalEndpointState()
+
+	var s strings.Builder
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(nodeColor(string(m.detailNode)))
+	s.WriteString(headerStyle.Render(fmt.Sprintf("[%d] %s (%s) [%s]", index+1, m.detailNode, n.AdvertisedAddress(), n.Status())))
+	if n.Paused() {
+		s.WriteString(" [paused]")
+	}
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("Heartbeat: generation %d, version %d\n\n",
+		local.HeartbeatState.Generation, local.HeartbeatState.Version))
+
+	sectionStyle := lipgloss.NewStyle().Bold(true)
+	s.WriteString(sectionStyle.Render("Application states:"))
+	s.WriteString("\n")
+	w := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+	keys := make([]gossip.AppStateKey, 0, len(local.ApplicationStates))
+	for key := range local.ApplicationStates {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		state := local.ApplicationStates[key]
+		fmt.Fprintf(w, "  %s\t%s\tv%d\n", key, state.Value, state.Version)
+	}
+	w.Flush()
+	s.WriteString("\n")
+
+	s.WriteString(sectionStyle.Render("Peers:"))
+	s.WriteString("\n")
+	peers := n.GetPeers()
+	if len(peers) == 0 {
+		s.WriteString("  (no peers)\n")
+	} else {
+		endpoints := make(map[gossip.NodeID]gossip.EndpointInfo)
+		for _, endpoint := range n.GetGossipState().Endpoints(now) {
+			endpoints[endpoint.State.HeartbeatState.NodeID] = endpoint
+		}
+		w = tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  NODE\tADDRESS\tSTATE\tGENERATION\tVERSION\tPHI\tCONNECTION\tLAST EXCHANGE\tFAILURES\tLAST ERROR")
+		for _, peer := range peers {
+			nodeID, liveness, generation, version, phi := "?", "-", "-", "-", "-"
+			if endpoint, ok := endpoints[peer.NodeID]; ok && peer.NodeID != "" {
+				nodeID = string(peer.NodeID)
+				liveness = "DOWN"
+				if endpoint.Alive {
+					liveness = "UP"
+				}
+				generation = fmt.Sprint(endpoint.State.HeartbeatState.Generation)
+				version = fmt.Sprint(endpoint.State.MaxVersion())
+				phi = fmt.Sprintf("%.2f", endpoint.Phi)
+			}
+			lastExchange := "-"
+			if !peer.LastExchange.IsZero() {
+				lastExchange = fmt.Sprintf("%v ago", now.Sub(peer.LastExchange).Round(time.Second))
+			}
+			lastError := "-"
+			if peer.LastError != nil {
+				lastError = peer.LastError.Error()
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n", nodeID, peer.Address, liveness,
+				generation, version, phi, peer.State, lastExchange, peer.Failures, lastError)
+		}
+		w.Flush()
+	}
+	s.WriteString("\n")
+
+	s.WriteString(sectionStyle.Render("Round-trip latency:"))
+	s.WriteString("\n")
+	s.WriteString(renderLatencies(n.GetGossipState().Stats().PeerLatency))
+	s.WriteString("\n")
+
+	s.WriteString(sectionStyle.Render("Recent events:"))
+	s.WriteString("\n")
+	var events []node.Event
+	for i := len(m.events) - 1; i >= 0 && len(events) < detailEventCount; i-- {
+		if m.events[i].NodeID == m.detailNode {
+			events = append(events, m.events[i])
+		}
+	}
+	if len(events) == 0 {
+		s.WriteString("  (no events yet)\n")
+	}
+	for _, event := range events {
+		s.WriteString(fmt.Sprintf("  %s %s\n", event.Time.Format("15:04:05.000"), formatEvent(event)))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// renderLatencies renders a line per peer with the round trips of the gossip RPCs to it:
+// how many, their mean, 50th and 99th percentiles and maximum, and their distribution over
+// gossip.LatencyBuckets, fastest first
+func renderLatencies(latencies map[gossip.NodeID]gossip.LatencyHistogram) string {
+	if len(latencies) == 0 {
+		return "  (no exchanges yet)\n"
+	}
+	ids := make([]gossip.NodeID, 0, len(latencies))
+	for id := range latencies {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var s strings.Builder
+	w := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  NODE\tRPCS\tMEAN\tP50\tP99\tMAX\tDISTRIBUTION (%v to %v+)\n",
+		gossip.LatencyBuckets[0], gossip.LatencyBuckets[len(gossip.LatencyBuckets)-1])
+	for _, id := range ids {
+		h := latencies[id]
+		buckets := make([]float64, len(h.Buckets))
+		for i, count := range h.Buckets {
+			buckets[i] = float64(count)
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%v\t%v\t%v\t%v\t%s\n", id, h.Count,
+			h.Mean().Round(time.Microsecond), h.Quantile(0.5).Round(time.Microsecond),
+			h.Quantile(0.99).Round(time.Microsecond), h.Max.Round(time.Microsecond),
+			sparkline(buckets, len(buckets)))
+	}
+	w.Flush()
+	return s.String()
+}
+
+// formatEvent describes event without its time and node, e.g. "PeerDown node-2"
+func formatEvent(event node.Event) string {
+	text := event.Type.String()
+	if event.Peer != "" {
+		text += " " + string(event.Peer)
+	}
+	if event.Address != "" {
+		text += " (" + event.Address + ")"
+	}
+	if event.Err != nil {
+		text += ": " + event.Err.Error()
+	}
+	return text
+}
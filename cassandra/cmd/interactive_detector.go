@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// detectorBarWidth is the width of a phi bar
+	detectorBarWidth = 24
+
+	// detectorBarScale is the phi filling a bar, as a multiple of the threshold, so a convicted
+	// peer's bar runs past the threshold mark
+	detectorBarScale = 1.5
+)
+
+// handleDetectorKey handles V key (toggle the failure detector panel)
+func handleDetectorKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.showDetector = !m.showDetector
+	return m.state, nil
+}
+
+// renderDetector renders what every node's phi-accrual failure detector thinks of its peers:
+// when the last heartbeat arrived, the mean interval between heartbeats, how late the
+// peer's round-trip latency allows a heartbeat to be, and phi with a bar
+// filling up to the threshold mark as suspicion grows, green, then yellow past half the
+// threshold and red past it, when the peer is marked DOWN.
+func (m *model) renderDetector() string {
+	if len(m.nodes) == 0 {
+		return ""
+	}
+	now := time.Now()
+
+	var s strings.Builder
+	s.WriteString("Failure detector (phi = (time since the last heartbeat - allowance) / mean interval × 0.43):\n\n")
+	for _, n := range m.nodes {
+		gossipState := n.GetGossipState()
+		detector := gossipState.FailureDetector()
+		threshold := detector.Threshold()
+		s.WriteString(fmt.Sprintf("  %s, marking peers DOWN once phi crosses %.1f\n", n.GetConfig().NodeID, threshold))
+
+		w := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+		peers := 0
+		for _, endpoint := range gossipState.Endpoints(now) {
+			if endpoint.Local {
+				continue
+			}
+			peers++
+			if peers == 1 {
+				fmt.Fprintln(w, "    PEER\tSTATE\tLAST HEARTBEAT\tMEAN INTERVAL\tALLOWANCE\tPHI")
+			}
+			nodeID := endpoint.State.HeartbeatState.NodeID
+			state := "DOWN"
+			if endpoint.Alive {
+				state = "UP"
+			}
+			lastHeartbeat, meanInterval, allowance := "-", "-", "-"
+			if arrivals, ok := detector.Arrivals(nodeID); ok {
+				lastHeartbeat = fmt.Sprintf("%v ago", now.Sub(arrivals.Last).Round(100*time.Millisecond))
+				meanInterval = arrivals.MeanInterval.Round(100 * time.Millisecond).String()
+				allowance = arrivals.Allowance.Round(time.Microsecond).String()
+			}
+			// The bar is the last column, its escape codes would throw off the widths of the others
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%s\t%s\t%s\n", nodeID, state, lastHeartbeat, meanInterval,
+				allowance, phiBar(endpoint.Phi, threshold))
+		}
+		if peers == 0 {
+			fmt.Fprintln(w, "    (no peers)")
+		}
+		w.Flush()
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// phiBar renders phi and a bar of it against threshold, e.g. "5.20 █████████████···│·······"
+func phiBar(phi, threshold float64) string {
+	color := activeTheme.ok
+	switch {
+	case phi >= threshold:
+		color = activeTheme.err
+	case phi >= threshold/2:
+		color = activeTheme.warn
+	}
+
+	scale := threshold * detectorBarScale
+	filled := min(detectorBarWidth, int(phi/scale*detectorBarWidth))
+	mark := int(detectorBarWidth / detectorBarScale)
+
+	var empty strings.Builder
+	for i := filled; i < detectorBarWidth; i++ {
+		if i == mark {
+			empty.WriteString("│")
+		} else {
+			empty.WriteString("·")
+		}
+	}
+	style := lipgloss.NewStyle().Foreground(color)
+	return style.Render(fmt.Sprintf("%5.2f %s", phi, strings.Repeat("█", filled))) +
+		lipgloss.NewStyle().Foreground(activeTheme.muted).Render(empty.String())
+}
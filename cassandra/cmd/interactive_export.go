@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// toastDuration is how long a confirmation stays on screen
+const toastDuration = 3 * time.Second
+
+// handleExportKey handles e key (export the logs the log panel shows)
+func handleExportKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	exportLogs(m, false)
+	return m.state, nil
+}
+
+// handleExportAllKey handles E key (export the whole log buffer)
+func handleExportAllKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	exportLogs(m, true)
+	return m.state, nil
+}
+
+// exportLogs writes the buffered log entries, only those shown unless all is set, to a
+// timestamped file in the working directory
+func exportLogs(m *model, all bool) {
+	var entries []logger.LogEntry
+	for _, entry := range m.logEntries {
+		if all || m.shouldShowLogEntry(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		m.err = fmt.Errorf("no logs to export")
+		return
+	}
+
+	path := fmt.Sprintf("cassandra-logs-%s.log", time.Now().Format("20060102-150405.000"))
+	if err := writeLogFile(path, entries); err != nil {
+		m.toast = ""
+		m.err = fmt.Errorf("failed to export logs: %w", err)
+		return
+	}
+	m.err = nil
+	m.showToast(fmt.Sprintf("Exported %d log entries to %s", len(entries), path))
+}
+
+// writeLogFile writes entries to path, one per line with the date and milliseconds, which
+// the log panel leaves out
+func writeLogFile(path string, entries []logger.LogEntry) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s %s: %s\n", entry.Timestamp.Format("2006-01-02 15:04:05.000"), entry.NodeID, entry.Message)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// showToast shows message below the title for toastDuration
+func (m *model) showToast(message string) {
+	m.toast = message
+	m.toastExpires = time.Now().Add(toastDuration)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// filterLevelKeys are the keys toggling a log level in filter mode
+var filterLevelKeys = map[string]logger.Level{
+	"d": logger.LevelDebug,
+	"i": logger.LevelInfo,
+	"w": logger.LevelWarn,
+	"e": logger.LevelError,
+}
+
+// filterComponentKeys are the keys toggling a component, the package that logged, in filter mode
+var filterComponentKeys = map[string]string{
+	"g": "gossip",
+	"t": "transport",
+	"n": "node",
+}
+
+// handleFilterLevelKey handles D, I, W and E keys in filter mode (toggle showing that level)
+func handleFilterLevelKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	level := filterLevelKeys[strings.ToLower(msg.String())]
+	m.logLevels[level] = !m.logLevels[level]
+	m.logFilterInput = ""
+	return m.state, nil
+}
+
+// handleFilterComponentKey handles G, T and N keys in filter mode (toggle showing that component)
+func handleFilterComponentKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	component := filterComponentKeys[strings.ToLower(msg.String())]
+	m.logComponents[component] = !m.logComponents[component]
+	m.logFilterInput = ""
+	return m.state, nil
+}
+
+// passesLevelAndComponent reports whether entry has one of the selected levels and components.
+// Nothing selected shows every level or component.
+func (m *model) passesLevelAndComponent(entry logger.LogEntry) bool {
+	if levels := m.selectedLevels(); len(levels) > 0 && !slices.Contains(levels, entry.Level) {
+		return false
+	}
+	if components := m.selectedComponents(); len(components) > 0 && !slices.Contains(components, entry.Component) {
+		return false
+	}
+	return true
+}
+
+// selectedLevels returns the levels selected in filter mode, from debug to error
+func (m *model) selectedLevels() []logger.Level {
+	var levels []logger.Level
+	for _, level := range []logger.Level{logger.LevelDebug, logger.LevelInfo, logger.LevelWarn, logger.LevelError} {
+		if m.logLevels[level] {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// selectedComponents returns the components selected in filter mode, sorted
+func (m *model) selectedComponents() []string {
+	var components []string
+	for component, selected := range m.logComponents {
+		if selected {
+			components = append(components, component)
+		}
+	}
+	slices.Sort(components)
+	return components
+}
+
+// levelComponentFilterStatus describes the level and component filters for the status line,
+// e.g. " | Levels: ERROR | Components: gossip", empty if there are none
+func (m *model) levelComponentFilterStatus() string {
+	var status string
+	if levels := m.selectedLevels(); len(levels) > 0 {
+		names := make([]string, len(levels))
+		for i, level := range levels {
+			names[i] = strings.ToUpper(level.String())
+		}
+		status += " | Levels: " + strings.Join(names, ",")
+	}
+	if components := m.selectedComponents(); len(components) > 0 {
+		status += " | Components: " + strings.Join(components, ",")
+	}
+	return status
+}
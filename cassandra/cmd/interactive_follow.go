@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// handleFollowKey handles F key (pause following the newest logs, or follow them again)
+func handleFollowKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.logPaused {
+		resumeLogs(m)
+	} else {
+		pauseLogs(m)
+	}
+	return m.state, nil
+}
+
+// pauseLogs stops the log panel from following new entries, it keeps showing what it shows
+func pauseLogs(m *model) {
+	if m.logPaused {
+		return
+	}
+	m.logPaused = true
+	m.logNewLines = 0
+}
+
+// resumeLogs makes the log panel follow the newest entries again
+func resumeLogs(m *model) {
+	m.logPaused = false
+	m.logNewLines = 0
+	m.logScroll = 0
+}
+
+// addLogEntries adds entries, just added to the log buffer, to the entries shown, dropping the
+// oldest ones like the buffer does. A paused log panel scrolls back as many entries so it keeps
+// showing the same ones.
+func (m *model) addLogEntries(entries []logger.LogEntry) {
+	m.logEntries = append(m.logEntries, entries...)
+	if excess := len(m.logEntries) - m.logBuffer.MaxSize(); excess > 0 {
+		m.logEntries = m.logEntries[excess:]
+	}
+	if !m.logPaused {
+		return
+	}
+
+	// The panel scrolls by shown entries, filtered ones don't move it
+	added := m.countShownLogs(entries)
+	m.logNewLines += added
+	m.logScroll = min(m.logScroll+added, max(0, m.countShownLogs(m.logEntries)-m.logPanelLines()))
+}
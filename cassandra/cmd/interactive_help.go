@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpBinding is a key and what it does, a line of the help screen
+type helpBinding struct {
+	keys        string
+	description string
+}
+
+// helpSection is a mode and its key bindings on the help screen
+type helpSection struct {
+	title    string
+	bindings []helpBinding
+}
+
+// helpSections are the contents of the help screen opened with ?, keep them in sync with keyHandlers
+var helpSections = []helpSection{
+	{
+		title: "Normal mode",
+		bindings: []helpBinding{
+			{"c", "Create a node with the default settings"},
+			{"C", "Create a node with settings of your choice"},
+			{"D", "Delete a node (DD deletes the first one)"},
+			{"R", "Restart a node"},
+			{"X", "Decommission a node"},
+			{"Z", "Bring back the last deleted node"},
+			{"G", "Run a gossip round from a node"},
+			{"P", "Pause or resume a node"},
+			{"p", "Partition the cluster"},
+			{"U", "Heal every partition"},
+			{"I", "Inspect a node"},
+			{"A", "Open a node's action menu"},
+			{"M", "Show or hide the convergence matrix"},
+			{"H", "Show or hide the metrics sparklines"},
+			{"V", "Show or hide the failure detector: every peer's phi and last heartbeat"},
+			{"T", "Show the event timeline or the logs, ↑/↓ scroll it"},
+			{"W", "Save the topology"},
+			{"Enter", "Repeat the last command"},
+			{"?", "Show this help"},
+			{"Q, Ctrl+C", "Quit, stopping every node"},
+		},
+	},
+	{
+		title: "Logs",
+		bindings: []helpBinding{
+			{"↑/↓, K/J", "Scroll the logs"},
+			{"/", "Search the logs (regular expression)"},
+			{"n/N", "Next older/newer match"},
+			{"Esc", "Clear the search"},
+			{"F", "Pause or follow the logs"},
+			{"e", "Export the shown logs"},
+			{"E", "Export every buffered log"},
+			{"L", "Filter the logs"},
+			{"S", "Cycle unified, colored, column and row views"},
+		},
+	},
+	{
+		title: "Selection modes (delete, restart, decommission, gossip, pause, inspect, actions)",
+		bindings: []helpBinding{
+			{"↑/↓, K/J", "Move the selection"},
+			{"1-9", "Pick a node by number, multi-digit supported"},
+			{"Enter, Space", "Confirm"},
+			{"Esc", "Cancel"},
+		},
+	},
+	{
+		title: "Confirmation (delete, decommission)",
+		bindings: []helpBinding{
+			{"Y", "Go ahead"},
+			{"N, Esc", "Cancel"},
+		},
+	},
+	{
+		title: "Filter mode (L)",
+		bindings: []helpBinding{
+			{"1-9", "Show only a node's logs, again to show them no more"},
+			{"A", "Select every node"},
+			{"D/I/W/E", "Show only DEBUG/INFO/WARN/ERROR entries"},
+			{"G/T/N", "Show only gossip/transport/node logs"},
+			{"Enter", "Confirm"},
+			{"Esc", "Leave filter mode"},
+		},
+	},
+	{
+		title: "Split view (S, columns and rows)",
+		bindings: []helpBinding{
+			{"1-9", "Hide a node's panel, again to show it"},
+			{"[ ]", "Previous and next page of panels"},
+		},
+	},
+	{
+		title: "Create form (C)",
+		bindings: []helpBinding{
+			{"Tab, Shift+Tab", "Next and previous field"},
+			{"↑/↓", "Previous and next field"},
+			{"Space", "Toggle manual gossip"},
+			{"Enter", "Create the node"},
+			{"Esc", "Cancel"},
+		},
+	},
+	{
+		title: "Partition mode (p)",
+		bindings: []helpBinding{
+			{"↑/↓, K/J", "Move the selection"},
+			{"A/B", "Put the node in group A/B, again to take it out"},
+			{"Space", "Cycle the node through no group, A and B"},
+			{"Enter", "Partition group A from group B"},
+			{"Esc", "Cancel"},
+		},
+	},
+	{
+		title: "Action menu (A)",
+		bindings: []helpBinding{
+			{"↑/↓, K/J", "Move the selection"},
+			{"1-5", "Pick an action by number"},
+			{"Enter, Space", "Run the action"},
+			{"Esc", "Close the menu"},
+		},
+	},
+	{
+		title: "Mouse (unless --mouse=false)",
+		bindings: []helpBinding{
+			{"Click a node", "Select it, again to run the action; toggle its logs in filter mode and split view"},
+			{"Click a header", "Hide the split view panel"},
+			{"Wheel", "Scroll the logs, timeline or help; the selection over the node list; pages in split view"},
+		},
+	},
+	{
+		title: "Detail view (I)",
+		bindings: []helpBinding{
+			{"↑/↓, K/J", "Scroll the node's logs"},
+			{"Esc, I", "Back to the node list"},
+		},
+	},
+}
+
+// handleHelpKey handles ? key (show the help screen)
+func handleHelpKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.helpScroll = 0
+	return StateHelp, nil
+}
+
+// handleCloseHelp handles ?, Esc and Q keys on the help screen (back to normal mode)
+func handleCloseHelp(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	return StateNormal, nil
+}
+
+// handleHelpScroll handles ↑/↓ and K/J keys on the help screen
+func handleHelpScroll(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.helpScroll = max(0, m.helpScroll-1)
+	case "down", "j":
+		// Stop scrolling once the last line shows
+		m.helpScroll = min(m.helpScroll+1, max(0, strings.Count(renderHelpSections(), "\n")+1-m.helpLines()))
+	}
+	return m.state, nil
+}
+
+// helpLines is how many lines of help fit on the screen, below the title and above the hint
+func (m *model) helpLines() int {
+	if m.height <= 0 {
+		return strings.Count(renderHelpSections(), "\n") + 1
+	}
+	return max(1, m.height-3) // Title, blank line and hint
+}
+
+// renderHelpSections renders every help section, its key bindings aligned
+func renderHelpSections() string {
+	width := 0
+	for _, section := range helpSections {
+		for _, binding := range section.bindings {
+			width = max(width, lipgloss.Width(binding.keys))
+		}
+	}
+
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(activeTheme.title)
+	keyStyle := lipgloss.NewStyle().Bold(true).Width(width + 2)
+	var lines []string
+	for i, section := range helpSections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, sectionStyle.Render(section.title))
+		for _, binding := range section.bindings {
+			lines = append(lines, "  "+keyStyle.Render(binding.keys)+binding.description)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderHelp renders the help screen, which takes the whole terminal
+func (m *model) renderHelp() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(activeTheme.title)
+	hintStyle := lipgloss.NewStyle().
+		Foreground(activeTheme.muted).
+		Italic(true)
+
+	width := m.logBoxWidth()
+	helpView := viewport.New(width, m.helpLines())
+	helpView.SetContent(renderHelpSections())
+	helpView.SetYOffset(m.helpScroll)
+
+	hint := "? or Esc to close"
+	if helpView.TotalLineCount() > helpView.Height {
+		hint = fmt.Sprintf("↑/↓/j/k to scroll (%d%%) | %s", int(helpView.ScrollPercent()*100), hint)
+	}
+	return titleStyle.Render("Cassandra Node Manager - Help") + "\n\n" + helpView.View() + "\n" + hintStyle.Render(hint)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// defaultLogPanelLines is how many log entries the log panel shows before the terminal size is known
+	defaultLogPanelLines = 15
+	// minLogPanelLines is how many log entries the log panel shows at least, however small the terminal
+	minLogPanelLines = 3
+	// logBoxChrome is the lines of the log section that aren't entries: the blank lines around the
+	// box, its border and its title
+	logBoxChrome = 6
+	// minColumnWidth is the narrowest a log panel gets in the columns split view, border included
+	minColumnWidth = 32
+	// minRowHeight is the lowest a log panel gets in the rows split view: border, title and one entry
+	minRowHeight = 4
+)
+
+// logBoxWidth is the width of the log section, borders included
+func (m *model) logBoxWidth() int {
+	if m.width > 0 {
+		return m.width - 2 // Leave some margin
+	}
+	return 102
+}
+
+// logPanelLines is how many log entries fit in the log panel, the terminal's height less
+// everything shown above and below it
+func (m *model) logPanelLines() int {
+	if m.height <= 0 {
+		return defaultLogPanelLines
+	}
+	// The renderer cuts lines to the terminal's width, so every line takes one row
+	used := strings.Count(m.renderHeader(), "\n") + strings.Count(m.renderInstructions(), "\n") + logBoxChrome
+	return max(minLogPanelLines, m.height-used)
+}
+
+// logSectionHeight is the height of the log section, the log panel's entries, title and border
+func (m *model) logSectionHeight() int {
+	return m.logPanelLines() + 3
+}
+
+// splitPage returns the nodes on the shown page of the split view, the page and the number of pages,
+// and the height left for the panels out of height, the lines of the log section
+func (m *model) splitPage(height int) (nodeIndices []int, page, pages, panelsHeight int) {
+	nodeIndices = m.getNodesToDisplay()
+	perPage := func(height int) int {
+		if m.logSplitView == "columns" {
+			return max(1, m.logBoxWidth()/minColumnWidth)
+		}
+		return max(1, height/minRowHeight)
+	}
+
+	panelsHeight = height
+	n := perPage(panelsHeight)
+	if len(nodeIndices) > n {
+		panelsHeight-- // for the page line
+		n = perPage(panelsHeight)
+	}
+	pages = max(1, (len(nodeIndices)+n-1)/n)
+	page = min(m.splitPageIndex, pages-1)
+	start := page * n
+	return nodeIndices[start:min(start+n, len(nodeIndices))], page, pages, panelsHeight
+}
+
+// handleSplitPageKey handles [ and ] keys (show the previous or next page of panels in split view)
+func handleSplitPageKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.logSplitView != "columns" && m.logSplitView != "rows" {
+		return m.state, nil
+	}
+	_, page, pages, _ := m.splitPage(m.logSectionHeight())
+	if msg.String() == "[" {
+		m.splitPageIndex = (page - 1 + pages) % pages
+	} else {
+		m.splitPageIndex = (page + 1) % pages
+	}
+	return m.state, nil
+}
+
+// splitPageLine describes the shown page of the split view, e.g. "Page 2/3 ([/] for more nodes)"
+func splitPageLine(page, pages int) string {
+	return fmt.Sprintf("Page %d/%d ([/] for more nodes)", page+1, pages)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+// matrixLagWarn is how many versions a belief can lag behind the newest before it is shown
+// as stale rather than catching up
+const matrixLagWarn = 2
+
+// handleMatrixKey handles M key (toggle the convergence matrix)
+func handleMatrixKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.showMatrix = !m.showMatrix
+	return m.state, nil
+}
+
+// renderMatrix renders what every node believes about every node, see Manager.ClusterView.
+// A cell is green when it has the newest state of the node, yellow when it lags at most
+// matrixLagWarn versions behind, and red when it lags further or has an older generation.
+func (m *model) renderMatrix() string {
+	view := m.manager.ClusterView()
+	if len(view.Observers) == 0 {
+		return "Convergence matrix: (no running nodes)\n\n"
+	}
+
+	cells := make([][]string, len(view.Observers)+1)
+	cells[0] = []string{"OBSERVER"}
+	for _, nodeID := range view.Nodes {
+		cells[0] = append(cells[0], string(nodeID))
+	}
+	latest := make([]node.Belief, len(view.Nodes))
+	for j := range view.Nodes {
+		latest[j] = view.Latest(j)
+	}
+	for i, observer := range view.Observers {
+		cells[i+1] = []string{string(observer)}
+		for j := range view.Nodes {
+			cells[i+1] = append(cells[i+1], formatBelief(view.Beliefs[i][j], latest[j]))
+		}
+	}
+
+	// Pad before coloring, escape codes would throw off the widths
+	widths := make([]int, len(cells[0]))
+	for _, row := range cells {
+		for j, cell := range row {
+			widths[j] = max(widths[j], len(cell))
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString("Convergence matrix (row believes column):\n\n")
+	for i, row := range cells {
+		s.WriteString("  ")
+		for j, cell := range row {
+			padded := fmt.Sprintf("%-*s  ", widths[j], cell)
+			switch {
+			case i == 0 || j == 0:
+				padded = lipgloss.NewStyle().Bold(true).Render(padded)
+			default:
+				padded = lipgloss.NewStyle().Foreground(beliefColor(view.Beliefs[i-1][j-1], latest[j-1])).Render(padded)
+			}
+			s.WriteString(padded)
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	if view.Converged() {
+		s.WriteString(lipgloss.NewStyle().Foreground(activeTheme.ok).Render("Converged: every node has the same state of every node"))
+	} else {
+		s.WriteString(lipgloss.NewStyle().Foreground(activeTheme.warn).Render("Not converged"))
+	}
+	s.WriteString("\n\n")
+	return s.String()
+}
+
+// beliefColor colors a cell of the matrix by how far belief lags behind latest
+func beliefColor(belief, latest node.Belief) lipgloss.Color {
+	switch {
+	case !belief.Known:
+		return activeTheme.muted
+	case belief.Generation < latest.Generation || latest.Version-belief.Version > matrixLagWarn:
+		return activeTheme.err
+	case belief.Version < latest.Version:
+		return activeTheme.warn
+	default:
+		return activeTheme.ok
+	}
+}
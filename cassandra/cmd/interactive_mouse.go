@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleMouse handles mouse events, unless --mouse=false: a click on a node picks it like its
+// number would, a click on a split view panel's header hides the panel, and the wheel scrolls
+func handleMouse(m *model, msg tea.MouseMsg) (State, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m.state, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return handleWheel(m, msg, "up")
+	case tea.MouseButtonWheelDown:
+		return handleWheel(m, msg, "down")
+	case tea.MouseButtonLeft:
+		if index, ok := m.nodeAt(msg.Y); ok {
+			return handleNodeClick(m, index)
+		}
+		if index, ok := m.panelHeaderAt(msg.X, msg.Y); ok {
+			m.hiddenNodes[index] = true
+		}
+	}
+	return m.state, nil
+}
+
+// handleWheel scrolls in direction ("up" or "down") what is under the pointer: the selection over
+// the node list of a selection mode, the pages of the split view, or else the help or the logs
+func handleWheel(m *model, msg tea.MouseMsg, direction string) (State, tea.Cmd) {
+	key := tea.KeyMsg{Type: tea.KeyUp}
+	if direction == "down" {
+		key = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	if m.state == StateHelp {
+		return handleHelpScroll(m, key)
+	}
+	if _, ok := m.nodeAt(msg.Y); ok && (m.selecting() || m.state == StatePartition) {
+		if direction == "up" {
+			return handleUp(m, key)
+		}
+		return handleDown(m, key)
+	}
+	if m.splitViewShown() && msg.Y >= m.logSectionTop() {
+		// Panels don't scroll, the wheel pages through them instead
+		page := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")}
+		if direction == "up" {
+			page.Runes = []rune("[")
+		}
+		return handleSplitPageKey(m, page)
+	}
+	scrollLogSection(m, direction)
+	return m.state, nil
+}
+
+// handleNodeClick picks the node at index, clicked in the node list. Selection modes move the
+// selection to it, and run their action if it was selected already; filter mode and the split
+// view toggle its logs.
+func handleNodeClick(m *model, index int) (State, tea.Cmd) {
+	switch {
+	case m.selecting():
+		m.numericInput = ""
+		if index == m.selected {
+			return handleEnter(m, tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		m.selected = index
+	case m.state == StatePartition:
+		m.selected = index
+	case m.state == StateLogFilter:
+		m.logFilter[index] = !m.logFilter[index]
+		m.logFilterMode = true
+	case m.state == StateNormal && m.splitViewShown():
+		m.hiddenNodes[index] = !m.hiddenNodes[index]
+	}
+	return m.state, nil
+}
+
+// splitViewShown reports whether the log section shows a panel per node
+func (m *model) splitViewShown() bool {
+	return (m.logSplitView == "columns" || m.logSplitView == "rows") && len(m.nodes) > 0 && !m.showTimeline
+}
+
+// nodeAt returns the index of the node listed on line y of the screen
+func (m *model) nodeAt(y int) (int, bool) {
+	if m.state == StateHelp || m.state == StateNodeDetail || len(m.nodes) == 0 {
+		return 0, false
+	}
+	// The list starts below "Nodes:" and a blank line, one node per line
+	index := y - strings.Count(m.renderStatus(), "\n") - 2
+	return index, index >= 0 && index < len(m.nodes)
+}
+
+// logSectionTop is the line of the screen the log section starts on
+func (m *model) logSectionTop() int {
+	return strings.Count(m.renderHeader(), "\n") + 1
+}
+
+// panelHeaderAt returns the index of the node whose split view panel has its top border or
+// title at column x, line y of the screen
+func (m *model) panelHeaderAt(x, y int) (int, bool) {
+	if m.state == StateHelp || !m.splitViewShown() {
+		return 0, false
+	}
+	nodeIndices, _, pages, height := m.splitPage(m.logSectionHeight())
+	if len(nodeIndices) == 0 {
+		return 0, false
+	}
+	top := m.logSectionTop()
+	if pages > 1 {
+		top++ // the page line
+	}
+
+	// Same sizes as renderSplitView
+	if m.logSplitView == "columns" {
+		panelWidth := m.logBoxWidth() / len(nodeIndices)
+		i := x / panelWidth
+		if y < top || y > top+1 || i >= len(nodeIndices) {
+			return 0, false
+		}
+		return nodeIndices[i], true
+	}
+	for i, nodeIndex := range nodeIndices {
+		panelHeight := height / len(nodeIndices)
+		if i < height%len(nodeIndices) {
+			panelHeight++
+		}
+		if y == top || y == top+1 {
+			return nodeIndex, true
+		}
+		top += panelHeight
+	}
+	return 0, false
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// partitionGroup is the side of the partition a node is put on in partition mode
+type partitionGroup int
+
+const (
+	groupNone partitionGroup = iota
+	groupA
+	groupB
+)
+
+func (g partitionGroup) String() string {
+	switch g {
+	case groupA:
+		return "A"
+	case groupB:
+		return "B"
+	default:
+		return ""
+	}
+}
+
+// groupColor is the color of a group's nodes in partition mode
+func groupColor(g partitionGroup) lipgloss.Color {
+	if g == groupA {
+		return activeTheme.groupA
+	}
+	return activeTheme.groupB
+}
+
+// handlePartitionKey handles p key press (enters partition mode)
+func handlePartitionKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if len(m.nodes) < 2 {
+		m.err = fmt.Errorf("a partition needs at least two nodes")
+		return m.state, nil
+	}
+	m.selected = 0
+	m.partitionGroups = make(map[gossip.NodeID]partitionGroup)
+	return StatePartition, nil
+}
+
+// handlePartitionGroupKey handles A and B keys in partition mode, putting the selected node in
+// that group, or taking it out if it already is
+func handlePartitionGroupKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	group := groupA
+	if strings.EqualFold(msg.String(), "b") {
+		group = groupB
+	}
+	if m.selected >= len(m.nodes) {
+		return m.state, nil
+	}
+	nodeID := m.nodes[m.selected].GetConfig().NodeID
+	if m.partitionGroups[nodeID] == group {
+		delete(m.partitionGroups, nodeID)
+	} else {
+		m.partitionGroups[nodeID] = group
+	}
+	return m.state, nil
+}
+
+// handlePartitionCycleKey handles Space in partition mode, moving the selected node from no
+// group to A, to B and back
+func handlePartitionCycleKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.selected >= len(m.nodes) {
+		return m.state, nil
+	}
+	nodeID := m.nodes[m.selected].GetConfig().NodeID
+	switch m.partitionGroups[nodeID] {
+	case groupNone:
+		m.partitionGroups[nodeID] = groupA
+	case groupA:
+		m.partitionGroups[nodeID] = groupB
+	default:
+		delete(m.partitionGroups, nodeID)
+	}
+	return m.state, nil
+}
+
+// applyPartition partitions the nodes of group A from those of group B
+func applyPartition(m *model) State {
+	var a, b []gossip.NodeID
+	for _, n := range m.nodes {
+		nodeID := n.GetConfig().NodeID
+		switch m.partitionGroups[nodeID] {
+		case groupA:
+			a = append(a, nodeID)
+		case groupB:
+			b = append(b, nodeID)
+		}
+	}
+	if len(a) == 0 || len(b) == 0 {
+		m.err = fmt.Errorf("put at least one node in each group (A and B)")
+		return m.state
+	}
+	m.partitionGroups = nil
+	m.err = partitionNodes(m, a, b)
+	return StateNormal
+}
+
+// partitionNodes partitions the nodes a from the nodes b, for the banner until healed
+func partitionNodes(m *model, a, b []gossip.NodeID) error {
+	if err := m.manager.Partition(a, b); err != nil {
+		return err
+	}
+	m.partitions = append(m.partitions, fmt.Sprintf("%s | %s", joinNodeIDs(a), joinNodeIDs(b)))
+	m.record(scenarioStep{Action: "partition", Groups: [][]gossip.NodeID{a, b}})
+	return nil
+}
+
+// handleHealKey handles U key press (heals every partition)
+func handleHealKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if err := healPartitions(m); err != nil {
+		m.err = err
+		return m.state, nil
+	}
+	m.err = nil
+	m.showToast("Partition healed")
+	return m.state, nil
+}
+
+// healPartitions heals every partition
+func healPartitions(m *model) error {
+	if !m.manager.Partitioned() {
+		return fmt.Errorf("the cluster is not partitioned")
+	}
+	m.manager.Heal()
+	m.partitions = nil
+	m.record(scenarioStep{Action: "heal"})
+	return nil
+}
+
+// renderPartitionBanner renders the banner shown while the cluster is partitioned
+func (m *model) renderPartitionBanner() string {
+	if !m.manager.Partitioned() {
+		return ""
+	}
+	text := "PARTITIONED"
+	if len(m.partitions) > 0 {
+		text += ": " + strings.Join(m.partitions, "; ")
+	}
+	text += " (U to heal)"
+	bannerStyle := activeTheme.banner.Padding(0, 1)
+	return bannerStyle.Render(text) + "\n\n"
+}
+
+func joinNodeIDs(nodeIDs []gossip.NodeID) string {
+	ids := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		ids[i] = string(nodeID)
+	}
+	return strings.Join(ids, ", ")
+}
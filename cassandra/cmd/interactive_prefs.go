@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// preferences are the layout of the TUI, saved when it quits and restored when it starts so a
+// session resumes where the last one left off. --reset-prefs starts from the defaults.
+type preferences struct {
+	SplitView      string   `yaml:"split_view"`             // none, colored, columns or rows
+	FilterNodes    []int    `yaml:"filter_nodes,omitempty"` // numbers of the nodes shown by the log filter
+	HiddenNodes    []int    `yaml:"hidden_nodes,omitempty"` // numbers of the nodes without a split view panel
+	Levels         []string `yaml:"levels,omitempty"`       // levels shown, every level if none
+	Components     []string `yaml:"components,omitempty"`   // components shown, every component if none
+	PausedLogs     bool     `yaml:"paused_logs"`            // whether the log panel doesn't follow new logs
+	ShowMatrix     bool     `yaml:"show_matrix"`
+	ShowSparklines bool     `yaml:"show_sparklines"`
+	ShowDetector   bool     `yaml:"show_detector"`
+	ShowTimeline   bool     `yaml:"show_timeline"`
+	Theme          string   `yaml:"theme,omitempty"` // theme of the last --theme, default if none
+}
+
+// preferencesPath is where the preferences are kept, under the user's config directory
+// (e.g. ~/.config/cassandra/interactive.yaml on Linux)
+func preferencesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cassandra", "interactive.yaml"), nil
+}
+
+// loadPreferences reads the saved preferences, the defaults if none were saved yet
+func loadPreferences() (preferences, error) {
+	prefs := preferences{SplitView: "none"}
+	path, err := preferencesPath()
+	if err != nil {
+		return prefs, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, fmt.Errorf("failed to read preferences: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&prefs); err != nil {
+		return preferences{SplitView: "none"}, fmt.Errorf("failed to parse preferences %s: %w", path, err)
+	}
+	if !slices.Contains([]string{"none", "colored", "columns", "rows"}, prefs.SplitView) {
+		return preferences{SplitView: "none"}, fmt.Errorf("invalid preferences %s: unknown split view %q", path, prefs.SplitView)
+	}
+	if prefs.Theme != "" {
+		if err := validateTheme(prefs.Theme); err != nil {
+			return preferences{SplitView: "none"}, fmt.Errorf("invalid preferences %s: %w", path, err)
+		}
+	}
+	return prefs, nil
+}
+
+// savePreferences writes prefs, creating the config directory if needed
+func savePreferences(prefs preferences) error {
+	path, err := preferencesPath()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(prefs); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
+
+// resetPreferences deletes the saved preferences, for --reset-prefs
+func resetPreferences() error {
+	path, err := preferencesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to reset preferences: %w", err)
+	}
+	return nil
+}
+
+// preferences returns the layout of the session, to save
+func (m *model) preferences() preferences {
+	prefs := preferences{
+		SplitView:      m.logSplitView,
+		PausedLogs:     m.logPaused,
+		ShowMatrix:     m.showMatrix,
+		ShowSparklines: m.showSparklines,
+		ShowDetector:   m.showDetector,
+		ShowTimeline:   m.showTimeline,
+		Theme:          m.theme,
+	}
+	if m.logFilterMode {
+		prefs.FilterNodes = nodeNumbers(m.logFilter)
+	}
+	prefs.HiddenNodes = nodeNumbers(m.hiddenNodes)
+	for level, shown := range m.logLevels {
+		if shown {
+			prefs.Levels = append(prefs.Levels, level.String())
+		}
+	}
+	slices.Sort(prefs.Levels)
+	for component, shown := range m.logComponents {
+		if shown {
+			prefs.Components = append(prefs.Components, component)
+		}
+	}
+	slices.Sort(prefs.Components)
+	return prefs
+}
+
+// nodeNumbers returns the numbers, 1-based, of the node indices set in nodes
+func nodeNumbers(nodes map[int]bool) []int {
+	var numbers []int
+	for index, set := range nodes {
+		if set {
+			numbers = append(numbers, index+1)
+		}
+	}
+	slices.Sort(numbers)
+	return numbers
+}
+
+// applyPreferences restores the layout of a previous session. Node numbers apply to the
+// nodes in the order they are created, as --restore does.
+func (m *model) applyPreferences(prefs preferences) error {
+	// Checked first, so invalid preferences change nothing
+	levels := make([]logger.Level, 0, len(prefs.Levels))
+	for _, name := range prefs.Levels {
+		level, err := logger.ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("invalid preferences: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	m.logSplitView = prefs.SplitView
+	for _, number := range prefs.FilterNodes {
+		m.logFilter[number-1] = true
+		m.logFilterMode = true
+	}
+	for _, number := range prefs.HiddenNodes {
+		m.hiddenNodes[number-1] = true
+	}
+	for _, level := range levels {
+		m.logLevels[level] = true
+	}
+	for _, component := range prefs.Components {
+		m.logComponents[component] = true
+	}
+	if prefs.PausedLogs {
+		pauseLogs(m)
+	}
+	m.showMatrix = prefs.ShowMatrix
+	m.showSparklines = prefs.ShowSparklines
+	m.showDetector = prefs.ShowDetector
+	m.showTimeline = prefs.ShowTimeline
+	m.theme = prefs.Theme
+	return nil
+}
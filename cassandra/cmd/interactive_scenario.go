@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+// recordResolution is what the waits of a recorded scenario are rounded to
+const recordResolution = 100 * time.Millisecond
+
+// scenario is the YAML form of a scenario, the steps run by --scenario and recorded by --record
+type scenario struct {
+	Steps []scenarioStep `yaml:"steps"`
+}
+
+// scenarioStep is an action of a scenario. Fields that don't apply to the Action are empty.
+// Nodes are named by ID, so a step acts on the same node whatever nodes were deleted before.
+type scenarioStep struct {
+	// create, delete, restart, decommission, gossip, pause, resume, partition, heal, set-state or wait
+	Action string `yaml:"action"`
+
+	Node              gossip.NodeID `yaml:"node,omitempty"`               // node acted on, or the ID of the node created
+	Count             int           `yaml:"count,omitempty"`              // nodes created, 1 if not set
+	Port              int           `yaml:"port,omitempty"`               // port of the node created
+	Seeds             *[]string     `yaml:"seeds,omitempty"`              // seeds of the node created, [] for none
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval,omitempty"` // heartbeat interval of the node created
+	ManualGossip      bool          `yaml:"manual_gossip,omitempty"`      // whether the node created gossips manually
+
+	Groups [][]gossip.NodeID `yaml:"groups,omitempty"` // the two groups of a partition
+
+	Key   gossip.AppStateKey `yaml:"key,omitempty"` // application state set
+	Value string             `yaml:"value,omitempty"`
+
+	Duration time.Duration `yaml:"duration,omitempty"` // how long to wait
+}
+
+// String describes the step for the status line, e.g. "restart node-2"
+func (s scenarioStep) String() string {
+	switch s.Action {
+	case "create":
+		if s.Count > 1 {
+			return fmt.Sprintf("create %d nodes", s.Count)
+		}
+		if s.Node != "" {
+			return "create " + string(s.Node)
+		}
+		return "create"
+	case "partition":
+		return fmt.Sprintf("partition %s from %s", joinNodeIDs(s.Groups[0]), joinNodeIDs(s.Groups[1]))
+	case "heal":
+		return "heal"
+	case "set-state":
+		return fmt.Sprintf("set %s=%s on %s", s.Key, s.Value, s.Node)
+	case "wait":
+		return "wait " + s.Duration.String()
+	default:
+		return s.Action + " " + string(s.Node)
+	}
+}
+
+// validate checks that the step has what its action needs
+func (s scenarioStep) validate() error {
+	switch s.Action {
+	case "create":
+		if s.Count < 0 {
+			return fmt.Errorf("invalid count %d", s.Count)
+		}
+		if s.Count > 1 && (s.Node != "" || s.Port != 0) {
+			return fmt.Errorf("node and port can't be set when creating %d nodes", s.Count)
+		}
+		if s.Port < 0 || s.Port > 65535 {
+			return fmt.Errorf("invalid port %d, expected 1-65535", s.Port)
+		}
+	case "delete", "restart", "decommission", "gossip", "pause", "resume":
+		if s.Node == "" {
+			return fmt.Errorf("%s needs a node", s.Action)
+		}
+	case "partition":
+		if len(s.Groups) != 2 || len(s.Groups[0]) == 0 || len(s.Groups[1]) == 0 {
+			return fmt.Errorf("partition needs two groups of nodes")
+		}
+	case "heal":
+	case "set-state":
+		if s.Node == "" || s.Key == "" {
+			return fmt.Errorf("set-state needs a node and a key")
+		}
+	case "wait":
+		if s.Duration <= 0 {
+			return fmt.Errorf("wait needs a duration, e.g. 5s")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
+
+// loadScenario reads the steps of a scenario file. Unknown keys are rejected, like in config files.
+func loadScenario(path string) ([]scenarioStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	var file scenario
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	for i, step := range file.Steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("invalid scenario %s: step %d: %w", path, i+1, err)
+		}
+	}
+	return file.Steps, nil
+}
+
+// saveScenario writes steps as a scenario file that loadScenario reads back
+func saveScenario(path string, steps []scenarioStep) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Recorded by cassandra interactive on %s, replay with --scenario\n", time.Now().Format(time.DateTime))
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(scenario{Steps: steps}); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to save scenario: %w", err)
+	}
+	return nil
+}
+
+// record adds step to the recorded scenario with --record, after a wait as long as the time since
+// the previous step
+func (m *model) record(step scenarioStep) {
+	if m.recordPath == "" {
+		return
+	}
+	now := time.Now()
+	if !m.lastRecorded.IsZero() {
+		if wait := now.Sub(m.lastRecorded).Round(recordResolution); wait > 0 {
+			m.recorded = append(m.recorded, scenarioStep{Action: "wait", Duration: wait})
+		}
+	}
+	m.lastRecorded = now
+	m.recorded = append(m.recorded, step)
+}
+
+// createStep is the step creating a node with overrides, only with the settings that were set
+func createStep(overrides node.NodeOverrides) scenarioStep {
+	step := scenarioStep{
+		Action:            "create",
+		Node:              overrides.NodeID,
+		Port:              overrides.Port,
+		HeartbeatInterval: overrides.HeartbeatInterval,
+		ManualGossip:      overrides.ManualHeartbeat,
+	}
+	if overrides.Seeds != nil {
+		seeds := overrides.Seeds
+		step.Seeds = &seeds
+	}
+	return step
+}
+
+// scenarioStepMsg runs the next step of the scenario
+type scenarioStepMsg struct{}
+
+// nextScenarioStep returns the command running the next step of the scenario, after delay
+func nextScenarioStep(delay time.Duration) tea.Cmd {
+	if delay <= 0 {
+		return func() tea.Msg { return scenarioStepMsg{} }
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return scenarioStepMsg{} })
+}
+
+// runScenarioStep runs the next step of the scenario and returns the command running the one
+// after it. A failing step stops the scenario, with the error shown.
+func (m *model) runScenarioStep() tea.Cmd {
+	if m.scenarioNext >= len(m.scenario) {
+		if len(m.scenario) > 0 {
+			m.showToast(fmt.Sprintf("Scenario finished, %d steps", len(m.scenario)))
+			m.scenario = nil
+			m.scenarioNext = 0
+		}
+		return nil
+	}
+	step := m.scenario[m.scenarioNext]
+	m.scenarioNext++
+	if step.Action == "wait" {
+		return nextScenarioStep(step.Duration)
+	}
+
+	if err := m.applyStep(step); err != nil {
+		m.err = fmt.Errorf("scenario stopped at step %d (%s): %w", m.scenarioNext, step, err)
+		m.scenario = nil
+		m.scenarioNext = 0
+		return nil
+	}
+	return nextScenarioStep(0)
+}
+
+// applyStep runs step, like the keys doing the same do, so it is recorded too with --record
+func (m *model) applyStep(step scenarioStep) error {
+	switch step.Action {
+	case "create":
+		overrides := m.newNode
+		overrides.NodeID = step.Node
+		if step.Port != 0 {
+			overrides.Port = step.Port
+		}
+		if step.Seeds != nil {
+			overrides.Seeds = *step.Seeds
+		}
+		if step.HeartbeatInterval > 0 {
+			overrides.HeartbeatInterval = step.HeartbeatInterval
+		}
+		overrides.ManualHeartbeat = overrides.ManualHeartbeat || step.ManualGossip
+		for range max(1, step.Count) {
+			if _, err := createNode(m, overrides); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "partition":
+		return partitionNodes(m, step.Groups[0], step.Groups[1])
+	case "heal":
+		return healPartitions(m)
+	}
+
+	index := m.getNodeIndexByID(string(step.Node))
+	if index < 0 {
+		return fmt.Errorf("%w: %s", node.ErrNodeNotFound, step.Node)
+	}
+	switch step.Action {
+	case "delete":
+		return deleteNode(m, index).err
+	case "restart":
+		return handleRestartNode(m, index).err
+	case "decommission":
+		return decommissionNode(m, index).err
+	case "gossip":
+		return handleGossipNode(m, index).err
+	case "pause", "resume":
+		if m.nodes[index].Paused() == (step.Action == "pause") {
+			return fmt.Errorf("%s is already %sd", step.Node, step.Action)
+		}
+		return handlePauseNode(m, index).err
+	case "set-state":
+		_, err := setNodeAppState(m, index, step.Key, step.Value)
+		return err
+	}
+	return fmt.Errorf("unknown action %q", step.Action)
+}
+
+// scenarioStatus describes the scenario running and the recording for the status line,
+// e.g. " | Scenario: step 3/7 (wait 5s) | Recording 4 steps to demo.yaml"
+func (m *model) scenarioStatus() string {
+	var status string
+	if m.scenarioNext > 0 {
+		status += fmt.Sprintf(" | Scenario: step %d/%d (%s)", m.scenarioNext, len(m.scenario), m.scenario[m.scenarioNext-1])
+	}
+	if m.recordPath != "" {
+		status += fmt.Sprintf(" | Recording %d steps to %s", len(m.recorded), m.recordPath)
+	}
+	return status
+}
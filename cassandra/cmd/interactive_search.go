@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// handleSearchKey handles / key (start typing a search pattern)
+func handleSearchKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.searchInput = ""
+	return StateLogSearch, nil
+}
+
+// handleSearchBackspace deletes the last character of the search pattern being typed
+func handleSearchBackspace(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	if m.searchInput == "" {
+		return StateNormal, nil
+	}
+	m.searchInput = trimLastRune(m.searchInput)
+	return m.state, nil
+}
+
+// typedText returns the text typed with msg, "" for keys that don't type any
+func typedText(msg tea.KeyMsg) string {
+	switch msg.Type {
+	case tea.KeyRunes:
+		return string(msg.Runes)
+	case tea.KeySpace:
+		return " "
+	default:
+		return ""
+	}
+}
+
+// trimLastRune removes the last character of s, for Backspace
+func trimLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// startSearch searches the logs for the typed pattern, jumping to the newest match. An empty
+// pattern clears the search.
+func startSearch(m *model) State {
+	input := m.searchInput
+	m.searchInput = ""
+	if input == "" {
+		clearSearch(m)
+		return StateNormal
+	}
+
+	// Smart case, like vim's: case-insensitive unless the pattern has an upper-case letter
+	expr := input
+	if strings.IndexFunc(input, unicode.IsUpper) < 0 {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		m.err = fmt.Errorf("invalid search pattern %q: %v", input, err)
+		return StateNormal
+	}
+	m.search = pattern
+	m.searchText = input
+	m.hasCurrentMatch = false
+
+	entries := m.logEntries
+	matches := m.searchMatches(entries)
+	if len(matches) == 0 {
+		m.err = fmt.Errorf("pattern not found: %s", input)
+		return StateNormal
+	}
+	m.err = nil
+	m.jumpToMatch(entries, matches[len(matches)-1])
+	return StateNormal
+}
+
+// clearSearch removes the search and its highlighting
+func clearSearch(m *model) {
+	m.search = nil
+	m.searchText = ""
+	m.hasCurrentMatch = false
+}
+
+// handleNextMatch handles n key (jump to the next older match, further down the log panel)
+func handleNextMatch(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	moveMatch(m, -1)
+	return m.state, nil
+}
+
+// handlePrevMatch handles N key (jump to the next newer match, further up the log panel)
+func handlePrevMatch(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	moveMatch(m, 1)
+	return m.state, nil
+}
+
+// moveMatch moves the current match by step positions in the buffer, wrapping around
+func moveMatch(m *model, step int) {
+	if m.search == nil {
+		m.err = fmt.Errorf("no search, press / to search the logs")
+		return
+	}
+	entries := m.logEntries
+	matches := m.searchMatches(entries)
+	if len(matches) == 0 {
+		m.err = fmt.Errorf("pattern not found: %s", m.searchText)
+		return
+	}
+	m.err = nil
+
+	// Start from the newest match if the current one left the buffer
+	current := len(matches) - 1
+	if index := m.currentMatchIndex(entries, matches); index >= 0 {
+		current = (index + step + len(matches)) % len(matches)
+	}
+	m.jumpToMatch(entries, matches[current])
+}
+
+// searchMatches returns the positions in entries of the shown entries matching the search
+func (m *model) searchMatches(entries []logger.LogEntry) []int {
+	if m.search == nil {
+		return nil
+	}
+	var matches []int
+	for i, entry := range entries {
+		if m.shouldShowLogEntry(entry) && m.search.MatchString(logger.FormatLogEntry(entry)) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// currentMatchIndex returns the index in matches of the current match, or -1. The match is
+// remembered by entry rather than position, positions shift when the buffer is full.
+func (m *model) currentMatchIndex(entries []logger.LogEntry, matches []int) int {
+	if !m.hasCurrentMatch {
+		return -1
+	}
+	for i, position := range matches {
+		if entries[position].Equal(m.currentMatch) {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpToMatch makes the entry at position the current match and scrolls it into the middle
+// of the log panel, which stops following new entries so the match stays in view
+func (m *model) jumpToMatch(entries []logger.LogEntry, position int) {
+	m.currentMatch = entries[position]
+	m.hasCurrentMatch = true
+	pauseLogs(m)
+	lines := m.logPanelLines()
+	scroll := m.countShownLogs(entries[position+1:]) - lines/2
+	m.logScroll = max(0, min(scroll, m.countShownLogs(entries)-lines))
+}
+
+// searchStatus describes the search for the status line, e.g. "/heartbeat (3/42)"
+func (m *model) searchStatus() string {
+	entries := m.logEntries
+	matches := m.searchMatches(entries)
+	if index := m.currentMatchIndex(entries, matches); index >= 0 {
+		// Number matches from the newest, like the log panel's line numbers
+		return fmt.Sprintf("/%s (%d/%d)", m.searchText, len(matches)-index, len(matches))
+	}
+	return fmt.Sprintf("/%s (%d matches)", m.searchText, len(matches))
+}
+
+// highlightSearch renders text, the formatted entry, with the search's matches highlighted
+// and the rest in color. It returns false if the search doesn't match it.
+func (m *model) highlightSearch(entry logger.LogEntry, text string, color lipgloss.Color) (string, bool) {
+	if m.search == nil {
+		return "", false
+	}
+	locations := m.search.FindAllStringIndex(text, -1)
+	if len(locations) == 0 {
+		return "", false
+	}
+
+	base := lipgloss.NewStyle()
+	if color != "" {
+		base = base.Foreground(color)
+	}
+	match := activeTheme.searchMatch
+	if m.hasCurrentMatch && entry.Equal(m.currentMatch) {
+		match = activeTheme.searchCurrent
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, location := range locations {
+		if location[0] == location[1] {
+			continue // an empty match has nothing to highlight
+		}
+		if location[0] > last {
+			b.WriteString(base.Render(text[last:location[0]]))
+		}
+		b.WriteString(match.Render(text[location[0]:location[1]]))
+		last = location[1]
+	}
+	if last < len(text) {
+		b.WriteString(base.Render(text[last:]))
+	}
+	return b.String(), true
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+// sparklineSamples is how many ticks, seconds, the sparklines span
+const sparklineSamples = 30
+
+// sparkBlocks are the bars of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// nodeSamples is the recent history of a node's metrics, one sample per tick, oldest first
+type nodeSamples struct {
+	last        node.Stats // counters at the previous tick, rates are the differences
+	rounds      []float64  // gossip rounds per second
+	synFailures []float64  // SYNs without ACK per second
+	merges      []float64  // states merged per second
+	phi         []float64  // highest phi among the node's peers
+}
+
+// handleSparklinesKey handles H key (toggle the metrics sparklines)
+func handleSparklinesKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.showSparklines = !m.showSparklines
+	return m.state, nil
+}
+
+// sampleMetrics records a sample of every node's metrics, on each tick. Samples are taken
+// while the sparklines are hidden too, so they show a history right away.
+func (m *model) sampleMetrics(now time.Time) {
+	current := make(map[gossip.NodeID]*nodeSamples, len(m.nodes))
+	for _, n := range m.nodes {
+		nodeID := n.GetConfig().NodeID
+		stats := n.Stats()
+		samples, ok := m.samples[nodeID]
+		if !ok {
+			// The first sample only sets the baseline of the rates
+			current[nodeID] = &nodeSamples{last: stats}
+			continue
+		}
+		current[nodeID] = samples
+
+		maxPhi := 0.0
+		for _, endpoint := range n.GetGossipState().Endpoints(now) {
+			if !endpoint.Local {
+				maxPhi = max(maxPhi, endpoint.Phi)
+			}
+		}
+		samples.rounds = appendSample(samples.rounds, float64(stats.GossipRounds-samples.last.GossipRounds))
+		samples.synFailures = appendSample(samples.synFailures, float64(stats.SynFailures-samples.last.SynFailures))
+		samples.merges = appendSample(samples.merges, float64(stats.StatesMerged-samples.last.StatesMerged))
+		samples.phi = appendSample(samples.phi, maxPhi)
+		samples.last = stats
+	}
+	// Deleted nodes are forgotten
+	m.samples = current
+}
+
+// appendSample appends value to samples, dropping the oldest beyond sparklineSamples
+func appendSample(samples []float64, value float64) []float64 {
+	samples = append(samples, value)
+	if len(samples) > sparklineSamples {
+		samples = samples[len(samples)-sparklineSamples:]
+	}
+	return samples
+}
+
+// sparkline draws the last width values as bars scaled to the highest of them, padded on
+// the left so the newest samples of every line align
+func sparkline(values []float64, width int) string {
+	values = values[max(0, len(values)-width):]
+	highest := 0.0
+	for _, v := range values {
+		highest = max(highest, v)
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if highest > 0 {
+			level = int(v / highest * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// lastSample returns the newest of samples, 0 if there are none
+func lastSample(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	return samples[len(samples)-1]
+}
+
+// renderSparklines renders a line of sparklines per node: gossip rounds, SYN failures and
+// merged states per second, and the highest phi of its peers, red once a peer would be
+// convicted. The sparklines are as long as the terminal's width allows. Below them are
+// the totals of every node's gossip, see renderGossipStats.
+func (m *model) renderSparklines() string {
+	if len(m.nodes) == 0 {
+		return ""
+	}
+	idWidth := len("NODE")
+	for _, n := range m.nodes {
+		idWidth = max(idWidth, len(n.GetConfig().NodeID))
+	}
+	// Four columns of a sparkline, a space and a 4 wide value, 2 spaces apart
+	sparkWidth := min(sparklineSamples, max(8, (m.logBoxWidth()-2-idWidth-4*7)/4))
+	columnWidth := sparkWidth + 5
+
+	warnStyle := lipgloss.NewStyle().Foreground(activeTheme.err)
+	column := func(samples []float64, format string, warn bool) string {
+		text := fmt.Sprintf("%s "+format, sparkline(samples, sparkWidth), lastSample(samples))
+		if warn {
+			return warnStyle.Render(text)
+		}
+		return text
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Metrics (last %ds):\n\n", sparkWidth))
+	s.WriteString(fmt.Sprintf("  %-*s  %-*s  %-*s  %-*s  %s\n", idWidth, "NODE", columnWidth, "ROUNDS/S",
+		columnWidth, "SYN FAILURES/S", columnWidth, "MERGES/S", "MAX PHI OF PEERS"))
+	for _, n := range m.nodes {
+		nodeID := n.GetConfig().NodeID
+		samples, ok := m.samples[nodeID]
+		if !ok {
+			samples = &nodeSamples{}
+		}
+		s.WriteString(fmt.Sprintf("  %-*s  %s  %s  %s  %s\n", idWidth, nodeID,
+			column(samples.rounds, "%4.0f", false),
+			column(samples.synFailures, "%4.0f", lastSample(samples.synFailures) > 0),
+			column(samples.merges, "%4.0f", false),
+			column(samples.phi, "%4.1f", lastSample(samples.phi) > gossip.DefaultPhiConvictThreshold)))
+	}
+	s.WriteString("\n")
+	s.WriteString(m.renderGossipStats(idWidth))
+	return s.String()
+}
+
+// renderGossipStats renders a line per node with its gossip.Stats: rounds, digests sent and
+// received, states pushed and pulled, how long the last round took and the convergence
+// estimate, yellow below 100%
+func (m *model) renderGossipStats(idWidth int) string {
+	warnStyle := lipgloss.NewStyle().Foreground(activeTheme.warn)
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("  %-*s  %8s  %17s  %17s  %10s  %s\n", idWidth, "NODE", "ROUNDS",
+		"DIGESTS SENT/RECV", "STATES PUSH/PULL", "LAST ROUND", "CONVERGENCE"))
+	for _, n := range m.nodes {
+		stats := n.GetGossipState().Stats()
+		convergence := "-"
+		if stats.DigestsSent+stats.DigestsReceived > 0 {
+			convergence = fmt.Sprintf("%.0f%%", stats.Convergence*100)
+			if stats.Convergence < 1 {
+				convergence = warnStyle.Render(convergence)
+			}
+		}
+		s.WriteString(fmt.Sprintf("  %-*s  %8d  %17s  %17s  %10s  %s\n", idWidth, n.GetConfig().NodeID, stats.Rounds,
+			fmt.Sprintf("%d/%d", stats.DigestsSent, stats.DigestsReceived),
+			fmt.Sprintf("%d/%d", stats.StatesPushed, stats.StatesPulled),
+			stats.LastRoundDuration.Round(time.Microsecond), convergence))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the set of colors the TUI draws with, picked with --theme
+type theme struct {
+	title    lipgloss.Color // title and help sections
+	muted    lipgloss.Color // borders, hints and unknown or deleted nodes
+	err      lipgloss.Color // errors, delete selection and partitions
+	ok       lipgloss.Color // toasts, heals and convergence
+	warn     lipgloss.Color // lagging beliefs
+	selected lipgloss.Color // the selected node, menu entry or form field
+	groupA   lipgloss.Color // nodes of the partition builder's groups
+	groupB   lipgloss.Color
+
+	// Styles rather than colors where the monochrome theme needs reverse video or underlines
+	banner        lipgloss.Style // the partition banner
+	searchMatch   lipgloss.Style
+	searchCurrent lipgloss.Style
+
+	// nodes are the colors of the nodes' logs and panels, see nodeColor
+	nodes []lipgloss.Color
+}
+
+// themeNames are the themes of --theme, the first being the default
+var themeNames = []string{"default", "high-contrast", "monochrome"}
+
+var themes = map[string]theme{
+	"default": {
+		title:         lipgloss.Color("62"),
+		muted:         lipgloss.Color("240"),
+		err:           lipgloss.Color("196"),
+		ok:            lipgloss.Color("46"),
+		warn:          lipgloss.Color("226"),
+		selected:      lipgloss.Color("214"),
+		groupA:        lipgloss.Color("39"),
+		groupB:        lipgloss.Color("201"),
+		banner:        lipgloss.NewStyle().Background(lipgloss.Color("196")).Foreground(lipgloss.Color("255")).Bold(true),
+		searchMatch:   lipgloss.NewStyle().Background(lipgloss.Color("240")).Foreground(lipgloss.Color("255")),
+		searchCurrent: lipgloss.NewStyle().Background(lipgloss.Color("226")).Foreground(lipgloss.Color("0")).Bold(true),
+		// Blue, green, yellow, magenta, cyan, orange, purple, lime, pink, teal, gold, violet
+		nodes: colors("39", "46", "226", "201", "51", "208", "141", "118", "213", "44", "220", "99"),
+	},
+	// high-contrast keeps to bright colors, readable on dark and light backgrounds alike
+	"high-contrast": {
+		title:         lipgloss.Color("51"),
+		muted:         lipgloss.Color("250"),
+		err:           lipgloss.Color("196"),
+		ok:            lipgloss.Color("46"),
+		warn:          lipgloss.Color("226"),
+		selected:      lipgloss.Color("208"),
+		groupA:        lipgloss.Color("51"),
+		groupB:        lipgloss.Color("201"),
+		banner:        lipgloss.NewStyle().Background(lipgloss.Color("196")).Foreground(lipgloss.Color("231")).Bold(true),
+		searchMatch:   lipgloss.NewStyle().Background(lipgloss.Color("231")).Foreground(lipgloss.Color("16")),
+		searchCurrent: lipgloss.NewStyle().Background(lipgloss.Color("226")).Foreground(lipgloss.Color("16")).Bold(true).Underline(true),
+		nodes:         colors("51", "46", "226", "201", "208", "231", "87", "154", "219"),
+	},
+	// monochrome has no colors at all: nodes are told apart by their numbers and markers,
+	// highlights are reverse video and underlines
+	"monochrome": {
+		banner:        lipgloss.NewStyle().Reverse(true).Bold(true),
+		searchMatch:   lipgloss.NewStyle().Underline(true),
+		searchCurrent: lipgloss.NewStyle().Reverse(true).Bold(true),
+		nodes:         colors(""),
+	},
+}
+
+// activeTheme is the theme of the session, set once before the TUI starts
+var activeTheme = themes["default"]
+
+func colors(codes ...string) []lipgloss.Color {
+	palette := make([]lipgloss.Color, len(codes))
+	for i, code := range codes {
+		palette[i] = lipgloss.Color(code)
+	}
+	return palette
+}
+
+// validateTheme checks that name is a theme of --theme
+func validateTheme(name string) error {
+	if !slices.Contains(themeNames, name) {
+		return fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(themeNames, ", "))
+	}
+	return nil
+}
+
+// selectTheme returns the theme of the session: the one of --theme, else monochrome if NO_COLOR
+// is set (https://no-color.org), else the one saved in the preferences, else the default
+func selectTheme(flag, saved string) theme {
+	switch {
+	case flag != "":
+		return themes[flag]
+	case os.Getenv("NO_COLOR") != "":
+		return themes["monochrome"]
+	case saved != "":
+		return themes[saved]
+	}
+	return themes[themeNames[0]]
+}
+
+// nodeColor returns the color of the node with nodeID. It depends on the ID only, so a node keeps
+// its color when nodes before it are deleted: IDs ending in a number (node-3) take the palette's
+// colors in turn, so the first nodes all differ, and other IDs are hashed.
+func nodeColor(nodeID string) lipgloss.Color {
+	palette := activeTheme.nodes
+	digits := strings.TrimRight(nodeID, "0123456789")
+	if number, err := strconv.Atoi(nodeID[len(digits):]); err == nil && number > 0 {
+		return palette[(number-1)%len(palette)]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(nodeID))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+)
+
+// timelineEventTypes are the membership events the timeline shows, leaving out the noise of
+// merged states, gossip rounds and connections
+var timelineEventTypes = map[node.EventType]bool{
+	node.EventNodeStarted:    true,
+	node.EventPeerDiscovered: true,
+	node.EventPeerUp:         true,
+	node.EventPeerDown:       true,
+	node.EventPeerRemoved:    true,
+	node.EventNodePaused:     true,
+	node.EventNodeResumed:    true,
+	node.EventPartitioned:    true,
+	node.EventHealed:         true,
+}
+
+// handleTimelineKey handles T key (toggle the event timeline in place of the logs)
+func handleTimelineKey(m *model, msg tea.KeyMsg) (State, tea.Cmd) {
+	m.showTimeline = !m.showTimeline
+	m.timelineScroll = 0
+	return m.state, nil
+}
+
+// scrollTimeline scrolls the timeline by delta events, positive to older ones
+func (m *model) scrollTimeline(delta int) {
+	maxScroll := max(0, len(m.timelineLines())-m.logPanelLines())
+	m.timelineScroll = min(max(0, m.timelineScroll+delta), maxScroll)
+}
+
+// timelineLines describes the timeline's events, newest first, e.g.
+// "12:01:02.345  node-1  marked node-2 down", colored by the node that saw the event
+func (m *model) timelineLines() []string {
+	idWidth := len("cluster")
+	for _, event := range m.events {
+		idWidth = max(idWidth, len(event.NodeID))
+	}
+
+	// Generations seen so far, to tell restarts from first sightings
+	started := make(map[gossip.NodeID]int64)
+	seen := make(map[[2]gossip.NodeID]int64) // by node and peer
+
+	var lines []string
+	for _, event := range m.events {
+		if !timelineEventTypes[event.Type] {
+			continue
+		}
+
+		var text string
+		switch event.Type {
+		case node.EventNodeStarted:
+			text = fmt.Sprintf("started with generation %d", event.Generation)
+			if _, ok := started[event.NodeID]; ok {
+				text = fmt.Sprintf("restarted with generation %d", event.Generation)
+			}
+			started[event.NodeID] = event.Generation
+		case node.EventPeerDiscovered:
+			key := [2]gossip.NodeID{event.NodeID, event.Peer}
+			text = fmt.Sprintf("discovered %s (generation %d)", event.Peer, event.Generation)
+			if generation, ok := seen[key]; ok && generation != event.Generation {
+				text = fmt.Sprintf("saw %s restart with generation %d", event.Peer, event.Generation)
+			}
+			seen[key] = event.Generation
+		case node.EventPeerUp:
+			text = fmt.Sprintf("marked %s up", event.Peer)
+		case node.EventPeerDown:
+			text = fmt.Sprintf("marked %s down", event.Peer)
+		case node.EventPeerRemoved:
+			text = fmt.Sprintf("removed %s", event.Peer)
+		case node.EventNodePaused:
+			text = "paused"
+		case node.EventNodeResumed:
+			text = "resumed"
+		case node.EventPartitioned:
+			text = fmt.Sprintf("partitioned %s from %s", joinNodeIDs(event.Groups[0]), joinNodeIDs(event.Groups[1]))
+		case node.EventHealed:
+			text = "healed every partition"
+		}
+
+		source := string(event.NodeID)
+		if source == "" {
+			source = "cluster" // published by the Manager
+		}
+		line := fmt.Sprintf("%s  %-*s  %s", event.Time.Format("15:04:05.000"), idWidth, source, text)
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.timelineColor(event)).Render(line))
+	}
+	slices.Reverse(lines)
+	return lines
+}
+
+// timelineColor is the color of event on the timeline: its node's color, red for partitions,
+// green for heals and gray for nodes deleted since
+func (m *model) timelineColor(event node.Event) lipgloss.Color {
+	switch event.Type {
+	case node.EventPartitioned:
+		return activeTheme.err
+	case node.EventHealed:
+		return activeTheme.ok
+	}
+	if m.getNodeIndexByID(string(event.NodeID)) < 0 {
+		return activeTheme.muted
+	}
+	return nodeColor(string(event.NodeID))
+}
+
+// renderTimeline renders the event timeline in a box the size of the log section, newest
+// first, scrolled back by timelineScroll events
+func (m *model) renderTimeline() string {
+	contentWidth := m.logBoxWidth() - 4 // Border and padding
+
+	lines := m.timelineLines()
+	if len(lines) == 0 {
+		lines = []string{"(no events yet)"}
+	}
+
+	timelineView := viewport.New(contentWidth, m.logPanelLines())
+	timelineView.SetContent(strings.Join(lines, "\n"))
+	timelineView.SetYOffset(m.timelineScroll)
+
+	timelineStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.muted).
+		Padding(0, 1)
+
+	title := ansi.Truncate("Timeline (T for logs):", contentWidth, "")
+	return timelineStyle.Render(title + "\n" + timelineView.View())
+}
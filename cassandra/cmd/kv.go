@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var (
+	kvConsistency string
+	kvTTL         time.Duration
+	kvTrace       bool
+	kvLimit       int
+)
+
+var kvCmd = &cobra.Command{
+	Use:   "kv",
+	Short: "Read and write the cluster's key-value store",
+	Long: `Read and write the cluster's key-value store through the node at --target, which
+coordinates the request. Every key has the replicas its replication_strategy picks on the
+token ring of the live members of the cluster the node sees through gossip, spread over
+racks and datacenters with NetworkTopologyStrategy; the request is sent to all of them,
+and answered once as many as --cl asks for have: ONE, QUORUM (a majority) or ALL. Replicas
+keep their keys in their --data-dir, or in memory without one, and a node joining the ring
+streams the keys of the ranges it takes over.
+
+A delete writes a tombstone, which replicas compare and repair like values, so a replica
+that missed the delete doesn't bring the key back. Values written with --ttl expire.
+
+With --trace, the node traces the request, like Cassandra's query tracing: its events,
+from the node receiving it to every replica answering and the read repairs it triggers,
+are printed to stderr, and can be printed again with 'cassandra trace ID'. With --count,
+get, put and delete run as a benchmark instead: KEY is the prefix of --count keys, KEY-0,
+KEY-1, ..., read, written or deleted by --concurrency clients at once, and the throughput
+and latencies are printed.
+
+Examples:
+  cassandra kv put greeting hello --target=127.0.0.1:50051 --cl=QUORUM
+  cassandra kv get greeting --target=127.0.0.1:50053 --cl=QUORUM --trace
+  cassandra kv delete greeting --cl=ALL
+  cassandra kv scan --limit=20
+
+  # Write then read 10000 keys, 32 at a time
+  cassandra kv put bench value --count=10000 --concurrency=32
+  cassandra kv get bench --count=10000 --concurrency=32 --cl=QUORUM`,
+}
+
+var kvGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print the value of a key",
+	Long: `Print the value of a key. Exits with code 4 if the key isn't set.
+
+Examples:
+  cassandra kv get greeting
+  cassandra kv get greeting --output json | jq -r .value`,
+	Args: cobra.ExactArgs(1),
+	Run:  runKVGet,
+}
+
+var kvPutCmd = &cobra.Command{
+	Use:   "put KEY VALUE",
+	Short: "Set a key to a value",
+	Long: `Set a key to a value. With --ttl the value expires that long after it is written, and
+the key reads as not set.
+
+Examples:
+  cassandra kv put greeting hello
+  cassandra kv put session abc123 --ttl=30m`,
+	Args: cobra.ExactArgs(2),
+	Run:  runKVPut,
+}
+
+var kvDeleteCmd = &cobra.Command{
+	Use:     "delete KEY",
+	Aliases: []string{"del"},
+	Short:   "Remove a key",
+	Args:    cobra.ExactArgs(1),
+	Run:     runKVDelete,
+}
+
+var kvScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "List the keys of the cluster and their values",
+	Long: `List the live keys of the cluster and their values, in token order, like a range scan in
+Cassandra. Every token range is read from as many of its replicas as --cl asks for, and
+the newest value of every key is kept; deleted and expired keys are left out. The node at
+--target holds every key while it scans, so it suits the small stores of a test cluster.
+
+Examples:
+  cassandra kv scan
+  cassandra kv scan --limit=0 --cl=ALL --output json | jq -r '.entries[].key'`,
+	Args: cobra.NoArgs,
+	Run:  runKVScan,
+}
+
+func init() {
+	rootCmd.AddCommand(kvCmd)
+	for _, cmd := range []*cobra.Command{kvGetCmd, kvPutCmd, kvDeleteCmd, kvScanCmd} {
+		kvCmd.AddCommand(cmd)
+		addAdminFlags(cmd)
+		cmd.Flags().StringVar(&kvConsistency, "cl", storage.DefaultConsistencyLevel.String(), "Consistency level: how many replicas must answer, ONE, QUORUM or ALL")
+		cmd.Flags().BoolVar(&kvTrace, "trace", false, "Trace the request on the node, and print its events to stderr (see 'cassandra trace')")
+	}
+	for _, cmd := range []*cobra.Command{kvGetCmd, kvPutCmd, kvDeleteCmd} {
+		cmd.Flags().IntVar(&kvBenchCount, "count", 0, "Benchmark: send this many requests, for keys KEY-0, KEY-1, ..., and print the throughput and latencies")
+		cmd.Flags().IntVar(&kvBenchConcurrency, "concurrency", 16, "Benchmark: requests in flight at once, with --count")
+	}
+	kvPutCmd.Flags().DurationVar(&kvTTL, "ttl", 0, "Expire the value this long after it is written, 0 for never")
+	kvScanCmd.Flags().IntVar(&kvLimit, "limit", 100, "Most keys to list, 0 for all of them")
+}
+
+// kvOutput is the JSON output of the kv commands
+type kvOutput struct {
+	Key   string        `json:"key"`
+	Value *string       `json:"value,omitempty"`  // set by get when the key is found
+	TTLMs int64         `json:"ttl_ms,omitempty"` // the value expires this long after it was written
+	Found *bool         `json:"found,omitempty"`  // set by get and delete
+	Owner gossip.NodeID `json:"owner"`            // the key's first replica
+
+	Consistency string          `json:"consistency"`
+	Replicas    []gossip.NodeID `json:"replicas"`           // the replicas that answered in time
+	TraceID     string          `json:"trace_id,omitempty"` // with --trace, see 'cassandra trace'
+}
+
+// kvConsistencyLevel returns the level of --cl, exiting if it is invalid
+func kvConsistencyLevel() storage.ConsistencyLevel {
+	cl, err := storage.ParseConsistencyLevel(kvConsistency)
+	if err != nil {
+		fatalf(exitConfig, "invalid --cl: %v", err)
+	}
+	return cl
+}
+
+// dialKV connects to the KVService at --target, exiting on failure
+func dialKV() *transport.KVClient {
+	client, err := transport.DialKV(adminTarget, adminTLSConfig())
+	if err != nil {
+		fatalf(exitConfig, "failed to connect to %s: %v", adminTarget, err)
+	}
+	client.Trace = kvTrace
+	return client
+}
+
+// traceKV prints the trace of a request to stderr, with --trace: its events until the node
+// answered, and those recorded since, e.g. the replicas that answered late
+func traceKV(client *transport.KVClient, traceID string) {
+	if !kvTrace || jsonOutput() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	trace, err := client.GetTrace(ctx, traceID)
+	if err != nil {
+		warnf("failed to get trace %s: %v", traceID, err)
+		return
+	}
+	printTrace(os.Stderr, trace)
+	fmt.Fprintln(os.Stderr)
+}
+
+func runKVGet(cmd *cobra.Command, args []string) {
+	client := dialKV()
+	defer client.Close()
+
+	key, cl := args[0], kvConsistencyLevel()
+	if kvBenchCount > 0 {
+		runKVBench("get", key, cl, func(ctx context.Context, key string) (bool, error) {
+			result, err := client.Get(ctx, key, cl)
+			return result.Found, err
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.Get(ctx, key, cl)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get %s from %s: %v", key, adminTarget, err)
+	}
+	traceKV(client, result.TraceID)
+	if !result.Found {
+		fatalf(exitRejected, "%s is not set (owned by %s)", key, result.Owner)
+	}
+	if jsonOutput() {
+		value := string(result.Value)
+		printJSON(kvOutput{Key: key, Value: &value, TTLMs: result.TTL.Milliseconds(), Found: &result.Found,
+			Owner: result.Owner, Consistency: cl.String(), Replicas: result.Replicas, TraceID: result.TraceID})
+		return
+	}
+	fmt.Println(string(result.Value))
+}
+
+func runKVPut(cmd *cobra.Command, args []string) {
+	client := dialKV()
+	defer client.Close()
+
+	key, value, cl := args[0], args[1], kvConsistencyLevel()
+	if kvTTL < 0 {
+		fatalf(exitConfig, "invalid --ttl: must not be negative")
+	}
+	if kvBenchCount > 0 {
+		runKVBench("put", key, cl, func(ctx context.Context, key string) (bool, error) {
+			_, err := client.Put(ctx, key, []byte(value), kvTTL, cl)
+			return true, err
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.Put(ctx, key, []byte(value), kvTTL, cl)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to put %s on %s: %v", key, adminTarget, err)
+	}
+	traceKV(client, result.TraceID)
+	if jsonOutput() {
+		printJSON(kvOutput{Key: key, Value: &value, TTLMs: kvTTL.Milliseconds(), Owner: result.Owner,
+			Consistency: cl.String(), Replicas: result.Replicas, TraceID: result.TraceID})
+		return
+	}
+	if kvTTL > 0 {
+		fmt.Printf("Set %s on %s (%s), expiring in %s\n", key, joinNodeIDs(result.Replicas), cl, kvTTL)
+		return
+	}
+	fmt.Printf("Set %s on %s (%s)\n", key, joinNodeIDs(result.Replicas), cl)
+}
+
+func runKVDelete(cmd *cobra.Command, args []string) {
+	client := dialKV()
+	defer client.Close()
+
+	key, cl := args[0], kvConsistencyLevel()
+	if kvBenchCount > 0 {
+		runKVBench("delete", key, cl, func(ctx context.Context, key string) (bool, error) {
+			result, err := client.Delete(ctx, key, cl)
+			return result.Found, err
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.Delete(ctx, key, cl)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to delete %s on %s: %v", key, adminTarget, err)
+	}
+	traceKV(client, result.TraceID)
+	if jsonOutput() {
+		printJSON(kvOutput{Key: key, Found: &result.Found, Owner: result.Owner,
+			Consistency: cl.String(), Replicas: result.Replicas, TraceID: result.TraceID})
+		return
+	}
+	if !result.Found {
+		fmt.Printf("%s was not set on %s (%s)\n", key, joinNodeIDs(result.Replicas), cl)
+		return
+	}
+	fmt.Printf("Deleted %s from %s (%s)\n", key, joinNodeIDs(result.Replicas), cl)
+}
+
+// kvScanEntry is a key listed by kv scan, in JSON
+type kvScanEntry struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Timestamp int64  `json:"timestamp"`        // when the value was written, in microseconds since the epoch
+	TTLMs     int64  `json:"ttl_ms,omitempty"` // the value expires this long after it was written
+}
+
+// kvScanOutput is the JSON output of kv scan
+type kvScanOutput struct {
+	Entries     []kvScanEntry   `json:"entries"`
+	Truncated   bool            `json:"truncated"` // --limit left keys out
+	Consistency string          `json:"consistency"`
+	Replicas    []gossip.NodeID `json:"replicas"`
+	TraceID     string          `json:"trace_id,omitempty"`
+}
+
+func runKVScan(cmd *cobra.Command, args []string) {
+	client := dialKV()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cl := kvConsistencyLevel()
+	if kvLimit < 0 {
+		fatalf(exitConfig, "invalid --limit: must not be negative")
+	}
+	result, err := client.Scan(ctx, cl, kvLimit)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to scan through %s: %v", adminTarget, err)
+	}
+	traceKV(client, result.TraceID)
+
+	if jsonOutput() {
+		output := kvScanOutput{Entries: []kvScanEntry{}, Truncated: result.Truncated, Consistency: cl.String(),
+			Replicas: result.Replicas, TraceID: result.TraceID}
+		for _, entry := range result.Entries {
+			output.Entries = append(output.Entries, kvScanEntry{Key: entry.Key, Value: string(entry.Cell.Value),
+				Timestamp: entry.Cell.Timestamp, TTLMs: entry.Cell.TTL.Milliseconds()})
+		}
+		printJSON(output)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE")
+	for _, entry := range result.Entries {
+		fmt.Fprintf(w, "%s\t%s\n", entry.Key, entry.Cell.Value)
+	}
+	w.Flush()
+	if result.Truncated {
+		fmt.Printf("\nFirst %d keys (%s), raise --limit for more\n", len(result.Entries), cl)
+		return
+	}
+	fmt.Printf("\n%d keys (%s)\n", len(result.Entries), cl)
+}
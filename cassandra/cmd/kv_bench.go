@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+)
+
+var (
+	kvBenchCount       int
+	kvBenchConcurrency int
+)
+
+// kvBenchOutput is the JSON output of the kv commands with --count
+type kvBenchOutput struct {
+	Operation   string  `json:"operation"`
+	Requests    int     `json:"requests"`
+	Failed      int     `json:"failed"`
+	Found       *int    `json:"found,omitempty"` // for get and delete, the keys that were set
+	Consistency string  `json:"consistency"`
+	Concurrency int     `json:"concurrency"`
+	DurationMs  float64 `json:"duration_ms"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+
+	// Latencies of the requests that succeeded, in milliseconds
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// runKVBench sends --count requests with request, for the keys prefix-0, prefix-1, ..., from
+// --concurrency clients at once, and prints the throughput and latencies. request reports
+// whether the key was set, for get and delete.
+func runKVBench(operation, prefix string, cl storage.ConsistencyLevel,
+	request func(ctx context.Context, key string) (bool, error)) {
+	if kvBenchConcurrency < 1 {
+		fatalf(exitConfig, "--concurrency must be at least 1, got %d", kvBenchConcurrency)
+	}
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var failed, found int
+	var lastErr error
+	var next atomic.Int64 // the next key to request
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range min(kvBenchConcurrency, kvBenchCount) {
+		wg.Go(func() {
+			for i := next.Add(1) - 1; i < int64(kvBenchCount); i = next.Add(1) - 1 {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				requestStart := time.Now()
+				set, err := request(ctx, fmt.Sprintf("%s-%d", prefix, i))
+				latency := time.Since(requestStart)
+				cancel()
+
+				mu.Lock()
+				if err != nil {
+					failed++
+					lastErr = err
+				} else {
+					latencies = append(latencies, latency)
+					if set {
+						found++
+					}
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	slices.Sort(latencies)
+	output := kvBenchOutput{
+		Operation:   operation,
+		Requests:    kvBenchCount,
+		Failed:      failed,
+		Consistency: cl.String(),
+		Concurrency: kvBenchConcurrency,
+		DurationMs:  durationMs(elapsed),
+		OpsPerSec:   float64(len(latencies)) / elapsed.Seconds(),
+	}
+	if operation != "put" {
+		output.Found = &found
+	}
+	if len(latencies) > 0 {
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+		output.MeanMs = durationMs(total / time.Duration(len(latencies)))
+		output.P50Ms = durationMs(percentile(latencies, 0.50))
+		output.P99Ms = durationMs(percentile(latencies, 0.99))
+		output.MaxMs = durationMs(latencies[len(latencies)-1])
+	}
+
+	if jsonOutput() {
+		printJSON(output)
+	} else {
+		fmt.Printf("%d %s requests (%s, %d at once) through %s in %v: %.0f ops/s\n", output.Requests, operation,
+			cl, kvBenchConcurrency, adminTarget, elapsed.Round(time.Millisecond), output.OpsPerSec)
+		if len(latencies) > 0 {
+			fmt.Printf("  Latency: mean %.2fms, p50 %.2fms, p99 %.2fms, max %.2fms\n",
+				output.MeanMs, output.P50Ms, output.P99Ms, output.MaxMs)
+		}
+		if output.Found != nil {
+			fmt.Printf("  Found: %d of %d keys\n", *output.Found, len(latencies))
+		}
+		if output.Failed > 0 {
+			fmt.Printf("  Failed: %d, last: %v\n", output.Failed, lastErr)
+		}
+	}
+	if output.Failed > 0 {
+		fatalf(rpcExitCode(lastErr), "%d of %d %s requests failed", output.Failed, output.Requests, operation)
+	}
+}
+
+// percentile returns the latency below which a share p of the sorted latencies are
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
+
+// durationMs returns d in milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
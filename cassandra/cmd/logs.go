@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var (
+	logsFollow bool
+	logsTail   int
+	logsNode   string
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print a running node's log",
+	Long: `Print the latest entries of a running node's log through its Logs RPC, and with --follow
+keep printing new entries as they are logged, until Ctrl+C. No access to the node's stdout is
+needed. A node keeps its last 1000 entries.
+
+A process running several nodes (cluster, start --count) serves one log for all of them, each
+entry tagged with its node; --node keeps only one node's entries.
+
+Examples:
+  cassandra logs --target=127.0.0.1:50051 --tail=20
+  cassandra logs --follow
+  cassandra logs --target=127.0.0.1:50051 --node=node-3 --follow`,
+	Run: runLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	addAdminFlags(logsCmd)
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new entries until interrupted")
+	logsCmd.Flags().IntVar(&logsTail, "tail", 100, "Number of the latest entries to print first, -1 for all")
+	logsCmd.Flags().StringVar(&logsNode, "node", "", "Only print entries of this node")
+}
+
+func runLogs(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if !logsFollow {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+
+	entries, err := client.Logs(ctx, transport.LogsQuery{NodeID: logsNode, Tail: logsTail, Follow: logsFollow})
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get logs from %s: %v", adminTarget, err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for entry := range entries {
+		if jsonOutput() {
+			// One object per line, so it can be piped to jq while following
+			encoder.Encode(struct {
+				Time      time.Time `json:"time"`
+				NodeID    string    `json:"node_id"`
+				Level     string    `json:"level"`
+				Component string    `json:"component,omitempty"`
+				Message   string    `json:"message"`
+			}{entry.Timestamp, entry.NodeID, entry.Level.String(), entry.Component, entry.Message})
+			continue
+		}
+		fmt.Println(logger.FormatLogEntry(entry))
+	}
+	if logsFollow && ctx.Err() == nil {
+		fatalf(exitConnection, "lost the stream from %s", adminTarget)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes, so scripts can tell why a command failed
+const (
+	exitFailure    = 1 // anything else, e.g. a node failed to start
+	exitConfig     = 2 // invalid flags, arguments or config
+	exitConnection = 3 // the node could not be reached, or the connection broke
+	exitRejected   = 4 // the node refused the request, e.g. an unknown node or a reserved key
+)
+
+// Output formats accepted by --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var outputFormat string
+
+// cliError is the JSON form of an error, printed to stderr with --output json
+type cliError struct {
+	Code    int    `json:"code"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// exitKinds names the exit codes in JSON errors
+var exitKinds = map[int]string{
+	exitFailure:    "failure",
+	exitConfig:     "config",
+	exitConnection: "connection",
+	exitRejected:   "rejected",
+}
+
+// validateOutput checks --output
+func validateOutput() error {
+	if outputFormat != outputText && outputFormat != outputJSON {
+		return fmt.Errorf("unknown output format %q: use %s or %s", outputFormat, outputText, outputJSON)
+	}
+	return nil
+}
+
+// jsonOutput reports whether --output json was given
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// fatalf prints an error to stderr, as "Error: ..." or as a JSON object with --output json,
+// and exits with code
+func fatalf(code int, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if jsonOutput() {
+		json.NewEncoder(os.Stderr).Encode(struct {
+			Error cliError `json:"error"`
+		}{cliError{Code: code, Kind: exitKinds[code], Message: message}})
+	} else {
+		fmt.Fprintln(os.Stderr, "Error: "+message)
+	}
+	os.Exit(code)
+}
+
+// warnf prints a problem the command continues after to stderr, as "Warning: ..." or as a
+// JSON object with --output json
+func warnf(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if jsonOutput() {
+		json.NewEncoder(os.Stderr).Encode(struct {
+			Warning string `json:"warning"`
+		}{message})
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Warning: "+message)
+}
+
+// rpcExitCode is the exit code for a failed request to a node: exitConnection if it
+// couldn't be reached or didn't answer in time, exitRejected if it refused the request
+func rpcExitCode(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return exitConnection
+		}
+		return exitFailure
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Unauthenticated:
+		return exitConnection
+	default:
+		return exitRejected
+	}
+}
+
+// printJSON prints v as indented JSON to stdout
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fatalf(exitFailure, "failed to encode output: %v", err)
+	}
+}
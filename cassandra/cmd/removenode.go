@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var (
+	removeNodeID    string
+	removeNodeForce bool
+)
+
+var removeNodeCmd = &cobra.Command{
+	Use:   "removenode",
+	Short: "Evict a dead node from the cluster's gossip state",
+	Long: `Evict a node that will never come back from the cluster's gossip state, like
+nodetool removenode. The node at --target and every live node it knows forget the endpoint
+and quarantine its generation, so gossip still carrying it from other nodes is ignored. Only
+a restart of the removed node, with a new generation, brings it back.
+
+A node that is UP is refused unless --force is given: decommission it instead.
+
+Examples:
+  cassandra removenode --target=127.0.0.1:50051 --node=node-3`,
+	Run: runRemoveNode,
+}
+
+func init() {
+	rootCmd.AddCommand(removeNodeCmd)
+	addAdminFlags(removeNodeCmd)
+	removeNodeCmd.Flags().StringVar(&removeNodeID, "node", "", "ID of the node to remove")
+	removeNodeCmd.Flags().BoolVar(&removeNodeForce, "force", false, "Remove the node even if it is UP")
+	removeNodeCmd.MarkFlagRequired("node")
+}
+
+func runRemoveNode(cmd *cobra.Command, args []string) {
+	nodeID := gossip.NodeID(removeNodeID)
+	state, err := fetchClusterState(adminTarget)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get cluster state from %s: %v", adminTarget, err)
+	}
+
+	var removed *gossip.EndpointInfo
+	for i, endpoint := range state.Endpoints {
+		if endpoint.State.HeartbeatState.NodeID == nodeID {
+			removed = &state.Endpoints[i]
+		}
+	}
+	switch {
+	case removed == nil:
+		fatalf(exitRejected, "%s does not know node %s", state.NodeID, nodeID)
+	case removed.Local:
+		fatalf(exitRejected, "%s is the node at %s, it cannot remove itself", nodeID, adminTarget)
+	case removed.Alive && !removeNodeForce:
+		fatalf(exitRejected, "%s is UP: decommission it instead, or use --force", nodeID)
+	}
+
+	if err := removeNodeFrom(adminTarget, nodeID); err != nil {
+		fatalf(rpcExitCode(err), "failed to remove %s from %s: %v", nodeID, state.NodeID, err)
+	}
+	removedFrom := []gossip.NodeID{state.NodeID}
+	if !jsonOutput() {
+		fmt.Printf("Removed %s from %s\n", nodeID, state.NodeID)
+	}
+
+	// The other nodes would keep gossiping about it, and keep it in their own state
+	for _, endpoint := range state.Endpoints {
+		peer := endpoint.State.HeartbeatState.NodeID
+		if endpoint.Local || peer == nodeID || !endpoint.Alive {
+			continue
+		}
+		addr, ok := endpoint.State.ApplicationStates[gossip.AppHeartbeat]
+		if !ok {
+			warnf("%s: address unknown, skipped", peer)
+			continue
+		}
+		if err := removeNodeFrom(addr.Value, nodeID); err != nil {
+			warnf("%s (%s): %v", peer, addr.Value, err)
+			continue
+		}
+		removedFrom = append(removedFrom, peer)
+		if !jsonOutput() {
+			fmt.Printf("Removed %s from %s\n", nodeID, peer)
+		}
+	}
+
+	if jsonOutput() {
+		printJSON(struct {
+			NodeID      gossip.NodeID   `json:"node_id"`
+			RemovedFrom []gossip.NodeID `json:"removed_from"`
+		}{nodeID, removedFrom})
+	}
+}
+
+// removeNodeFrom makes the node at target forget nodeID
+func removeNodeFrom(target string, nodeID gossip.NodeID) error {
+	client, err := transport.DialAdmin(target, adminTLSConfig())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.RemoveNode(ctx, nodeID)
+}
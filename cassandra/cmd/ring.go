@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var ringSummary bool
+
+var ringCmd = &cobra.Command{
+	Use:   "ring",
+	Short: "Show the token ring a running node routes keys with",
+	Long: `Show the token ring as a running node sees it, like nodetool ring: every node it spreads
+keys over with how many tokens (vnodes) it has and how much of the ring it owns, then the
+token ranges each node owns. A range (START, END] holds the keys whose token is after START,
+up to and including END, the owner's token; the first one wraps around the ring.
+
+Only nodes that are UP with STATUS NORMAL are on the ring, the others are listed below it:
+run it again after a node joins, leaves or goes down to see the ranges rebalanced.
+
+Examples:
+  cassandra ring --target=127.0.0.1:50051
+  cassandra ring --summary
+
+  # For scripts
+  cassandra ring --output json | jq '.nodes[] | {node_id, owns}'`,
+	Run: runRing,
+}
+
+func init() {
+	rootCmd.AddCommand(ringCmd)
+	addAdminFlags(ringCmd)
+	ringCmd.Flags().BoolVar(&ringSummary, "summary", false, "Only show how much of the ring each node owns, not its ranges")
+}
+
+// ringOutput is the output of the ring command
+type ringOutput struct {
+	NodeID   gossip.NodeID `json:"node_id"` // the node whose view this is
+	Tokens   int           `json:"tokens"`
+	Nodes    []ringNode    `json:"nodes"`
+	Excluded []string      `json:"excluded"` // nodes gossiping tokens that aren't on the ring, and why
+}
+
+// ringNode is a node on the ring and the ranges it owns
+type ringNode struct {
+	NodeID  gossip.NodeID `json:"node_id"`
+	Address string        `json:"address"`
+	Tokens  int           `json:"tokens"`
+	Owns    float64       `json:"owns"` // 0 to 1
+	Ranges  []ringRange   `json:"ranges"`
+}
+
+// ringRange is a token range, see ring.Range. Tokens are strings in JSON, as they don't fit
+// in the float64 many JSON tools read numbers into.
+type ringRange struct {
+	Start ring.Token `json:"start,string"`
+	End   ring.Token `json:"end,string"`
+	Owns  float64    `json:"owns"`
+}
+
+func runRing(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := client.GetClusterState(ctx)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get cluster state from %s: %v", adminTarget, err)
+	}
+
+	output := newRingOutput(state)
+	if jsonOutput() {
+		printJSON(output)
+		return
+	}
+	printRing(output)
+}
+
+// newRingOutput builds the ring the node routes keys with from its cluster state, as
+// Node.Ring does
+func newRingOutput(state transport.ClusterState) ringOutput {
+	r := ring.FromEndpoints(ring.Members(state.Endpoints))
+	output := ringOutput{
+		NodeID:   state.NodeID,
+		Tokens:   r.Len(),
+		Nodes:    make([]ringNode, 0),
+		Excluded: make([]string, 0),
+	}
+
+	addresses := make(map[gossip.NodeID]string)
+	onRing := make(map[gossip.NodeID]bool)
+	for _, nodeID := range r.Nodes() {
+		onRing[nodeID] = true
+	}
+	for _, endpoint := range state.Endpoints {
+		nodeID := endpoint.State.HeartbeatState.NodeID
+		addresses[nodeID] = endpoint.State.ApplicationStates[gossip.AppHeartbeat].Value
+		if _, ok := endpoint.State.ApplicationStates[gossip.AppTokens]; !ok || onRing[nodeID] {
+			continue
+		}
+		reason := "DOWN"
+		if endpoint.Alive {
+			reason = "STATUS " + endpoint.State.ApplicationStates[gossip.AppStatus].Value
+		}
+		output.Excluded = append(output.Excluded, fmt.Sprintf("%s (%s)", nodeID, reason))
+	}
+
+	byNode := make(map[gossip.NodeID]*ringNode)
+	for _, nodeID := range r.Nodes() {
+		output.Nodes = append(output.Nodes, ringNode{NodeID: nodeID, Address: addresses[nodeID]})
+	}
+	for i := range output.Nodes {
+		byNode[output.Nodes[i].NodeID] = &output.Nodes[i]
+	}
+	for _, rng := range r.Ranges() {
+		node := byNode[rng.Owner]
+		node.Tokens++
+		node.Owns += rng.Size()
+		node.Ranges = append(node.Ranges, ringRange{Start: rng.Start, End: rng.End, Owns: rng.Size()})
+	}
+	return output
+}
+
+func printRing(output ringOutput) {
+	if output.Tokens == 0 {
+		fmt.Printf("Ring: empty (as seen by %s)\n", output.NodeID)
+	} else {
+		fmt.Printf("Ring: %d tokens on %d nodes (as seen by %s)\n\n", output.Tokens, len(output.Nodes), output.NodeID)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NODE\tADDRESS\tTOKENS\tOWNS")
+		for _, node := range output.Nodes {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%.1f%%\n", node.NodeID, node.Address, node.Tokens, node.Owns*100)
+		}
+		w.Flush()
+	}
+
+	if len(output.Excluded) > 0 {
+		fmt.Println("\nNot on the ring:")
+		for _, excluded := range output.Excluded {
+			fmt.Printf("  %s\n", excluded)
+		}
+	}
+
+	if ringSummary {
+		return
+	}
+	for _, node := range output.Nodes {
+		fmt.Printf("\n%s (%s):\n", node.NodeID, node.Address)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "  START\tEND\tOWNS\t")
+		for _, rng := range node.Ranges {
+			fmt.Fprintf(w, "  (%d,\t%d]\t%.2f%%\t\n", rng.Start, rng.End, rng.Owns*100)
+		}
+		w.Flush()
+	}
+}
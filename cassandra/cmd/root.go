@@ -1,27 +1,156 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/tracing"
+)
+
+// tracingFlushTimeout bounds how long exporting the last spans may delay exiting
+const tracingFlushTimeout = 5 * time.Second
+
+var (
+	logLevel  string
+	logFile   string
+	logFormat string
+
+	// logRepeatWindow collapses messages repeated within it, see logger.SetRepeatWindow
+	logRepeatWindow time.Duration
+
+	// otlpEndpoint is the collector spans are exported to, see initTracing
+	otlpEndpoint string
+	// stopTracing flushes the spans not yet exported, set by initTracing
+	stopTracing func(context.Context) error
+
+	// Rotation of --log-file
+	logMaxSize    int
+	logMaxBackups int
+	logMaxAge     int
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "cassandra",
 	Short: "Cassandra gossip protocol implementation",
 	Long: `A distributed database system that implements a simple key-value store
-with gossip protocol for cluster membership and state management.`,
+with gossip protocol for cluster membership and state management.
+
+Commands exit with 0 on success, 1 on a failure, 2 on invalid flags, arguments or config,
+3 when a node can't be reached and 4 when a node refuses the request. With --output json,
+errors are printed to stderr as {"error": {"code": 3, "kind": "connection", "message": "..."}}
+and commands print their result as JSON.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Fail before doing anything, rather than when the command starts logging
+		if err := validateOutput(); err != nil {
+			return err
+		}
+		if _, err := logger.ParseLevel(logLevel); err != nil {
+			return err
+		}
+		if err := tracing.ValidateEndpoint(otlpEndpoint); err != nil {
+			return err
+		}
+		if logRepeatWindow < 0 {
+			return fmt.Errorf("invalid --log-repeat-window %v: can't be negative", logRepeatWindow)
+		}
+		_, err := logger.ParseFormat(logFormat)
+		return err
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if stopTracing != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+			defer cancel()
+			if err := stopTracing(ctx); err != nil {
+				warnf("failed to export the last spans: %v", err)
+			}
+		}
+	},
+	// Errors are printed by Execute, in the --output format
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		if validateOutput() != nil {
+			outputFormat = outputText
+		}
+		fatalf(exitConfig, "%v", err)
 	}
 }
 
 func init() {
-	// Global flags can be added here
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Lowest level to log: debug, info, warn or error")
+	rootCmd.PersistentFlags().DurationVar(&logRepeatWindow, "log-repeat-window", 5*time.Second, "Collapse a message a node repeats within this long into one \"... repeated N times\" entry (0 logs every message)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Also append the log to this file")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of the log on stdout and in --log-file: text or json (one object per line)")
+	rootCmd.PersistentFlags().IntVar(&logMaxSize, "log-max-size", 0, "Rotate --log-file once it reaches this many MB (default: never)")
+	rootCmd.PersistentFlags().IntVar(&logMaxBackups, "log-max-backups", 0, "Rotated log files to keep, the newest ones (default: all)")
+	rootCmd.PersistentFlags().IntVar(&logMaxAge, "log-max-age", 0, "Delete rotated log files older than this many days (default: never)")
+	rootCmd.PersistentFlags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "Export OpenTelemetry spans of gossip exchanges to this OTLP gRPC collector, e.g. http://localhost:4317 (default: $OTEL_EXPORTER_OTLP_ENDPOINT, if set)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format: text or json")
+	rootCmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(
+		[]string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(
+		[]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{outputText, outputJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// initLogger sets the default logger from --log-level, --log-format, --log-repeat-window, --log-file and its rotation.
+// Commands that run nodes call it instead of logger.Init; stdout says whether the log is also written to the terminal.
+// The log is always kept in the global log buffer, shown by the TUI and served by the Logs RPC.
+func initLogger(stdout bool) {
+	level, err := logger.ParseLevel(logLevel)
+	if err != nil {
+		fatalf(exitConfig, "%v", err)
+	}
+	format, err := logger.ParseFormat(logFormat)
+	if err != nil {
+		fatalf(exitConfig, "%v", err)
+	}
+
+	outputs := []io.Writer{}
+	if stdout {
+		outputs = append(outputs, os.Stdout)
+	}
+	outputs = append(outputs, logger.NewLogBufferWriter(logger.GetGlobalLogBuffer()))
+	if logFile != "" {
+		file, err := logger.OpenRotatingFile(logFile, logger.RotateOptions{
+			MaxSizeMB:  logMaxSize,
+			MaxBackups: logMaxBackups,
+			MaxAgeDays: logMaxAge,
+		})
+		if err != nil {
+			fatalf(exitConfig, "%v", err)
+		}
+		outputs = append(outputs, file)
+	}
+
+	// Replacing any logger of an earlier command in the process
+	logger.SetDefault(logger.New(logger.Options{
+		Outputs:      outputs,
+		Level:        level,
+		Format:       format,
+		RepeatWindow: logRepeatWindow,
+	}))
+}
+
+// initTracing exports spans of gossip exchanges if --otlp-endpoint or the OTEL_EXPORTER_OTLP_*
+// environment variables say where to. Commands that run nodes call it after initLogger.
+func initTracing() {
+	if !tracing.Configured(otlpEndpoint) {
+		return
+	}
+	stop, err := tracing.Setup(context.Background(), otlpEndpoint)
+	if err != nil {
+		fatalf(exitConfig, "%v", err)
+	}
+	stopTracing = stop
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+var (
+	setAppStateKey   string
+	setAppStateValue string
+)
+
+var setAppStateCmd = &cobra.Command{
+	Use:     "set-app-state {KEY VALUE | --key KEY --value VALUE}",
+	Aliases: []string{"set-state"},
+	Short:   "Set an application state on a running node",
+	Long: `Set an application state on a running node, e.g. LOAD=42, and print its new version.
+The other nodes learn the new value through gossip, so it can be watched spreading through
+the cluster. STATUS, ADDR and TOKENS are managed by the node and can't be set.
+
+The key and value are given as arguments, or with --key and --value for scripts.
+
+Examples:
+  cassandra set-app-state LOAD 42 --target=127.0.0.1:50053
+
+  # Change it in a loop, and follow it with: cassandra watch
+  for load in 10 50 87; do cassandra set-state --key=LOAD --value=$load; sleep 2; done`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("key") || cmd.Flags().Changed("value") {
+			if len(args) > 0 {
+				return fmt.Errorf("give the key and value either as arguments or with --key and --value, not both")
+			}
+			if !cmd.Flags().Changed("key") || !cmd.Flags().Changed("value") {
+				return fmt.Errorf("--key and --value must be given together")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Run: runSetAppState,
+}
+
+func init() {
+	rootCmd.AddCommand(setAppStateCmd)
+	addAdminFlags(setAppStateCmd)
+	setAppStateCmd.Flags().StringVar(&setAppStateKey, "key", "", "Application state to set, instead of the KEY argument")
+	setAppStateCmd.Flags().StringVar(&setAppStateValue, "value", "", "Value to set, instead of the VALUE argument")
+}
+
+func runSetAppState(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key, value := gossip.AppStateKey(setAppStateKey), setAppStateValue
+	if len(args) == 2 {
+		key, value = gossip.AppStateKey(args[0]), args[1]
+	}
+	state, err := client.SetAppState(ctx, key, value)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to set %s on %s: %v", key, adminTarget, err)
+	}
+	if jsonOutput() {
+		printJSON(struct {
+			Target  string             `json:"target"`
+			Key     gossip.AppStateKey `json:"key"`
+			Value   string             `json:"value"`
+			Version int64              `json:"version"`
+		}{adminTarget, key, state.Value, state.Version})
+		return
+	}
+	fmt.Printf("%s=%s on %s (version %d)\n", key, state.Value, adminTarget, state.Version)
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"log"
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,14 +11,39 @@ import (
 	"github.com/adamgarcia4/goLearning/cassandra/gossip"
 	"github.com/adamgarcia4/goLearning/cassandra/logger"
 	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/snitch"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
 )
 
 var (
-	address      string
-	port         string
-	nodeID       string
-	clientMode   bool
-	targetServer string
+	address       string
+	advertise     string
+	port          string
+	nodeID        string
+	clientMode    bool
+	targetServer  string
+	clusterID     string
+	seeds         []string
+	transportName string
+	compression   string
+	configFile    string
+	dataDir       string
+	numTokens     int
+	replication   int
+	manualGossip  bool
+	startCount    int
+	basePort      int
+	startValidate bool
+
+	snitchName   string
+	dc           string
+	rack         string
+	topologyFile string
+
+	tlsCert              string
+	tlsKey               string
+	tlsCA                string
+	tlsRequireClientCert bool
 )
 
 var startCmd = &cobra.Command{
@@ -31,51 +56,209 @@ Examples:
   # Start a node in server mode
   cassandra start --node-id=node-1 --port=50051
 
+  # Start a second node that joins the cluster through node-1
+  cassandra start --node-id=node-2 --port=50052 --seeds=127.0.0.1:50051
+
   # Start a node in client mode that sends heartbeats to another node
-  cassandra start --node-id=node-2 --port=50052 --client --target=127.0.0.1:50051`,
+  cassandra start --node-id=node-2 --port=50052 --client --target=127.0.0.1:50051
+
+  # Start a node from a config file, overriding its port
+  cassandra start --config=config.yaml --port=50053
+
+  # Start 5 nodes in this process, on ports 50061-50065, the first three seeding the others
+  cassandra start --count=5 --base-port=50061
+
+  # Check the config, ports, seeds and TLS files without starting, e.g. in CI
+  cassandra start --config=config.yaml --validate`,
 	Run: runStart,
 }
 
 func init() {
 	rootCmd.AddCommand(startCmd)
 
+	startCmd.Flags().StringVar(&configFile, "config", "", "YAML config file (flags that are set override its values, see 'cassandra config example')")
+	startCmd.Flags().BoolVar(&startValidate, "validate", false, "Check the config, ports, seeds and TLS files, print a report and exit instead of starting")
+
 	// Server flags
-	startCmd.Flags().StringVarP(&address, "address", "a", node.DefaultAddress, "Address to bind the server to")
-	startCmd.Flags().StringVarP(&port, "port", "p", node.DefaultPort, "Port to bind the server to")
+	startCmd.Flags().StringVarP(&address, "address", "a", node.DefaultAddress, "Address to bind the server to, or a unix socket (unix:///path/to/node.sock)")
+	startCmd.Flags().StringVarP(&port, "port", "p", node.DefaultPort, "Port to bind the server to (0 picks a free port)")
+	startCmd.Flags().StringVar(&advertise, "advertise", "", "Address (host:port) other nodes reach this one at, if not the bind address (e.g. behind NAT or Docker)")
 	startCmd.Flags().StringVarP(&nodeID, "node-id", "n", node.DefaultNodeID, "Unique node identifier")
+	startCmd.Flags().IntVar(&startCount, "count", 1, "Number of nodes to start in this process, on consecutive ports")
+	startCmd.Flags().IntVar(&basePort, "base-port", 0, "Port of the first node with --count (default: --port)")
+
+	// Gossip flags
+	startCmd.Flags().StringVar(&clusterID, "cluster", node.DefaultClusterID, "Cluster ID (nodes only gossip within the same cluster)")
+	startCmd.Flags().StringSliceVar(&seeds, "seeds", nil, "Comma-separated seed addresses (host:port) to join the cluster through")
+	startCmd.Flags().StringVar(&dataDir, "data-dir", "", "Directory to save known peers and keys in, so a restarted node finds the cluster even if the seeds are down and keeps its keys")
+	startCmd.Flags().IntVar(&numTokens, "num-tokens", node.DefaultNumTokens, "Number of tokens (vnodes) the node picks on the ring when first created")
+	startCmd.Flags().IntVar(&replication, "replication-factor", node.DefaultReplicationFactor, "Number of nodes holding a copy of each key")
+	startCmd.Flags().StringVar(&snitchName, "snitch", snitch.SimpleName, "Snitch telling which datacenter and rack nodes are in: SimpleSnitch, PropertyFileSnitch or GossipingPropertyFileSnitch")
+	startCmd.Flags().StringVar(&dc, "dc", snitch.DefaultDC, "Datacenter of the node, with GossipingPropertyFileSnitch")
+	startCmd.Flags().StringVar(&rack, "rack", snitch.DefaultRack, "Rack of the node, with GossipingPropertyFileSnitch")
+	startCmd.Flags().StringVar(&topologyFile, "topology-file", "", "Topology file mapping every node to its datacenter and rack, with PropertyFileSnitch")
+	startCmd.Flags().BoolVar(&manualGossip, "manual-gossip", false, "Don't gossip on a timer, only when triggered with 'cassandra gossip-once'")
+	startCmd.Flags().StringVar(&compression, "compression", "", "Compress gossip messages larger than 1KiB with this algorithm (gzip)")
+	startCmd.Flags().StringVar(&transportName, "transport", node.TransportGRPC, "Gossip transport: grpc or udp (udp falls back to gRPC for large payloads)")
 
 	// Client flags
 	startCmd.Flags().BoolVarP(&clientMode, "client", "c", node.DefaultClientMode, "Run in client mode (send heartbeats)")
 	startCmd.Flags().StringVarP(&targetServer, "target", "t", node.DefaultTarget, "Target server address (required in client mode)")
+
+	// TLS flags
+	startCmd.Flags().StringVar(&tlsCert, "tls-cert", "", "PEM certificate file (enables TLS)")
+	startCmd.Flags().StringVar(&tlsKey, "tls-key", "", "PEM private key file for --tls-cert")
+	startCmd.Flags().StringVar(&tlsCA, "tls-ca", "", "PEM CA bundle used to verify peers")
+	startCmd.Flags().BoolVar(&tlsRequireClientCert, "tls-require-client-cert", false, "Require peers to present a certificate signed by --tls-ca (mTLS)")
 }
 
 func runStart(cmd *cobra.Command, args []string) {
 	// Initialize logger for non-interactive mode (write to stdout)
-	logger.Init("", true) // No prefix, write to stdout
+	initLogger(true)
+	initTracing()
 
-	// Create node configuration with defaults
+	// Create node configuration with defaults, or from the config file
 	config := node.DefaultConfig(gossip.NodeID(nodeID))
+	if configFile != "" {
+		var err error
+		config, err = node.LoadConfig(configFile)
+		if err != nil {
+			fatalf(exitConfig, "failed to load config: %v", err)
+		}
+	}
+
+	// Override with CLI flags. With a config file only the flags given on the command line
+	// override it, otherwise the flag defaults apply.
+	flags := cmd.Flags()
+	override := func(name string) bool {
+		return configFile == "" || flags.Changed(name)
+	}
+	if override("node-id") {
+		config.NodeID = gossip.NodeID(nodeID)
+	}
+	if override("address") {
+		config.Address = address
+	}
+	if override("port") {
+		config.Port = port
+	}
+	if override("advertise") {
+		config.AdvertisedAddress = advertise
+	}
+	if override("client") {
+		config.ClientMode = clientMode
+	}
+	if override("target") {
+		config.TargetServer = targetServer
+	}
+	if override("cluster") {
+		config.ClusterID = clusterID
+	}
+	if override("seeds") {
+		config.Seeds = seeds
+	}
+	if override("manual-gossip") {
+		config.ManualHeartbeat = manualGossip
+	}
+	if override("compression") {
+		config.Compression = compression
+	}
+	if override("data-dir") {
+		config.DataDir = dataDir
+	}
+	if override("num-tokens") {
+		config.NumTokens = numTokens
+	}
+	if override("replication-factor") {
+		config.ReplicationFactor = replication
+	}
+	if override("snitch") {
+		config.Snitch = snitchName
+	}
+	if override("dc") {
+		config.DC = dc
+	}
+	if override("rack") {
+		config.Rack = rack
+	}
+	if override("topology-file") {
+		config.TopologyFile = topologyFile
+	}
+	if override("transport") {
+		factory, err := node.TransportByName(transportName)
+		if err != nil {
+			fatalf(exitConfig, "invalid --transport: %v", err)
+		}
+		config.Transport = factory
+	}
 
-	// Override with CLI flags
-	config.Address = address
-	config.Port = port
-	config.ClientMode = clientMode
-	config.TargetServer = targetServer
+	if tlsCert != "" || tlsKey != "" || tlsCA != "" {
+		if config.TLS == nil {
+			config.TLS = &transport.TLSConfig{}
+		}
+		if tlsCert != "" {
+			config.TLS.CertFile = tlsCert
+		}
+		if tlsKey != "" {
+			config.TLS.KeyFile = tlsKey
+		}
+		if tlsCA != "" {
+			config.TLS.CAFile = tlsCA
+		}
+	}
+	if config.TLS != nil && override("tls-require-client-cert") {
+		config.TLS.RequireClientCert = tlsRequireClientCert
+	}
+
+	if startValidate {
+		configs := []*node.Config{config}
+		if startCount > 1 {
+			var err error
+			if configs, err = startManyConfigs(config, flags.Changed("base-port")); err != nil {
+				fatalf(exitConfig, "%v", err)
+			}
+		}
+		if !validateStart(configs) {
+			os.Exit(exitConfig)
+		}
+		return
+	}
+
+	if startCount > 1 {
+		runStartMany(config, flags.Changed("base-port"))
+		return
+	}
 
 	// Create and start the node
 	n, err := node.New(config)
 	if err != nil {
-		log.Fatalf("failed to create node: %v", err)
+		fatalf(exitConfig, "failed to create node: %v", err)
 	}
 
 	if err := n.Start(); err != nil {
-		log.Fatalf("failed to start node: %v", err)
+		fatalf(exitFailure, "failed to start node: %v", err)
 	}
 
+	// Exit once the node stops by itself, e.g. after 'cassandra decommission'
+	stopped := make(chan struct{})
+	go func() {
+		for event := range n.WatchStatus(context.Background()) {
+			if event.To == node.StatusStopped {
+				close(stopped)
+				return
+			}
+		}
+	}()
+
 	// Wait for interrupt signal for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	select {
+	case <-sigChan:
+	case <-stopped:
+		logger.Info("Node stopped")
+		return
+	}
 
 	logger.Info("Shutting down...")
 	if err := n.Stop(); err != nil {
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// startManySeeds is how many of the nodes started with --count seed the others, unless --seeds is given
+const startManySeeds = 3
+
+// runStartMany runs --count nodes configured like config in this process, on consecutive
+// ports from --base-port (or config's port), until interrupted. Their logs are interleaved,
+// each line prefixed with its node ID.
+func runStartMany(config *node.Config, basePortSet bool) {
+	configs, err := startManyConfigs(config, basePortSet)
+	if err != nil {
+		fatalf(exitConfig, "%v", err)
+	}
+
+	var nodes []*node.Node
+	for _, c := range configs {
+		n, err := node.New(c)
+		if err == nil {
+			err = n.Start()
+		}
+		if err != nil {
+			stopNodes(nodes)
+			fatalf(exitFailure, "failed to start %s: %v", c.NodeID, err)
+		}
+		nodes = append(nodes, n)
+	}
+	logger.Infof("%d nodes running on ports %s-%s", len(nodes), configs[0].Port, configs[len(configs)-1].Port)
+
+	waitForSignal()
+	logger.Info("Shutting down...")
+	stopNodes(nodes)
+}
+
+// startManyConfigs derives the configs of the --count nodes from config
+func startManyConfigs(config *node.Config, basePortSet bool) ([]*node.Config, error) {
+	if transport.IsUnixAddress(config.Address) {
+		return nil, fmt.Errorf("--count needs a TCP address, nodes can't share the unix socket %s", config.Address)
+	}
+	if config.AdvertisedAddress != "" {
+		return nil, fmt.Errorf("--count can't be used with --advertise, nodes can't share an advertised address")
+	}
+	first := basePort
+	if !basePortSet {
+		var err error
+		if first, err = strconv.Atoi(config.Port); err != nil || first == 0 {
+			return nil, fmt.Errorf("--count needs a fixed --port or --base-port, got %q", config.Port)
+		}
+	}
+
+	configs := make([]*node.Config, startCount)
+	var seeds []string
+	for i := range configs {
+		c := *config
+		c.Port = strconv.Itoa(first + i)
+		// Nodes get numbered IDs, or the given ID numbered
+		c.NodeID = gossip.NodeID(fmt.Sprintf("node-%d", i+1))
+		if config.NodeID != node.DefaultNodeID {
+			c.NodeID = gossip.NodeID(fmt.Sprintf("%s-%d", config.NodeID, i+1))
+		}
+		if config.DataDir != "" {
+			c.DataDir = filepath.Join(config.DataDir, string(c.NodeID))
+		}
+		if i < startManySeeds {
+			seeds = append(seeds, c.GetAddress())
+		}
+		configs[i] = &c
+	}
+	for _, c := range configs {
+		if len(config.Seeds) == 0 {
+			c.Seeds = seeds
+		}
+	}
+	return configs, nil
+}
+
+// stopNodes stops nodes concurrently
+func stopNodes(nodes []*node.Node) {
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Go(func() {
+			if err := n.Stop(); err != nil {
+				logger.Errorf("Error stopping %s: %v", n.GetConfig().NodeID, err)
+			}
+		})
+	}
+	wg.Wait()
+}
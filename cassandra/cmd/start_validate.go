@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// validateLookupTimeout bounds resolving each seed's host
+const validateLookupTimeout = 5 * time.Second
+
+// validationCheck is one line of the start --validate report
+type validationCheck struct {
+	name   string
+	detail string
+	err    error
+}
+
+// validateStart checks that nodes with configs could start: their config is valid, their
+// addresses can be bound, their seeds resolve and their TLS files load. It prints a report
+// and returns whether every check passed.
+func validateStart(configs []*node.Config) bool {
+	checks := make([][]validationCheck, len(configs))
+	failed := 0
+	for i, config := range configs {
+		checks[i] = validateConfig(config)
+		for _, check := range checks[i] {
+			if check.err != nil {
+				failed++
+			}
+		}
+	}
+
+	if jsonOutput() {
+		printJSON(newValidationReport(configs, checks))
+		return failed == 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, config := range configs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%s)\n", config.NodeID, config.GetAddress())
+		for _, check := range checks[i] {
+			result, detail := "OK", check.detail
+			if check.err != nil {
+				result, detail = "FAIL", check.err.Error()
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", result, check.name, detail)
+		}
+	}
+	w.Flush()
+
+	switch {
+	case failed == 1:
+		fmt.Println("\n1 check failed")
+	case failed > 1:
+		fmt.Printf("\n%d checks failed\n", failed)
+	case len(configs) == 1:
+		fmt.Println("\nValid: the node can start")
+	default:
+		fmt.Printf("\nValid: the %d nodes can start\n", len(configs))
+	}
+	return failed == 0
+}
+
+// validationReport is the start --validate JSON output
+type validationReport struct {
+	Valid bool                   `json:"valid"`
+	Nodes []nodeValidationReport `json:"nodes"`
+}
+
+type nodeValidationReport struct {
+	NodeID  gossip.NodeID `json:"node_id"`
+	Address string        `json:"address"`
+	Checks  []checkReport `json:"checks"`
+}
+
+type checkReport struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"` // the error if the check failed
+}
+
+func newValidationReport(configs []*node.Config, checks [][]validationCheck) validationReport {
+	report := validationReport{Valid: true}
+	for i, config := range configs {
+		nodeReport := nodeValidationReport{NodeID: config.NodeID, Address: config.GetAddress()}
+		for _, check := range checks[i] {
+			result := checkReport{Name: check.name, OK: check.err == nil, Detail: check.detail}
+			if check.err != nil {
+				result.Detail = check.err.Error()
+				report.Valid = false
+			}
+			nodeReport.Checks = append(nodeReport.Checks, result)
+		}
+		report.Nodes = append(report.Nodes, nodeReport)
+	}
+	return report
+}
+
+// validateConfig runs every check on config. They all run, even after one failed, so the
+// report shows every problem at once.
+func validateConfig(config *node.Config) []validationCheck {
+	checks := []validationCheck{{
+		name:   "config",
+		detail: fmt.Sprintf("cluster %s, gossip every %v, heartbeat every %v", config.ClusterID, config.GossipInterval, config.HeartbeatInterval),
+		err:    config.Validate(),
+	}}
+	checks = append(checks, checkListen(config))
+
+	if config.ClientMode {
+		checks = append(checks, checkResolves("target", config.TargetServer))
+	}
+	for _, seed := range config.Seeds {
+		if seed == config.GetAdvertisedAddress() {
+			checks = append(checks, validationCheck{name: "seed", detail: seed + " is this node"})
+			continue
+		}
+		checks = append(checks, checkResolves("seed", seed))
+	}
+	if len(config.Seeds) == 0 && !config.ClientMode {
+		checks = append(checks, validationCheck{name: "seed", detail: "none, the node starts a new cluster"})
+	}
+
+	checks = append(checks, checkSnitch(config))
+	checks = append(checks, checkTLS(config.TLS))
+	if config.DataDir != "" {
+		checks = append(checks, checkDataDir(config.DataDir))
+	}
+	return checks
+}
+
+// checkSnitch loads the snitch, reading its topology file if it has one
+func checkSnitch(config *node.Config) validationCheck {
+	check := validationCheck{name: "snitch"}
+	s, err := config.LoadSnitch()
+	if err != nil {
+		check.err = err
+		return check
+	}
+	check.detail = fmt.Sprintf("%s puts the node in %s", s.Name(), s.Local())
+	return check
+}
+
+// checkListen binds the node's address, and releases it right away
+func checkListen(config *node.Config) validationCheck {
+	addr := config.GetAddress()
+	check := validationCheck{name: "listen"}
+	if transport.IsUnixAddress(addr) {
+		path := strings.TrimPrefix(addr, "unix://")
+		if _, err := os.Stat(filepath.Dir(path)); err != nil {
+			check.err = err
+		} else if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			check.err = fmt.Errorf("%s is in use by a running node", path)
+		} else {
+			check.detail = path + " is free"
+		}
+		return check
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	bound := listener.Addr().String()
+	listener.Close()
+	check.detail = bound + " is free (tcp)"
+	if config.Port == "0" {
+		check.detail = fmt.Sprintf("%s can be bound, the port is picked when starting", config.Address)
+	}
+
+	if usesUDP(config) {
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			check.err = err
+			return check
+		}
+		conn.Close()
+		check.detail = strings.Replace(check.detail, "(tcp)", "(tcp, udp)", 1)
+	}
+	return check
+}
+
+// usesUDP reports whether config gossips over UDP, which binds a UDP port next to the gRPC one
+func usesUDP(config *node.Config) bool {
+	return reflect.ValueOf(config.Transport).Pointer() == reflect.ValueOf(node.NewUDPTransport).Pointer()
+}
+
+// checkResolves looks up the host of addr (host:port). Unix sockets are only checked to exist.
+func checkResolves(name, addr string) validationCheck {
+	check := validationCheck{name: name}
+	if transport.IsUnixAddress(addr) {
+		if _, err := os.Stat(strings.TrimPrefix(addr, "unix://")); err != nil {
+			check.err = err
+		} else {
+			check.detail = addr + " exists"
+		}
+		return check
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), validateLookupTimeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		check.err = fmt.Errorf("%s: %w", addr, err)
+		return check
+	}
+	check.detail = fmt.Sprintf("%s resolves to %s", addr, strings.Join(ips, ", "))
+	return check
+}
+
+// checkTLS loads the certificate, key and CA like the node would, and checks the
+// certificate is currently valid
+func checkTLS(config *transport.TLSConfig) validationCheck {
+	check := validationCheck{name: "tls"}
+	if !config.Enabled() {
+		check.detail = "disabled, plaintext"
+		return check
+	}
+	if err := config.Validate(); err != nil {
+		check.err = err
+		return check
+	}
+	if _, err := config.ServerCredentials(); err != nil {
+		check.err = err
+		return check
+	}
+	if _, err := config.ClientCredentials(); err != nil {
+		check.err = err
+		return check
+	}
+	if config.CertFile == "" {
+		check.detail = "CA " + config.CAFile + " loaded"
+		return check
+	}
+
+	pair, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		check.err = err
+		return check
+	}
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		check.err = fmt.Errorf("certificate %s is not valid before %s", cert.Subject, cert.NotBefore.Format(time.DateTime))
+	case now.After(cert.NotAfter):
+		check.err = fmt.Errorf("certificate %s expired on %s", cert.Subject, cert.NotAfter.Format(time.DateTime))
+	default:
+		check.detail = fmt.Sprintf("certificate %s, valid until %s", cert.Subject, cert.NotAfter.Format(time.DateOnly))
+		if config.RequireClientCert {
+			check.detail += ", client certificates required"
+		}
+	}
+	return check
+}
+
+// checkDataDir checks the data directory is a writable directory, or can be created
+func checkDataDir(dir string) validationCheck {
+	check := validationCheck{name: "data-dir"}
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		check.detail = dir + " does not exist yet, it is created when starting"
+		return check
+	case err != nil:
+		check.err = err
+		return check
+	case !info.IsDir():
+		check.err = fmt.Errorf("%s is not a directory", dir)
+		return check
+	}
+
+	file, err := os.CreateTemp(dir, ".validate-*")
+	if err != nil {
+		check.err = fmt.Errorf("%s is not writable: %w", dir, err)
+		return check
+	}
+	file.Close()
+	os.Remove(file.Name())
+	check.detail = dir + " is writable"
+	return check
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var statusJSON bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show a running node's view of the cluster",
+	Long: `Show every node a running node knows about, like nodetool status: its ID, address,
+datacenter and rack, whether the failure detector considers it UP or DOWN, its gossiped STATUS, generation and
+heartbeat version, when it was last heard from and its phi. Nodes sharing an ID or an
+address are reported below the table, followed by the node's gossip statistics: rounds
+run, digests and states exchanged, how long the last round took, and how much the last
+exchange found the cluster agreeing (convergence), and the round-trip latency of its
+gossip RPCs to each peer. Last come the read repairs of the key-value reads it coordinated.
+
+Examples:
+  cassandra status --target=127.0.0.1:50051
+
+  # For scripts
+  cassandra status --json | jq '.endpoints[] | select(.state == "DOWN") | .node_id'
+  cassandra status --json | jq '.gossip.convergence'`,
+	Run: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	addAdminFlags(statusCmd)
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the cluster state as JSON, like --output json")
+}
+
+// endpointStatus is one row of the status output
+type endpointStatus struct {
+	NodeID     gossip.NodeID `json:"node_id"`
+	Address    string        `json:"address"`
+	DC         string        `json:"dc"`     // gossiped datacenter, see package snitch
+	Rack       string        `json:"rack"`   // gossiped rack
+	State      string        `json:"state"`  // UP or DOWN
+	Status     string        `json:"status"` // gossiped STATUS, e.g. NORMAL or LEAVING
+	Generation int64         `json:"generation"`
+	Version    int64         `json:"version"`
+	LastSeen   *time.Time    `json:"last_seen,omitempty"` // nil for the local node
+	Phi        float64       `json:"phi"`
+	Local      bool          `json:"local"`
+}
+
+// gossipStatus is the gossip statistics of the status output, see gossip.Stats
+type gossipStatus struct {
+	Rounds          int64               `json:"rounds"`
+	DigestsSent     int64               `json:"digests_sent"`
+	DigestsReceived int64               `json:"digests_received"`
+	StatesPushed    int64               `json:"states_pushed"`
+	StatesPulled    int64               `json:"states_pulled"`
+	LastRoundMs     float64             `json:"last_round_ms"`
+	Convergence     float64             `json:"convergence"` // 0 to 1
+	PeerLatency     []peerLatencyStatus `json:"peer_latency"`
+}
+
+// peerLatencyStatus is the round-trip latency of the gossip RPCs to a peer, see gossip.LatencyHistogram
+type peerLatencyStatus struct {
+	NodeID  gossip.NodeID `json:"node_id"`
+	Count   int64         `json:"count"`
+	MeanMs  float64       `json:"mean_ms"`
+	P50Ms   float64       `json:"p50_ms"`
+	P99Ms   float64       `json:"p99_ms"`
+	MaxMs   float64       `json:"max_ms"`
+	Buckets []int64       `json:"buckets"` // round trips per bucket of gossip.LatencyBuckets, slower ones last
+}
+
+// milliseconds returns d in fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// readRepairStatus is the read repair counters of the status output, see storage.ReadRepairStats
+type readRepairStatus struct {
+	Reads      int64 `json:"reads"`
+	Checked    int64 `json:"checked"`
+	Mismatches int64 `json:"mismatches"`
+	Repaired   int64 `json:"repaired"`
+	Failed     int64 `json:"failed"`
+}
+
+// storageStatus is the KV store of the status output, see storage.Stats
+type storageStatus struct {
+	MemtableKeys  int   `json:"memtable_keys"`
+	MemtableBytes int64 `json:"memtable_bytes"`
+	SSTables      int   `json:"sstables"`
+	SSTableBytes  int64 `json:"sstable_bytes"`
+	Flushes       int64 `json:"flushes"`
+	Compactions   int64 `json:"compactions"`
+}
+
+// clusterStatus is the status output
+type clusterStatus struct {
+	NodeID     gossip.NodeID    `json:"node_id"`
+	ClusterID  string           `json:"cluster_id"`
+	Endpoints  []endpointStatus `json:"endpoints"`
+	Conflicts  []string         `json:"conflicts"`
+	Gossip     gossipStatus     `json:"gossip"`
+	ReadRepair readRepairStatus `json:"read_repair"`
+	Storage    storageStatus    `json:"storage"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := client.GetClusterState(ctx)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get cluster state from %s: %v", adminTarget, err)
+	}
+
+	status := newClusterStatus(state)
+	if statusJSON || jsonOutput() {
+		printJSON(status)
+		return
+	}
+	printClusterStatus(status, time.Now())
+}
+
+func newClusterStatus(state transport.ClusterState) clusterStatus {
+	status := clusterStatus{
+		NodeID:    state.NodeID,
+		ClusterID: state.ClusterID,
+		Endpoints: make([]endpointStatus, 0, len(state.Endpoints)),
+		Conflicts: make([]string, 0, len(state.Conflicts)),
+		Gossip: gossipStatus{
+			Rounds:          state.Stats.Rounds,
+			DigestsSent:     state.Stats.DigestsSent,
+			DigestsReceived: state.Stats.DigestsReceived,
+			StatesPushed:    state.Stats.StatesPushed,
+			StatesPulled:    state.Stats.StatesPulled,
+			LastRoundMs:     milliseconds(state.Stats.LastRoundDuration),
+			Convergence:     state.Stats.Convergence,
+			PeerLatency:     make([]peerLatencyStatus, 0, len(state.Stats.PeerLatency)),
+		},
+		ReadRepair: readRepairStatus(state.ReadRepair),
+		Storage:    storageStatus(state.Storage),
+	}
+	for id, h := range state.Stats.PeerLatency {
+		status.Gossip.PeerLatency = append(status.Gossip.PeerLatency, peerLatencyStatus{
+			NodeID:  id,
+			Count:   h.Count,
+			MeanMs:  milliseconds(h.Mean()),
+			P50Ms:   milliseconds(h.Quantile(0.5)),
+			P99Ms:   milliseconds(h.Quantile(0.99)),
+			MaxMs:   milliseconds(h.Max),
+			Buckets: h.Buckets,
+		})
+	}
+	sort.Slice(status.Gossip.PeerLatency, func(i, j int) bool {
+		return status.Gossip.PeerLatency[i].NodeID < status.Gossip.PeerLatency[j].NodeID
+	})
+	for _, endpoint := range state.Endpoints {
+		row := endpointStatus{
+			NodeID:     endpoint.State.HeartbeatState.NodeID,
+			State:      "DOWN",
+			Generation: endpoint.State.HeartbeatState.Generation,
+			Version:    endpoint.State.HeartbeatState.Version,
+			Phi:        endpoint.Phi,
+			Local:      endpoint.Local,
+		}
+		if endpoint.Alive {
+			row.State = "UP"
+		}
+		if addr, ok := endpoint.State.ApplicationStates[gossip.AppHeartbeat]; ok {
+			row.Address = addr.Value
+		}
+		if appStatus, ok := endpoint.State.ApplicationStates[gossip.AppStatus]; ok {
+			row.Status = appStatus.Value
+		}
+		row.DC = endpoint.State.ApplicationStates[gossip.AppDC].Value
+		row.Rack = endpoint.State.ApplicationStates[gossip.AppRack].Value
+		if !endpoint.Local && endpoint.UpdateTimestamp > 0 {
+			lastSeen := time.Unix(endpoint.UpdateTimestamp, 0)
+			row.LastSeen = &lastSeen
+		}
+		status.Endpoints = append(status.Endpoints, row)
+	}
+	for _, conflict := range state.Conflicts {
+		status.Conflicts = append(status.Conflicts, conflict.String())
+	}
+	return status
+}
+
+func printClusterStatus(status clusterStatus, now time.Time) {
+	fmt.Printf("Cluster: %s (as seen by %s)\n\n", status.ClusterID, status.NodeID)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tADDRESS\tDC\tRACK\tSTATE\tSTATUS\tGENERATION\tVERSION\tLAST SEEN\tPHI")
+	for _, row := range status.Endpoints {
+		nodeID := string(row.NodeID)
+		lastSeen := "-"
+		phi := "-"
+		dc, rack := "-", "-" // not gossiped by older nodes
+		if row.DC != "" {
+			dc, rack = row.DC, row.Rack
+		}
+		if row.Local {
+			nodeID += " (local)"
+		} else {
+			phi = fmt.Sprintf("%.2f", row.Phi)
+			if row.LastSeen != nil {
+				lastSeen = fmt.Sprintf("%v ago", now.Sub(*row.LastSeen).Round(time.Second))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			nodeID, row.Address, dc, rack, row.State, row.Status, row.Generation, row.Version, lastSeen, phi)
+	}
+	w.Flush()
+
+	if len(status.Conflicts) > 0 {
+		fmt.Println("\nConflicts:")
+		for _, conflict := range status.Conflicts {
+			fmt.Printf("  %s\n", conflict)
+		}
+	}
+
+	g := status.Gossip
+	fmt.Printf("\nGossip: %d rounds, last took %.1fms\n", g.Rounds, g.LastRoundMs)
+	fmt.Printf("  Digests: %d sent, %d received\n", g.DigestsSent, g.DigestsReceived)
+	fmt.Printf("  States:  %d pushed, %d pulled\n", g.StatesPushed, g.StatesPulled)
+	if g.DigestsSent+g.DigestsReceived == 0 {
+		fmt.Println("  Convergence: - (no exchange yet)")
+	} else {
+		fmt.Printf("  Convergence: %.0f%%\n", g.Convergence*100)
+	}
+
+	if len(g.PeerLatency) > 0 {
+		fmt.Println("\nRound-trip latency:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  PEER\tRPCS\tMEAN\tP50\tP99\tMAX")
+		for _, peer := range g.PeerLatency {
+			fmt.Fprintf(w, "  %s\t%d\t%.2fms\t%.2fms\t%.2fms\t%.2fms\n",
+				peer.NodeID, peer.Count, peer.MeanMs, peer.P50Ms, peer.P99Ms, peer.MaxMs)
+		}
+		w.Flush()
+	}
+
+	r := status.ReadRepair
+	fmt.Printf("\nRead repair: %d reads, %d checked on every replica\n", r.Reads, r.Checked)
+	fmt.Printf("  Mismatches: %d, %d replicas repaired, %d failed\n", r.Mismatches, r.Repaired, r.Failed)
+
+	s := status.Storage
+	fmt.Printf("\nStorage: %d keys in the memtable (%d bytes), %d SSTables (%d bytes)\n",
+		s.MemtableKeys, s.MemtableBytes, s.SSTables, s.SSTableBytes)
+	fmt.Printf("  Flushes: %d, compactions: %d\n", s.Flushes, s.Compactions)
+}
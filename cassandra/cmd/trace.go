@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace ID",
+	Short: "Print the events of a traced key-value request",
+	Long: `Print the events of a key-value request traced with 'cassandra kv ... --trace', like
+Cassandra's query tracing: when the node at --target, which coordinated it, received it,
+which replicas it sent it to, when each answered or failed, when enough had answered for
+the consistency level, and the read repairs it triggered. Events recorded after the client
+got its answer, such as replicas answering late and repairs, are included, so a trace
+printed again may have grown.
+
+ELAPSED is the time since the coordinator received the request, and SOURCE the node the
+event is about. Nodes keep the traces of their latest 1000 traced requests.
+
+Examples:
+  cassandra kv get greeting --cl=QUORUM --trace
+  cassandra trace 3f9c2a7b0d4e1f86 --target=127.0.0.1:50051
+
+  # For scripts
+  cassandra trace 3f9c2a7b0d4e1f86 --output json | jq -r '.events[].activity'`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTrace,
+}
+
+func init() {
+	rootCmd.AddCommand(traceCmd)
+	addAdminFlags(traceCmd)
+}
+
+// traceOutput is the JSON output of trace
+type traceOutput struct {
+	ID          string             `json:"id"`
+	Request     string             `json:"request"`
+	Coordinator gossip.NodeID      `json:"coordinator"`
+	StartedAtMs int64              `json:"started_at_ms"` // unix milliseconds
+	Events      []traceEventOutput `json:"events"`
+}
+
+type traceEventOutput struct {
+	ElapsedUs int64         `json:"elapsed_us"` // since the coordinator received the request
+	Source    gossip.NodeID `json:"source"`
+	Activity  string        `json:"activity"`
+}
+
+func runTrace(cmd *cobra.Command, args []string) {
+	client := dialKV()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	trace, err := client.GetTrace(ctx, args[0])
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to get trace %s from %s: %v", args[0], adminTarget, err)
+	}
+	if jsonOutput() {
+		output := traceOutput{ID: trace.ID, Request: trace.Request, Coordinator: trace.Coordinator,
+			StartedAtMs: trace.Started.UnixMilli(), Events: []traceEventOutput{}}
+		for _, event := range trace.Events {
+			output.Events = append(output.Events, traceEventOutput{ElapsedUs: event.Elapsed.Microseconds(),
+				Source: event.Source, Activity: event.Activity})
+		}
+		printJSON(output)
+		return
+	}
+	printTrace(cmd.OutOrStdout(), trace)
+}
+
+// printTrace prints the events of trace as a table
+func printTrace(out io.Writer, trace transport.Trace) {
+	fmt.Fprintf(out, "Trace %s: %s, coordinated by %s at %s\n\n", trace.ID, trace.Request, trace.Coordinator,
+		trace.Started.Format("15:04:05.000"))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ELAPSED\tSOURCE\tACTIVITY")
+	for _, event := range trace.Events {
+		fmt.Fprintf(w, "%v\t%s\t%s\n", event.Elapsed.Round(time.Microsecond), event.Source, event.Activity)
+	}
+	w.Flush()
+}
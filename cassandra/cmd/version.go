@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, set when building a release:
+//
+//	go build -ldflags "-X github.com/adamgarcia4/goLearning/cassandra/cmd.version=v1.0.0 \
+//	  -X github.com/adamgarcia4/goLearning/cassandra/cmd.commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/adamgarcia4/goLearning/cassandra/cmd.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Whatever isn't set is taken from the build info the Go toolchain embeds, if any.
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of this binary",
+	Long: `Print the version, commit and build date of this binary, and the Go version it was built with.
+
+Examples:
+  cassandra version
+  cassandra version --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		info := readBuildInfo()
+		if jsonOutput() {
+			printJSON(info)
+			return
+		}
+		fmt.Println(info)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
+
+// buildInfo describes the build of this binary
+type buildInfo struct {
+	Version  string `json:"version"`
+	Commit   string `json:"commit,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Go       string `json:"go"`
+	Platform string `json:"platform"`
+}
+
+// readBuildInfo returns the build information set with -ldflags, completed from the
+// build info embedded by the Go toolchain
+func readBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:  version,
+		Commit:   commit,
+		Date:     date,
+		Go:       runtime.Version(),
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	dirty := false
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && embedded.Main.Version != "(devel)" {
+			info.Version = embedded.Main.Version
+		}
+		for _, setting := range embedded.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value[:min(len(setting.Value), 7)]
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			case setting.Key == "vcs.modified":
+				dirty = setting.Value == "true" && commit == ""
+			}
+		}
+	}
+	if dirty && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+}
+
+// String describes the build, e.g.
+// "cassandra v1.0.0 (commit 1a2b3c4, built 2026-01-02T15:04:05Z) go1.25.0 linux/amd64"
+func (b buildInfo) String() string {
+	s := "cassandra " + b.Version
+	if b.Commit != "" || b.Date != "" {
+		s += fmt.Sprintf(" (commit %s, built %s)", orUnknown(b.Commit), orUnknown(b.Date))
+	}
+	return fmt.Sprintf("%s %s %s", s, b.Go, b.Platform)
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// watchRedrawInterval is how often --table redraws at most
+const watchRedrawInterval = 500 * time.Millisecond
+
+var (
+	watchTable      bool
+	watchHeartbeats bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream a running node's view of the cluster as it changes",
+	Long: `Follow a running node's view of the cluster through its WatchClusterState stream. Every
+change is printed as it happens: nodes discovered, going UP or DOWN, restarting with a new
+generation and application states changing. Heartbeat-only changes are left out unless
+--heartbeats is given. With --table the whole cluster is redrawn as a table instead.
+Stop it with Ctrl+C.
+
+Examples:
+  cassandra watch --target=127.0.0.1:50051
+  cassandra watch --table`,
+	Run: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	addAdminFlags(watchCmd)
+	watchCmd.Flags().BoolVar(&watchTable, "table", false, "Redraw a table of every endpoint instead of printing changes")
+	watchCmd.Flags().BoolVar(&watchHeartbeats, "heartbeats", false, "Also print changes that only bump a heartbeat")
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	client := dialAdmin()
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	updates, err := client.WatchClusterState(ctx)
+	if err != nil {
+		fatalf(rpcExitCode(err), "failed to watch %s: %v", adminTarget, err)
+	}
+
+	known := make(map[gossip.NodeID]gossip.StateUpdate)
+	redraw := time.NewTicker(watchRedrawInterval)
+	defer redraw.Stop()
+	dirty := false
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				fatalf(exitConnection, "lost the stream from %s", adminTarget)
+			}
+			nodeID := update.State.HeartbeatState.NodeID
+			previous, seen := known[nodeID]
+			known[nodeID] = update
+			if watchTable {
+				dirty = true
+			} else if line := describeUpdate(previous, seen, update); line != "" {
+				fmt.Printf("%s %s %s\n", time.Now().Format("15:04:05.000"), nodeID, line)
+			}
+		case <-redraw.C:
+			if dirty {
+				drawWatchTable(known)
+				dirty = false
+			}
+		}
+	}
+}
+
+// describeUpdate summarizes what changed between previous and update, or returns "" if only
+// the heartbeat did and --heartbeats isn't set
+func describeUpdate(previous gossip.StateUpdate, seen bool, update gossip.StateUpdate) string {
+	heartbeat := update.State.HeartbeatState
+	liveness := "DOWN"
+	if update.Alive {
+		liveness = "UP"
+	}
+	if !seen {
+		return fmt.Sprintf("discovered, %s, generation %d, version %d%s",
+			liveness, heartbeat.Generation, heartbeat.Version, formatAppStates(update.State.ApplicationStates, nil))
+	}
+	if heartbeat.Generation != previous.State.HeartbeatState.Generation {
+		return fmt.Sprintf("restarted, %s, generation %d%s",
+			liveness, heartbeat.Generation, formatAppStates(update.State.ApplicationStates, nil))
+	}
+
+	var changes []string
+	if update.Alive != previous.Alive {
+		changes = append(changes, "is now "+liveness)
+	}
+	if states := formatAppStates(update.State.ApplicationStates, previous.State.ApplicationStates); states != "" {
+		changes = append(changes, strings.TrimPrefix(states, ", "))
+	}
+	if len(changes) == 0 {
+		if !watchHeartbeats || heartbeat.Version == previous.State.HeartbeatState.Version {
+			return ""
+		}
+		return fmt.Sprintf("heartbeat %d", heartbeat.Version)
+	}
+	return strings.Join(changes, ", ")
+}
+
+// formatAppStates lists the application states that are new or newer than in previous,
+// sorted by key, as ", KEY=value (vN)..."
+func formatAppStates(states, previous map[gossip.AppStateKey]gossip.AppState) string {
+	keys := make([]gossip.AppStateKey, 0, len(states))
+	for key, state := range states {
+		if old, ok := previous[key]; !ok || state.Version > old.Version {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, ", %s=%s (v%d)", key, states[key].Value, states[key].Version)
+	}
+	return b.String()
+}
+
+// drawWatchTable clears the terminal and prints every endpoint
+func drawWatchTable(known map[gossip.NodeID]gossip.StateUpdate) {
+	nodeIDs := make([]gossip.NodeID, 0, len(known))
+	for nodeID := range known {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+
+	fmt.Print("\033[H\033[2J")
+	fmt.Printf("Watching %s, %s\n\n", adminTarget, time.Now().Format("15:04:05"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tADDRESS\tSTATE\tSTATUS\tGENERATION\tVERSION")
+	for _, nodeID := range nodeIDs {
+		update := known[nodeID]
+		liveness := "DOWN"
+		if update.Alive {
+			liveness = "UP"
+		}
+		states := update.State.ApplicationStates
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\n", nodeID, states[gossip.AppHeartbeat].Value, liveness,
+			states[gossip.AppStatus].Value, update.State.HeartbeatState.Generation, update.State.MaxVersion())
+	}
+	w.Flush()
+}
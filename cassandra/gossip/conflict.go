@@ -0,0 +1,164 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// conflictRetention is how long a conflict is reported after it was last observed
+const conflictRetention = time.Minute
+
+var (
+	// ErrSelfConnection is returned for a SYN this node sent to itself, e.g. through a seed
+	// that is its own address in another form.
+	ErrSelfConnection = errors.New("gossip from this node to itself")
+	// ErrDuplicateNodeID is returned for a SYN from another node using this node's ID.
+	ErrDuplicateNodeID = errors.New("node ID is already used by another node")
+)
+
+// ConflictKind is the kind of identity conflict found in the cluster
+type ConflictKind int
+
+const (
+	ConflictDuplicateNodeID  ConflictKind = iota + 1 // one NodeID gossiped from several addresses
+	ConflictDuplicateAddress                         // one address gossiped by several live NodeIDs
+)
+
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictDuplicateNodeID:
+		return "duplicate-node-id"
+	case ConflictDuplicateAddress:
+		return "duplicate-address"
+	default:
+		return fmt.Sprintf("ConflictKind(%d)", int(k))
+	}
+}
+
+// Conflict is two nodes that can't both be right about who they are. StateByNode keeps one
+// state per NodeID, so the losing side of a conflict isn't merged; the conflict is kept here instead.
+type Conflict struct {
+	Kind      ConflictKind
+	NodeIDs   []NodeID // for a duplicate address, every node claiming it
+	Addresses []string // for a duplicate NodeID, every address it was seen at
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+func (c Conflict) String() string {
+	ids := make([]string, len(c.NodeIDs))
+	for i, id := range c.NodeIDs {
+		ids[i] = string(id)
+	}
+	return fmt.Sprintf("%s: node %s at %s", c.Kind, strings.Join(ids, ", "), strings.Join(c.Addresses, ", "))
+}
+
+// Conflicts returns the conflicts observed within the last minute, oldest first.
+func (g *GossipState) Conflicts() []Conflict {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	conflicts := make([]Conflict, 0, len(g.conflicts))
+	for _, conflict := range g.conflicts {
+		conflicts = append(conflicts, *conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].FirstSeen.Before(conflicts[j].FirstSeen)
+	})
+	return conflicts
+}
+
+// CheckSender rejects a SYN whose sender can't be who it claims: ErrSelfConnection if it is
+// from this node, ErrDuplicateNodeID (recorded as a conflict) if it uses our ID, or the ID of a
+// node we know at another address without having restarted since.
+func (g *GossipState) CheckSender(syn SynMessage, now time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var known string
+	if syn.SenderID == g.nodeID {
+		known = g.localAppStates[AppHeartbeat].Value
+		if syn.SenderAddress == "" || syn.SenderAddress == known {
+			return ErrSelfConnection
+		}
+	} else {
+		state, ok := g.stateByNode[syn.SenderID]
+		if !ok {
+			return nil
+		}
+		known = state.applicationStates[AppHeartbeat].Value
+		for _, digest := range syn.Digests {
+			if digest.NodeID == syn.SenderID && digest.Generation > state.HeartbeatState.Generation {
+				// Restarted, possibly at a new address
+				return nil
+			}
+		}
+	}
+
+	if known == "" || syn.SenderAddress == "" || syn.SenderAddress == known {
+		return nil
+	}
+	g.reportConflictLocked(ConflictDuplicateNodeID, []NodeID{syn.SenderID}, []string{known, syn.SenderAddress}, now)
+	return fmt.Errorf("%w: %s is at %s, not %s", ErrDuplicateNodeID, syn.SenderID, known, syn.SenderAddress)
+}
+
+// checkDuplicateNodeIDLocked records a conflict if remote, a state for nodeID we won't merge,
+// was gossiped from another address than the one we know nodeID by. Caller must hold g.mu.
+func (g *GossipState) checkDuplicateNodeIDLocked(nodeID NodeID, known string, remote EndpointStateSnapshot, now time.Time) {
+	address := remote.ApplicationStates[AppHeartbeat].Value
+	if known == "" || address == "" || address == known {
+		return
+	}
+	g.reportConflictLocked(ConflictDuplicateNodeID, []NodeID{nodeID}, []string{known, address}, now)
+}
+
+// checkDuplicateAddressesLocked records a conflict for every address claimed by more than one
+// live node. Only endpoints we heard a fresh heartbeat from count, so a stale state for a node
+// that was replaced at the same address isn't mistaken for a conflict. Caller must hold g.mu.
+func (g *GossipState) checkDuplicateAddressesLocked(now time.Time) {
+	byAddress := make(map[string][]NodeID)
+	if local := g.localAppStates[AppHeartbeat].Value; local != "" {
+		byAddress[local] = append(byAddress[local], g.nodeID)
+	}
+	for id, state := range g.stateByNode {
+		address := state.applicationStates[AppHeartbeat].Value
+		if address == "" || !state.isAlive || !state.heardFresh || state.applicationStates[AppStatus].Value == StatusLeft {
+			continue
+		}
+		byAddress[address] = append(byAddress[address], id)
+	}
+
+	for address, ids := range byAddress {
+		if len(ids) > 1 {
+			g.reportConflictLocked(ConflictDuplicateAddress, ids, []string{address}, now)
+		}
+	}
+}
+
+// reportConflictLocked records a conflict, logging it the first time. Caller must hold g.mu.
+func (g *GossipState) reportConflictLocked(kind ConflictKind, nodeIDs []NodeID, addresses []string, now time.Time) {
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+	sort.Strings(addresses)
+
+	conflict := Conflict{Kind: kind, NodeIDs: nodeIDs, Addresses: addresses, FirstSeen: now, LastSeen: now}
+	key := conflict.String()
+	if existing, ok := g.conflicts[key]; ok {
+		existing.LastSeen = now
+		return
+	}
+	g.conflicts[key] = &conflict
+	g.log.Warnf("CONFLICT %s. Give every node a unique ID and address", key)
+}
+
+// expireConflictsLocked forgets conflicts that haven't been seen for conflictRetention. Caller must hold g.mu.
+func (g *GossipState) expireConflictsLocked(now time.Time) {
+	for key, conflict := range g.conflicts {
+		if now.Sub(conflict.LastSeen) >= conflictRetention {
+			delete(g.conflicts, key)
+			g.log.Printf("Conflict resolved: %s", key)
+		}
+	}
+}
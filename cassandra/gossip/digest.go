@@ -0,0 +1,78 @@
+package gossip
+
+/*
+*
+Three-way gossip exchange.
+
+Reference: https://github.com/apache/cassandra/blob/trunk/src/java/org/apache/cassandra/gms/GossipDigestSynVerbHandler.java
+
+	Initiator                                   Responder
+	    |  SYN  (digests: node, generation, maxVersion) |
+	    | ----------------------------------------------> | HandleSyn: compare digests with local view
+	    |  ACK  (states responder has newer,              |
+	    |        digests responder wants)                 |
+	    | <---------------------------------------------- |
+	    | HandleAck: merge states, build requested deltas |
+	    |  ACK2 (states the responder asked for)          |
+	    | ----------------------------------------------> | HandleAck2: merge states
+
+These types are protocol-agnostic; the transport layer converts them to and from its wire format.
+*/
+
+// GossipDigest summarizes what a node knows about one endpoint.
+type GossipDigest struct {
+	NodeID     NodeID
+	Generation int64
+	MaxVersion int64 // highest heartbeat or application state version seen
+}
+
+// EndpointStateSnapshot is a copy of an EndpointState that is safe to send over the network.
+type EndpointStateSnapshot struct {
+	HeartbeatState    HeartbeatStateSnapshot
+	ApplicationStates map[AppStateKey]AppState
+}
+
+// MaxVersion returns the highest version across the heartbeat and all application states.
+func (s EndpointStateSnapshot) MaxVersion() int64 {
+	max := s.HeartbeatState.Version
+	for _, state := range s.ApplicationStates {
+		if state.Version > max {
+			max = state.Version
+		}
+	}
+	return max
+}
+
+// since returns a copy containing the heartbeat and only the application states newer than version.
+func (s EndpointStateSnapshot) since(version int64) EndpointStateSnapshot {
+	delta := EndpointStateSnapshot{
+		HeartbeatState:    s.HeartbeatState,
+		ApplicationStates: make(map[AppStateKey]AppState),
+	}
+	for key, state := range s.ApplicationStates {
+		if state.Version > version {
+			delta.ApplicationStates[key] = state
+		}
+	}
+	return delta
+}
+
+// SynMessage starts a gossip round (GOSSIP_DIGEST_SYN).
+type SynMessage struct {
+	ClusterID     string
+	SenderID      NodeID
+	SenderAddress string
+	Digests       []GossipDigest
+}
+
+// AckMessage answers a SYN (GOSSIP_DIGEST_ACK).
+type AckMessage struct {
+	Requests []GossipDigest          // endpoints the responder wants newer state for
+	States   []EndpointStateSnapshot // endpoints the responder has newer state for
+}
+
+// Ack2Message completes a gossip round (GOSSIP_DIGEST_ACK2).
+type Ack2Message struct {
+	SenderID NodeID
+	States   []EndpointStateSnapshot
+}
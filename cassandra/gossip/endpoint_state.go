@@ -1,5 +1,7 @@
 package gossip
 
+import "time"
+
 /**
 This is the per-node snapshot that ties everything together.
 Represents both the heartbeat state and the ApplicationState in an EndpointState object.
@@ -27,6 +29,79 @@ type EndpointState struct {
 
 	isAlive         bool
 	updateTimestamp int64
+	leftSince       time.Time // when RemoveLeft first saw STATUS LEFT
+	heardFresh      bool      // a newer heartbeat arrived after the endpoint was discovered
+	heardVersion    int64     // highest heartbeat version the node sent us directly, see applyHeartbeat
 	// phi (float64) - Failure detection metric (phi accrual)
 	// phi float64
 }
+
+// newEndpointStateFromSnapshot creates an EndpointState for a node first seen (or restarted) with the given state.
+func newEndpointStateFromSnapshot(snapshot EndpointStateSnapshot, now int64) *EndpointState {
+	appStates := make(map[AppStateKey]AppState, len(snapshot.ApplicationStates))
+	for k, v := range snapshot.ApplicationStates {
+		appStates[k] = v
+	}
+	return &EndpointState{
+		HeartbeatState:    snapshot.HeartbeatState,
+		applicationStates: appStates,
+		isAlive:           true,
+		updateTimestamp:   now,
+	}
+}
+
+// IsAlive reports whether the node was considered alive at the last update.
+func (e *EndpointState) IsAlive() bool {
+	return e.isAlive
+}
+
+// UpdateTimestamp returns the unix time (seconds) we last heard from this node.
+func (e *EndpointState) UpdateTimestamp() int64 {
+	return e.updateTimestamp
+}
+
+// GetApplicationState returns the application state for key, if present.
+func (e *EndpointState) GetApplicationState(key AppStateKey) (AppState, bool) {
+	state, ok := e.applicationStates[key]
+	return state, ok
+}
+
+// GetApplicationStates returns a copy of all application states.
+func (e *EndpointState) GetApplicationStates() map[AppStateKey]AppState {
+	appStates := make(map[AppStateKey]AppState, len(e.applicationStates))
+	for k, v := range e.applicationStates {
+		appStates[k] = v
+	}
+	return appStates
+}
+
+// mergeApplicationStates keeps the higher-versioned value for every key and reports whether anything changed.
+func (e *EndpointState) mergeApplicationStates(remote map[AppStateKey]AppState) bool {
+	changed := false
+	for key, state := range remote {
+		if local, ok := e.applicationStates[key]; ok && local.Version >= state.Version {
+			continue
+		}
+		e.applicationStates[key] = state
+		changed = true
+	}
+	return changed
+}
+
+// snapshot returns a copy of the heartbeat and application states that is safe to send over the network.
+func (e *EndpointState) snapshot() EndpointStateSnapshot {
+	return EndpointStateSnapshot{
+		HeartbeatState:    e.HeartbeatState,
+		ApplicationStates: e.GetApplicationStates(),
+	}
+}
+
+// copy returns a deep copy of the EndpointState that is safe to hand out to callers.
+func (e *EndpointState) copy() EndpointState {
+	return EndpointState{
+		HeartbeatState:    e.HeartbeatState,
+		applicationStates: e.GetApplicationStates(),
+		isAlive:           e.isAlive,
+		updateTimestamp:   e.updateTimestamp,
+	}
+}
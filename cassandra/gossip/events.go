@@ -0,0 +1,56 @@
+package gossip
+
+import "time"
+
+// EndpointEventKind is what happened to a remote endpoint, like the callbacks of
+// Cassandra's IEndpointStateChangeSubscriber (onJoin, onAlive, onDead, onChange, onRemove).
+type EndpointEventKind int
+
+const (
+	EndpointDiscovered EndpointEventKind = iota + 1 // first heard of, or restarted with a new generation
+	EndpointUp                                      // the failure detector considers it alive again
+	EndpointDown                                    // the failure detector convicted it, or it shut down
+	EndpointChanged                                 // newer heartbeat or application states were merged
+	EndpointRemoved                                 // removed, see RemoveEndpoint and RemoveLeft
+)
+
+func (k EndpointEventKind) String() string {
+	switch k {
+	case EndpointDiscovered:
+		return "discovered"
+	case EndpointUp:
+		return "up"
+	case EndpointDown:
+		return "down"
+	case EndpointChanged:
+		return "changed"
+	case EndpointRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// EndpointEvent reports a change to a remote endpoint
+type EndpointEvent struct {
+	Kind       EndpointEventKind
+	NodeID     NodeID
+	Generation int64
+	Time       time.Time
+}
+
+// OnEndpointEvent registers fn to be called for every endpoint event. fn is called with the
+// state locked, so it must return quickly and must not call back into the GossipState.
+func (g *GossipState) OnEndpointEvent(fn func(EndpointEvent)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.listeners = append(g.listeners, fn)
+}
+
+// publishLocked calls the endpoint listeners. Caller must hold g.mu.
+func (g *GossipState) publishLocked(kind EndpointEventKind, nodeID NodeID, generation int64, now time.Time) {
+	event := EndpointEvent{Kind: kind, NodeID: nodeID, Generation: generation, Time: now}
+	for _, fn := range g.listeners {
+		fn(event)
+	}
+}
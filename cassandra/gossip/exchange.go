@@ -0,0 +1,365 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// TickHeartbeat bumps the local heartbeat version, marking the start of a gossip round.
+func (g *GossipState) TickHeartbeat() HeartbeatStateSnapshot {
+	heartbeat := g.myHeartbeatState.UpdateHeartbeat()
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	g.notifyEndpointLocked(g.nodeID)
+	return heartbeat
+}
+
+// SetLocalAppState sets an application state on the local endpoint and returns it with its version.
+// The value is versioned from the same counter as the heartbeat so peers can order updates.
+func (g *GossipState) SetLocalAppState(key AppStateKey, value string) AppState {
+	version := g.myHeartbeatState.UpdateHeartbeat().Version
+	state := AppState{Value: value, Version: version}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.localAppStates[key] = state
+	g.notifyEndpointLocked(g.nodeID)
+	return state
+}
+
+// GetLocalAppState returns the local application state for key, if set.
+func (g *GossipState) GetLocalAppState(key AppStateKey) (AppState, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	state, ok := g.localAppStates[key]
+	return state, ok
+}
+
+// LocalEndpointState returns a snapshot of the local node's heartbeat and application states.
+func (g *GossipState) LocalEndpointState() EndpointStateSnapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.localSnapshotLocked()
+}
+
+func (g *GossipState) localSnapshotLocked() EndpointStateSnapshot {
+	appStates := make(map[AppStateKey]AppState, len(g.localAppStates))
+	for k, v := range g.localAppStates {
+		appStates[k] = v
+	}
+	return EndpointStateSnapshot{
+		HeartbeatState:    g.myHeartbeatState.GetSnapshot(),
+		ApplicationStates: appStates,
+	}
+}
+
+// snapshotLocked returns what we know about nodeID, including ourselves. Caller must hold g.mu.
+func (g *GossipState) snapshotLocked(nodeID NodeID) (EndpointStateSnapshot, bool) {
+	if nodeID == g.nodeID {
+		return g.localSnapshotLocked(), true
+	}
+	state, ok := g.stateByNode[nodeID]
+	if !ok {
+		return EndpointStateSnapshot{}, false
+	}
+	return state.snapshot(), true
+}
+
+// Digests returns a digest for every endpoint we know about, starting with ourselves.
+func (g *GossipState) Digests() []GossipDigest {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	local := g.localSnapshotLocked()
+	digests := make([]GossipDigest, 0, len(g.stateByNode)+1)
+	digests = append(digests, GossipDigest{
+		NodeID:     g.nodeID,
+		Generation: local.HeartbeatState.Generation,
+		MaxVersion: local.MaxVersion(),
+	})
+	for id, state := range g.stateByNode {
+		snapshot := state.snapshot()
+		digests = append(digests, GossipDigest{
+			NodeID:     id,
+			Generation: snapshot.HeartbeatState.Generation,
+			MaxVersion: snapshot.MaxVersion(),
+		})
+	}
+	return digests
+}
+
+// HandleSyn compares the initiator's digests with the local view and builds the ACK:
+// states we have that are newer than the initiator's, and digests for states we want.
+func (g *GossipState) HandleSyn(syn SynMessage) AckMessage {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ack := AckMessage{}
+	mentioned := make(map[NodeID]bool, len(syn.Digests))
+
+	for _, digest := range syn.Digests {
+		mentioned[digest.NodeID] = true
+
+		local, known := g.snapshotLocked(digest.NodeID)
+		if !known {
+			// Never heard of this node, ask for everything
+			ack.Requests = append(ack.Requests, GossipDigest{NodeID: digest.NodeID, Generation: digest.Generation})
+			continue
+		}
+
+		localGeneration := local.HeartbeatState.Generation
+		localMaxVersion := local.MaxVersion()
+		switch {
+		case digest.Generation > localGeneration:
+			// The node restarted since we last heard from it; we only ask about others, never ourselves
+			if digest.NodeID != g.nodeID {
+				ack.Requests = append(ack.Requests, GossipDigest{NodeID: digest.NodeID, Generation: digest.Generation})
+			}
+		case digest.Generation < localGeneration:
+			ack.States = append(ack.States, local)
+		case digest.MaxVersion > localMaxVersion:
+			if digest.NodeID != g.nodeID {
+				ack.Requests = append(ack.Requests, GossipDigest{NodeID: digest.NodeID, Generation: localGeneration, MaxVersion: localMaxVersion})
+			}
+		case digest.MaxVersion < localMaxVersion:
+			ack.States = append(ack.States, local.since(digest.MaxVersion))
+		}
+	}
+
+	// Anything the initiator didn't mention, it doesn't know about yet
+	compared := len(mentioned)
+	if !mentioned[g.nodeID] {
+		ack.States = append(ack.States, g.localSnapshotLocked())
+		compared++
+	}
+	for id, state := range g.stateByNode {
+		if !mentioned[id] {
+			ack.States = append(ack.States, state.snapshot())
+			compared++
+		}
+	}
+
+	g.stats.digestsReceived.Add(int64(len(syn.Digests)))
+	g.stats.statesPushed.Add(int64(len(ack.States)))
+	g.recordAgreement(compared, len(ack.States)+len(ack.Requests))
+	return ack
+}
+
+// HandleAck merges the states in the responder's ACK and builds the ACK2 with the states it requested.
+// What the merge logs carries the trace ID of ctx, see logger.WithTraceID.
+func (g *GossipState) HandleAck(ctx context.Context, ack AckMessage) Ack2Message {
+	g.applyStates(g.log.WithContext(ctx), ack.States, time.Now())
+	g.stats.statesPulled.Add(int64(len(ack.States)))
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Having merged the ACK, we know every endpoint either node knew
+	g.recordAgreement(len(g.stateByNode)+1, len(ack.States)+len(ack.Requests))
+
+	ack2 := Ack2Message{SenderID: g.nodeID}
+	for _, request := range ack.Requests {
+		local, known := g.snapshotLocked(request.NodeID)
+		if !known {
+			continue
+		}
+		switch {
+		case local.HeartbeatState.Generation == request.Generation:
+			ack2.States = append(ack2.States, local.since(request.MaxVersion))
+		case local.HeartbeatState.Generation > request.Generation:
+			ack2.States = append(ack2.States, local)
+		}
+	}
+	g.stats.statesPushed.Add(int64(len(ack2.States)))
+	return ack2
+}
+
+// HandleAck2 merges the states the initiator sent to complete the round.
+// What the merge logs carries the trace ID of ctx, see logger.WithTraceID.
+func (g *GossipState) HandleAck2(ctx context.Context, ack2 Ack2Message) {
+	g.applyStates(g.log.WithContext(ctx), ack2.States, time.Now())
+	g.stats.statesPulled.Add(int64(len(ack2.States)))
+}
+
+// applyStates merges remote endpoint states into StateByNode, logging with log, and returns
+// how many were accepted.
+func (g *GossipState) applyStates(log *logger.SubLogger, states []EndpointStateSnapshot, now time.Time) int {
+	applied := 0
+	for _, state := range states {
+		if g.applyState(log, state, now) {
+			applied++
+		}
+	}
+	return applied
+}
+
+// applyState merges a single remote endpoint state into StateByNode.
+//
+// Merge rules (see types.go):
+//   - unknown node: create a new EndpointState
+//   - higher generation: the node restarted, replace its EndpointState entirely
+//   - same generation: take the higher heartbeat version and, per key, the higher application state version
+//   - lower generation: stale, ignored
+//
+// Only heartbeats with a newer version, than both the gossiped and the directly heard one, are
+// reported to the failure detector.
+func (g *GossipState) applyState(log *logger.SubLogger, remote EndpointStateSnapshot, now time.Time) bool {
+	remoteID := remote.HeartbeatState.NodeID
+	if remoteID == "" {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if remoteID == g.nodeID {
+		// Nobody knows more about us than we do, unless it's another node with our ID
+		g.checkDuplicateNodeIDLocked(remoteID, g.localAppStates[AppHeartbeat].Value, remote, now)
+		return false
+	}
+
+	if removedGeneration, ok := g.removed[remoteID]; ok {
+		if remote.HeartbeatState.Generation <= removedGeneration {
+			return false
+		}
+		delete(g.removed, remoteID)
+	}
+
+	local, known := g.stateByNode[remoteID]
+	switch {
+	case !known:
+		g.stateByNode[remoteID] = newEndpointStateFromSnapshot(remote, now.Unix())
+		log.Printf("Discovered node %s (generation %d, version %d)",
+			string(remoteID), remote.HeartbeatState.Generation, remote.HeartbeatState.Version)
+		g.publishLocked(EndpointDiscovered, remoteID, remote.HeartbeatState.Generation, now)
+	case remote.HeartbeatState.Generation > local.HeartbeatState.Generation:
+		// Restart = new generation, which overrides all old state
+		g.stateByNode[remoteID] = newEndpointStateFromSnapshot(remote, now.Unix())
+		g.detector.Reset(remoteID)
+		log.Printf("Node %s restarted (generation %d -> %d)",
+			string(remoteID), local.HeartbeatState.Generation, remote.HeartbeatState.Generation)
+		g.publishLocked(EndpointDiscovered, remoteID, remote.HeartbeatState.Generation, now)
+	case remote.HeartbeatState.Generation == local.HeartbeatState.Generation:
+		advanced := remote.HeartbeatState.Version > local.HeartbeatState.Version
+		fresh := remote.HeartbeatState.Version > max(local.HeartbeatState.Version, local.heardVersion)
+		changed := local.mergeApplicationStates(remote.ApplicationStates)
+		if advanced {
+			local.HeartbeatState = remote.HeartbeatState
+		}
+		if !fresh {
+			if changed || advanced {
+				g.notifyEndpointLocked(remoteID)
+				g.publishLocked(EndpointChanged, remoteID, remote.HeartbeatState.Generation, now)
+			}
+			return changed || advanced
+		}
+		local.updateTimestamp = now.Unix()
+		local.heardFresh = true
+		g.publishLocked(EndpointChanged, remoteID, remote.HeartbeatState.Generation, now)
+	default:
+		// Older generation than what we already have. Only the node itself gossips its old
+		// generation, so one from another address means two nodes share the ID.
+		g.checkDuplicateNodeIDLocked(remoteID, local.applicationStates[AppHeartbeat].Value, remote, now)
+		return false
+	}
+
+	g.detector.Report(remoteID, now)
+	g.notifyEndpointLocked(remoteID)
+	return true
+}
+
+// applyHeartbeat merges a heartbeat the remote node sent us directly, following the rules of
+// applyState. Unlike a gossiped state it carries none of the node's application states, so
+// its version is kept apart, in heardVersion, rather than in the HeartbeatState digests are
+// built from: a digest claiming that version would keep peers from sending the application
+// states older than it, which we may never have received. A node first heard of, or
+// restarted, this way is known at version 0, so the next gossip round asks for all of it.
+func (g *GossipState) applyHeartbeat(log *logger.SubLogger, remote HeartbeatStateSnapshot, now time.Time) {
+	remoteID := remote.NodeID
+	if remoteID == "" {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if remoteID == g.nodeID {
+		// Nobody knows more about us than we do, unless it's another node with our ID
+		g.checkDuplicateNodeIDLocked(remoteID, g.localAppStates[AppHeartbeat].Value, EndpointStateSnapshot{HeartbeatState: remote}, now)
+		return
+	}
+
+	if removedGeneration, ok := g.removed[remoteID]; ok {
+		if remote.Generation <= removedGeneration {
+			return
+		}
+		delete(g.removed, remoteID)
+	}
+
+	local, known := g.stateByNode[remoteID]
+	switch {
+	case !known || remote.Generation > local.HeartbeatState.Generation:
+		state := newEndpointStateFromSnapshot(EndpointStateSnapshot{
+			HeartbeatState: HeartbeatStateSnapshot{NodeID: remoteID, Generation: remote.Generation},
+		}, now.Unix())
+		state.heardVersion = remote.Version
+		g.stateByNode[remoteID] = state
+		if known {
+			g.detector.Reset(remoteID)
+			log.Printf("Node %s restarted (generation %d -> %d)",
+				string(remoteID), local.HeartbeatState.Generation, remote.Generation)
+		} else {
+			log.Printf("Discovered node %s (generation %d, version %d)", string(remoteID), remote.Generation, remote.Version)
+		}
+		g.publishLocked(EndpointDiscovered, remoteID, remote.Generation, now)
+	case remote.Generation == local.HeartbeatState.Generation:
+		if remote.Version <= max(local.HeartbeatState.Version, local.heardVersion) {
+			return
+		}
+		local.heardVersion = remote.Version
+		local.updateTimestamp = now.Unix()
+		local.heardFresh = true
+		g.publishLocked(EndpointChanged, remoteID, remote.Generation, now)
+	default:
+		// Older generation than what we already have, see applyState
+		g.checkDuplicateNodeIDLocked(remoteID, local.applicationStates[AppHeartbeat].Value, EndpointStateSnapshot{HeartbeatState: remote}, now)
+		return
+	}
+
+	g.detector.Report(remoteID, now)
+	g.notifyEndpointLocked(remoteID)
+}
+
+// UpdateLiveness re-evaluates every endpoint against the failure detector and logs transitions.
+func (g *GossipState) UpdateLiveness(now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for id, state := range g.stateByNode {
+		shutdown := state.applicationStates[AppStatus].Value == StatusShutdown
+		alive := g.detector.IsAlive(id, now) && !shutdown
+		if alive == state.isAlive {
+			continue
+		}
+		state.isAlive = alive
+		g.notifyEndpointLocked(id)
+		if alive {
+			g.log.Printf("Node %s is now UP", string(id))
+			g.publishLocked(EndpointUp, id, state.HeartbeatState.Generation, now)
+			continue
+		}
+		if shutdown {
+			g.log.Printf("Node %s is now DOWN (shut down)", string(id))
+		} else {
+			g.log.With("phi", fmt.Sprintf("%.2f", g.detector.Phi(id, now))).Printf("Node %s is now DOWN", string(id))
+		}
+		g.publishLocked(EndpointDown, id, state.HeartbeatState.Generation, now)
+	}
+
+	g.checkDuplicateAddressesLocked(now)
+	g.expireConflictsLocked(now)
+}
@@ -0,0 +1,208 @@
+package gossip
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+/*
+*
+FailureDetector is a phi-accrual failure detector.
+
+Reference: https://github.com/apache/cassandra/blob/trunk/src/java/org/apache/cassandra/gms/FailureDetector.java
+
+For every remote node it keeps a window of heartbeat inter-arrival times.
+Phi is the suspicion level that the node is down given how long it has been
+since the last heartbeat, assuming exponentially distributed arrivals:
+
+	phi = (now - lastArrival) / mean(intervals) * log10(e)
+
+A phi of 1 means roughly a 10% chance the node is still alive, 2 means 1%, etc.
+Once phi crosses the threshold (Cassandra defaults to 8) the node is convicted.
+
+A heartbeat can only arrive as fast as the network carries it, so the time since the last
+arrival only counts once it exceeds the node's 99th percentile round-trip latency, as
+measured by the gossip exchanges with it (see ReportLatency). A slow link doesn't add to
+the suspicion of a node on it, up to the latency it usually has.
+*/
+
+const (
+	// DefaultPhiConvictThreshold matches Cassandra's phi_convict_threshold default.
+	DefaultPhiConvictThreshold = 8.0
+
+	// arrivalWindowSize bounds the number of inter-arrival samples kept per node.
+	arrivalWindowSize = 1000
+)
+
+// phiFactor converts from natural log to log10, i.e. 1 / ln(10).
+var phiFactor = 1.0 / math.Log(10.0)
+
+// arrivalWindow tracks heartbeat inter-arrival times for a single node.
+type arrivalWindow struct {
+	lastArrival time.Time
+	intervals   []time.Duration
+	sum         time.Duration
+}
+
+func (w *arrivalWindow) add(now time.Time, bootstrapInterval time.Duration) {
+	if w.lastArrival.IsZero() {
+		// First heartbeat: seed the window with the expected interval so phi is meaningful immediately
+		w.push(bootstrapInterval)
+	} else if interval := now.Sub(w.lastArrival); interval > 0 {
+		w.push(interval)
+	}
+	w.lastArrival = now
+}
+
+func (w *arrivalWindow) push(interval time.Duration) {
+	if len(w.intervals) >= arrivalWindowSize {
+		w.sum -= w.intervals[0]
+		w.intervals = w.intervals[1:]
+	}
+	w.intervals = append(w.intervals, interval)
+	w.sum += interval
+}
+
+func (w *arrivalWindow) mean() time.Duration {
+	if len(w.intervals) == 0 {
+		return 0
+	}
+	return w.sum / time.Duration(len(w.intervals))
+}
+
+// phi is the suspicion at now, allowing for a heartbeat to be late by allowance
+func (w *arrivalWindow) phi(now time.Time, allowance time.Duration) float64 {
+	mean := w.mean()
+	if mean <= 0 || w.lastArrival.IsZero() {
+		return 0
+	}
+	sinceLast := max(0, now.Sub(w.lastArrival)-allowance)
+	return phiFactor * float64(sinceLast) / float64(mean)
+}
+
+// latencyAllowanceQuantile is the quantile of a node's round-trip latency its heartbeats
+// may be late by
+const latencyAllowanceQuantile = 0.99
+
+// FailureDetector computes phi for every node it has received heartbeats from.
+type FailureDetector struct {
+	mu                sync.Mutex
+	threshold         float64
+	bootstrapInterval time.Duration // expected heartbeat interval, used before any samples exist
+	windows           map[NodeID]*arrivalWindow
+	latencies         map[NodeID]*LatencyHistogram // round trips of the gossip RPCs to each node
+}
+
+// NewFailureDetector creates a failure detector that convicts nodes once phi exceeds threshold.
+// expectedInterval is used as the initial inter-arrival estimate for newly seen nodes.
+func NewFailureDetector(threshold float64, expectedInterval time.Duration) *FailureDetector {
+	if threshold <= 0 {
+		threshold = DefaultPhiConvictThreshold
+	}
+	return &FailureDetector{
+		threshold:         threshold,
+		bootstrapInterval: expectedInterval,
+		windows:           make(map[NodeID]*arrivalWindow),
+		latencies:         make(map[NodeID]*LatencyHistogram),
+	}
+}
+
+// Report records a heartbeat arrival for nodeID at the given time.
+func (fd *FailureDetector) Report(nodeID NodeID, now time.Time) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	w, ok := fd.windows[nodeID]
+	if !ok {
+		w = &arrivalWindow{}
+		fd.windows[nodeID] = w
+	}
+	w.add(now, fd.bootstrapInterval)
+}
+
+// Phi returns the current suspicion level for nodeID, or 0 if the node is unknown.
+func (fd *FailureDetector) Phi(nodeID NodeID, now time.Time) float64 {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	w, ok := fd.windows[nodeID]
+	if !ok {
+		return 0
+	}
+	return w.phi(now, fd.latencyAllowanceLocked(nodeID))
+}
+
+// latencyAllowanceLocked is how late a heartbeat of nodeID may be, see the package doc.
+// Caller must hold fd.mu.
+func (fd *FailureDetector) latencyAllowanceLocked(nodeID NodeID) time.Duration {
+	h, ok := fd.latencies[nodeID]
+	if !ok {
+		return 0
+	}
+	return h.Quantile(latencyAllowanceQuantile)
+}
+
+// ReportLatency records a gossip RPC to nodeID that took rtt to get an answer
+func (fd *FailureDetector) ReportLatency(nodeID NodeID, rtt time.Duration) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	h, ok := fd.latencies[nodeID]
+	if !ok {
+		h = &LatencyHistogram{}
+		fd.latencies[nodeID] = h
+	}
+	h.record(rtt)
+}
+
+// Latencies returns the round-trip latency histogram of every node an RPC was timed to
+func (fd *FailureDetector) Latencies() map[NodeID]LatencyHistogram {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	latencies := make(map[NodeID]LatencyHistogram, len(fd.latencies))
+	for id, h := range fd.latencies {
+		latencies[id] = h.copy()
+	}
+	return latencies
+}
+
+// Arrivals is the heartbeat history phi is computed from for a node.
+type Arrivals struct {
+	Last         time.Time     // when the last heartbeat arrived
+	MeanInterval time.Duration // mean time between heartbeats
+	Samples      int           // inter-arrival times in the window
+	Allowance    time.Duration // how late a heartbeat may be, the node's 99th percentile round trip
+}
+
+// Arrivals returns the heartbeat history of nodeID, false if no heartbeat arrived from it yet.
+func (fd *FailureDetector) Arrivals(nodeID NodeID) (Arrivals, bool) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	w, ok := fd.windows[nodeID]
+	if !ok {
+		return Arrivals{}, false
+	}
+	return Arrivals{Last: w.lastArrival, MeanInterval: w.mean(), Samples: len(w.intervals),
+		Allowance: fd.latencyAllowanceLocked(nodeID)}, true
+}
+
+// IsAlive reports whether phi for nodeID is still below the conviction threshold.
+func (fd *FailureDetector) IsAlive(nodeID NodeID, now time.Time) bool {
+	return fd.Phi(nodeID, now) < fd.threshold
+}
+
+// Threshold returns the phi conviction threshold.
+func (fd *FailureDetector) Threshold() float64 {
+	return fd.threshold
+}
+
+// Reset discards the arrival history for nodeID, e.g. after the node restarts with a new generation.
+// Its latencies are kept, they are the network's rather than the process'.
+func (fd *FailureDetector) Reset(nodeID NodeID) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	delete(fd.windows, nodeID)
+}
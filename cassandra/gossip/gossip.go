@@ -3,6 +3,8 @@ package gossip
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/adamgarcia4/goLearning/cassandra/logger"
@@ -107,7 +109,16 @@ type GossipState struct {
 	heartbeatInterval time.Duration
 	myHeartbeatState  *HeartbeatState // pointer to avoid copying mutex
 
-	// StateByNode map[NodeID]*EndpointState
+	mu             sync.RWMutex              // guards stateByNode, localAppStates and watchers
+	stateByNode    map[NodeID]*EndpointState // everything we know about remote nodes
+	localAppStates map[AppStateKey]AppState  // application states of the local node
+	detector       *FailureDetector
+	watchers       map[*watcher]struct{} // subscribers to state changes, see Watch
+	removed        map[NodeID]int64      // generation of endpoints removed with RemoveEndpoint
+	conflicts      map[string]*Conflict  // identity conflicts by description, see Conflicts
+	listeners      []func(EndpointEvent) // see OnEndpointEvent
+	log            *logger.SubLogger     // logs as the gossip component of the local node
+	stats          gossipStats           // see Stats
 }
 
 // HeartbeatSender is a function that sends a heartbeat and returns the response node ID and timestamp
@@ -125,7 +136,7 @@ func (g *GossipState) SendHeartbeat(sendHeartbeat HeartbeatSender) (string, int6
 func (g *GossipState) InitializeHeartbeatSending(ctx context.Context, sendHeartbeat HeartbeatSender) {
 	ticker := time.NewTicker(g.heartbeatInterval)
 	defer ticker.Stop()
-	logger.Printf("Node %s: Starting to send heartbeats every %v", string(g.nodeID), g.heartbeatInterval)
+	g.log.Printf("Starting to send heartbeats every %v", g.heartbeatInterval)
 
 	for {
 		select {
@@ -134,7 +145,7 @@ func (g *GossipState) InitializeHeartbeatSending(ctx context.Context, sendHeartb
 		case <-ticker.C:
 			_, _, err := g.SendHeartbeat(sendHeartbeat)
 			if err != nil {
-				logger.Printf("Node %s: Failed to send heartbeat: %v", string(g.nodeID), err)
+				g.log.Warnf("Failed to send heartbeat: %v", err)
 			}
 		}
 	}
@@ -149,22 +160,143 @@ func (g *GossipState) LocalHeartbeat() HeartbeatStateSnapshot {
 }
 
 // HandleHeartbeat processes an incoming heartbeat from a remote node
-// It merges the remote state and returns the local node's heartbeat state
+// It merges the remote heartbeat into StateByNode and returns the local node's heartbeat state
+// (see applyHeartbeat for the merge rules).
 func (g *GossipState) HandleHeartbeat(remoteNodeID string, remoteGeneration int64, remoteVersion int64) (localNodeID string, localGeneration int64, localVersion int64, err error) {
 	if g.myHeartbeatState == nil {
 		panic("GossipState not initialized: use NewGossipState")
 	}
-	// TODO: Implement proper state merging logic
-	// For now, just return our local state
-	// In the future, this should:
-	// 1. Compare remote generation/version with local state
-	// 2. Merge remote state into StateByNode map
-	// 3. Update local state if remote is newer
 
 	snapshot := g.myHeartbeatState.GetSnapshot()
+
+	if remoteNodeID == "" {
+		return "", 0, 0, fmt.Errorf("remote node ID must be set")
+	}
+
+	// Our own heartbeat echoed back to us is ignored by applyHeartbeat
+	g.applyHeartbeat(g.log, HeartbeatStateSnapshot{
+		NodeID:     NodeID(remoteNodeID),
+		Generation: remoteGeneration,
+		Version:    remoteVersion,
+	}, time.Now())
+
 	return string(snapshot.NodeID), snapshot.Generation, snapshot.Version, nil
 }
 
+// GetEndpointState returns a copy of what we know about nodeID.
+func (g *GossipState) GetEndpointState(nodeID NodeID) (EndpointState, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	state, ok := g.stateByNode[nodeID]
+	if !ok {
+		return EndpointState{}, false
+	}
+	return state.copy(), true
+}
+
+// GetStateByNode returns a copy of the endpoint states for all known remote nodes.
+func (g *GossipState) GetStateByNode() map[NodeID]EndpointState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	states := make(map[NodeID]EndpointState, len(g.stateByNode))
+	for id, state := range g.stateByNode {
+		states[id] = state.copy()
+	}
+	return states
+}
+
+// EndpointInfo is an endpoint's state together with the local node's opinion of it.
+type EndpointInfo struct {
+	State           EndpointStateSnapshot
+	Alive           bool
+	Phi             float64
+	UpdateTimestamp int64 // unix seconds we last heard from the endpoint
+	Local           bool
+}
+
+// Endpoints returns every known endpoint, including the local one, sorted by node ID.
+func (g *GossipState) Endpoints(now time.Time) []EndpointInfo {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	endpoints := make([]EndpointInfo, 0, len(g.stateByNode)+1)
+	endpoints = append(endpoints, EndpointInfo{
+		State:           g.localSnapshotLocked(),
+		Alive:           true,
+		UpdateTimestamp: now.Unix(),
+		Local:           true,
+	})
+	for id, state := range g.stateByNode {
+		endpoints = append(endpoints, EndpointInfo{
+			State:           state.snapshot(),
+			Alive:           state.isAlive,
+			Phi:             g.detector.Phi(id, now),
+			UpdateTimestamp: state.updateTimestamp,
+		})
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].State.HeartbeatState.NodeID < endpoints[j].State.HeartbeatState.NodeID
+	})
+	return endpoints
+}
+
+// RemoveEndpoint forgets nodeID, like nodetool removenode. Gossip about the removed
+// generation is ignored from then on, so only a restart of the node brings it back.
+func (g *GossipState) RemoveEndpoint(nodeID NodeID) error {
+	if nodeID == g.nodeID {
+		return fmt.Errorf("cannot remove the local node")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.stateByNode[nodeID]
+	if !ok {
+		return fmt.Errorf("unknown node %s", nodeID)
+	}
+	g.removeLocked(nodeID, state)
+	return nil
+}
+
+// RemoveLeft removes (and quarantines, like RemoveEndpoint) endpoints whose STATUS has been
+// LEFT for at least retention, so a decommissioned node is gossiped long enough for every
+// node to hear it left. Call it periodically; it returns the removed endpoints.
+func (g *GossipState) RemoveLeft(now time.Time, retention time.Duration) []EndpointState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var removed []EndpointState
+	for nodeID, state := range g.stateByNode {
+		if state.applicationStates[AppStatus].Value != StatusLeft {
+			continue
+		}
+		if state.leftSince.IsZero() {
+			state.leftSince = now
+		}
+		if now.Sub(state.leftSince) >= retention {
+			g.removeLocked(nodeID, state)
+			removed = append(removed, state.copy())
+		}
+	}
+	return removed
+}
+
+// removeLocked forgets nodeID and ignores its current generation from now on. Caller must hold g.mu.
+func (g *GossipState) removeLocked(nodeID NodeID, state *EndpointState) {
+	delete(g.stateByNode, nodeID)
+	g.removed[nodeID] = state.HeartbeatState.Generation
+	g.detector.Reset(nodeID)
+	g.publishLocked(EndpointRemoved, nodeID, state.HeartbeatState.Generation, time.Now())
+	g.log.Printf("Removed node %s (generation %d)", string(nodeID), state.HeartbeatState.Generation)
+}
+
+// FailureDetector returns the failure detector fed by incoming heartbeats.
+func (g *GossipState) FailureDetector() *FailureDetector {
+	return g.detector
+}
+
 func (g *GossipState) Start(ctx context.Context, sendHeartbeat HeartbeatSender) {
 	go g.InitializeHeartbeatSending(ctx, sendHeartbeat)
 }
@@ -178,9 +310,41 @@ func NewGossipState(nodeID NodeID, interval time.Duration) (*GossipState, error)
 		return nil, fmt.Errorf("nodeID must be set")
 	}
 
+	return newGossipState(nodeID, interval, time.Now().Unix()), nil
+}
+
+// ResumeGossipState is NewGossipState for a node that last ran with generation previous (e.g.
+// saved to disk). The generation is strictly higher, even if the node restarts within the same second.
+func ResumeGossipState(nodeID NodeID, interval time.Duration, previous int64) (*GossipState, error) {
+	g, err := NewGossipState(nodeID, interval)
+	if err != nil {
+		return nil, err
+	}
+	if generation := g.LocalHeartbeat().Generation; generation <= previous {
+		g = newGossipState(nodeID, interval, previous+1)
+	}
+	return g, nil
+}
+
+// Restarted returns a fresh state for the same node, as if its process had restarted: the
+// generation is strictly higher than g's (even within the same second), so peers replace
+// what they know about the node instead of merging, and nothing is known about other endpoints yet.
+func (g *GossipState) Restarted() *GossipState {
+	generation := max(time.Now().Unix(), g.LocalHeartbeat().Generation+1)
+	return newGossipState(g.nodeID, g.heartbeatInterval, generation)
+}
+
+func newGossipState(nodeID NodeID, interval time.Duration, generation int64) *GossipState {
 	return &GossipState{
 		nodeID:            nodeID,
 		heartbeatInterval: interval,
-		myHeartbeatState:  NewHeartbeatState(nodeID, time.Now().Unix()),
-	}, nil
+		myHeartbeatState:  NewHeartbeatState(nodeID, generation),
+		stateByNode:       make(map[NodeID]*EndpointState),
+		localAppStates:    make(map[AppStateKey]AppState),
+		detector:          NewFailureDetector(DefaultPhiConvictThreshold, interval),
+		watchers:          make(map[*watcher]struct{}),
+		removed:           make(map[NodeID]int64),
+		conflicts:         make(map[string]*Conflict),
+		log:               logger.Named("gossip").WithNode(string(nodeID)),
+	}
 }
@@ -0,0 +1,168 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandleHeartbeat(t *testing.T) {
+	const remote = NodeID("node-2")
+
+	// A heartbeat carries none of the node's application states, so the version it vouches for
+	// never reaches the gossiped heartbeat state: a node first heard of, or restarted, is known
+	// at version 0 until gossip brings its states
+	tests := []struct {
+		name     string
+		known    *HeartbeatStateSnapshot // what the local node knew through gossip, nil if nothing
+		heard    int64                   // a heartbeat version the node sent directly before, 0 if none
+		incoming HeartbeatStateSnapshot
+		want     HeartbeatStateSnapshot // the gossiped heartbeat state after
+		fed      bool                   // whether the failure detector got a heartbeat
+	}{
+		{
+			name:     "unknown node",
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 3},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100},
+			fed:      true,
+		},
+		{
+			name:     "restart with a higher generation",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 50},
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 200, Version: 1},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 200},
+			fed:      true,
+		},
+		{
+			name:     "same generation, higher version",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 6},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			fed:      true,
+		},
+		{
+			name:     "same generation, version already heard",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			heard:    8,
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 7},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+		},
+		{
+			name:     "same generation and version",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+		},
+		{
+			name:     "same generation, stale version",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 4},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+		},
+		{
+			name:     "older generation",
+			known:    &HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+			incoming: HeartbeatStateSnapshot{NodeID: remote, Generation: 99, Version: 80},
+			want:     HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGossipState("node-1", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.known != nil {
+				g.applyState(g.log, EndpointStateSnapshot{HeartbeatState: *tt.known}, time.Now())
+			}
+			if tt.heard > 0 {
+				g.HandleHeartbeat(string(remote), tt.known.Generation, tt.heard)
+			}
+			// Forget the arrivals of the setup, so any left afterwards came from the heartbeat
+			g.FailureDetector().Reset(remote)
+
+			localID, localGeneration, localVersion, err := g.HandleHeartbeat(
+				string(tt.incoming.NodeID), tt.incoming.Generation, tt.incoming.Version)
+			if err != nil {
+				t.Fatalf("HandleHeartbeat: %v", err)
+			}
+			local := g.LocalHeartbeat()
+			if localID != string(local.NodeID) || localGeneration != local.Generation || localVersion != local.Version {
+				t.Errorf("HandleHeartbeat returned (%s, %d, %d), want the local heartbeat %+v",
+					localID, localGeneration, localVersion, local)
+			}
+
+			state, ok := g.GetEndpointState(remote)
+			if !ok {
+				t.Fatalf("%s is unknown after its heartbeat", remote)
+			}
+			if state.HeartbeatState != tt.want {
+				t.Errorf("heartbeat state = %+v, want %+v", state.HeartbeatState, tt.want)
+			}
+			if _, fed := g.FailureDetector().Arrivals(remote); fed != tt.fed {
+				t.Errorf("failure detector fed = %v, want %v", fed, tt.fed)
+			}
+		})
+	}
+}
+
+func TestHandleHeartbeatIgnoresOwnAndAnonymous(t *testing.T) {
+	g, err := NewGossipState("node-1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := g.LocalHeartbeat()
+
+	if _, _, _, err := g.HandleHeartbeat("", 100, 1); err == nil {
+		t.Error("HandleHeartbeat accepted a heartbeat without a node ID")
+	}
+	if _, _, _, err := g.HandleHeartbeat(string(local.NodeID), local.Generation+1, 1); err != nil {
+		t.Fatalf("HandleHeartbeat: %v", err)
+	}
+	if states := g.GetStateByNode(); len(states) != 0 {
+		t.Errorf("known endpoints = %v, want none", states)
+	}
+	if _, fed := g.FailureDetector().Arrivals(local.NodeID); fed {
+		t.Error("failure detector fed with the local node's own heartbeat")
+	}
+}
+
+func TestHandleHeartbeatKeepsApplicationStatesGossiped(t *testing.T) {
+	const remote = NodeID("node-2")
+	g, err := NewGossipState("node-1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Known through gossip before the remote node set LOAD at version 9
+	g.applyState(g.log, EndpointStateSnapshot{
+		HeartbeatState: HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 5},
+	}, time.Now())
+	// Then heard directly, far past LOAD's version
+	if _, _, _, err := g.HandleHeartbeat(string(remote), 100, 110); err != nil {
+		t.Fatalf("HandleHeartbeat: %v", err)
+	}
+
+	// The remote node's digest must still get LOAD requested
+	remoteState := EndpointStateSnapshot{
+		HeartbeatState:    HeartbeatStateSnapshot{NodeID: remote, Generation: 100, Version: 110},
+		ApplicationStates: map[AppStateKey]AppState{"LOAD": {Value: "42", Version: 9}},
+	}
+	ack := g.HandleSyn(SynMessage{SenderID: remote, Digests: []GossipDigest{
+		{NodeID: remote, Generation: 100, MaxVersion: remoteState.MaxVersion()},
+	}})
+	var request *GossipDigest
+	for i := range ack.Requests {
+		if ack.Requests[i].NodeID == remote {
+			request = &ack.Requests[i]
+		}
+	}
+	if request == nil {
+		t.Fatalf("ACK requests %v, want %s's newer states", ack.Requests, remote)
+	}
+
+	g.applyState(g.log, remoteState.since(request.MaxVersion), time.Now())
+	state, _ := g.GetEndpointState(remote)
+	if load, ok := state.GetApplicationState("LOAD"); !ok || load.Value != "42" {
+		t.Errorf("LOAD = %+v, %v after gossip, want 42", load, ok)
+	}
+}
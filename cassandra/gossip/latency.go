@@ -0,0 +1,73 @@
+package gossip
+
+import "time"
+
+// LatencyBuckets are the upper bounds of the buckets of a LatencyHistogram. A last bucket
+// counts the round trips slower than all of them.
+var LatencyBuckets = []time.Duration{
+	250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyHistogram is the distribution of the round trips of the gossip RPCs (SYN -> ACK
+// and ACK2) to a peer
+type LatencyHistogram struct {
+	Buckets []int64 // round trips per bucket of LatencyBuckets, and slower ones last
+	Count   int64
+	Sum     time.Duration
+	Max     time.Duration
+}
+
+// record adds a round trip that took rtt
+func (h *LatencyHistogram) record(rtt time.Duration) {
+	if h.Buckets == nil {
+		h.Buckets = make([]int64, len(LatencyBuckets)+1)
+	}
+	bucket := len(LatencyBuckets)
+	for i, bound := range LatencyBuckets {
+		if rtt <= bound {
+			bucket = i
+			break
+		}
+	}
+	h.Buckets[bucket]++
+	h.Count++
+	h.Sum += rtt
+	h.Max = max(h.Max, rtt)
+}
+
+// copy returns a copy of h that doesn't share its buckets
+func (h *LatencyHistogram) copy() LatencyHistogram {
+	c := *h
+	c.Buckets = append([]int64(nil), h.Buckets...)
+	return c
+}
+
+// Mean returns the mean round trip, 0 if there were none
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns the round trip q (0 to 1) of the round trips were at most as slow as,
+// rounded up to the upper bound of its bucket but never above Max. 0 if there were none.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := int64(q*float64(h.Count) + 0.5)
+	rank = min(max(rank, 1), h.Count)
+	var seen int64
+	for i, count := range h.Buckets {
+		seen += count
+		if seen >= rank && i < len(LatencyBuckets) {
+			return min(LatencyBuckets[i], h.Max)
+		}
+	}
+	return h.Max
+}
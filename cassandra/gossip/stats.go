@@ -0,0 +1,89 @@
+package gossip
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the gossip of a GossipState, since it was created (a restarted node
+// starts over, see Restarted)
+type Stats struct {
+	Rounds            int64         // gossip rounds run, see RecordRound
+	DigestsSent       int64         // digests in the SYNs sent, see SynSent
+	DigestsReceived   int64         // digests in the SYNs received
+	StatesPushed      int64         // states sent to peers, in ACKs and ACK2s
+	StatesPulled      int64         // states received from peers, in ACKs and ACK2s
+	LastRoundDuration time.Duration // how long the last round took, exchanges included
+	// Convergence estimates how much the cluster agrees: the fraction of endpoints the last
+	// exchange, SYN sent or received, found both nodes at the same version of. 1 once gossip
+	// has nothing left to spread, 0 until the first exchange.
+	Convergence float64
+	// PeerLatency is the round-trip latency of the gossip RPCs to each peer, see RecordLatency
+	PeerLatency map[NodeID]LatencyHistogram
+}
+
+// gossipStats holds the counters behind Stats
+type gossipStats struct {
+	rounds            atomic.Int64
+	digestsSent       atomic.Int64
+	digestsReceived   atomic.Int64
+	statesPushed      atomic.Int64
+	statesPulled      atomic.Int64
+	lastRoundDuration atomic.Int64
+	agreement         atomic.Uint64 // endpoints agreed on in the upper 32 bits, compared in the lower ones
+}
+
+// Stats returns a snapshot of the gossip statistics. Every value is read atomically, though
+// not all at once. Sample them to get rates.
+func (g *GossipState) Stats() Stats {
+	stats := Stats{
+		Rounds:            g.stats.rounds.Load(),
+		DigestsSent:       g.stats.digestsSent.Load(),
+		DigestsReceived:   g.stats.digestsReceived.Load(),
+		StatesPushed:      g.stats.statesPushed.Load(),
+		StatesPulled:      g.stats.statesPulled.Load(),
+		LastRoundDuration: time.Duration(g.stats.lastRoundDuration.Load()),
+		PeerLatency:       g.detector.Latencies(),
+	}
+	agreement := g.stats.agreement.Load()
+	if compared := agreement & 0xffffffff; compared > 0 {
+		stats.Convergence = float64(agreement>>32) / float64(compared)
+	}
+	return stats
+}
+
+// RecordRound counts a gossip round that took duration, see Stats
+func (g *GossipState) RecordRound(duration time.Duration) {
+	g.stats.rounds.Add(1)
+	g.stats.lastRoundDuration.Store(int64(duration))
+}
+
+// SynSent counts the digests of syn, sent to a peer, see Stats
+func (g *GossipState) SynSent(syn SynMessage) {
+	g.stats.digestsSent.Add(int64(len(syn.Digests)))
+}
+
+// RecordLatency records a gossip RPC to the node at address that took rtt to get an answer,
+// for Stats and the failure detector (see FailureDetector.ReportLatency). It is ignored while
+// no known endpoint gossips address.
+func (g *GossipState) RecordLatency(address string, rtt time.Duration) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for id, state := range g.stateByNode {
+		if state.applicationStates[AppHeartbeat].Value == address {
+			g.detector.ReportLatency(id, rtt)
+			return
+		}
+	}
+}
+
+// recordAgreement sets the convergence estimate to an exchange comparing compared endpoints,
+// of which differing were exchanged because the nodes didn't agree on them
+func (g *GossipState) recordAgreement(compared, differing int) {
+	if compared <= 0 {
+		return
+	}
+	agreed := max(0, compared-differing)
+	g.stats.agreement.Store(uint64(agreed)<<32 | uint64(compared)&0xffffffff)
+}
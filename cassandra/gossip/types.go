@@ -46,9 +46,21 @@ type AppStateKey string
 const (
 	AppStatus    AppStateKey = "STATUS"
 	AppHeartbeat AppStateKey = "ADDR"
+	AppTokens    AppStateKey = "TOKENS" // the node's tokens on the ring, see package ring
+	AppDC        AppStateKey = "DC"     // the node's datacenter, see package snitch
+	AppRack      AppStateKey = "RACK"   // the node's rack, see package snitch
 	// TODO: Add more app state keys here
 )
 
+// Values for the STATUS application state
+const (
+	StatusBoot     = "BOOT" // joining, streaming the ranges it takes over; not on the ring yet
+	StatusNormal   = "NORMAL"
+	StatusLeaving  = "LEAVING"  // decommissioning, still serving
+	StatusLeft     = "LEFT"     // decommissioned, about to stop; peers remove it after a while
+	StatusShutdown = "SHUTDOWN" // stopping; peers mark it down without waiting for the failure detector
+)
+
 type AppState struct {
 	Value   string
 	Version int64
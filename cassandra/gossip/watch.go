@@ -0,0 +1,70 @@
+package gossip
+
+import "context"
+
+// watchBufferSize is how many updates a watcher can fall behind before updates are dropped.
+// Every update carries an endpoint's full state, so a dropped update is superseded by that endpoint's next one.
+const watchBufferSize = 256
+
+// StateUpdate is the full state of one endpoint, sent to watchers whenever it changes.
+type StateUpdate struct {
+	State EndpointStateSnapshot
+	Alive bool
+}
+
+// watcher receives StateUpdates until its context is done.
+type watcher struct {
+	updates chan StateUpdate
+}
+
+// Watch returns a channel that first receives the current state of every endpoint
+// (including the local one) and then an update whenever an endpoint's state or liveness changes.
+// The channel is closed once ctx is done.
+func (g *GossipState) Watch(ctx context.Context) <-chan StateUpdate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	w := &watcher{
+		updates: make(chan StateUpdate, len(g.stateByNode)+1+watchBufferSize),
+	}
+	w.updates <- StateUpdate{State: g.localSnapshotLocked(), Alive: true}
+	for _, state := range g.stateByNode {
+		w.updates <- StateUpdate{State: state.snapshot(), Alive: state.isAlive}
+	}
+	g.watchers[w] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		delete(g.watchers, w)
+		close(w.updates)
+	}()
+
+	return w.updates
+}
+
+// notifyLocked sends update to every watcher without blocking. Caller must hold g.mu (read or write).
+func (g *GossipState) notifyLocked(update StateUpdate) {
+	for w := range g.watchers {
+		select {
+		case w.updates <- update:
+		default:
+			// Slow watcher, it will catch up with this endpoint's next update
+		}
+	}
+}
+
+// notifyEndpointLocked notifies watchers of nodeID's current state. Caller must hold g.mu.
+func (g *GossipState) notifyEndpointLocked(nodeID NodeID) {
+	if len(g.watchers) == 0 {
+		return
+	}
+	if nodeID == g.nodeID {
+		g.notifyLocked(StateUpdate{State: g.localSnapshotLocked(), Alive: true})
+		return
+	}
+	if state, ok := g.stateByNode[nodeID]; ok {
+		g.notifyLocked(StateUpdate{State: state.snapshot(), Alive: state.isAlive})
+	}
+}
@@ -1,87 +1,264 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// followBufferSize is how many entries a follower may lag behind before new ones are
+// dropped for it, so a slow follower can't block logging
+const followBufferSize = 256
+
 // LogEntry represents a single log entry
 type LogEntry struct {
 	Timestamp time.Time
 	NodeID    string
 	Message   string
+	Level     Level
+	Component string // package that logged it, e.g. "gossip", empty if unknown
+	Fields    Fields // key/value pairs beside the message, see SubLogger.With
+}
+
+// Fields are the key/value pairs of a log entry, e.g. method=/Gossip/Syn code=Unavailable
+type Fields map[string]string
+
+// String renders the fields sorted by key, "key=value key2=value2", quoting values with spaces
+// or quotes
+func (f Fields) String() string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(f)) {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		value := f[key]
+		if value == "" || strings.ContainsAny(value, " =\"") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(key + "=" + value)
+	}
+	return b.String()
+}
+
+// Equal reports whether e and other are the same entry. Entries can't be compared with ==
+// because of their fields.
+func (e LogEntry) Equal(other LogEntry) bool {
+	return e.Timestamp.Equal(other.Timestamp) && e.NodeID == other.NodeID && e.Message == other.Message &&
+		e.Level == other.Level && e.Component == other.Component && maps.Equal(e.Fields, other.Fields)
 }
 
-// LogBuffer is a thread-safe buffer for log entries
+// Text returns the message followed by the fields, "rpc method=/Gossip/Syn code=Unavailable"
+func (e LogEntry) Text() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	return e.Message + " " + e.Fields.String()
+}
+
+// LogBuffer is a thread-safe buffer keeping the last maxSize log entries. It is a ring: an
+// entry overwrites the oldest one once it is full. Entries are numbered in the order they are
+// added, the entry numbered seq being in slot seq % maxSize, and indexed by node.
 type LogBuffer struct {
-	entries []LogEntry
-	maxSize int
-	mu      sync.RWMutex
+	entries   []LogEntry          // the ring, of maxSize slots
+	count     int                 // slots in use
+	next      uint64              // number of the next entry
+	byNode    map[string][]uint64 // numbers of the entries in the buffer of each node, oldest first
+	maxSize   int
+	followers map[chan LogEntry]struct{}
+	mu        sync.RWMutex
 }
 
 // NewLogBuffer creates a new log buffer
 func NewLogBuffer(maxSize int) *LogBuffer {
+	maxSize = max(0, maxSize)
 	return &LogBuffer{
-		entries: make([]LogEntry, 0, maxSize),
+		entries: make([]LogEntry, maxSize),
+		byNode:  make(map[string][]uint64),
 		maxSize: maxSize,
 	}
 }
 
-// Add adds a new log entry
+// Add adds a new info-level log entry
 func (lb *LogBuffer) Add(nodeID, message string) {
+	lb.AddEntry(LogEntry{NodeID: nodeID, Message: message, Level: LevelInfo})
+}
+
+// AddEntry adds entry, timestamped now unless it has a timestamp
+func (lb *LogBuffer) AddEntry(entry LogEntry) {
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
 
-	entry := LogEntry{
-		Timestamp: time.Now(),
-		NodeID:    nodeID,
-		Message:   message,
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
 	}
 
-	lb.entries = append(lb.entries, entry)
+	if lb.maxSize > 0 {
+		lb.store(entry)
+	}
 
-	// Keep only the last maxSize entries
-	if len(lb.entries) > lb.maxSize {
-		lb.entries = lb.entries[len(lb.entries)-lb.maxSize:]
+	for follower := range lb.followers {
+		select {
+		case follower <- entry:
+		default:
+		}
 	}
 }
 
-// GetRecent returns the most recent log entries
-func (lb *LogBuffer) GetRecent(count int) []LogEntry {
-	lb.mu.RLock()
-	defer lb.mu.RUnlock()
-
-	if count > len(lb.entries) {
-		count = len(lb.entries)
+// store puts entry in the next slot, overwriting the oldest entry once the buffer is full.
+// Caller must hold lb.mu.
+func (lb *LogBuffer) store(entry LogEntry) {
+	slot := lb.next % uint64(lb.maxSize)
+	if lb.count == lb.maxSize {
+		// The overwritten entry is the oldest of its node too
+		oldest := lb.entries[slot].NodeID
+		if seqs := lb.byNode[oldest]; len(seqs) > 1 {
+			lb.byNode[oldest] = seqs[1:]
+		} else {
+			delete(lb.byNode, oldest)
+		}
+	} else {
+		lb.count++
 	}
+	lb.entries[slot] = entry
+	lb.byNode[entry.NodeID] = append(lb.byNode[entry.NodeID], lb.next)
+	lb.next++
+}
 
-	start := len(lb.entries) - count
-	if start < 0 {
-		start = 0
-	}
+// at returns the entry numbered seq. Caller must hold lb.mu.
+func (lb *LogBuffer) at(seq uint64) LogEntry {
+	return lb.entries[seq%uint64(lb.maxSize)]
+}
 
+// recentLocked returns the count most recent entries, oldest first. Caller must hold lb.mu.
+func (lb *LogBuffer) recentLocked(count int) []LogEntry {
+	count = max(0, min(count, lb.count))
 	result := make([]LogEntry, count)
-	copy(result, lb.entries[start:])
+	first := lb.next - uint64(count)
+	for i := range result {
+		result[i] = lb.at(first + uint64(i))
+	}
 	return result
 }
 
+// Subscribe returns a channel receiving every entry added from now on, and a function to
+// stop receiving them, which closes the channel. Entries are dropped for a subscriber that
+// falls too far behind, so a slow one can't block logging.
+func (lb *LogBuffer) Subscribe() (<-chan LogEntry, func()) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.subscribeLocked()
+}
+
+// subscribeLocked is Subscribe for a caller holding lb.mu
+func (lb *LogBuffer) subscribeLocked() (<-chan LogEntry, func()) {
+	subscriber := make(chan LogEntry, followBufferSize)
+	if lb.followers == nil {
+		lb.followers = make(map[chan LogEntry]struct{})
+	}
+	lb.followers[subscriber] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			lb.mu.Lock()
+			delete(lb.followers, subscriber)
+			close(subscriber)
+			lb.mu.Unlock()
+		})
+	}
+	return subscriber, unsubscribe
+}
+
+// Follow returns the entries in the buffer, and a channel receiving every entry added after
+// them until ctx is done. Entries are dropped for a follower that falls too far behind.
+func (lb *LogBuffer) Follow(ctx context.Context) ([]LogEntry, <-chan LogEntry) {
+	lb.mu.Lock()
+	history := lb.recentLocked(lb.count)
+	follower, unsubscribe := lb.subscribeLocked()
+	lb.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	return history, follower
+}
+
+// MaxSize returns how many entries the buffer keeps
+func (lb *LogBuffer) MaxSize() int {
+	return lb.maxSize
+}
+
+// GetRecent returns the most recent log entries
+func (lb *LogBuffer) GetRecent(count int) []LogEntry {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return lb.recentLocked(count)
+}
+
 // GetAll returns all log entries
 func (lb *LogBuffer) GetAll() []LogEntry {
 	lb.mu.RLock()
 	defer lb.mu.RUnlock()
 
-	result := make([]LogEntry, len(lb.entries))
-	copy(result, lb.entries)
+	return lb.recentLocked(lb.count)
+}
+
+// Query returns the most recent entries of the node with nodeID, of every node if it is
+// empty, at level or above and logged at or after since unless it is zero, oldest first. It
+// returns at most limit entries, the newest ones, unless limit is 0 or less. A node's entries
+// are found through its index, without going through the other nodes'.
+func (lb *LogBuffer) Query(nodeID string, level Level, since time.Time, limit int) []LogEntry {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	var result []LogEntry
+	// add adds the entry numbered seq if it matches, and reports whether to look for more
+	add := func(seq uint64) bool {
+		entry := lb.at(seq)
+		if entry.Level >= level && (since.IsZero() || !entry.Timestamp.Before(since)) {
+			result = append(result, entry)
+		}
+		return limit <= 0 || len(result) < limit
+	}
+
+	// Newest first, to stop at the limit
+	if nodeID == "" {
+		oldest := lb.next - uint64(lb.count)
+		for seq := lb.next; seq > oldest; seq-- {
+			if !add(seq - 1) {
+				break
+			}
+		}
+	} else {
+		seqs := lb.byNode[nodeID]
+		for i := len(seqs) - 1; i >= 0; i-- {
+			if !add(seqs[i]) {
+				break
+			}
+		}
+	}
+	slices.Reverse(result)
 	return result
 }
 
-// FormatLogEntry formats a log entry for display
+// FormatLogEntry formats a log entry for display, marking the levels other than info and
+// followed by its fields
 func FormatLogEntry(entry LogEntry) string {
+	message := entry.Text()
+	if entry.Level != LevelInfo {
+		message = fmt.Sprintf("[%s] %s", strings.ToUpper(entry.Level.String()), message)
+	}
 	return fmt.Sprintf("[%s] %s: %s",
 		entry.Timestamp.Format("15:04:05"),
 		entry.NodeID,
-		entry.Message,
+		message,
 	)
 }
 
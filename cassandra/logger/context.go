@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+)
+
+// traceIDKey is the context key of the trace ID
+type traceIDKey struct{}
+
+// traceField is the field loggers set to the trace ID
+const traceField = "trace"
+
+// NewTraceID returns a random ID for a trace, e.g. "3f9c2a7b0d4e1f86"
+func NewTraceID() string {
+	return fmt.Sprintf("%016x", rand.Uint64())
+}
+
+// WithTraceID returns a copy of ctx carrying the trace ID id, which the loggers of FromContext
+// and SubLogger.WithContext set as the "trace" field of their entries. A trace ties together
+// the entries of one operation across nodes, e.g. a gossip exchange.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID returns the trace ID of ctx, empty if it has none
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// FromContext returns a logger for the package it is called from with the trace ID of ctx,
+// if it has one
+func FromContext(ctx context.Context) *SubLogger {
+	return (&SubLogger{}).WithContext(ctx)
+}
+
+// WithContext returns l with the trace ID of ctx, or l if ctx has none
+func (l *SubLogger) WithContext(ctx context.Context) *SubLogger {
+	id := TraceID(ctx)
+	if id == "" {
+		return l
+	}
+	return l.With(traceField, id)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format is how entries are written to the outputs that only take text, like stdout and log
+// files. Outputs keeping entries, like LogBufferWriter, get them whatever the format.
+type Format int
+
+const (
+	FormatText Format = iota // "[WARN] [node-1] message", as logged
+	FormatJSON               // one JSON object per line, see jsonEntry
+)
+
+// String returns the format's name as accepted by ParseFormat
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// ParseFormat parses "text" or "json"
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q: use text or json", s)
+	}
+}
+
+// jsonEntry is an entry as FormatJSON writes it, for jq, Loki, Elasticsearch and the like:
+// {"timestamp":"2025-01-02T15:04:05.123Z","level":"warn","node_id":"node-1","component":"gossip","msg":"...","fields":{"peer":"node-2"}}
+type jsonEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	NodeID    string    `json:"node_id,omitempty"`
+	Component string    `json:"component,omitempty"`
+	Msg       string    `json:"msg"`
+	Fields    Fields    `json:"fields,omitempty"`
+}
+
+// encodeJSON returns msg logged at level as a line of JSON, without its level marker
+func encodeJSON(now time.Time, level Level, component, nodeID string, fields Fields, msg string) []byte {
+	_, msg = parseLevelMarker(msg)
+	// Strings and a time can't fail to encode
+	line, _ := json.Marshal(jsonEntry{
+		Timestamp: now,
+		Level:     level.String(),
+		NodeID:    nodeID,
+		Component: component,
+		Msg:       msg,
+		Fields:    fields,
+	})
+	return append(line, '\n')
+}
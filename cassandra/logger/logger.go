@@ -1,6 +1,8 @@
 // Package logger provides a configurable logger that can write to multiple outputs.
-// Init must be called early in the application lifecycle before using other logger functions.
-// Functions like AddOutput and SetEnabled will return errors if called before Init.
+// The package functions log through a default logger, set by Init or SetDefault, which must
+// be called early in the application lifecycle before using other logger functions.
+// Functions like AddOutput and SetEnabled will return errors if called before it is set.
+// Loggers of their own, e.g. for tests, are created with New.
 package logger
 
 import (
@@ -9,124 +11,381 @@ import (
 	"io"
 	"log"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// Options configure a Logger created with New
+type Options struct {
+	Prefix       string        // put before every message as "[prefix] ", if set
+	Outputs      []io.Writer   // written every entry, see AddOutput
+	Level        Level         // lowest level logged, LevelDebug if unset
+	Format       Format        // of the outputs that only take text
+	RepeatWindow time.Duration // see SetRepeatWindow
+}
+
 // Logger is a configurable logger that can write to multiple outputs
 type Logger struct {
 	mu       sync.Mutex
 	outputs  []io.Writer
 	prefix   string
 	enabled  bool
+	level    Level
+	format   Format // of the outputs that only take text
+
+	// Collapsing of repeated messages, see SetRepeatWindow
+	repeatWindow time.Duration
+	repeats      map[repeatKey]*repeat
+}
+
+// Level is the severity of a log message. Messages below the logger's level are dropped.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level's name as accepted by ParseLevel
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// entryWriter is implemented by outputs that keep the level, component and node of every line
+// rather than just its text, like LogBufferWriter. nodeID is empty when the line was logged
+// without a SubLogger for a node (see ForNode).
+type entryWriter interface {
+	writeEntry(level Level, component, nodeID string, fields Fields, line string)
+}
+
+// ParseLevel parses "debug", "info", "warn" (or "warning") or "error"
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q: use debug, info, warn or error", s)
+	}
 }
 
 var (
-	globalLogger *Logger
-	once         sync.Once
+	globalLogger atomic.Pointer[Logger] // the default logger, nil until Init or SetDefault
+	bufferMu     sync.Mutex
 	globalBuffer *LogBuffer
-	bufferOnce   sync.Once
 )
 
+// errNotInitialized is returned by the package functions configuring the default logger
+// before it is set
+var errNotInitialized = errors.New("logger not initialized: call logger.Init() first")
+
 // GetGlobalLogBuffer returns the global log buffer
 func GetGlobalLogBuffer() *LogBuffer {
-	bufferOnce.Do(func() {
+	bufferMu.Lock()
+	defer bufferMu.Unlock()
+	if globalBuffer == nil {
 		globalBuffer = NewLogBuffer(1000) // Keep last 1000 log entries
-	})
+	}
 	return globalBuffer
 }
 
-// Init initializes the global logger
+// New creates a logger configured with options. It logs independently of the default logger
+// and of other loggers.
+func New(options Options) *Logger {
+	return &Logger{
+		outputs:      slices.Clone(options.Outputs),
+		prefix:       options.Prefix,
+		enabled:      true,
+		level:        options.Level,
+		format:       options.Format,
+		repeatWindow: max(0, options.RepeatWindow),
+	}
+}
+
+// Init initializes the default logger at info level, unless it is already set
 func Init(prefix string, writeToStdout bool) {
-	once.Do(func() {
-		outputs := []io.Writer{}
-		if writeToStdout {
-			outputs = append(outputs, os.Stdout)
-		}
-		globalLogger = &Logger{
-			outputs: outputs,
-			prefix:  prefix,
-			enabled: true,
-		}
-	})
+	outputs := []io.Writer{}
+	if writeToStdout {
+		outputs = append(outputs, os.Stdout)
+	}
+	globalLogger.CompareAndSwap(nil, New(Options{Prefix: prefix, Outputs: outputs, Level: LevelInfo}))
+}
+
+// SetDefault makes l the logger of the package functions, and of the SubLoggers created with
+// Named and ForNode, replacing the one set by Init or a previous SetDefault
+func SetDefault(l *Logger) {
+	globalLogger.Store(l)
+}
+
+// ResetForTest unsets the default logger and empties the global log buffer, so a test can
+// Init them again as it needs them
+func ResetForTest() {
+	globalLogger.Store(nil)
+	bufferMu.Lock()
+	globalBuffer = nil
+	bufferMu.Unlock()
+}
+
+// defaultLogger returns the default logger, or an error if it isn't set yet
+func defaultLogger() (*Logger, error) {
+	l := globalLogger.Load()
+	if l == nil {
+		return nil, errNotInitialized
+	}
+	return l, nil
 }
 
 // AddOutput adds an additional output writer (e.g., for TUI log buffer).
 // Returns an error if called before Init.
 func AddOutput(w io.Writer) error {
-	if globalLogger == nil {
-		return errors.New("logger not initialized: call logger.Init() first")
+	l, err := defaultLogger()
+	if err != nil {
+		return err
 	}
-	globalLogger.mu.Lock()
-	defer globalLogger.mu.Unlock()
-	globalLogger.outputs = append(globalLogger.outputs, w)
+	l.AddOutput(w)
 	return nil
 }
 
+// AddOutput adds an additional output writer
+func (l *Logger) AddOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.outputs = append(l.outputs, w)
+}
+
 // RemoveOutput removes an output writer.
 // Returns an error if called before Init.
 func RemoveOutput(w io.Writer) error {
-	if globalLogger == nil {
-		return errors.New("logger not initialized: call logger.Init() first")
+	l, err := defaultLogger()
+	if err != nil {
+		return err
 	}
-	globalLogger.mu.Lock()
-	defer globalLogger.mu.Unlock()
-	
+	l.RemoveOutput(w)
+	return nil
+}
+
+// RemoveOutput removes an output writer
+func (l *Logger) RemoveOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	newOutputs := []io.Writer{}
-	for _, output := range globalLogger.outputs {
+	for _, output := range l.outputs {
 		if output != w {
 			newOutputs = append(newOutputs, output)
 		}
 	}
-	globalLogger.outputs = newOutputs
-	return nil
+	l.outputs = newOutputs
 }
 
 // SetEnabled enables or disables logging.
 // Returns an error if called before Init.
 func SetEnabled(enabled bool) error {
-	if globalLogger == nil {
-		return errors.New("logger not initialized: call logger.Init() first")
+	l, err := defaultLogger()
+	if err != nil {
+		return err
+	}
+	l.SetEnabled(enabled)
+	return nil
+}
+
+// SetEnabled enables or disables logging
+func (l *Logger) SetEnabled(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enabled = enabled
+}
+
+// SetLevel sets the lowest level that is logged, LevelInfo by default.
+// Returns an error if called before Init.
+func SetLevel(level Level) error {
+	l, err := defaultLogger()
+	if err != nil {
+		return err
+	}
+	l.SetLevel(level)
+	return nil
+}
+
+// SetLevel sets the lowest level that is logged
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetFormat sets how entries are written to the outputs that only take text, FormatText by default.
+// Returns an error if called before Init.
+func SetFormat(format Format) error {
+	l, err := defaultLogger()
+	if err != nil {
+		return err
 	}
-	globalLogger.mu.Lock()
-	defer globalLogger.mu.Unlock()
-	globalLogger.enabled = enabled
+	l.SetFormat(format)
 	return nil
 }
 
-// Printf logs a formatted message
+// SetFormat sets how entries are written to the outputs that only take text
+func (l *Logger) SetFormat(format Format) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+}
+
+// Printf logs a formatted message at info level
 func Printf(format string, v ...interface{}) {
-	if globalLogger == nil {
+	logf(LevelInfo, format, v...)
+}
+
+// logf logs a formatted message if level is at least the logger's level
+func logf(level Level, format string, v ...interface{}) {
+	write(level, "", "", nil, fmt.Sprintf(format, v...))
+}
+
+// write logs through the default logger, see Logger.write
+func write(level Level, component, nodeID string, fields Fields, msg string) {
+	l := globalLogger.Load()
+	if l == nil {
 		// Fallback to standard log if not initialized
-		log.Printf(format, v...)
+		log.Print(withFields(withNode(msg, nodeID), fields))
 		return
 	}
+	l.write(level, component, nodeID, fields, msg)
+}
+
+// write logs msg, which may start with a level marker, and fields if level is at least the
+// logger's level. An empty component is the caller's package, and an empty nodeID makes it
+// the system's.
+func (l *Logger) write(level Level, component, nodeID string, fields Fields, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	
-	globalLogger.mu.Lock()
-	defer globalLogger.mu.Unlock()
-	
-	if !globalLogger.enabled {
+	if !l.enabled || level < l.level {
 		return
 	}
 	
-	msg := fmt.Sprintf(format, v...)
 	// Remove trailing newline if present (we'll add it back)
 	msg = strings.TrimSuffix(msg, "\n")
+	if component == "" {
+		component = callerComponent()
+	}
+	if l.suppressRepeat(level, component, nodeID, fields, msg) {
+		return
+	}
+	l.emit(level, component, nodeID, fields, msg)
+}
+
+// emit writes msg to every output. Caller must hold l.mu.
+func (l *Logger) emit(level Level, component, nodeID string, fields Fields, msg string) {
+	var line []byte
+	if l.format == FormatJSON {
+		line = encodeJSON(time.Now(), level, component, nodeID, fields, msg)
+	}
 	
 	// Add prefix if specified
-	if globalLogger.prefix != "" {
-		msg = fmt.Sprintf("[%s] %s", globalLogger.prefix, msg)
+	if l.prefix != "" {
+		msg = fmt.Sprintf("[%s] %s", l.prefix, msg)
 	}
 	
 	// Write to all outputs
-	if len(globalLogger.outputs) > 0 {
-		msgWithNewline := msg + "\n"
-		for _, output := range globalLogger.outputs {
-			output.Write([]byte(msgWithNewline))
+	if len(l.outputs) > 0 {
+		if line == nil {
+			line = []byte(withFields(withNode(msg, nodeID), fields) + "\n")
+		}
+		for _, output := range l.outputs {
+			if ew, ok := output.(entryWriter); ok {
+				ew.writeEntry(level, component, nodeID, fields, msg)
+				continue
+			}
+			output.Write(line)
 		}
 	}
 }
 
+// withFields puts fields after msg, for outputs that only keep the text
+func withFields(msg string, fields Fields) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	return msg + " " + fields.String()
+}
+
+// withNode puts the "[nodeID] " prefix in msg, after its level marker, for outputs that only
+// keep the text: "[WARN] [node-1] message"
+func withNode(msg, nodeID string) string {
+	if nodeID == "" {
+		return msg
+	}
+	_, rest := parseLevelMarker(msg)
+	marker := msg[:len(msg)-len(rest)]
+	return marker + "[" + nodeID + "] " + rest
+}
+
+// callerComponent returns the package name of the first caller outside this package,
+// e.g. "gossip" or "transport"
+func callerComponent() string {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		name := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		if pkg, _, _ := strings.Cut(name, "."); pkg != "logger" {
+			return pkg
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// Printf logs a formatted message at info level
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.write(LevelInfo, "", "", nil, fmt.Sprintf(format, v...))
+}
+
+// Debugf logs a debug-level formatted message
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.write(LevelDebug, "", "", nil, "[DEBUG] "+fmt.Sprintf(format, v...))
+}
+
+// Infof logs an info-level formatted message
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.write(LevelInfo, "", "", nil, "[INFO] "+fmt.Sprintf(format, v...))
+}
+
+// Warnf logs a warning-level formatted message
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.write(LevelWarn, "", "", nil, "[WARN] "+fmt.Sprintf(format, v...))
+}
+
+// Errorf logs an error-level formatted message
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.write(LevelError, "", "", nil, "[ERROR] "+fmt.Sprintf(format, v...))
+}
+
 // Print logs a message
 func Print(v ...interface{}) {
 	Printf("%s", fmt.Sprint(v...))
@@ -137,28 +396,48 @@ func Println(v ...interface{}) {
 	Printf("%s", fmt.Sprintln(v...))
 }
 
+// Debugf logs a debug-level formatted message
+func Debugf(format string, v ...interface{}) {
+	logf(LevelDebug, "[DEBUG] "+format, v...)
+}
+
+// Debug logs a debug-level message
+func Debug(v ...interface{}) {
+	logf(LevelDebug, "[DEBUG] %s", fmt.Sprint(v...))
+}
+
 // Infof logs an info-level formatted message
 func Infof(format string, v ...interface{}) {
-	Printf("[INFO] "+format, v...)
+	logf(LevelInfo, "[INFO] "+format, v...)
 }
 
 // Info logs an info-level message
 func Info(v ...interface{}) {
-	Printf("[INFO] %s", fmt.Sprint(v...))
+	logf(LevelInfo, "[INFO] %s", fmt.Sprint(v...))
+}
+
+// Warnf logs a warning-level formatted message
+func Warnf(format string, v ...interface{}) {
+	logf(LevelWarn, "[WARN] "+format, v...)
+}
+
+// Warn logs a warning-level message
+func Warn(v ...interface{}) {
+	logf(LevelWarn, "[WARN] %s", fmt.Sprint(v...))
 }
 
 // Errorf logs an error-level formatted message
 func Errorf(format string, v ...interface{}) {
-	Printf("[ERROR] "+format, v...)
+	logf(LevelError, "[ERROR] "+format, v...)
 }
 
 // Error logs an error-level message
 func Error(v ...interface{}) {
-	Printf("[ERROR] %s", fmt.Sprint(v...))
+	logf(LevelError, "[ERROR] %s", fmt.Sprint(v...))
 }
 
-// GetGlobalLogger returns the global logger instance (for testing/debugging)
+// GetGlobalLogger returns the default logger (for testing/debugging)
 func GetGlobalLogger() *Logger {
-	return globalLogger
+	return globalLogger.Load()
 }
 
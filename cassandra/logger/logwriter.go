@@ -3,21 +3,18 @@ package logger
 import (
 	"bytes"
 	"io"
-	"regexp"
 	"strings"
 	"sync"
 )
 
-// LogBufferWriter is an io.Writer that writes to the log buffer
-// It extracts node ID from log messages in the format "[nodeID] message"
+// LogBufferWriter is an io.Writer that writes to the log buffer. The logger gives it the node
+// of every entry logged with a SubLogger for a node (see ForNode); other lines are the system's.
 type LogBufferWriter struct {
 	buffer *LogBuffer
 	buf    bytes.Buffer
 	mu     sync.Mutex
 }
 
-var nodeIDRegex = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
-
 // NewLogBufferWriter creates a new writer that writes to the log buffer
 func NewLogBufferWriter(buffer *LogBuffer) *LogBufferWriter {
 	return &LogBufferWriter{
@@ -52,20 +49,43 @@ func (lw *LogBufferWriter) Write(p []byte) (n int, err error) {
 			continue
 		}
 
-		// Try to extract node ID from format "[nodeID] message"
-		nodeID := "system"
-		message := line
+		level, line := parseLevelMarker(line)
+		lw.add(level, "", "", nil, line)
+	}
 
-		matches := nodeIDRegex.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			nodeID = matches[1]
-			message = matches[2]
-		}
+	return written, nil
+}
+
+// writeEntry adds a line logged at level by component, see entryWriter
+func (lw *LogBufferWriter) writeEntry(level Level, component, nodeID string, fields Fields, line string) {
+	_, line = parseLevelMarker(line)
+	lw.add(level, component, nodeID, fields, line)
+}
 
-		// Add to log buffer
-		lw.buffer.Add(nodeID, message)
+// add adds line to the log buffer, as the system's if nodeID is empty
+func (lw *LogBufferWriter) add(level Level, component, nodeID string, fields Fields, line string) {
+	if nodeID == "" {
+		nodeID = "system"
 	}
+	lw.buffer.AddEntry(LogEntry{NodeID: nodeID, Message: line, Level: level, Component: component, Fields: fields})
+}
 
-	return written, nil
+// levelMarkers are the markers Debugf, Infof, Warnf and Errorf put before messages
+var levelMarkers = map[string]Level{
+	"[DEBUG] ": LevelDebug,
+	"[INFO] ":  LevelInfo,
+	"[WARN] ":  LevelWarn,
+	"[ERROR] ": LevelError,
+}
+
+// parseLevelMarker strips the level marker from line and returns its level, LevelInfo for
+// lines without marker
+func parseLevelMarker(line string) (Level, string) {
+	for marker, level := range levelMarkers {
+		if rest, ok := strings.CutPrefix(line, marker); ok {
+			return level, rest
+		}
+	}
+	return LevelInfo, line
 }
 
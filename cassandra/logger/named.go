@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+)
+
+// SubLogger logs through a Logger, the default one unless created from one, on behalf of a
+// component, and of a node once WithNode is called. Both are set on every entry as they are,
+// rather than found from the caller's package and parsed out of a "[nodeID] " prefix of the
+// message, and so are the fields added with With.
+type SubLogger struct {
+	logger    *Logger // nil for the default logger
+	component string
+	nodeID    string
+	fields    Fields
+}
+
+// Named returns a logger for component, e.g. logger.Named("gossip"). It can be created before
+// Init, it logs through the default logger as it is when logging.
+func Named(component string) *SubLogger {
+	return &SubLogger{component: component}
+}
+
+// ForNode returns a logger tagging its entries with nodeID, for the package it is called from,
+// e.g. "node"
+func ForNode(nodeID string) *SubLogger {
+	return &SubLogger{nodeID: nodeID}
+}
+
+// Named returns a logger for component logging through l
+func (l *Logger) Named(component string) *SubLogger {
+	return &SubLogger{logger: l, component: component}
+}
+
+// ForNode returns a logger tagging its entries with nodeID logging through l
+func (l *Logger) ForNode(nodeID string) *SubLogger {
+	return &SubLogger{logger: l, nodeID: nodeID}
+}
+
+// WithNode returns a logger for l's component that tags its entries with nodeID
+func (l *SubLogger) WithNode(nodeID string) *SubLogger {
+	return &SubLogger{logger: l.logger, component: l.component, nodeID: nodeID, fields: l.fields}
+}
+
+// With returns a logger adding the key/value pairs of keysAndValues to l's fields, e.g.
+// log.With("peer", peerID, "attempt", 3). Values are formatted with fmt.Sprint; a key
+// without a value gets an empty one.
+func (l *SubLogger) With(keysAndValues ...any) *SubLogger {
+	fields := make(Fields, len(l.fields)+len(keysAndValues)/2)
+	maps.Copy(fields, l.fields)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		fields[key] = ""
+		if i+1 < len(keysAndValues) {
+			fields[key] = fmt.Sprint(keysAndValues[i+1])
+		}
+	}
+	return &SubLogger{logger: l.logger, component: l.component, nodeID: l.nodeID, fields: fields}
+}
+
+// write logs msg through l's logger
+func (l *SubLogger) write(level Level, msg string) {
+	if l.logger != nil {
+		l.logger.write(level, l.component, l.nodeID, l.fields, msg)
+		return
+	}
+	write(level, l.component, l.nodeID, l.fields, msg)
+}
+
+// Printf logs a formatted message at info level, like the global Printf
+func (l *SubLogger) Printf(format string, v ...interface{}) {
+	l.write(LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// Debugf logs a debug-level formatted message
+func (l *SubLogger) Debugf(format string, v ...interface{}) {
+	l.write(LevelDebug, "[DEBUG] "+fmt.Sprintf(format, v...))
+}
+
+// Infof logs an info-level formatted message
+func (l *SubLogger) Infof(format string, v ...interface{}) {
+	l.write(LevelInfo, "[INFO] "+fmt.Sprintf(format, v...))
+}
+
+// Warnf logs a warning-level formatted message
+func (l *SubLogger) Warnf(format string, v ...interface{}) {
+	l.write(LevelWarn, "[WARN] "+fmt.Sprintf(format, v...))
+}
+
+// Errorf logs an error-level formatted message
+func (l *SubLogger) Errorf(format string, v ...interface{}) {
+	l.write(LevelError, "[ERROR] "+fmt.Sprintf(format, v...))
+}
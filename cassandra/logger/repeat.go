@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// repeatKey identifies the messages collapsed together: the same text, fields included, of
+// the same node
+type repeatKey struct {
+	nodeID string
+	msg    string
+}
+
+// repeat is a message logged in the current window, and how many times it was repeated since
+type repeat struct {
+	level     Level
+	component string
+	fields    Fields
+	msg       string
+	count     int
+}
+
+// SetRepeatWindow collapses a message repeated within window of being logged, e.g. a failed
+// heartbeat every gossip round, into one "msg ... repeated N times" entry at the end of the
+// window. Messages are compared per node, fields but the trace ID included. 0, the default, logs every message.
+// Returns an error if called before Init.
+func SetRepeatWindow(window time.Duration) error {
+	l, err := defaultLogger()
+	if err != nil {
+		return err
+	}
+	l.SetRepeatWindow(window)
+	return nil
+}
+
+// SetRepeatWindow collapses messages repeated within window, see the package's SetRepeatWindow
+func (l *Logger) SetRepeatWindow(window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.repeatWindow = max(0, window)
+}
+
+// suppressRepeat reports whether msg repeats a message logged within the window, counting it
+// rather than logging it. A message starting a window ends it after the window. Caller must
+// hold l.mu.
+func (l *Logger) suppressRepeat(level Level, component, nodeID string, fields Fields, msg string) bool {
+	if l.repeatWindow <= 0 {
+		return false
+	}
+	// The trace ID tells occurrences apart, it isn't part of the message
+	if _, ok := fields[traceField]; ok {
+		fields = maps.Clone(fields)
+		delete(fields, traceField)
+	}
+	key := repeatKey{nodeID: nodeID, msg: withFields(msg, fields)}
+	if r, ok := l.repeats[key]; ok {
+		r.count++
+		return true
+	}
+	if l.repeats == nil {
+		l.repeats = make(map[repeatKey]*repeat)
+	}
+	l.repeats[key] = &repeat{level: level, component: component, fields: fields, msg: msg}
+	time.AfterFunc(l.repeatWindow, func() { l.endRepeat(key) })
+	return false
+}
+
+// endRepeat ends the window of the message with key, logging how many times it was repeated
+// in it if it was
+func (l *Logger) endRepeat(key repeatKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.repeats[key]
+	delete(l.repeats, key)
+	if r == nil || r.count == 0 || !l.enabled {
+		return
+	}
+	l.emit(r.level, r.component, key.nodeID, r.fields, fmt.Sprintf("%s ... repeated %d times", r.msg, r.count))
+}
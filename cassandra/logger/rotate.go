@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the time in the name of rotated files, sortable and valid on every OS
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions bound the disk a RotatingFile uses. Zero values don't bound anything.
+type RotateOptions struct {
+	MaxSizeMB  int // size the file is rotated at
+	MaxBackups int // rotated files kept, the newest ones
+	MaxAgeDays int // age rotated files are deleted at
+}
+
+// RotatingFile is an output appending to a file, which it renames once it reaches MaxSizeMB,
+// e.g. node.log to node-2025-01-02T15-04-05.000.log, to start a new one. Old rotated files
+// are deleted beyond MaxBackups and MaxAgeDays.
+type RotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	options RotateOptions
+	file    *os.File
+	size    int64
+}
+
+// OpenRotatingFile opens path for appending, creating it if needed, and deletes the rotated
+// files beyond the limits
+func OpenRotatingFile(path string, options RotateOptions) (*RotatingFile, error) {
+	if options.MaxSizeMB < 0 || options.MaxBackups < 0 || options.MaxAgeDays < 0 {
+		return nil, fmt.Errorf("invalid log rotation: sizes, backups and ages can't be negative")
+	}
+	f := &RotatingFile{path: path, options: options}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	f.prune(time.Now())
+	return f, nil
+}
+
+// open opens the file at f.path, with its current size
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write implements io.Writer, rotating the file first if p would take it past MaxSizeMB.
+// The logger writes a line at a time, so lines aren't split across files.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	maxSize := int64(f.options.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > maxSize {
+		// A file that couldn't be rotated keeps growing rather than losing the log
+		if err := f.rotate(); err != nil && f.file == nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// rotate renames the file to a backup, starts a new one and deletes the backups beyond the
+// limits. Caller must hold f.mu.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	ext := filepath.Ext(f.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), time.Now().Format(backupTimeFormat), ext)
+	if err := os.Rename(f.path, backup); err != nil {
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.prune(time.Now())
+	return nil
+}
+
+// prune deletes the backups beyond MaxBackups and older than MaxAgeDays. Failing to is only
+// a waste of disk, so errors are ignored. Caller must hold f.mu.
+func (f *RotatingFile) prune(now time.Time) {
+	backups := f.backups()
+	// Newest first: the time in their names sorts them
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	for i, backup := range backups {
+		expired := false
+		if f.options.MaxAgeDays > 0 {
+			info, err := os.Stat(backup)
+			expired = err == nil && now.Sub(info.ModTime()) > time.Duration(f.options.MaxAgeDays)*24*time.Hour
+		}
+		if (f.options.MaxBackups > 0 && i >= f.options.MaxBackups) || expired {
+			os.Remove(backup)
+		}
+	}
+}
+
+// backups returns the paths of the rotated files of f.path
+func (f *RotatingFile) backups() []string {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+	entries, _ := os.ReadDir(dir)
+
+	var backups []string
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext)); err == nil {
+			backups = append(backups, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return backups
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// The gossipHandler also serves the AdminService (transport.AdminHandler), so operators
+// can inspect and change a running node over the same transport.
+var _ transport.AdminHandler = (*gossipHandler)(nil)
+
+func (h *gossipHandler) GetClusterState(ctx context.Context) (transport.ClusterState, error) {
+	gossipState := h.node.GetGossipState()
+	state := transport.ClusterState{
+		NodeID:     h.node.config.NodeID,
+		ClusterID:  h.node.config.ClusterID,
+		Endpoints:  gossipState.Endpoints(time.Now()),
+		Conflicts:  gossipState.Conflicts(),
+		Stats:      gossipState.Stats(),
+		ReadRepair: h.node.coordinator.ReadRepairStats(),
+	}
+	if store := h.node.Store(); store != nil {
+		state.Storage = store.Stats()
+	}
+	return state, nil
+}
+
+func (h *gossipHandler) RemoveNode(ctx context.Context, nodeID gossip.NodeID) error {
+	return h.node.GetGossipState().RemoveEndpoint(nodeID)
+}
+
+func (h *gossipHandler) SetAppState(ctx context.Context, key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	return h.node.SetAppState(key, value)
+}
+
+func (h *gossipHandler) TriggerGossipRound(ctx context.Context) error {
+	h.node.logf("Gossip round triggered by admin request")
+	return h.node.SendGossipRound()
+}
+
+// Decommission runs Node.Decommission in the background, since the node stops at the end
+func (h *gossipHandler) Decommission(ctx context.Context) (time.Duration, error) {
+	if status := h.node.Status(); status != StatusRunning {
+		return 0, fmt.Errorf("%w: cannot decommission a %s node", ErrInvalidTransition, status)
+	}
+	if h.node.Decommissioning() {
+		return 0, ErrDecommissioning
+	}
+	go func() {
+		if err := h.node.Decommission(); err != nil {
+			h.node.logf("Decommission failed: %v", err)
+		}
+	}()
+	return h.node.config.DecommissionDrain, nil
+}
+
+// Logs streams the process's log buffer. With several nodes in one process (cluster,
+// interactive) it holds every node's entries, query.NodeID picks one node's.
+func (h *gossipHandler) Logs(ctx context.Context, query transport.LogsQuery) (<-chan logger.LogEntry, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	history, follow := logger.GetGlobalLogBuffer().Follow(ctx)
+	matches := func(entry logger.LogEntry) bool {
+		return query.NodeID == "" || entry.NodeID == query.NodeID
+	}
+
+	var tail []logger.LogEntry
+	for _, entry := range history {
+		if matches(entry) {
+			tail = append(tail, entry)
+		}
+	}
+	if query.Tail >= 0 && len(tail) > query.Tail {
+		tail = tail[len(tail)-query.Tail:]
+	}
+
+	entries := make(chan logger.LogEntry)
+	go func() {
+		defer close(entries)
+		defer cancel()
+		send := func(entry logger.LogEntry) bool {
+			select {
+			case entries <- entry:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, entry := range tail {
+			if !send(entry) {
+				return
+			}
+		}
+		if !query.Follow {
+			return
+		}
+		for entry := range follow {
+			if matches(entry) && !send(entry) {
+				return
+			}
+		}
+	}()
+	return entries, nil
+}
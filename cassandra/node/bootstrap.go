@@ -0,0 +1,189 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// bootstrap makes a node that picked new tokens join the ring with the keys of the ranges it
+// takes over, like Cassandra's bootstrap: it learns the ring from its seeds, streams every
+// range it becomes a replica of from the nodes holding it now, and then gossips STATUS NORMAL
+// so the cluster adds it to the ring. Until then it gossips STATUS BOOT, which keeps it off
+// every node's ring, so reads of its ranges still go to their current replicas. Writes reach
+// it as well, as a pending replica of the ranges it takes over (see writeReplicas), so it
+// misses none of the keys written while it streams. It runs once the node is running, until
+// ctx is done.
+//
+// Seeds join without bootstrapping, as does a node with no one to learn the ring from. Ranges
+// that no replica could stream are left to read repair.
+func (n *Node) bootstrap(ctx context.Context) error {
+	if reason := n.skipBootstrap(); reason != "" {
+		n.logf("Joining without bootstrapping: %s", reason)
+	} else if err := n.streamJoiningRanges(ctx); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.joining = false
+	n.mu.Unlock()
+	n.GetGossipState().SetLocalAppState(gossip.AppStatus, gossip.StatusNormal)
+	// Spread it now instead of waiting for the next round
+	n.startGossipRound(ctx)
+	return nil
+}
+
+// skipBootstrap returns why the node joins without bootstrapping, "" if it bootstraps
+func (n *Node) skipBootstrap() string {
+	contacts := len(n.savedPeers)
+	for _, seed := range n.config.Seeds {
+		if n.isSelf(seed) {
+			return "it is a seed"
+		}
+		contacts++
+	}
+	if contacts == 0 {
+		return "no seeds to learn the ring from"
+	}
+	return ""
+}
+
+// streamJoiningRanges streams the ranges the node takes over into its store
+func (n *Node) streamJoiningRanges(ctx context.Context) error {
+	start := time.Now()
+	current, err := n.waitForRing(ctx)
+	if err != nil {
+		return err
+	}
+	if current.Len() == 0 {
+		n.logf("Joining without bootstrapping: learned no ring within %v", n.config.RingDelay)
+		return nil
+	}
+
+	transfers := current.Joining(n.config.NodeID, n.tokens, n.strategy)
+	n.logf("Bootstrapping: streaming %d ranges from a ring of %d nodes", len(transfers), len(current.Nodes()))
+
+	// Every range is asked of its first source, then of the next one if that fails
+	keys := 0
+	pending := transfers
+	var missed []ring.Transfer
+	for attempt := 0; len(pending) > 0; attempt++ {
+		bySource := make(map[gossip.NodeID][]ring.Transfer)
+		for _, transfer := range pending {
+			if attempt == len(transfer.Sources) {
+				missed = append(missed, transfer)
+				continue
+			}
+			source := transfer.Sources[attempt]
+			bySource[source] = append(bySource[source], transfer)
+		}
+
+		pending = nil
+		for source, sourceTransfers := range bySource {
+			streamed, err := n.streamFrom(ctx, source, sourceTransfers)
+			keys += streamed
+			if ctx.Err() != nil {
+				return fmt.Errorf("node stopped while bootstrapping")
+			}
+			if err != nil {
+				n.logf("Failed to stream %d ranges from %s: %v", len(sourceTransfers), source, err)
+				pending = append(pending, sourceTransfers...)
+				continue
+			}
+			n.logf("Streamed %d keys of %d ranges from %s", streamed, len(sourceTransfers), source)
+		}
+	}
+
+	n.logf("Bootstrapped: streamed %d keys of %d ranges in %v", keys, len(transfers)-len(missed),
+		time.Since(start).Round(time.Millisecond))
+	if len(missed) > 0 {
+		n.logf("No replica could stream %d ranges, joining without them: read repair will fill them in", len(missed))
+	}
+	return nil
+}
+
+// waitForRing gossips with the seeds for RingDelay, like Cassandra before it streams: long
+// enough to learn the ring of the other nodes, which it returns, and for them to learn the node
+// is joining, so writes reach it as a pending replica before the ranges it streams are read.
+// The ring is empty if it learned none.
+func (n *Node) waitForRing(ctx context.Context) (*ring.Ring, error) {
+	// Contact the seeds now instead of waiting for the next round
+	n.startGossipRound(ctx)
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("node stopped while bootstrapping")
+	case <-time.After(n.config.RingDelay):
+	}
+	return n.Ring(), nil
+}
+
+// streamFrom streams the ranges of transfers from source into the store, returning how many
+// keys it received
+func (n *Node) streamFrom(ctx context.Context, source gossip.NodeID, transfers []ring.Transfer) (int, error) {
+	peer, err := n.remoteReplica(source).kvPeer()
+	if err != nil {
+		return 0, err
+	}
+	ranges := make([]ring.Range, len(transfers))
+	for i, transfer := range transfers {
+		ranges[i] = transfer.Range
+	}
+
+	store := n.Store()
+	keys := 0
+	err = peer.StreamRanges(ctx, ranges, func(version transport.KeyVersion) error {
+		// Put keeps the newest version, so a range streamed again after a failure merges
+		if err := store.Put(version.Key, version.Cell); err != nil {
+			return err
+		}
+		keys++
+		return nil
+	})
+	return keys, err
+}
+
+// streamRanges passes send the version of every key the node holds in ranges, for a node
+// that is bootstrapping
+func (n *Node) streamRanges(ctx context.Context, ranges []ring.Range, send func(transport.KeyVersion) error) error {
+	if status := n.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: a %s node serves no keys", ErrInvalidTransition, status)
+	}
+	store := n.Store()
+	keys, err := store.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token := ring.KeyToken(key)
+		if !slices.ContainsFunc(ranges, func(r ring.Range) bool { return r.Contains(token) }) {
+			continue
+		}
+		cell, found, err := store.Get(key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue // purged since
+		}
+		if err := send(transport.KeyVersion{Key: key, Cell: cell}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *gossipHandler) StreamRanges(ctx context.Context, ranges []ring.Range, send func(transport.KeyVersion) error) error {
+	if err := h.checkPaused(); err != nil {
+		return err
+	}
+	return h.node.streamRanges(ctx, ranges, send)
+}
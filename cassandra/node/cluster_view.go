@@ -0,0 +1,116 @@
+package node
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// Belief is what one node believes about another
+type Belief struct {
+	Known      bool // false when the observer hasn't heard of the node
+	Generation int64
+	Version    int64 // highest version of the node's state it has seen
+	Alive      bool
+}
+
+// ClusterView is what every observed node believes about every node any of them knows:
+// Beliefs[i][j] is what Observers[i] believes about Nodes[j]. Both are sorted by node ID.
+type ClusterView struct {
+	Observers []gossip.NodeID
+	Nodes     []gossip.NodeID
+	Beliefs   [][]Belief
+}
+
+// NewClusterView builds the view of the nodes that reported states
+func NewClusterView(states []transport.ClusterState) ClusterView {
+	states = slices.Clone(states)
+	slices.SortFunc(states, func(a, b transport.ClusterState) int {
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+
+	var view ClusterView
+	for _, state := range states {
+		view.Observers = append(view.Observers, state.NodeID)
+		for _, endpoint := range state.Endpoints {
+			view.Nodes = append(view.Nodes, endpoint.State.HeartbeatState.NodeID)
+		}
+	}
+	slices.Sort(view.Nodes)
+	view.Nodes = slices.Compact(view.Nodes)
+
+	column := make(map[gossip.NodeID]int, len(view.Nodes))
+	for j, nodeID := range view.Nodes {
+		column[nodeID] = j
+	}
+	view.Beliefs = make([][]Belief, len(states))
+	for i, state := range states {
+		view.Beliefs[i] = make([]Belief, len(view.Nodes))
+		for _, endpoint := range state.Endpoints {
+			view.Beliefs[i][column[endpoint.State.HeartbeatState.NodeID]] = Belief{
+				Known:      true,
+				Generation: endpoint.State.HeartbeatState.Generation,
+				Version:    endpoint.State.MaxVersion(),
+				Alive:      endpoint.Alive,
+			}
+		}
+	}
+	return view
+}
+
+// Latest returns the newest state any observer has seen of the node in column j
+func (v ClusterView) Latest(j int) Belief {
+	var latest Belief
+	for _, row := range v.Beliefs {
+		belief := row[j]
+		if !belief.Known {
+			continue
+		}
+		if !latest.Known || belief.Generation > latest.Generation ||
+			(belief.Generation == latest.Generation && belief.Version > latest.Version) {
+			latest = belief
+		}
+	}
+	return latest
+}
+
+// Converged reports whether every observer knows every node at the same generation and version.
+// Heartbeats keep versions moving, so a running cluster is only converged between rounds.
+func (v ClusterView) Converged() bool {
+	for j := range v.Nodes {
+		latest := v.Latest(j)
+		for _, row := range v.Beliefs {
+			if !row[j].Known || row[j].Generation != latest.Generation || row[j].Version != latest.Version {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ClusterView returns what every running managed node, and every connected attached node,
+// believes about every node it knows. Stopped and failed nodes are left out as observers,
+// their state is frozen.
+func (m *Manager) ClusterView() ClusterView {
+	now := time.Now()
+	var states []transport.ClusterState
+	for _, node := range m.GetNodes() {
+		if status := node.Status(); status == StatusStopped || status == StatusFailed {
+			continue
+		}
+		states = append(states, transport.ClusterState{
+			NodeID:    node.config.NodeID,
+			ClusterID: node.config.ClusterID,
+			Endpoints: node.GetGossipState().Endpoints(now),
+		})
+	}
+	for _, remote := range m.RemoteNodes() {
+		if remote.Connected() {
+			states = append(states, transport.ClusterState{NodeID: remote.nodeID, Endpoints: remote.Endpoints()})
+		}
+	}
+	return NewClusterView(states)
+}
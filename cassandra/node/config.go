@@ -1,46 +1,218 @@
 package node
 
 import (
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/snitch"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
 )
 
 // Default configuration constants
 const (
-	DefaultAddress     = "127.0.0.1"
-	DefaultPort        = "50051"
-	DefaultNodeID      = "node-1"
-	DefaultTarget      = "127.0.0.1:50051"
-	DefaultClientMode  = false
+	DefaultAddress    = "127.0.0.1"
+	DefaultPort       = "50051"
+	DefaultNodeID     = "node-1"
+	DefaultTarget     = "127.0.0.1:50051"
+	DefaultClientMode = false
+	DefaultClusterID  = "my-cluster"
+	DefaultNumTokens  = 16 // Cassandra's num_tokens default since 4.0
+
+	DefaultReplicationFactor = 3
 )
 
 // Config holds the configuration for a node
 type Config struct {
 	// Node identification
-	NodeID gossip.NodeID
+	NodeID    gossip.NodeID
+	ClusterID string // nodes only gossip with nodes of the same cluster
 
 	// Server configuration
 	Address string
 	Port    string
 
+	// AdvertisedAddress (host:port) is how other nodes reach this one, gossiped instead of the
+	// bind address. Set it when binding 0.0.0.0 behind NAT or Docker. Defaults to Address:Port.
+	AdvertisedAddress string
+
 	// Client configuration (optional)
 	ClientMode   bool
 	TargetServer string
 
 	// Gossip configuration
 	HeartbeatInterval time.Duration
+	GossipInterval    time.Duration
+	Seeds             []string // addresses (host:port) contacted to join the cluster
+
+	// ManualHeartbeat turns off the gossip loop: the heartbeat is only bumped, and gossip only
+	// sent, by SendGossipRound (e.g. 'cassandra gossip-once'), to step through the protocol.
+	ManualHeartbeat bool
+
+	// Timeouts
+	RPCTimeout       time.Duration // deadline for each gossip RPC, so a hung peer can't stall a round
+	DialTimeout      time.Duration // bound on establishing a connection to a peer
+	KeepaliveTime    time.Duration // ping idle connections after this long (0 disables keepalive)
+	KeepaliveTimeout time.Duration // drop a connection whose ping isn't acknowledged within this
+
+	// Compression of gossip messages (optional, "" disables it)
+	Compression          string // gRPC compressor name, e.g. "gzip"
+	CompressionThreshold int    // only messages of at least this many bytes are compressed
+
+	// Peer reconnection
+	ReconnectBackoff     time.Duration // delay before redialing a failed peer, doubled on each consecutive failure
+	MaxReconnectBackoff  time.Duration // upper bound on the redial delay
+	MaxReconnectAttempts int           // consecutive failures before giving up on a peer (0 means never)
+
+	// RebindAttempts is how many times the server is restarted (with backoff) after it stops
+	// serving unexpectedly, before the node is marked failed. 0 fails the node immediately.
+	RebindAttempts int
+
+	// DecommissionDrain is how long Decommission gossips each of STATUS LEAVING and LEFT before
+	// stopping, and how long a node keeps an endpoint that left before removing it.
+	DecommissionDrain time.Duration
+
+	// DrainTimeout bounds Stop: announcing the shutdown, waiting for in-flight RPCs and closing
+	// peers. Once it expires the server is stopped forcefully.
+	DrainTimeout time.Duration
+
+	// NumTokens is how many tokens (vnodes) the node picks at random on the ring when it is
+	// first created. More tokens spread the keys more evenly, and a node joining or leaving
+	// takes or hands over small ranges from every other node instead of one neighbour. A node
+	// restarted with DataDir keeps the tokens it saved, whatever NumTokens says.
+	NumTokens int
+
+	// ReplicationFactor is how many nodes hold a copy of each key: its owner on the ring and
+	// the owners of the next tokens, see ring.Ring.Replicas. Every node must use the same.
+	ReplicationFactor int
+
+	// ReplicationStrategy places the copies of each key, see ring.Strategy: SimpleStrategy
+	// (the default) on ReplicationFactor nodes, or NetworkTopologyStrategy on as many nodes of
+	// each datacenter as DatacenterReplication says, spread over its racks. Every node must
+	// use the same.
+	ReplicationStrategy   string
+	DatacenterReplication map[string]int
+
+	// Snitch tells which datacenter and rack every node is in, see package snitch:
+	// SimpleSnitch (the default) puts them all in one, PropertyFileSnitch reads TopologyFile,
+	// and GossipingPropertyFileSnitch puts the node in DC and Rack and learns where the others
+	// are from gossip. Every node gossips its own location.
+	Snitch       string
+	DC           string
+	Rack         string
+	TopologyFile string
+
+	// ReadRepairChance is the share of KV reads, 0 to 1, compared on every replica of the key
+	// rather than only those the consistency level waited for, see storage.Coordinator.
+	ReadRepairChance float64
+
+	// AutoBootstrap makes a node joining the cluster with new tokens stream the ranges it
+	// takes over from their current replicas before it serves them, see Node.Start. RingDelay
+	// is how long it gossips first, to learn the ring and let the cluster learn it is joining.
+	AutoBootstrap bool
+	RingDelay     time.Duration
+
+	// DataDir (optional) is where the node saves its known peers and gossip snapshot every
+	// PersistInterval, so after a restart it finds the cluster even if every seed is down.
+	// Its key-value store is kept there too, see storage.Open; without a DataDir the keys
+	// are held in memory only.
+	DataDir         string
+	PersistInterval time.Duration
+
+	// When the commit log of the key-value store is synced to disk, see storage.SyncPolicy
+	CommitLogSync       storage.SyncPolicy
+	CommitLogSyncPeriod time.Duration
+
+	// MemtableFlushSize is the size in bytes the memtable of the key-value store grows to
+	// before it is flushed to an SSTable, and CompactionThreshold how many SSTables of similar
+	// size are merged into one, see storage.Options
+	MemtableFlushSize   int
+	CompactionThreshold int
+
+	// GCGrace is how long the tombstones of deletes, and expired values, are kept before
+	// compaction drops them, see storage.Options. A replica down for longer than GCGrace must
+	// not rejoin with its old data: the keys deleted meanwhile would come back.
+	GCGrace time.Duration
+
+	// Inbound gossip rate limits (optional, nil means unlimited)
+	RateLimit *transport.RateLimitConfig
+
+	// Security configuration (optional, nil means plaintext)
+	TLS *transport.TLSConfig
+
+	// Drops gossip to and from some peers (optional), e.g. a transport.BlockList to simulate
+	// a network partition. nil lets every message through.
+	MessageFilter transport.MessageFilter
+
+	// Delays and drops gossip to some peers (optional), e.g. a transport.LinkFaults to
+	// simulate a slow or lossy network. nil leaves the links alone.
+	Faults transport.FaultInjector
+
+	// Bus the node publishes its events on (optional). Share one to follow many nodes,
+	// as the Manager does; New creates one per node when nil.
+	Events *EventBus
+
+	// Logger the node logs with (optional), tagging its entries with the node ID; New creates
+	// one with logger.ForNode when nil.
+	Logger *logger.SubLogger
+
+	// Transport used to serve and dial peers (optional, defaults to gRPC)
+	Transport TransportFactory
+
+	// Extra gRPC server options (optional), e.g. grpc.MaxRecvMsgSize or grpc.MaxConcurrentStreams
+	GRPCServerOptions []transport.ServerOption
 }
 
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig(nodeID gossip.NodeID) *Config {
 	return &Config{
 		NodeID:            nodeID,
+		ClusterID:         DefaultClusterID,
 		Address:           DefaultAddress,
 		Port:              DefaultPort,
 		ClientMode:        DefaultClientMode,
 		TargetServer:      DefaultTarget,
 		HeartbeatInterval: 5 * time.Second,
+		GossipInterval:    1 * time.Second,
+
+		RPCTimeout:       2 * time.Second,
+		DialTimeout:      5 * time.Second,
+		KeepaliveTime:    30 * time.Second,
+		KeepaliveTimeout: 10 * time.Second,
+
+		CompressionThreshold: transport.DefaultCompressionThreshold,
+
+		ReconnectBackoff:     1 * time.Second,
+		MaxReconnectBackoff:  30 * time.Second,
+		MaxReconnectAttempts: 10,
+
+		RebindAttempts: 3,
+
+		DecommissionDrain: 5 * time.Second,
+
+		NumTokens:         DefaultNumTokens,
+		ReplicationFactor: DefaultReplicationFactor,
+		ReadRepairChance:  0.1,
+		AutoBootstrap:     true,
+		RingDelay:         5 * time.Second,
+
+		ReplicationStrategy: ring.SimpleStrategyName,
+		Snitch:              snitch.SimpleName,
+		DC:                  snitch.DefaultDC,
+		Rack:                snitch.DefaultRack,
+
+		DrainTimeout:    5 * time.Second,
+		PersistInterval: 10 * time.Second,
+
+		CommitLogSync:       storage.SyncPeriodic,
+		CommitLogSyncPeriod: 10 * time.Second, // Cassandra's commitlog_sync_period
+		MemtableFlushSize:   storage.DefaultFlushSize,
+		CompactionThreshold: storage.DefaultCompactionThreshold,
+		GCGrace:             storage.DefaultGCGrace,
 	}
 }
 
@@ -52,20 +224,211 @@ func (c *Config) Validate() error {
 	if c.Address == "" {
 		return ErrAddressRequired
 	}
-	if c.Port == "" {
+	if c.Port == "" && !transport.IsUnixAddress(c.Address) {
 		return ErrPortRequired
 	}
+	if c.ClusterID == "" {
+		return ErrClusterIDRequired
+	}
+	if err := c.validateAdvertisedAddress(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAdvertised, err)
+	}
 	if c.HeartbeatInterval <= 0 {
 		return ErrInvalidHeartbeatInterval
 	}
+	if c.GossipInterval <= 0 {
+		return ErrInvalidGossipInterval
+	}
+	if c.RPCTimeout <= 0 || c.DialTimeout <= 0 {
+		return ErrInvalidTimeout
+	}
+	if c.KeepaliveTime < 0 || (c.KeepaliveTime > 0 && c.KeepaliveTimeout <= 0) {
+		return ErrInvalidKeepalive
+	}
+	if c.ReconnectBackoff <= 0 || c.MaxReconnectBackoff < c.ReconnectBackoff {
+		return ErrInvalidReconnectBackoff
+	}
+	if c.MaxReconnectAttempts < 0 {
+		return ErrInvalidReconnectAttempts
+	}
+	if c.RebindAttempts < 0 {
+		return ErrInvalidRebindAttempts
+	}
 	if c.ClientMode && c.TargetServer == "" {
 		return ErrTargetServerRequired
 	}
+	if c.DecommissionDrain <= 0 {
+		return ErrInvalidDecommissionDrain
+	}
+	if c.DrainTimeout <= 0 {
+		return ErrInvalidDrainTimeout
+	}
+	if c.NumTokens <= 0 {
+		return ErrInvalidNumTokens
+	}
+	if c.ReplicationFactor <= 0 {
+		return ErrInvalidReplicationFactor
+	}
+	if err := c.validateReplicationStrategy(); err != nil {
+		return err
+	}
+	if err := c.validateSnitch(); err != nil {
+		return err
+	}
+	if c.ReadRepairChance < 0 || c.ReadRepairChance > 1 {
+		return ErrInvalidReadRepairChance
+	}
+	if c.RingDelay <= 0 {
+		return ErrInvalidRingDelay
+	}
+	if c.DataDir != "" && c.PersistInterval <= 0 {
+		return ErrInvalidPersistInterval
+	}
+	if err := c.CommitLogSync.Validate(c.CommitLogSyncPeriod); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCommitLog, err)
+	}
+	if c.MemtableFlushSize <= 0 {
+		return ErrInvalidMemtableFlushSize
+	}
+	if c.CompactionThreshold < 2 {
+		return ErrInvalidCompactionThreshold
+	}
+	if c.GCGrace < 0 {
+		return ErrInvalidGCGrace
+	}
+	if err := c.compression().Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCompression, err)
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRateLimit, err)
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTLSConfig, err)
+	}
 	return nil
 }
 
-// GetAddress returns the full address (address:port)
+// GetAddress returns the full address (address:port), or the socket address
+// as is when Address is a unix socket (unix:///path/to/node.sock)
 func (c *Config) GetAddress() string {
+	if transport.IsUnixAddress(c.Address) {
+		return c.Address
+	}
 	return c.Address + ":" + c.Port
 }
 
+// GetAdvertisedAddress returns the address gossiped to other nodes: AdvertisedAddress,
+// or the bind address when it isn't set
+func (c *Config) GetAdvertisedAddress() string {
+	if c.AdvertisedAddress != "" {
+		return c.AdvertisedAddress
+	}
+	return c.GetAddress()
+}
+
+// validateAdvertisedAddress checks that other nodes can dial the advertised address
+func (c *Config) validateAdvertisedAddress() error {
+	if c.AdvertisedAddress == "" {
+		if ip := net.ParseIP(c.Address); ip != nil && ip.IsUnspecified() {
+			return fmt.Errorf("required when binding to %s, which peers can't dial", c.Address)
+		}
+		return nil
+	}
+	if transport.IsUnixAddress(c.AdvertisedAddress) {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(c.AdvertisedAddress)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		return fmt.Errorf("%s has no host", c.AdvertisedAddress)
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		return fmt.Errorf("%s can't be dialed by peers", c.AdvertisedAddress)
+	}
+	if port == "" || port == "0" {
+		return fmt.Errorf("%s needs a fixed port", c.AdvertisedAddress)
+	}
+	return nil
+}
+
+// validateReplicationStrategy checks the strategy is known, and that NetworkTopologyStrategy
+// places at least one copy
+func (c *Config) validateReplicationStrategy() error {
+	switch c.ReplicationStrategy {
+	case ring.SimpleStrategyName:
+		return nil
+	case ring.NetworkTopologyStrategyName:
+		total := 0
+		for dc, factor := range c.DatacenterReplication {
+			if dc == "" || factor < 0 {
+				return fmt.Errorf("%w: %d copies in %q", ErrInvalidDCReplication, factor, dc)
+			}
+			total += factor
+		}
+		if total == 0 {
+			return fmt.Errorf("%w: no copies in any datacenter", ErrInvalidDCReplication)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, want %s or %s", ErrInvalidReplicationStrategy, c.ReplicationStrategy,
+			ring.SimpleStrategyName, ring.NetworkTopologyStrategyName)
+	}
+}
+
+// validateSnitch checks the snitch is known and has what it needs, without reading its file
+func (c *Config) validateSnitch() error {
+	switch c.Snitch {
+	case snitch.SimpleName:
+	case snitch.GossipingPropertyFileName:
+		if c.DC == "" || c.Rack == "" {
+			return fmt.Errorf("%w: %s needs a datacenter and a rack", ErrInvalidSnitch, c.Snitch)
+		}
+	case snitch.PropertyFileName:
+		if c.TopologyFile == "" {
+			return fmt.Errorf("%w: %s needs a topology file", ErrInvalidSnitch, c.Snitch)
+		}
+	default:
+		return fmt.Errorf("%w: unknown snitch %q, want %s, %s or %s", ErrInvalidSnitch, c.Snitch,
+			snitch.SimpleName, snitch.PropertyFileName, snitch.GossipingPropertyFileName)
+	}
+	return nil
+}
+
+// LoadSnitch returns the configured snitch, reading its topology file if it has one
+func (c *Config) LoadSnitch() (snitch.Snitch, error) {
+	location := snitch.Location{DC: c.DC, Rack: c.Rack}
+	return snitch.New(c.Snitch, location, c.TopologyFile, c.NodeID, c.GetAdvertisedAddress())
+}
+
+// replicationStrategy returns the configured strategy, which finds nodes with locate
+func (c *Config) replicationStrategy(locate func(gossip.NodeID) snitch.Location) ring.Strategy {
+	if c.ReplicationStrategy == ring.NetworkTopologyStrategyName {
+		return ring.NetworkTopologyStrategy{ReplicationFactors: c.DatacenterReplication, Locate: locate}
+	}
+	return ring.SimpleStrategy{ReplicationFactor: c.ReplicationFactor}
+}
+
+// keepalive returns the transport keepalive settings, or nil when keepalive is disabled
+func (c *Config) keepalive() *transport.KeepaliveConfig {
+	if c.KeepaliveTime == 0 {
+		return nil
+	}
+	return &transport.KeepaliveConfig{
+		Time:    c.KeepaliveTime,
+		Timeout: c.KeepaliveTimeout,
+	}
+}
+
+// compression returns the transport compression settings, or nil when compression is disabled
+func (c *Config) compression() *transport.CompressionConfig {
+	if c.Compression == "" {
+		return nil
+	}
+	return &transport.CompressionConfig{
+		Algorithm: c.Compression,
+		Threshold: c.CompressionThreshold,
+	}
+}
@@ -0,0 +1,268 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// fileConfig is the YAML form of Config. Durations are written like "1s" or "500ms".
+// Keys missing from a file keep their DefaultConfig values.
+type fileConfig struct {
+	NodeID            string `yaml:"node_id"`
+	Cluster           string `yaml:"cluster"`
+	Address           string `yaml:"listen_addr"`
+	Port              string `yaml:"port"`
+	AdvertisedAddress string `yaml:"advertised_addr"`
+	Transport         string `yaml:"transport"` // grpc or udp
+
+	ClientMode   bool   `yaml:"client"`
+	TargetServer string `yaml:"target"`
+
+	Seeds             []string      `yaml:"seeds"`
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+	GossipInterval    time.Duration `yaml:"gossip_interval"`
+	ManualHeartbeat   bool          `yaml:"manual_gossip"`
+
+	RPCTimeout       time.Duration `yaml:"rpc_timeout"`
+	DialTimeout      time.Duration `yaml:"dial_timeout"`
+	KeepaliveTime    time.Duration `yaml:"keepalive_time"`
+	KeepaliveTimeout time.Duration `yaml:"keepalive_timeout"`
+
+	Compression          string `yaml:"compression"`
+	CompressionThreshold int    `yaml:"compression_threshold"`
+
+	ReconnectBackoff     time.Duration `yaml:"reconnect_backoff"`
+	MaxReconnectBackoff  time.Duration `yaml:"max_reconnect_backoff"`
+	MaxReconnectAttempts int           `yaml:"max_reconnect_attempts"`
+	RebindAttempts       int           `yaml:"rebind_attempts"`
+	DecommissionDrain    time.Duration `yaml:"decommission_drain"`
+	DrainTimeout         time.Duration `yaml:"drain_timeout"`
+	NumTokens            int           `yaml:"num_tokens"`
+	ReplicationFactor    int           `yaml:"replication_factor"`
+	ReadRepairChance     float64       `yaml:"read_repair_chance"`
+	AutoBootstrap        bool          `yaml:"auto_bootstrap"`
+	RingDelay            time.Duration `yaml:"ring_delay"`
+
+	ReplicationStrategy   string         `yaml:"replication_strategy"`   // SimpleStrategy or NetworkTopologyStrategy
+	DatacenterReplication map[string]int `yaml:"datacenter_replication"` // copies in each datacenter
+	Snitch                string         `yaml:"endpoint_snitch"`
+	DC                    string         `yaml:"dc"`
+	Rack                  string         `yaml:"rack"`
+	TopologyFile          string         `yaml:"topology_file"`
+
+	DataDir         string        `yaml:"data_dir"`
+	PersistInterval time.Duration `yaml:"persist_interval"`
+
+	CommitLogSync       string        `yaml:"commitlog_sync"` // periodic or batch
+	CommitLogSyncPeriod time.Duration `yaml:"commitlog_sync_period"`
+	MemtableFlushSize   int           `yaml:"memtable_flush_size"` // bytes
+	CompactionThreshold int           `yaml:"compaction_threshold"`
+	GCGrace             time.Duration `yaml:"gc_grace"`
+
+	RateLimit *fileRateLimit `yaml:"rate_limit,omitempty"`
+	TLS       *fileTLS       `yaml:"tls,omitempty"`
+}
+
+type fileRateLimit struct {
+	GlobalRate  float64 `yaml:"global_rate"`
+	GlobalBurst int     `yaml:"global_burst"`
+	PeerRate    float64 `yaml:"peer_rate"`
+	PeerBurst   int     `yaml:"peer_burst"`
+}
+
+type fileTLS struct {
+	CertFile          string `yaml:"cert"`
+	KeyFile           string `yaml:"key"`
+	CAFile            string `yaml:"ca"`
+	RequireClientCert bool   `yaml:"require_client_cert"`
+}
+
+// LoadConfig reads a YAML config file. Keys that aren't set keep their defaults and unknown
+// keys are rejected, so a typo doesn't silently fall back to a default. The result is validated.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	file := toFileConfig(DefaultConfig(DefaultNodeID))
+	file.Transport = TransportGRPC
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config, err := file.toConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// MarshalConfig renders config as YAML that LoadConfig reads back. The transport is written
+// as transportName, since Config only holds the factory.
+func MarshalConfig(config *Config, transportName string) ([]byte, error) {
+	file := toFileConfig(config)
+	file.Transport = transportName
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toFileConfig(c *Config) fileConfig {
+	file := fileConfig{
+		NodeID:               string(c.NodeID),
+		Cluster:              c.ClusterID,
+		Address:              c.Address,
+		Port:                 c.Port,
+		AdvertisedAddress:    c.AdvertisedAddress,
+		ClientMode:           c.ClientMode,
+		TargetServer:         c.TargetServer,
+		Seeds:                c.Seeds,
+		HeartbeatInterval:    c.HeartbeatInterval,
+		GossipInterval:       c.GossipInterval,
+		ManualHeartbeat:      c.ManualHeartbeat,
+		RPCTimeout:           c.RPCTimeout,
+		DialTimeout:          c.DialTimeout,
+		KeepaliveTime:        c.KeepaliveTime,
+		KeepaliveTimeout:     c.KeepaliveTimeout,
+		Compression:          c.Compression,
+		CompressionThreshold: c.CompressionThreshold,
+		ReconnectBackoff:     c.ReconnectBackoff,
+		MaxReconnectBackoff:  c.MaxReconnectBackoff,
+		MaxReconnectAttempts: c.MaxReconnectAttempts,
+		RebindAttempts:       c.RebindAttempts,
+		DecommissionDrain:    c.DecommissionDrain,
+		DrainTimeout:         c.DrainTimeout,
+		NumTokens:            c.NumTokens,
+		ReplicationFactor:    c.ReplicationFactor,
+		ReadRepairChance:     c.ReadRepairChance,
+		ReplicationStrategy:  c.ReplicationStrategy,
+		Snitch:               c.Snitch,
+		DC:                   c.DC,
+		Rack:                 c.Rack,
+		TopologyFile:         c.TopologyFile,
+		AutoBootstrap:        c.AutoBootstrap,
+		RingDelay:            c.RingDelay,
+		DataDir:              c.DataDir,
+		PersistInterval:      c.PersistInterval,
+		CommitLogSync:        string(c.CommitLogSync),
+		CommitLogSyncPeriod:  c.CommitLogSyncPeriod,
+		MemtableFlushSize:    c.MemtableFlushSize,
+		CompactionThreshold:  c.CompactionThreshold,
+		GCGrace:              c.GCGrace,
+	}
+	if len(c.DatacenterReplication) > 0 {
+		file.DatacenterReplication = maps.Clone(c.DatacenterReplication)
+	}
+	if c.RateLimit != nil {
+		file.RateLimit = &fileRateLimit{
+			GlobalRate:  c.RateLimit.GlobalRate,
+			GlobalBurst: c.RateLimit.GlobalBurst,
+			PeerRate:    c.RateLimit.PeerRate,
+			PeerBurst:   c.RateLimit.PeerBurst,
+		}
+	}
+	if c.TLS != nil {
+		file.TLS = &fileTLS{
+			CertFile:          c.TLS.CertFile,
+			KeyFile:           c.TLS.KeyFile,
+			CAFile:            c.TLS.CAFile,
+			RequireClientCert: c.TLS.RequireClientCert,
+		}
+	}
+	return file
+}
+
+func (f fileConfig) toConfig() (*Config, error) {
+	factory, err := TransportByName(f.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		NodeID:               gossip.NodeID(f.NodeID),
+		ClusterID:            f.Cluster,
+		Address:              f.Address,
+		Port:                 f.Port,
+		AdvertisedAddress:    f.AdvertisedAddress,
+		ClientMode:           f.ClientMode,
+		TargetServer:         f.TargetServer,
+		Seeds:                f.Seeds,
+		HeartbeatInterval:    f.HeartbeatInterval,
+		GossipInterval:       f.GossipInterval,
+		ManualHeartbeat:      f.ManualHeartbeat,
+		RPCTimeout:           f.RPCTimeout,
+		DialTimeout:          f.DialTimeout,
+		KeepaliveTime:        f.KeepaliveTime,
+		KeepaliveTimeout:     f.KeepaliveTimeout,
+		Compression:          f.Compression,
+		CompressionThreshold: f.CompressionThreshold,
+		ReconnectBackoff:     f.ReconnectBackoff,
+		MaxReconnectBackoff:  f.MaxReconnectBackoff,
+		MaxReconnectAttempts: f.MaxReconnectAttempts,
+		RebindAttempts:       f.RebindAttempts,
+		DecommissionDrain:    f.DecommissionDrain,
+		DrainTimeout:         f.DrainTimeout,
+		NumTokens:            f.NumTokens,
+		ReplicationFactor:    f.ReplicationFactor,
+		ReadRepairChance:     f.ReadRepairChance,
+		ReplicationStrategy:  f.ReplicationStrategy,
+		Snitch:               f.Snitch,
+		DC:                   f.DC,
+		Rack:                 f.Rack,
+		TopologyFile:         f.TopologyFile,
+		AutoBootstrap:        f.AutoBootstrap,
+		RingDelay:            f.RingDelay,
+		DataDir:              f.DataDir,
+		PersistInterval:      f.PersistInterval,
+		CommitLogSync:        storage.SyncPolicy(f.CommitLogSync),
+		CommitLogSyncPeriod:  f.CommitLogSyncPeriod,
+		MemtableFlushSize:    f.MemtableFlushSize,
+		CompactionThreshold:  f.CompactionThreshold,
+		GCGrace:              f.GCGrace,
+		Transport:            factory,
+	}
+	if len(f.DatacenterReplication) > 0 {
+		config.DatacenterReplication = maps.Clone(f.DatacenterReplication)
+	}
+	if f.RateLimit != nil {
+		config.RateLimit = &transport.RateLimitConfig{
+			GlobalRate:  f.RateLimit.GlobalRate,
+			GlobalBurst: f.RateLimit.GlobalBurst,
+			PeerRate:    f.RateLimit.PeerRate,
+			PeerBurst:   f.RateLimit.PeerBurst,
+		}
+	}
+	if f.TLS != nil {
+		config.TLS = &transport.TLSConfig{
+			CertFile:          f.TLS.CertFile,
+			KeyFile:           f.TLS.KeyFile,
+			CAFile:            f.TLS.CAFile,
+			RequireClientCert: f.TLS.RequireClientCert,
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,146 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+)
+
+// stateFileName is the file in DataDir the node's state is saved to
+const stateFileName = "state.json"
+
+// savedState is what a node keeps in its DataDir, like Cassandra's saved endpoints
+// (system.peers): enough to find the cluster again when every seed is down.
+type savedState struct {
+	NodeID     gossip.NodeID   `json:"node_id"`
+	Generation int64           `json:"generation"`
+	SavedAt    time.Time       `json:"saved_at"`
+	Tokens     []ring.Token    `json:"tokens"`    // the node's tokens, kept across restarts
+	Peers      []string        `json:"peers"`     // addresses of every endpoint known when saved
+	Endpoints  []savedEndpoint `json:"endpoints"` // gossip snapshot, for inspection
+}
+
+type savedEndpoint struct {
+	NodeID     gossip.NodeID                          `json:"node_id"`
+	Generation int64                                  `json:"generation"`
+	Version    int64                                  `json:"version"`
+	Alive      bool                                   `json:"alive"`
+	AppStates  map[gossip.AppStateKey]gossip.AppState `json:"app_states"`
+}
+
+// loadState reads the state saved in dir. It returns nil if nothing was saved yet, and
+// an error if the directory belongs to another node.
+func loadState(dir string, nodeID gossip.NodeID) (*savedState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved state: %w", err)
+	}
+
+	var state savedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse saved state %s: %w", filepath.Join(dir, stateFileName), err)
+	}
+	if state.NodeID != nodeID {
+		return nil, fmt.Errorf("%w: %s holds the state of node %s", ErrDataDirMismatch, dir, state.NodeID)
+	}
+	return &state, nil
+}
+
+// saveState writes the node's known peers and gossip snapshot to DataDir. The file is
+// replaced atomically, so a crash while saving leaves the previous state.
+func (n *Node) saveState() error {
+	now := time.Now()
+	state := savedState{NodeID: n.config.NodeID, SavedAt: now, Tokens: n.tokens}
+	for _, endpoint := range n.GetGossipState().Endpoints(now) {
+		heartbeat := endpoint.State.HeartbeatState
+		if endpoint.Local {
+			state.Generation = heartbeat.Generation
+		} else if addr, ok := endpoint.State.ApplicationStates[gossip.AppHeartbeat]; ok &&
+			endpoint.State.ApplicationStates[gossip.AppStatus].Value != gossip.StatusLeft {
+			state.Peers = append(state.Peers, addr.Value)
+		}
+		state.Endpoints = append(state.Endpoints, savedEndpoint{
+			NodeID:     heartbeat.NodeID,
+			Generation: heartbeat.Generation,
+			Version:    heartbeat.Version,
+			Alive:      endpoint.Alive,
+			AppStates:  endpoint.State.ApplicationStates,
+		})
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(n.config.DataDir, stateFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(n.config.DataDir, stateFileName))
+}
+
+// startPersistLoop saves the node's state every PersistInterval until the node is stopped.
+// Caller must hold n.mu.
+func (n *Node) startPersistLoop() {
+	if n.config.DataDir == "" {
+		return
+	}
+	ctx := n.ctx
+	go func() {
+		ticker := time.NewTicker(n.config.PersistInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := n.saveState(); err != nil {
+					n.logf("Failed to save state to %s: %v", n.config.DataDir, err)
+				}
+			}
+		}
+	}()
+}
+
+// persist saves the node's state once more, when it stops
+func (n *Node) persist() {
+	if n.config.DataDir == "" {
+		return
+	}
+	if err := n.saveState(); err != nil {
+		n.logf("Failed to save state to %s: %v", n.config.DataDir, err)
+	}
+}
+
+// randomSavedPeer picks a peer saved by a previous run, other than ourselves
+func (n *Node) randomSavedPeer() (string, bool) {
+	var peers []string
+	for _, addr := range n.savedPeers {
+		if !n.isSelf(addr) {
+			peers = append(peers, addr)
+		}
+	}
+	if len(peers) == 0 {
+		return "", false
+	}
+	return peers[rand.Intn(len(peers))], true
+}
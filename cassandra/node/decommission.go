@@ -0,0 +1,61 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Decommission makes the node leave the cluster gracefully, like nodetool decommission: it
+// gossips STATUS LEAVING for DecommissionDrain, then LEFT for another DecommissionDrain so
+// every node hears that it left (they remove it after their own drain), and then stops.
+// It blocks until the node has stopped.
+func (n *Node) Decommission() error {
+	if status := n.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: cannot decommission a %s node", ErrInvalidTransition, status)
+	}
+	if !n.decommissioning.CompareAndSwap(false, true) {
+		return ErrDecommissioning
+	}
+	defer n.decommissioning.Store(false)
+
+	// Orchestrators should stop sending work to a leaving node
+	n.setServing(false)
+
+	n.mu.RLock()
+	ctx := n.ctx
+	n.mu.RUnlock()
+
+	for _, status := range []string{gossip.StatusLeaving, gossip.StatusLeft} {
+		n.GetGossipState().SetLocalAppState(gossip.AppStatus, status)
+		n.logf("Decommissioning: STATUS %s, gossiping it for %v", status, n.config.DecommissionDrain)
+
+		// Spread it now instead of waiting for the next round
+		n.startGossipRound(ctx)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node stopped while decommissioning")
+		case <-time.After(n.config.DecommissionDrain):
+		}
+	}
+
+	n.logf("Decommissioned, stopping")
+	return n.Stop()
+}
+
+// Decommissioning reports whether Decommission is in progress
+func (n *Node) Decommissioning() bool {
+	return n.decommissioning.Load()
+}
+
+// removeLeft removes endpoints that have been LEFT for DecommissionDrain and drops their connections
+func (n *Node) removeLeft(gossipState *gossip.GossipState) {
+	for _, state := range gossipState.RemoveLeft(time.Now(), n.config.DecommissionDrain) {
+		n.logf("Node %s left the cluster, removed it", state.HeartbeatState.NodeID)
+		if addr, ok := state.GetApplicationState(gossip.AppHeartbeat); ok && !n.isSeed(addr.Value) {
+			n.closePeer(addr.Value)
+		}
+	}
+}
@@ -3,10 +3,51 @@ package node
 import "errors"
 
 var (
-	ErrNodeIDRequired        = errors.New("node ID is required")
-	ErrPortRequired          = errors.New("port is required")
-	ErrAddressRequired       = errors.New("address is required")
-	ErrInvalidHeartbeatInterval = errors.New("heartbeat interval must be greater than 0")
-	ErrTargetServerRequired = errors.New("target server is required when in client mode")
+	ErrNodeIDRequired             = errors.New("node ID is required")
+	ErrPortRequired               = errors.New("port is required")
+	ErrAddressRequired            = errors.New("address is required")
+	ErrInvalidAdvertised          = errors.New("invalid advertised address")
+	ErrInvalidHeartbeatInterval   = errors.New("heartbeat interval must be greater than 0")
+	ErrTargetServerRequired       = errors.New("target server is required when in client mode")
+	ErrInvalidTLSConfig           = errors.New("invalid TLS config")
+	ErrClusterIDRequired          = errors.New("cluster ID is required")
+	ErrInvalidGossipInterval      = errors.New("gossip interval must be greater than 0")
+	ErrClusterMismatch            = errors.New("cluster ID mismatch")
+	ErrInvalidTimeout             = errors.New("RPC and dial timeouts must be greater than 0")
+	ErrInvalidKeepalive           = errors.New("keepalive time must not be negative and needs a timeout greater than 0")
+	ErrInvalidCompression         = errors.New("invalid compression config")
+	ErrInvalidReconnectBackoff    = errors.New("reconnect backoff must be greater than 0 and not exceed the max backoff")
+	ErrInvalidReconnectAttempts   = errors.New("max reconnect attempts must not be negative")
+	ErrInvalidRebindAttempts      = errors.New("rebind attempts must not be negative")
+	ErrInvalidDecommissionDrain   = errors.New("decommission drain must be greater than 0")
+	ErrInvalidDrainTimeout        = errors.New("drain timeout must be greater than 0")
+	ErrInvalidPersistInterval     = errors.New("persist interval must be greater than 0 when a data directory is set")
+	ErrInvalidNumTokens           = errors.New("number of tokens must be greater than 0")
+	ErrInvalidReplicationFactor   = errors.New("replication factor must be greater than 0")
+	ErrInvalidReplicationStrategy = errors.New("invalid replication strategy")
+	ErrInvalidDCReplication       = errors.New("invalid datacenter replication")
+	ErrInvalidSnitch              = errors.New("invalid snitch")
+	ErrInvalidReadRepairChance    = errors.New("read repair chance must be between 0 and 1")
+	ErrInvalidRingDelay           = errors.New("ring delay must be greater than 0")
+	ErrInvalidCommitLog           = errors.New("invalid commit log config")
+	ErrInvalidMemtableFlushSize   = errors.New("memtable flush size must be greater than 0")
+	ErrInvalidCompactionThreshold = errors.New("compaction threshold must be at least 2")
+	ErrInvalidGCGrace             = errors.New("gc grace must not be negative")
+	ErrDataDirMismatch            = errors.New("data directory belongs to another node")
+	ErrDecommissioning            = errors.New("node is already decommissioning")
+	ErrNodePaused                 = errors.New("node is paused")
+	ErrNodeNotPaused              = errors.New("node is not paused")
+	ErrNodeNotFound               = errors.New("node not found")
+	ErrNodeExists                 = errors.New("a node with this ID already exists")
+	ErrAppStateKeyRequired        = errors.New("application state key is required")
+	ErrReservedAppState           = errors.New("application state is managed by the node")
+	ErrInvalidRateLimit           = errors.New("invalid rate limit config")
+	ErrInvalidTransition          = errors.New("invalid node status transition")
+	ErrServerFailed               = errors.New("server stopped serving")
+	ErrStopTimeout                = errors.New("node did not stop in time")
+	ErrPeerBackoff                = errors.New("peer is down, waiting to reconnect")
+	ErrPeerRetriesExhausted       = errors.New("peer is down, reconnect attempts exhausted")
+	ErrNoKeyOwner                 = errors.New("no node to own the key")
+	ErrKVUnsupported              = errors.New("transport does not support the key-value store")
+	ErrTraceNotFound              = errors.New("no such trace, it may have been dropped")
 )
-
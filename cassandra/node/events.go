@@ -0,0 +1,166 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// eventBuffer is how many events a subscriber can fall behind before events are dropped
+const eventBuffer = 256
+
+// EventType identifies what an Event reports
+type EventType int
+
+const (
+	EventNodeStarted          EventType = iota + 1 // the node is running (again, after Restart)
+	EventPeerDiscovered                            // gossip told us about a node, or that it restarted
+	EventPeerUp                                    // the failure detector considers a peer alive again
+	EventPeerDown                                  // the failure detector convicted a peer, or it shut down
+	EventPeerRemoved                               // a peer was removed (removenode, or it left)
+	EventStateMerged                               // newer state of a peer was merged
+	EventGossipRoundCompleted                      // a SYN/ACK/ACK2 exchange with Address finished
+	EventConnectionDown                            // the connection to Address failed
+	EventConnectionUp                              // the connection to Address works again
+	EventNodePaused                                // the node was paused, see Pause
+	EventNodeResumed                               // the node was resumed, see Resume
+	EventPartitioned                               // the Manager partitioned Groups, see Manager.Partition
+	EventHealed                                    // the Manager healed every partition, see Manager.Heal
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventNodeStarted:
+		return "NodeStarted"
+	case EventPeerDiscovered:
+		return "PeerDiscovered"
+	case EventPeerUp:
+		return "PeerUp"
+	case EventPeerDown:
+		return "PeerDown"
+	case EventPeerRemoved:
+		return "PeerRemoved"
+	case EventStateMerged:
+		return "StateMerged"
+	case EventGossipRoundCompleted:
+		return "GossipRoundCompleted"
+	case EventConnectionDown:
+		return "ConnectionDown"
+	case EventConnectionUp:
+		return "ConnectionUp"
+	case EventNodePaused:
+		return "NodePaused"
+	case EventNodeResumed:
+		return "NodeResumed"
+	case EventPartitioned:
+		return "Partitioned"
+	case EventHealed:
+		return "Healed"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Event is something that happened on a node. Fields that don't apply to the Type are zero.
+type Event struct {
+	Type       EventType
+	NodeID     gossip.NodeID      // the node that published the event, empty for the Manager's
+	Peer       gossip.NodeID      // the peer it is about
+	Generation int64              // generation of the started node, or of the peer discovered, up or down
+	Address    string             // the peer address of gossip rounds and connections
+	Err        error              // why a connection failed
+	Groups     [2][]gossip.NodeID // the groups a partition separates
+	Time       time.Time
+}
+
+// EventBus fans events out to subscribers. Publishing never blocks: a subscriber that
+// falls behind misses events. One bus can be shared by many nodes (see Config.Events).
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]map[EventType]bool // nil filter means every type
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan Event]map[EventType]bool)}
+}
+
+// Subscribe returns a channel receiving events of the given types, or of every type if none
+// are given. The channel is closed once ctx is done.
+func (b *EventBus) Subscribe(ctx context.Context, types ...EventType) <-chan Event {
+	var filter map[EventType]bool
+	if len(types) > 0 {
+		filter = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			filter[t] = true
+		}
+	}
+
+	events := make(chan Event, eventBuffer)
+	b.mu.Lock()
+	b.subscribers[events] = filter
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, events)
+		close(events)
+	}()
+
+	return events
+}
+
+// Publish sends event to every subscriber interested in its type
+func (b *EventBus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for events, filter := range b.subscribers {
+		if filter != nil && !filter[event.Type] {
+			continue
+		}
+		select {
+		case events <- event:
+		default:
+			// Slow subscriber
+		}
+	}
+}
+
+// Events returns the bus the node publishes its events on
+func (n *Node) Events() *EventBus {
+	return n.events
+}
+
+// publish sends an event from this node
+func (n *Node) publish(event Event) {
+	event.NodeID = n.config.NodeID
+	n.events.Publish(event)
+}
+
+// endpointEventTypes maps gossip endpoint events to node events
+var endpointEventTypes = map[gossip.EndpointEventKind]EventType{
+	gossip.EndpointDiscovered: EventPeerDiscovered,
+	gossip.EndpointUp:         EventPeerUp,
+	gossip.EndpointDown:       EventPeerDown,
+	gossip.EndpointChanged:    EventStateMerged,
+	gossip.EndpointRemoved:    EventPeerRemoved,
+}
+
+// publishEndpointEvents republishes gossipState's endpoint events on the node's bus
+func (n *Node) publishEndpointEvents(gossipState *gossip.GossipState) {
+	gossipState.OnEndpointEvent(func(event gossip.EndpointEvent) {
+		if event.Kind == gossip.EndpointChanged || event.Kind == gossip.EndpointDiscovered {
+			n.stats.statesMerged.Add(1)
+		}
+		n.publish(Event{Type: endpointEventTypes[event.Kind], Peer: event.NodeID, Generation: event.Generation, Time: event.Time})
+	})
+}
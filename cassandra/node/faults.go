@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// SetLinkLatency delays every gossip message between managed nodes a and b, in both
+// directions, by latency. 0 removes the delay.
+func (m *Manager) SetLinkLatency(a, b gossip.NodeID, latency time.Duration) error {
+	if latency < 0 {
+		return fmt.Errorf("latency must not be negative, got %v", latency)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	nodeA, nodeB, err := m.linkLocked(a, b)
+	if err != nil {
+		return err
+	}
+	m.faults[nodeA].SetLatency(nodeB.AdvertisedAddress(), latency)
+	m.faults[nodeB].SetLatency(nodeA.AdvertisedAddress(), latency)
+	logger.Infof("Link %s <-> %s: latency %v", a, b, latency)
+	return nil
+}
+
+// SetLinkLoss drops percent (0 to 100) of the gossip messages between managed nodes a and b,
+// in both directions. 0 stops dropping them.
+func (m *Manager) SetLinkLoss(a, b gossip.NodeID, percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("loss must be between 0 and 100 percent, got %v", percent)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	nodeA, nodeB, err := m.linkLocked(a, b)
+	if err != nil {
+		return err
+	}
+	m.faults[nodeA].SetLoss(nodeB.AdvertisedAddress(), percent)
+	m.faults[nodeB].SetLoss(nodeA.AdvertisedAddress(), percent)
+	logger.Infof("Link %s <-> %s: %.0f%% loss", a, b, percent)
+	return nil
+}
+
+// Degraded reports whether any link between managed nodes has latency or loss
+func (m *Manager) Degraded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, faults := range m.faults {
+		if faults.Degraded() {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearLinkFaults removes the latency and loss of every link
+func (m *Manager) ClearLinkFaults() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, faults := range m.faults {
+		faults.Clear()
+	}
+	logger.Info("Link faults cleared")
+}
+
+// linkLocked returns the managed nodes at both ends of a link. Caller must hold m.mu.
+func (m *Manager) linkLocked(a, b gossip.NodeID) (*Node, *Node, error) {
+	if a == b {
+		return nil, nil, fmt.Errorf("link needs two different nodes, got %s twice", a)
+	}
+	nodes, err := m.nodesByIDLocked([]gossip.NodeID{a, b})
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes[0], nodes[1], nil
+}
@@ -0,0 +1,241 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/tracing"
+)
+
+// startGossipLoop runs a gossip round every GossipInterval until the node is stopped.
+// With ManualHeartbeat there is no loop, rounds only run through SendGossipRound.
+// Caller must hold n.mu.
+func (n *Node) startGossipLoop() {
+	if n.config.ManualHeartbeat {
+		n.logf("Manual gossip: rounds only run when triggered")
+		return
+	}
+	ctx := n.ctx
+	go func() {
+		ticker := time.NewTicker(n.config.GossipInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n.paused.Load() {
+					continue
+				}
+				n.startGossipRound(ctx)
+			}
+		}
+	}()
+}
+
+// SendGossipRound runs one gossip round now, in addition to (or, with ManualHeartbeat,
+// instead of) the rounds of the gossip loop.
+func (n *Node) SendGossipRound() error {
+	if status := n.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: cannot gossip from a %s node", ErrInvalidTransition, status)
+	}
+	if n.Paused() {
+		return ErrNodePaused
+	}
+
+	n.mu.RLock()
+	ctx := n.ctx
+	n.mu.RUnlock()
+
+	n.startGossipRound(ctx)
+	return nil
+}
+
+// startGossipRound performs one round of gossip, following Cassandra's Gossiper.GossipTask:
+//  1. bump the local heartbeat
+//  2. gossip to a random live member
+//  3. maybe gossip to a random unreachable member
+//  4. gossip to a random seed if we didn't already, so partitions heal through seeds
+func (n *Node) startGossipRound(ctx context.Context) {
+	n.stats.gossipRounds.Add(1)
+	gossipState := n.GetGossipState()
+	start := time.Now()
+	defer func() { gossipState.RecordRound(time.Since(start)) }()
+	gossipState.TickHeartbeat()
+	gossipState.UpdateLiveness(time.Now())
+
+	n.removeLeft(gossipState)
+
+	live, unreachable := n.gossipCandidates(gossipState)
+	n.revivePeers(gossipState)
+	n.checkPeers(ctx, gossipState)
+
+	gossipedToSeed := false
+	if len(live) > 0 {
+		target := live[rand.Intn(len(live))]
+		n.gossipWith(ctx, gossipState, target)
+		gossipedToSeed = n.isSeed(target)
+	}
+
+	if len(unreachable) > 0 {
+		probability := float64(len(unreachable)) / float64(len(live)+1)
+		if rand.Float64() < probability {
+			n.gossipWith(ctx, gossipState, unreachable[rand.Intn(len(unreachable))])
+		}
+	}
+
+	if !gossipedToSeed || len(live) < len(n.config.Seeds) {
+		if seed, ok := n.randomSeed(); ok {
+			n.gossipWith(ctx, gossipState, seed)
+		}
+	}
+
+	// Until gossip finds someone, try the peers a previous run knew, in case the seeds are down
+	if len(live) == 0 {
+		if addr, ok := n.randomSavedPeer(); ok {
+			n.gossipWith(ctx, gossipState, addr)
+		}
+	}
+}
+
+// gossipWith runs a SYN -> ACK -> ACK2 exchange with the node at target. The exchange is a
+// span (see the tracing package) and has a trace ID, its span's if traces are exported, which
+// both nodes log it with, see logger.WithTraceID.
+func (n *Node) gossipWith(ctx context.Context, gossipState *gossip.GossipState, target string) {
+	peer, err := n.getPeer(target)
+	if errors.Is(err, ErrPeerBackoff) || errors.Is(err, ErrPeerRetriesExhausted) {
+		// Already logged when the peer went down
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "gossip.exchange",
+		tracing.AttrNodeID.String(string(n.config.NodeID)), tracing.AttrPeer.String(target))
+	var exchangeErr error
+	defer func() { tracing.End(span, exchangeErr) }()
+	traceID := tracing.TraceID(ctx)
+	if traceID == "" {
+		traceID = logger.NewTraceID()
+	}
+	ctx = logger.WithTraceID(ctx, traceID)
+	log := n.log.WithContext(ctx)
+
+	if err != nil {
+		exchangeErr = err
+		log.Printf("Failed to connect to %s: %v", target, err)
+		return
+	}
+
+	syn := gossip.SynMessage{
+		ClusterID:     n.config.ClusterID,
+		SenderID:      n.config.NodeID,
+		SenderAddress: n.config.GetAdvertisedAddress(),
+		Digests:       gossipState.Digests(),
+	}
+
+	gossipState.SynSent(syn)
+	synCtx, cancel := context.WithTimeout(ctx, n.config.RPCTimeout)
+	sent := time.Now()
+	ack, err := peer.SendSyn(synCtx, syn)
+	synRTT := time.Since(sent)
+	cancel()
+	if ctx.Err() != nil {
+		// Shutting down, not the peer's fault
+		return
+	}
+	if errors.Is(err, gossip.ErrSelfConnection) {
+		n.markSelf(target)
+		return
+	}
+	if errors.Is(err, gossip.ErrDuplicateNodeID) {
+		// The peer is reachable, it refuses to gossip until the conflict is resolved
+		exchangeErr = err
+		log.Printf("CONFLICT: %v. Give every node a unique ID", err)
+		return
+	}
+	n.reportPeer(target, err)
+	if err != nil {
+		exchangeErr = err
+		n.stats.synFailures.Add(1)
+		return
+	}
+
+	mergeCtx, merge := tracing.Start(ctx, "gossip.merge", tracing.AttrNodeID.String(string(n.config.NodeID)),
+		tracing.AttrStates.Int(len(ack.States)), tracing.AttrRequests.Int(len(ack.Requests)))
+	ack2 := gossipState.HandleAck(mergeCtx, ack)
+	merge.End()
+	// Once the ACK is merged, so a peer we just discovered is known by its address
+	gossipState.RecordLatency(target, synRTT)
+	log.Printf("Gossip with %s: sent %d digests, received %d states, %d requests",
+		target, len(syn.Digests), len(ack.States), len(ack.Requests))
+
+	if len(ack2.States) > 0 {
+		ack2Ctx, cancel := context.WithTimeout(ctx, n.config.RPCTimeout)
+		defer cancel()
+		sent := time.Now()
+		if err := peer.SendAck2(ack2Ctx, ack2); err != nil {
+			exchangeErr = err
+			log.Printf("Gossip ACK2 to %s failed: %v", target, err)
+			n.reportPeer(target, err)
+			return
+		}
+		gossipState.RecordLatency(target, time.Since(sent))
+	}
+	n.publish(Event{Type: EventGossipRoundCompleted, Address: target})
+}
+
+// gossipCandidates returns the addresses of live and unreachable endpoints, excluding ourselves
+func (n *Node) gossipCandidates(gossipState *gossip.GossipState) (live []string, unreachable []string) {
+	for _, state := range gossipState.GetStateByNode() {
+		addr, ok := state.GetApplicationState(gossip.AppHeartbeat)
+		if !ok || addr.Value == "" || n.isSelf(addr.Value) {
+			continue
+		}
+		if state.IsAlive() {
+			live = append(live, addr.Value)
+		} else {
+			unreachable = append(unreachable, addr.Value)
+		}
+	}
+	return live, unreachable
+}
+
+// randomSeed picks a seed other than ourselves
+func (n *Node) randomSeed() (string, bool) {
+	var seeds []string
+	for _, seed := range n.config.Seeds {
+		if !n.isSelf(seed) {
+			seeds = append(seeds, seed)
+		}
+	}
+	if len(seeds) == 0 {
+		return "", false
+	}
+	return seeds[rand.Intn(len(seeds))], true
+}
+
+// isSelf reports whether addr is this node: its bind or advertised address, or an
+// address that gossip showed reaches this node (see markSelf)
+func (n *Node) isSelf(addr string) bool {
+	if addr == n.config.GetAddress() || addr == n.config.GetAdvertisedAddress() {
+		return true
+	}
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+	_, ok := n.selfAddrs[addr]
+	return ok
+}
+
+func (n *Node) isSeed(addr string) bool {
+	for _, seed := range n.config.Seeds {
+		if seed == addr {
+			return true
+		}
+	}
+	return false
+}
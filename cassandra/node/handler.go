@@ -0,0 +1,75 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/tracing"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// gossipHandler adapts a Node to transport.GossipHandler.
+// It enforces node-level policy (e.g. cluster membership) before handing messages to GossipState.
+type gossipHandler struct {
+	node *Node
+}
+
+// checkPaused fails gossip messages to a paused node like messages to an unreachable one
+func (h *gossipHandler) checkPaused() error {
+	if !h.node.Paused() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s: %w", transport.ErrUnavailable, h.node.config.NodeID, ErrNodePaused)
+}
+
+func (h *gossipHandler) HandleHeartbeat(remoteNodeID string, remoteGeneration int64, remoteVersion int64) (string, int64, int64, error) {
+	if err := h.checkPaused(); err != nil {
+		return "", 0, 0, err
+	}
+	return h.node.GetGossipState().HandleHeartbeat(remoteNodeID, remoteGeneration, remoteVersion)
+}
+
+func (h *gossipHandler) HandleSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error) {
+	if err := h.checkPaused(); err != nil {
+		return gossip.AckMessage{}, err
+	}
+	log := h.node.log.WithContext(ctx)
+	if syn.ClusterID != h.node.config.ClusterID {
+		log.Printf("Rejected SYN from %s (%s): cluster %q does not match %q",
+			syn.SenderID, syn.SenderAddress, syn.ClusterID, h.node.config.ClusterID)
+		return gossip.AckMessage{}, fmt.Errorf("%w: got %q, expected %q", ErrClusterMismatch, syn.ClusterID, h.node.config.ClusterID)
+	}
+	gossipState := h.node.GetGossipState()
+	if err := gossipState.CheckSender(syn, time.Now()); err != nil {
+		if errors.Is(err, gossip.ErrSelfConnection) {
+			log.Printf("Rejected SYN from ourselves: a seed or peer address is this node")
+		} else {
+			log.Printf("Rejected SYN from %s: %v", syn.SenderAddress, err)
+		}
+		return gossip.AckMessage{}, err
+	}
+	_, span := tracing.Start(ctx, "gossip.compare_digests",
+		tracing.AttrNodeID.String(string(h.node.config.NodeID)), tracing.AttrDigests.Int(len(syn.Digests)))
+	ack := gossipState.HandleSyn(syn)
+	span.SetAttributes(tracing.AttrStates.Int(len(ack.States)), tracing.AttrRequests.Int(len(ack.Requests)))
+	span.End()
+	return ack, nil
+}
+
+func (h *gossipHandler) HandleAck2(ctx context.Context, ack2 gossip.Ack2Message) error {
+	if err := h.checkPaused(); err != nil {
+		return err
+	}
+	ctx, span := tracing.Start(ctx, "gossip.merge",
+		tracing.AttrNodeID.String(string(h.node.config.NodeID)), tracing.AttrStates.Int(len(ack2.States)))
+	h.node.GetGossipState().HandleAck2(ctx, ack2)
+	span.End()
+	return nil
+}
+
+func (h *gossipHandler) WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error) {
+	return h.node.GetGossipState().Watch(ctx), nil
+}
@@ -0,0 +1,370 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/snitch"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// The gossipHandler also serves the KVService (transport.KVHandler), so clients can use
+// the key-value store through any node.
+var _ transport.KVHandler = (*gossipHandler)(nil)
+
+// Tokens returns the node's tokens on the ring, sorted. Config.NumTokens of them are picked
+// at random when the node is first created and kept in DataDir, so a restarted node owns
+// the same keys.
+func (n *Node) Tokens() []ring.Token {
+	return slices.Clone(n.tokens)
+}
+
+// Ring returns the token ring keys are spread over, as this node sees the cluster: the
+// tokens of its ring.Members, including the node itself unless it is leaving. Nodes seeing
+// the same members agree on every key's owner.
+func (n *Node) Ring() *ring.Ring {
+	return ring.FromEndpoints(ring.Members(n.GetGossipState().Endpoints(time.Now())))
+}
+
+// KeyReplicas returns the nodes holding copies of key, as Config.ReplicationStrategy places
+// them on the ring, the first replica first. Fewer than the strategy's number of copies if the
+// ring is smaller.
+func (n *Node) KeyReplicas(key string) []gossip.NodeID {
+	return n.strategy.Replicas(n.Ring(), ring.KeyToken(key))
+}
+
+// Locate returns the datacenter and rack of a node, as the node's snitch sees it: for
+// another node, from what it gossips
+func (n *Node) Locate(nodeID gossip.NodeID) snitch.Location {
+	if nodeID == n.config.NodeID {
+		return n.snitch.Local()
+	}
+	state, ok := n.GetGossipState().GetEndpointState(nodeID)
+	if !ok {
+		return n.snitch.Locate(nodeID, nil)
+	}
+	return n.snitch.Locate(nodeID, state.GetApplicationStates())
+}
+
+// Store returns the keys the node holds a replica of, see Get, nil until the node starts.
+// With a DataDir they are kept in SSTables and the commit log there, otherwise they are lost
+// when it restarts.
+func (n *Node) Store() *storage.Store {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.store
+}
+
+// openStore opens the node's store: kept in DataDir, replaying its commit log, or held in
+// memory only without one. Caller must hold n.mu.
+func (n *Node) openStore() error {
+	if n.config.DataDir == "" {
+		n.store = storage.NewStore(storage.DefaultPartitions)
+		return nil
+	}
+	store, err := storage.Open(n.config.DataDir, storage.Options{
+		Sync:                n.config.CommitLogSync,
+		SyncPeriod:          n.config.CommitLogSyncPeriod,
+		FlushSize:           int64(n.config.MemtableFlushSize),
+		CompactionThreshold: n.config.CompactionThreshold,
+		GCGrace:             n.config.GCGrace,
+		Log:                 n.log,
+	})
+	if err != nil {
+		return err
+	}
+	if replayed := store.Replayed(); replayed.Entries > 0 || replayed.Skipped > 0 {
+		n.logf("Replayed %d writes from %d commit log segments, %d torn bytes skipped",
+			replayed.Entries, replayed.Segments, replayed.Skipped)
+	}
+	n.store = store
+	return nil
+}
+
+// closeStore flushes the memtable and closes the store's files when the node stops
+func (n *Node) closeStore() {
+	store := n.Store()
+	if store == nil {
+		return
+	}
+	if err := store.Close(); err != nil {
+		n.logf("Failed to close the commit log: %v", err)
+	}
+}
+
+// Get returns the newest value of key held by its replicas, once as many of them as cl asks
+// for have answered
+func (n *Node) Get(ctx context.Context, key string, cl storage.ConsistencyLevel) (transport.KVResult, error) {
+	return n.getKey(ctx, transport.KVRequest{Key: key, Consistency: cl})
+}
+
+// Put sets key to value on its replicas, returning once as many of them as cl asks for have
+// written it, see Get
+func (n *Node) Put(ctx context.Context, key string, value []byte, cl storage.ConsistencyLevel) (transport.KVResult, error) {
+	return n.putKey(ctx, transport.KVRequest{Key: key, Value: value, Consistency: cl})
+}
+
+// Delete writes a tombstone for key to its replicas, see Put
+func (n *Node) Delete(ctx context.Context, key string, cl storage.ConsistencyLevel) (transport.KVResult, error) {
+	return n.deleteKey(ctx, transport.KVRequest{Key: key, Consistency: cl})
+}
+
+func (n *Node) getKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := n.checkKVRequest(req); err != nil {
+		return transport.KVResult{}, err
+	}
+	if req.Forwarded {
+		// The coordinator compares every version, tombstones included, see storage.Coordinator
+		cell, found, err := n.Store().Get(req.Key)
+		return transport.KVResult{Value: cell.Value, Found: found, Timestamp: cell.Timestamp, TTL: cell.TTL,
+			Deleted: cell.Deleted, Owner: n.config.NodeID}, err
+	}
+
+	ctx, traceID := n.startTrace(ctx, req.Trace, "GET %s at %s", req.Key, req.Consistency)
+	replicas, err := n.keyReplicas(ctx, req.Key)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	result, err := n.coordinator.Get(ctx, req.Key, replicas, req.Consistency)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	if !result.Found {
+		storage.TraceFrom(ctx).Record("Answering the client: not set")
+		return transport.KVResult{Owner: replicas[0].Node(), Replicas: result.Replicas, TraceID: traceID}, nil
+	}
+	storage.TraceFrom(ctx).Record("Answering the client: the value written at %d", result.Cell.Timestamp)
+	return transport.KVResult{
+		Value:     result.Cell.Value,
+		Found:     true,
+		Timestamp: result.Cell.Timestamp,
+		TTL:       result.Cell.TTL,
+		Owner:     replicas[0].Node(),
+		Replicas:  result.Replicas,
+		TraceID:   traceID,
+	}, nil
+}
+
+func (n *Node) putKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := n.checkKVRequest(req); err != nil {
+		return transport.KVResult{}, err
+	}
+	if req.Forwarded {
+		err := n.Store().Put(req.Key, storage.Cell{Value: req.Value, Timestamp: req.Timestamp, TTL: req.TTL})
+		return transport.KVResult{Owner: n.config.NodeID}, err
+	}
+
+	ctx, traceID := n.startTrace(ctx, req.Trace, "PUT %s at %s", req.Key, req.Consistency)
+	replicas, pending, err := n.writeReplicas(ctx, req.Key)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	// The coordinator timestamps the write, so every replica keeps the same newest value
+	cell := storage.Cell{Value: req.Value, Timestamp: time.Now().UnixMicro(), TTL: req.TTL}
+	result, err := n.coordinator.Put(ctx, req.Key, cell, replicas, pending, req.Consistency)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	storage.TraceFrom(ctx).Record("Answering the client: written at %d", cell.Timestamp)
+	return transport.KVResult{Owner: replicas[0].Node(), Replicas: result.Replicas, TraceID: traceID}, nil
+}
+
+func (n *Node) deleteKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := n.checkKVRequest(req); err != nil {
+		return transport.KVResult{}, err
+	}
+	if req.Forwarded {
+		found, err := n.Store().Delete(req.Key, req.Timestamp)
+		return transport.KVResult{Found: found, Owner: n.config.NodeID}, err
+	}
+
+	ctx, traceID := n.startTrace(ctx, req.Trace, "DELETE %s at %s", req.Key, req.Consistency)
+	replicas, pending, err := n.writeReplicas(ctx, req.Key)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	timestamp := time.Now().UnixMicro()
+	result, err := n.coordinator.Delete(ctx, req.Key, timestamp, replicas, pending, req.Consistency)
+	if err != nil {
+		return transport.KVResult{}, traceError(ctx, traceID, err)
+	}
+	storage.TraceFrom(ctx).Record("Answering the client: tombstone written at %d", timestamp)
+	return transport.KVResult{Found: result.Found, Owner: replicas[0].Node(), Replicas: result.Replicas,
+		TraceID: traceID}, nil
+}
+
+// checkKVRequest rejects requests without a key, and requests to a node that isn't running
+func (n *Node) checkKVRequest(req transport.KVRequest) error {
+	if req.Key == "" {
+		return storage.ErrEmptyKey
+	}
+	if status := n.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: a %s node serves no keys", ErrInvalidTransition, status)
+	}
+	return nil
+}
+
+// keyReplicas returns the replicas of key, as the coordinator reaches them, recording them in
+// the request's trace
+func (n *Node) keyReplicas(ctx context.Context, key string) ([]storage.Replica, error) {
+	nodeIDs := n.KeyReplicas(key)
+	if len(nodeIDs) == 0 {
+		return nil, ErrNoKeyOwner
+	}
+	storage.TraceFrom(ctx).Record("Replicas of token %d: %v", ring.KeyToken(key), nodeIDs)
+	return n.replicas(nodeIDs), nil
+}
+
+// writeReplicas returns the replicas of key, like keyReplicas, and its pending replicas: the
+// nodes bootstrapping into the ring that become replicas of key once they join, see
+// ring.Ring.PendingReplicas. Writes reach both, so a node that streams a range misses none of
+// the keys written to it meanwhile.
+func (n *Node) writeReplicas(ctx context.Context, key string) (replicas, pending []storage.Replica, err error) {
+	// Both from the same view of the cluster, so a node finishing its bootstrap isn't in both
+	endpoints := n.GetGossipState().Endpoints(time.Now())
+	current := ring.FromEndpoints(ring.Members(endpoints))
+	token := ring.KeyToken(key)
+	nodeIDs := n.strategy.Replicas(current, token)
+	if len(nodeIDs) == 0 {
+		return nil, nil, ErrNoKeyOwner
+	}
+	trace := storage.TraceFrom(ctx)
+	trace.Record("Replicas of token %d: %v", token, nodeIDs)
+	pendingIDs := current.PendingReplicas(token, ring.EndpointTokens(ring.Bootstrapping(endpoints)), n.strategy)
+	if len(pendingIDs) > 0 {
+		trace.Record("Pending replicas of token %d, bootstrapping: %v", token, pendingIDs)
+	}
+	return n.replicas(nodeIDs), n.replicas(pendingIDs), nil
+}
+
+// replicas returns nodeIDs as the coordinator reaches them
+func (n *Node) replicas(nodeIDs []gossip.NodeID) []storage.Replica {
+	replicas := make([]storage.Replica, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		if nodeID == n.config.NodeID {
+			replicas[i] = localReplica{node: n}
+		} else {
+			replicas[i] = n.remoteReplica(nodeID)
+		}
+	}
+	return replicas
+}
+
+// remoteReplica returns another node as a replica, at the address it gossips. A replica
+// without an address fails its requests, like one that is down.
+func (n *Node) remoteReplica(nodeID gossip.NodeID) peerReplica {
+	replica := peerReplica{node: n, id: nodeID}
+	if state, ok := n.GetGossipState().GetEndpointState(nodeID); ok {
+		if addr, ok := state.GetApplicationState(gossip.AppHeartbeat); ok {
+			replica.addr = addr.Value
+		}
+	}
+	return replica
+}
+
+// localReplica is this node as a replica of its keys
+type localReplica struct {
+	node *Node
+}
+
+func (r localReplica) Node() gossip.NodeID {
+	return r.node.config.NodeID
+}
+
+func (r localReplica) Get(ctx context.Context, key string) (storage.Cell, bool, error) {
+	return r.node.Store().Get(key)
+}
+
+func (r localReplica) Put(ctx context.Context, key string, cell storage.Cell) error {
+	return r.node.Store().Put(key, cell)
+}
+
+func (r localReplica) Delete(ctx context.Context, key string, timestamp int64) (bool, error) {
+	return r.node.Store().Delete(key, timestamp)
+}
+
+// peerReplica is another node as a replica, reached over the gossip connection to it
+type peerReplica struct {
+	node *Node
+	id   gossip.NodeID
+	addr string // as gossiped by the replica, "" if unknown
+}
+
+func (r peerReplica) Node() gossip.NodeID {
+	return r.id
+}
+
+func (r peerReplica) Get(ctx context.Context, key string) (storage.Cell, bool, error) {
+	peer, err := r.kvPeer()
+	if err != nil {
+		return storage.Cell{}, false, err
+	}
+	result, err := peer.ForwardGet(ctx, key)
+	cell := storage.Cell{Value: result.Value, Timestamp: result.Timestamp, TTL: result.TTL, Deleted: result.Deleted}
+	return cell, result.Found, err
+}
+
+// Put writes cell to the replica, as a delete if it is a tombstone
+func (r peerReplica) Put(ctx context.Context, key string, cell storage.Cell) error {
+	if cell.Deleted {
+		_, err := r.Delete(ctx, key, cell.Timestamp)
+		return err
+	}
+	peer, err := r.kvPeer()
+	if err != nil {
+		return err
+	}
+	_, err = peer.ForwardPut(ctx, key, cell.Value, cell.Timestamp, cell.TTL)
+	return err
+}
+
+func (r peerReplica) Delete(ctx context.Context, key string, timestamp int64) (bool, error) {
+	peer, err := r.kvPeer()
+	if err != nil {
+		return false, err
+	}
+	result, err := peer.ForwardDelete(ctx, key, timestamp)
+	return result.Found, err
+}
+
+// kvPeer returns the connection to the replica
+func (r peerReplica) kvPeer() (transport.KVPeer, error) {
+	if r.addr == "" {
+		return nil, fmt.Errorf("%w: %s has no address", ErrNoKeyOwner, r.id)
+	}
+	peer, err := r.node.getPeer(r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", r.id, err)
+	}
+	kvPeer, ok := peer.(transport.KVPeer)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot forward to %s", ErrKVUnsupported, r.id)
+	}
+	return kvPeer, nil
+}
+
+func (h *gossipHandler) GetKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := h.checkPaused(); err != nil {
+		return transport.KVResult{}, err
+	}
+	return h.node.getKey(ctx, req)
+}
+
+func (h *gossipHandler) PutKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := h.checkPaused(); err != nil {
+		return transport.KVResult{}, err
+	}
+	return h.node.putKey(ctx, req)
+}
+
+func (h *gossipHandler) DeleteKey(ctx context.Context, req transport.KVRequest) (transport.KVResult, error) {
+	if err := h.checkPaused(); err != nil {
+		return transport.KVResult{}, err
+	}
+	return h.node.deleteKey(ctx, req)
+}
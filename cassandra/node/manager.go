@@ -1,19 +1,69 @@
 package node
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
 )
 
+// managerEventBuffer is how many status events the Manager queues before dropping them
+const managerEventBuffer = 64
+
+// managerSeeds is how many of the oldest managed nodes a new node gets as seeds
+const managerSeeds = 3
+
+// maxPortProbes is how many ports findAvailablePort tries before giving up
+const maxPortProbes = 100
+
+// stopGrace is how long StopAll waits for a node past its DrainTimeout before giving up on it
+const stopGrace = 2 * time.Second
+
+// maxBindAttempts is how many ports CreateNode tries when another process takes the probed
+// port before the node binds it
+const maxBindAttempts = 3
+
 // Manager manages multiple nodes
 type Manager struct {
 	nodes       []*Node // maintain order with slice
 	nodeMap     map[string]int // map node ID to index for quick lookup
 	mu          sync.RWMutex
 	portCounter int // for auto-assigning ports
+	releasedPorts []int // ports of deleted nodes, reused first
 	nextID      int // monotonically increasing counter for unique node IDs
+
+	// Status events of all managed nodes, see Events
+	events  chan StatusEvent
+	unwatch map[*Node]context.CancelFunc
+
+	// Events published by every managed node, see Bus
+	bus *EventBus
+
+	// Message filter of every managed node, see Partition
+	filters map[*Node]*transport.BlockList
+
+	// Link faults of every managed node, see SetLinkLatency and SetLinkLoss
+	faults map[*Node]*transport.LinkFaults
+
+	// Restart failed nodes, see SetAutoRestart
+	autoRestart atomic.Bool
+
+	// Nodes of other processes, see Attach
+	remotes []*RemoteNode
+
+	// Temporary directory holding the DataDir of every managed node, so their keys survive
+	// RestartNode. Created with the first node, removed by StopAll.
+	dataDir string
 }
 
 // NewManager creates a new node manager
@@ -23,39 +73,236 @@ func NewManager() *Manager {
 		nodeMap:     make(map[string]int),
 		portCounter: 50051, // start from default port
 		nextID:      1,     // start node IDs at 1
+		events:      make(chan StatusEvent, managerEventBuffer),
+		unwatch:     make(map[*Node]context.CancelFunc),
+		bus:         NewEventBus(),
+		filters:     make(map[*Node]*transport.BlockList),
+		faults:      make(map[*Node]*transport.LinkFaults),
 	}
 }
 
-// CreateNode creates and starts a new node
-func (m *Manager) CreateNode() (*Node, error) {
+// Events returns the status transitions of every managed node, from creation until it is deleted.
+// Events are dropped if nobody reads them.
+func (m *Manager) Events() <-chan StatusEvent {
+	return m.events
+}
+
+// Bus returns the event bus shared by every node the Manager creates
+func (m *Manager) Bus() *EventBus {
+	return m.bus
+}
+
+// watch forwards node's status events to m.events until unwatch is called, and restarts the
+// node when it fails if SetAutoRestart is on. Caller must hold m.mu.
+func (m *Manager) watch(node *Node) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.unwatch[node] = cancel
+
+	events := node.WatchStatus(ctx)
+	go func() {
+		var backoff restartBackoff
+		for event := range events {
+			if event.From == event.To {
+				// Initial status, not a transition
+				continue
+			}
+			if event.To == StatusFailed && m.autoRestart.Load() {
+				go m.restartFailed(ctx, node, backoff.next(event.Time))
+			}
+			select {
+			case m.events <- event:
+			default:
+			}
+		}
+	}()
+}
+
+// stopWatching stops forwarding node's status events
+func (m *Manager) stopWatching(node *Node) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if cancel, ok := m.unwatch[node]; ok {
+		cancel()
+		delete(m.unwatch, node)
+	}
+}
 
-	// Find next available port
-	port := m.findAvailablePort()
-	
-	// Generate unique node ID using monotonically increasing counter
-	nodeID := gossip.NodeID(fmt.Sprintf("node-%d", m.nextID))
-	m.nextID++ // increment counter for next node
+// NodeOverrides changes the config of a node created by the Manager, so nodes can differ,
+// e.g. one with a slow heartbeat. Zero fields keep the Manager's defaults.
+type NodeOverrides struct {
+	NodeID            gossip.NodeID `yaml:"node_id,omitempty"` // generated (node-1, node-2, ...) if empty
+	Port              int           `yaml:"port,omitempty"`    // the next free port if 0
+	ClusterID         string        `yaml:"cluster,omitempty"`
+	Seeds             []string      `yaml:"seeds"` // nil means the oldest managed nodes, empty means none
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval,omitempty"`
+	GossipInterval    time.Duration `yaml:"gossip_interval,omitempty"`
+	ManualHeartbeat   bool          `yaml:"manual_gossip,omitempty"`
+}
 
-	config := DefaultConfig(nodeID)
-	config.Port = fmt.Sprintf("%d", port)
-	config.Address = "127.0.0.1"
+// apply sets the overridden fields on config
+func (o NodeOverrides) apply(config *Config) {
+	if o.ClusterID != "" {
+		config.ClusterID = o.ClusterID
+	}
+	if o.Seeds != nil {
+		config.Seeds = o.Seeds
+	}
+	if o.HeartbeatInterval > 0 {
+		config.HeartbeatInterval = o.HeartbeatInterval
+	}
+	if o.GossipInterval > 0 {
+		config.GossipInterval = o.GossipInterval
+	}
+	config.ManualHeartbeat = o.ManualHeartbeat
+}
 
-	node, err := New(config)
+// CreateNode creates and starts a new node, named node-1, node-2, ...
+func (m *Manager) CreateNode() (*Node, error) {
+	return m.CreateNodeWith(NodeOverrides{})
+}
+
+// CreateNamedNode creates and starts a new node with the given ID, or a generated one if
+// nodeID is empty. IDs must be unique among the managed nodes.
+func (m *Manager) CreateNamedNode(nodeID gossip.NodeID) (*Node, error) {
+	return m.CreateNodeWith(NodeOverrides{NodeID: nodeID})
+}
+
+// CreateNodeWith creates and starts a new node with the Manager's defaults changed by overrides
+func (m *Manager) CreateNodeWith(overrides NodeOverrides) (*Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodeID := overrides.NodeID
+	if nodeID == "" {
+		// Generate unique node ID using monotonically increasing counter,
+		// skipping IDs that were picked by hand
+		for {
+			nodeID = gossip.NodeID(fmt.Sprintf("node-%d", m.nextID))
+			m.nextID++ // increment counter for next node
+			if !m.nodeIDTakenLocked(nodeID) {
+				break
+			}
+		}
+	} else if m.nodeIDTakenLocked(nodeID) {
+		return nil, fmt.Errorf("%w: %s", ErrNodeExists, nodeID)
+	}
+
+	if m.dataDir == "" {
+		dir, err := os.MkdirTemp("", "cassandra-nodes-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		m.dataDir = dir
+	}
+	// Every incarnation of an ID gets its own directory, so a node created again with the ID
+	// of a deleted one starts empty, whatever the deleted one is still doing with its files
+	dataDir, err := os.MkdirTemp(m.dataDir, string(nodeID)+"-")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create node: %w", err)
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	if err := node.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start node: %w", err)
+	for attempt := 1; ; attempt++ {
+		port := overrides.Port
+		if port == 0 {
+			var err error
+			if port, err = m.findAvailablePort(); err != nil {
+				return nil, fmt.Errorf("failed to create node: %w", err)
+			}
+		}
+
+		config := DefaultConfig(nodeID)
+		config.Port = strconv.Itoa(port)
+		config.Address = "127.0.0.1"
+		config.Seeds = m.seedsLocked()
+		config.DataDir = dataDir
+		config.Events = m.bus
+		filter := transport.NewBlockList()
+		config.MessageFilter = filter
+		faults := transport.NewLinkFaults()
+		config.Faults = faults
+		overrides.apply(config)
+
+		node, err := New(config)
+		if err != nil {
+			os.RemoveAll(dataDir)
+			return nil, fmt.Errorf("failed to create node: %w", err)
+		}
+
+		m.watch(node)
+		if err := node.Start(); err != nil {
+			// Release whatever was started before the failure
+			node.Stop()
+			m.unwatch[node]()
+			delete(m.unwatch, node)
+			if errors.Is(err, syscall.EADDRINUSE) && overrides.Port == 0 && attempt < maxBindAttempts {
+				// Taken since we probed it, try the next free port
+				continue
+			}
+			os.RemoveAll(dataDir)
+			return nil, fmt.Errorf("failed to start node: %w", err)
+		}
+
+		// Add to slice and map
+		nodeIDStr := string(nodeID)
+		m.filters[node] = filter
+		m.faults[node] = faults
+		m.nodes = append(m.nodes, node)
+		m.nodeMap[nodeIDStr] = len(m.nodes) - 1
+		return node, nil
 	}
+}
 
-	// Add to slice and map
-	nodeIDStr := string(nodeID)
-	m.nodes = append(m.nodes, node)
-	m.nodeMap[nodeIDStr] = len(m.nodes) - 1
-	return node, nil
+// CreateCluster creates and starts count nodes. Like every node created by the Manager they
+// join through the oldest managed nodes, so the first node seeds the ones after it.
+// On failure it returns the nodes that were started.
+func (m *Manager) CreateCluster(count int) ([]*Node, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("invalid node count: %d", count)
+	}
+
+	nodes := make([]*Node, 0, count)
+	for i := 0; i < count; i++ {
+		node, err := m.CreateNode()
+		if err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// seedsLocked returns the addresses of the oldest managed nodes, which new nodes join through.
+// Caller must hold m.mu.
+func (m *Manager) seedsLocked() []string {
+	var seeds []string
+	for _, node := range m.nodes {
+		if len(seeds) == managerSeeds {
+			break
+		}
+		if status := node.Status(); status == StatusStopped || status == StatusFailed {
+			continue
+		}
+		seeds = append(seeds, node.AdvertisedAddress())
+	}
+	for _, remote := range m.remotes {
+		if len(seeds) == managerSeeds {
+			break
+		}
+		seeds = append(seeds, remote.addr)
+	}
+	return seeds
+}
+
+// GetNode returns the managed node with the given ID
+func (m *Manager) GetNode(nodeID gossip.NodeID) (*Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	index, ok := m.nodeMap[string(nodeID)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+	return m.nodes[index], nil
 }
 
 // DeleteNode stops and removes a node by its index in the list
@@ -67,12 +314,39 @@ func (m *Manager) DeleteNode(index int) error {
 		return fmt.Errorf("invalid node index: %d", index)
 	}
 
+	m.deleteLocked(index)
+	return nil
+}
+
+// DeleteNodeByID stops and removes the node with the given ID. Unlike an index, the ID
+// still refers to the same node when others are added or removed concurrently.
+func (m *Manager) DeleteNodeByID(nodeID gossip.NodeID) error {
+	m.mu.Lock()
+
+	index, ok := m.nodeMap[string(nodeID)]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+
+	m.deleteLocked(index)
+	return nil
+}
+
+// deleteLocked removes the node at index from the list, unlocks m.mu and stops the node in
+// the background. Caller must hold m.mu.
+func (m *Manager) deleteLocked(index int) {
 	node := m.nodes[index]
 	nodeID := string(node.GetConfig().NodeID)
 	
 	// Remove from slice and map before unlocking
 	m.nodes = append(m.nodes[:index], m.nodes[index+1:]...)
 	delete(m.nodeMap, nodeID)
+	delete(m.filters, node)
+	delete(m.faults, node)
+	if port, err := strconv.Atoi(node.GetConfig().Port); err == nil {
+		m.releasedPorts = append(m.releasedPorts, port)
+	}
 	
 	// Rebuild map indices
 	for i, n := range m.nodes {
@@ -86,12 +360,94 @@ func (m *Manager) DeleteNode(index int) error {
 		if err := node.Stop(); err != nil {
 			// Log error but don't return it since we've already removed from list
 			fmt.Printf("Error stopping node %s: %v\n", nodeID, err)
+			// It may still be writing its files: StopAll removes them with the others
+			m.stopWatching(node)
+			return
+		}
+		m.stopWatching(node)
+		os.RemoveAll(node.GetConfig().DataDir)
+	}()
+}
+
+// RestartNode restarts the node at index in the list, keeping its NodeID and bumping its generation
+func (m *Manager) RestartNode(index int) error {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		m.mu.RUnlock()
+		return fmt.Errorf("invalid node index: %d", index)
+	}
+	node := m.nodes[index]
+	m.mu.RUnlock()
+
+	return node.Restart()
+}
+
+// DecommissionNode starts decommissioning the node at index in the list. The node leaves the
+// cluster and stops in the background; it stays in the list until deleted.
+func (m *Manager) DecommissionNode(index int) error {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		m.mu.RUnlock()
+		return fmt.Errorf("invalid node index: %d", index)
+	}
+	node := m.nodes[index]
+	m.mu.RUnlock()
+
+	if status := node.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: cannot decommission a %s node", ErrInvalidTransition, status)
+	}
+	if node.Decommissioning() {
+		return ErrDecommissioning
+	}
+	go func() {
+		if err := node.Decommission(); err != nil {
+			node.logf("Decommission failed: %v", err)
 		}
 	}()
-	
 	return nil
 }
 
+// SendGossipRound runs one gossip round on the node at index in the list, see Node.SendGossipRound
+func (m *Manager) SendGossipRound(index int) error {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		m.mu.RUnlock()
+		return fmt.Errorf("invalid node index: %d", index)
+	}
+	node := m.nodes[index]
+	m.mu.RUnlock()
+
+	node.logf("Gossip round triggered manually")
+	return node.SendGossipRound()
+}
+
+// SetAppState sets an application state of the node at index in the list, see Node.SetAppState
+func (m *Manager) SetAppState(index int, key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		m.mu.RUnlock()
+		return gossip.AppState{}, fmt.Errorf("invalid node index: %d", index)
+	}
+	node := m.nodes[index]
+	m.mu.RUnlock()
+
+	return node.SetAppState(key, value)
+}
+
+// CheckHealth probes the node at index in the list through the standard gRPC health service,
+// the same way an external orchestrator would. It reports SERVING only once the node is running.
+func (m *Manager) CheckHealth(ctx context.Context, index int) (bool, error) {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		m.mu.RUnlock()
+		return false, fmt.Errorf("invalid node index: %d", index)
+	}
+	node := m.nodes[index]
+	m.mu.RUnlock()
+
+	return transport.CheckHealth(ctx, node.Address(), node.config.TLS)
+}
+
 // GetNodes returns a list of all nodes (maintains order)
 func (m *Manager) GetNodes() []*Node {
 	m.mu.RLock()
@@ -102,31 +458,103 @@ func (m *Manager) GetNodes() []*Node {
 	return nodes
 }
 
-// findAvailablePort finds the next available port
-func (m *Manager) findAvailablePort() int {
-	// Simple implementation: increment port counter
-	port := m.portCounter
-	m.portCounter++
-	return port
+// findAvailablePort finds a port nothing is bound to, probing from portCounter up.
+// Caller must hold m.mu.
+func (m *Manager) findAvailablePort() (int, error) {
+	// Reuse the ports of deleted nodes first, lowest first. A port whose node is still
+	// stopping isn't free yet and stays for later.
+	sort.Ints(m.releasedPorts)
+	for i, port := range m.releasedPorts {
+		if portAvailable(port) {
+			m.releasedPorts = append(m.releasedPorts[:i], m.releasedPorts[i+1:]...)
+			return port, nil
+		}
+	}
+
+	for probe := 0; probe < maxPortProbes; probe++ {
+		port := m.portCounter
+		m.portCounter++
+		if portAvailable(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found in %d ports up to %d", maxPortProbes, m.portCounter-1)
 }
 
-// StopAll stops all nodes
+// portAvailable reports whether a node could bind port on 127.0.0.1 right now
+func portAvailable(port int) bool {
+	lis, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	lis.Close()
+	return true
+}
+
+// StopAll stops all nodes concurrently, each within its DrainTimeout, so one slow node doesn't
+// hold up the others. The error joins one error per node that didn't stop cleanly, naming it.
 func (m *Manager) StopAll() error {
 	m.mu.Lock()
 	nodes := make([]*Node, len(m.nodes))
 	copy(nodes, m.nodes)
+	remotes := m.remotes
+	m.remotes = nil
+	dataDir := m.dataDir
+	m.dataDir = ""
 	m.mu.Unlock()
 
-	var errs []error
-	for _, node := range nodes {
-		if err := node.Stop(); err != nil {
-			errs = append(errs, err)
-		}
+	// Attached nodes belong to other processes, only disconnect from them
+	for _, remote := range remotes {
+		remote.close()
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("errors stopping nodes: %v", errs)
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Go(func() {
+			if err := stopWithin(node, node.config.DrainTimeout); err != nil {
+				errs[i] = fmt.Errorf("node %s: %w", node.config.NodeID, err)
+			}
+		})
 	}
+	wg.Wait()
 
-	return nil
+	if dataDir != "" {
+		// Nodes given up on may still be flushing: leave them their directories
+		stopping := false
+		for i, node := range nodes {
+			if errors.Is(errs[i], ErrStopTimeout) {
+				stopping = true
+			} else {
+				os.RemoveAll(node.config.DataDir)
+			}
+		}
+		if !stopping {
+			os.RemoveAll(dataDir)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stopWithin stops node within timeout. A node that is still stopping stopGrace after the
+// timeout is given up on; it keeps stopping in the background.
+func stopWithin(node *Node, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- node.StopContext(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil && ctx.Err() != nil {
+			// Peers or in-flight RPCs didn't drain, the server was closed forcefully
+			return fmt.Errorf("stopped forcefully: %w", ctx.Err())
+		}
+		return err
+	case <-time.After(timeout + stopGrace):
+		return fmt.Errorf("%w: still stopping after %v", ErrStopTimeout, timeout+stopGrace)
+	}
 }
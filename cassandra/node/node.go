@@ -3,14 +3,17 @@ package node
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"net"
+	"os"
 	"sync"
+	"sync/atomic"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-
-	pbproto "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
 	"github.com/adamgarcia4/goLearning/cassandra/gossip"
 	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/snitch"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
 	"github.com/adamgarcia4/goLearning/cassandra/transport"
 )
 
@@ -18,13 +21,45 @@ import (
 type Node struct {
 	config      *Config
 	gossipState *gossip.GossipState
-	grpcServer  *transport.GRPC
-	clientConn  *grpc.ClientConn
+	transport   transport.Transport
+	clientPeer  transport.Peer // legacy heartbeat client (client mode only)
+
+	// Outbound connections to gossip peers, keyed by address
+	peersMu   sync.Mutex
+	peers     map[string]*peerConn
+	selfAddrs map[string]struct{} // addresses that turned out to be this node, never dialed again
+
+	// Peers saved in DataDir by a previous run, contacted like seeds while we know no one else
+	savedPeers []string
 
 	// Lifecycle management
 	ctx    context.Context
 	cancel context.CancelFunc
 	mu     sync.RWMutex
+
+	// Lifecycle state, see status.go
+	statusMu       sync.Mutex
+	status         Status
+	statusWatchers map[chan StatusEvent]struct{}
+	failErr        error // why the node failed, guarded by statusMu
+
+	// Closed when the node can no longer serve, see fail. Replaced on Restart.
+	failed chan struct{}
+
+	decommissioning atomic.Bool          // see Decommission
+	paused          atomic.Bool          // see Pause
+	restarts        atomic.Int64         // see Restarts
+	stats           stats                // see Stats
+	store           *storage.Store       // see Store, opened by start, guarded by mu
+	tokens          []ring.Token         // see Tokens, picked once
+	joining         bool                 // picked new tokens and hasn't bootstrapped yet, guarded by mu
+	coordinator     *storage.Coordinator // coordinates the KV requests the node takes
+	snitch          snitch.Snitch        // see Locate
+	strategy        ring.Strategy        // see KeyReplicas
+	traces          *storage.TraceStore  // see Trace
+
+	events *EventBus         // see Events
+	log    *logger.SubLogger // see Config.Logger
 }
 
 // New creates a new node with the given configuration
@@ -36,27 +71,182 @@ func New(config *Config) (*Node, error) {
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
+	nodeSnitch, err := config.LoadSnitch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snitch: %w", err)
+	}
+
+	var saved *savedState
+	if config.DataDir != "" {
+		if err := os.MkdirAll(config.DataDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		var err error
+		if saved, err = loadState(config.DataDir, config.NodeID); err != nil {
+			return nil, err
+		}
+	}
 
-	// Create gossip state
-	gossipState, err := gossip.NewGossipState(config.NodeID, config.HeartbeatInterval)
+	// Create gossip state, with a higher generation than a previous run saved
+	var gossipState *gossip.GossipState
+	if saved != nil {
+		gossipState, err = gossip.ResumeGossipState(config.NodeID, config.HeartbeatInterval, saved.Generation)
+	} else {
+		gossipState, err = gossip.NewGossipState(config.NodeID, config.HeartbeatInterval)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gossip state: %w", err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Node{
+	node := &Node{
 		config:      config,
 		gossipState: gossipState,
+		peers:       make(map[string]*peerConn),
+		selfAddrs:   make(map[string]struct{}),
 		ctx:         ctx,
 		cancel:      cancel,
-	}, nil
+		failed:      make(chan struct{}),
+		snitch:      nodeSnitch,
+
+		statusWatchers: make(map[chan StatusEvent]struct{}),
+	}
+	node.strategy = config.replicationStrategy(node.Locate)
+	node.coordinator = &storage.Coordinator{
+		ReplicationFactor: node.strategy.TotalReplicas(),
+		Timeout:           config.RPCTimeout,
+		ReadRepairChance:  config.ReadRepairChance,
+	}
+	node.traces = storage.NewTraceStore(traceCapacity)
+	if saved != nil {
+		node.savedPeers = saved.Peers
+		node.tokens = saved.Tokens
+	}
+	node.events = config.Events
+	if node.events == nil {
+		node.events = NewEventBus()
+	}
+	node.log = config.Logger
+	if node.log == nil {
+		node.log = logger.ForNode(string(config.NodeID))
+	}
+	if len(node.tokens) == 0 {
+		node.tokens = ring.RandomTokens(config.NumTokens)
+		node.joining = config.AutoBootstrap
+	} else if len(node.tokens) != config.NumTokens {
+		node.logf("Keeping the %d tokens saved in %s, not picking %d", len(node.tokens), config.DataDir, config.NumTokens)
+	}
+	node.publishEndpointEvents(gossipState)
+	return node, nil
 }
 
-// Start starts the node (both server and client if configured)
+// Start starts the node: the server, gossip with seeds, and the heartbeat client if configured.
+// A node joining with new tokens bootstraps in the background once Start returns, see
+// bootstrap. A node can only be started once. If starting fails the node is marked failed;
+// call Stop to release whatever was already started.
 func (n *Node) Start() error {
+	if err := n.transition(StatusStarting, nil, StatusCreated); err != nil {
+		return err
+	}
+	return n.run()
+}
+
+// Restart stops the node and starts it again with the same NodeID and a strictly higher
+// generation, like restarting the process: peers replace what they knew about the node,
+// and it rejoins through its seeds. A stopped or failed node can be restarted too.
+func (n *Node) Restart() error {
+	if err := n.Stop(); err != nil {
+		return err
+	}
+
 	n.mu.Lock()
-	defer n.mu.Unlock()
+	n.gossipState = n.gossipState.Restarted()
+	n.publishEndpointEvents(n.gossipState)
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	n.failed = make(chan struct{})
+	n.clientPeer = nil
+	n.paused.Store(false)
+	generation := n.gossipState.LocalHeartbeat().Generation
+	n.mu.Unlock()
+
+	// Fails if another Stop or Restart is still in progress
+	if err := n.transition(StatusStarting, nil, StatusStopped); err != nil {
+		return err
+	}
+	n.logf("Restarting with generation %d", generation)
+	if err := n.run(); err != nil {
+		return err
+	}
+	n.restarts.Add(1)
+	return nil
+}
+
+// Restarts returns how many times the node was restarted, by Restart or by the Manager's
+// supervisor (see Manager.SetAutoRestart)
+func (n *Node) Restarts() int {
+	return int(n.restarts.Load())
+}
+
+// run starts everything and moves the node from Starting to Running (or Failed)
+func (n *Node) run() error {
+	n.mu.Lock()
+	err := n.start()
+	generation := n.gossipState.LocalHeartbeat().Generation
+	joining, ctx := n.joining, n.ctx
+	n.mu.Unlock()
+	if err != nil {
+		n.fail(err)
+		return err
+	}
+
+	if err := n.transition(StatusRunning, nil, StatusStarting); err != nil {
+		// Stopped, or the server failed, while we were starting
+		return err
+	}
+	n.setServing(true)
+	n.publish(Event{Type: EventNodeStarted, Generation: generation})
+	n.logf("Node %s started on %s", n.config.NodeID, n.Address())
+	if joining {
+		// Waiting for the ring and streaming can take seconds: gossiping BOOT, the node is off
+		// every ring meanwhile, so it can already run
+		go func() {
+			if err := n.bootstrap(ctx); err != nil {
+				n.logf("Bootstrap abandoned: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// start does the work of Start. Caller must hold n.mu.
+func (n *Node) start() error {
+	// Replay the keys written before the node stopped before serving requests for them
+	if err := n.openStore(); err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	// Always start the server
+	if err := n.startServer(); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	// Announce how to reach us before the first gossip round
+	n.gossipState.SetLocalAppState(gossip.AppHeartbeat, n.config.GetAdvertisedAddress())
+	n.gossipState.SetLocalAppState(gossip.AppTokens, ring.FormatTokens(n.tokens))
+	location := n.snitch.Local()
+	n.gossipState.SetLocalAppState(gossip.AppDC, location.DC)
+	n.gossipState.SetLocalAppState(gossip.AppRack, location.Rack)
+	if n.joining {
+		// Kept off the ring until it has streamed its ranges, see bootstrap
+		n.gossipState.SetLocalAppState(gossip.AppStatus, gossip.StatusBoot)
+	} else {
+		n.gossipState.SetLocalAppState(gossip.AppStatus, gossip.StatusNormal)
+	}
+
+	n.connectToPeers()
+	n.startGossipLoop()
+	n.startPersistLoop()
 
 	// Start client mode if configured
 	if n.config.ClientMode {
@@ -66,49 +256,113 @@ func (n *Node) Start() error {
 		n.logf("Client mode enabled: node %s will send heartbeats to %s every %v",
 			n.config.NodeID, n.config.TargetServer, n.config.HeartbeatInterval)
 	}
-
-	// Always start the server
-	if err := n.startServer(); err != nil {
-		return fmt.Errorf("failed to start server: %w", err)
-	}
-
-	n.logf("Node %s started on %s", n.config.NodeID, n.config.GetAddress())
 	return nil
 }
 
-// Stop stops the node gracefully
+// Stop stops the node gracefully, within DrainTimeout. Stopping a node that is already
+// stopped (or stopping) does nothing.
 func (n *Node) Stop() error {
-	n.mu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.DrainTimeout)
+	defer cancel()
+	return n.StopContext(ctx)
+}
+
+// StopContext stops the node gracefully: it announces the shutdown to a peer, waits for
+// in-flight RPCs and closes its connections. Once ctx is done it stops waiting and closes
+// the server forcefully, so an unresponsive peer can't hold up the shutdown.
+func (n *Node) StopContext(ctx context.Context) error {
+	wasRunning := n.Status() == StatusRunning
+	if err := n.transition(StatusStopped, nil, StatusCreated); err == nil {
+		// Never started, nothing to release
+		n.mu.Lock()
+		n.cancel()
+		n.mu.Unlock()
+		return nil
+	}
+	if err := n.transition(StatusStopping, nil, StatusStarting, StatusRunning, StatusFailed); err != nil {
+		return nil
+	}
+
 	nodeID := n.config.NodeID
-	grpcServer := n.grpcServer
-	clientConn := n.clientConn
-	
-	// Cancel context to stop all goroutines (heartbeat sending, etc.)
+	n.logf("Stopping node %s...", nodeID)
+	n.setServing(false)
+
+	if wasRunning && !n.Decommissioning() {
+		n.announceShutdown(ctx)
+	}
+
+	n.mu.Lock()
+	nodeTransport := n.transport
+	clientPeer := n.clientPeer
+
+	// Cancel context to stop all goroutines (heartbeat sending, gossip rounds, etc.)
 	n.cancel()
 	n.mu.Unlock()
 
-	n.logf("Stopping node %s...", nodeID)
-
-	// Stop gRPC server first (this will unblock the Serve() call)
+	// Stop the transport first (this will unblock the Serve() call)
 	// Lock is released to avoid deadlocks if callbacks try to access Node
-	if grpcServer != nil {
-		if err := grpcServer.Stop(); err != nil {
-			n.logf("Error stopping gRPC server: %v", err)
+	if nodeTransport != nil {
+		if err := stopTransport(ctx, nodeTransport); err != nil {
+			n.logf("Error stopping transport: %v", err)
 		}
 	}
+	if ctx.Err() != nil {
+		n.logf("Drain deadline exceeded, stopped node %s forcefully", nodeID)
+	}
 
-	// Close client connection if exists
+	// Close client connections if they exist
 	// Lock is released to avoid deadlocks if callbacks try to access Node
-	if clientConn != nil {
-		if err := clientConn.Close(); err != nil {
+	if clientPeer != nil {
+		if err := clientPeer.Close(); err != nil {
 			n.logf("Error closing client connection: %v", err)
 		}
 	}
+	n.closePeers()
+	n.closeStore()
+	n.persist()
 
+	n.transition(StatusStopped, nil, StatusStopping)
 	n.logf("Node %s stopped", nodeID)
 	return nil
 }
 
+// announceShutdown gossips STATUS SHUTDOWN to one live peer (or a seed), so the cluster marks
+// the node down right away instead of waiting for the failure detector. It gives up when ctx is done.
+func (n *Node) announceShutdown(ctx context.Context) {
+	gossipState := n.GetGossipState()
+	gossipState.SetLocalAppState(gossip.AppStatus, gossip.StatusShutdown)
+
+	live, _ := n.gossipCandidates(gossipState)
+	if len(live) > 0 {
+		n.gossipWith(ctx, gossipState, live[rand.Intn(len(live))])
+	} else if seed, ok := n.randomSeed(); ok {
+		n.gossipWith(ctx, gossipState, seed)
+	}
+}
+
+// stopTransport stops t, forcefully once ctx is done if t supports it (transport.ContextStopper)
+func stopTransport(ctx context.Context, t transport.Transport) error {
+	if stopper, ok := t.(transport.ContextStopper); ok {
+		return stopper.StopContext(ctx)
+	}
+	return t.Stop()
+}
+
+// Address returns the address (address:port) the node serves on. Once started,
+// a configured port of 0 has been replaced by the port the OS assigned.
+func (n *Node) Address() string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.config.GetAddress()
+}
+
+// AdvertisedAddress returns the address the node gossips to other nodes, see Config.AdvertisedAddress
+func (n *Node) AdvertisedAddress() string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.config.GetAdvertisedAddress()
+}
+
 // GetGossipState returns the gossip state (for external access)
 func (n *Node) GetGossipState() *gossip.GossipState {
 	n.mu.RLock()
@@ -116,6 +370,30 @@ func (n *Node) GetGossipState() *gossip.GossipState {
 	return n.gossipState
 }
 
+// reservedAppStates are set by the node itself; changing them by hand would confuse its peers
+var reservedAppStates = map[gossip.AppStateKey]bool{
+	gossip.AppStatus:    true,
+	gossip.AppHeartbeat: true,
+	gossip.AppTokens:    true,
+	gossip.AppDC:        true,
+	gossip.AppRack:      true,
+}
+
+// SetAppState sets an application state of this node, e.g. LOAD=42, and returns it with its
+// new version. Peers learn about it through gossip like any other state change.
+func (n *Node) SetAppState(key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	if key == "" {
+		return gossip.AppState{}, ErrAppStateKeyRequired
+	}
+	if reservedAppStates[key] {
+		return gossip.AppState{}, fmt.Errorf("%w: %s", ErrReservedAppState, key)
+	}
+
+	state := n.GetGossipState().SetLocalAppState(key, value)
+	n.logf("Set %s=%s (version %d)", key, value, state.Version)
+	return state, nil
+}
+
 // GetConfig returns the node configuration (for external access)
 func (n *Node) GetConfig() *Config {
 	n.mu.RLock()
@@ -123,69 +401,91 @@ func (n *Node) GetConfig() *Config {
 	return n.config
 }
 
-// startServer starts the gRPC server
+// startServer creates the transport, starts serving and watches for the server failing later
 func (n *Node) startServer() error {
-	grpcTransport, err := transport.NewGRPC(
-		n.config.GetAddress(),
-		string(n.config.NodeID),
-		n.gossipState,
-	)
+	nodeTransport, err := n.bindTransport()
 	if err != nil {
-		return fmt.Errorf("failed to create gRPC transport: %w", err)
+		return err
 	}
 
-	n.grpcServer = grpcTransport
+	n.peersMu.Lock()
+	n.transport = nodeTransport
+	n.peersMu.Unlock()
 
-	n.logf("gRPC server starting on %s (node-id: %s)", n.config.GetAddress(), n.config.NodeID)
+	n.adoptBoundPort(nodeTransport)
+	n.monitorServeErrors(n.ctx, nodeTransport)
+	return nil
+}
+
+// adoptBoundPort replaces a configured port of 0 with the port the transport was bound to,
+// so the address we gossip (and rebind on) is one peers can actually reach
+func (n *Node) adoptBoundPort(nodeTransport transport.Transport) {
+	if n.config.Port != "0" || transport.IsUnixAddress(n.config.Address) {
+		return
+	}
+	_, port, err := net.SplitHostPort(nodeTransport.Addr())
+	if err != nil {
+		n.logf("Failed to parse bound address %s: %v", nodeTransport.Addr(), err)
+		return
+	}
+	n.config.Port = port
+	n.logf("Bound to port %s", port)
+}
+
+// bindTransport creates a transport with the configured factory and starts it
+func (n *Node) bindTransport() (transport.Transport, error) {
+	factory := n.config.Transport
+	if factory == nil {
+		factory = NewGRPCTransport
+	}
+
+	nodeTransport, err := factory(n.config, &gossipHandler{node: n})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
+
+	n.logf("Transport starting on %s (node-id: %s)", n.config.GetAddress(), n.config.NodeID)
 
 	// Start() performs binding synchronously and returns an error immediately if binding fails.
-	// If binding succeeds, it spawns Serve in a goroutine and returns nil.
+	// If binding succeeds, it serves in the background and returns nil.
 	// This ensures that binding errors (e.g., port already in use) are surfaced synchronously.
-	if err := grpcTransport.Start(); err != nil {
-		return fmt.Errorf("failed to bind gRPC server: %w", err)
+	if err := nodeTransport.Start(); err != nil {
+		return nil, fmt.Errorf("failed to bind transport: %w", err)
 	}
 
-	// Binding succeeded - server is now serving in a background goroutine
-	return nil
+	// Binding succeeded - server is now serving in the background
+	return nodeTransport, nil
 }
 
-// startClient starts the client that sends heartbeats
+// startClient starts the client that sends heartbeats. Caller must hold n.mu.
 func (n *Node) startClient() error {
-	// Create gRPC client connection
-	conn, err := grpc.NewClient(
-		n.config.TargetServer,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	peer, err := n.transport.Dial(n.config.TargetServer)
 	if err != nil {
 		return fmt.Errorf("failed to connect to target server: %w", err)
 	}
 
-	n.clientConn = conn
-	client := pbproto.NewHeartbeatServiceClient(conn)
+	n.clientPeer = peer
+	nodeCtx := n.ctx
 
 	// Create heartbeat sender function
 	sendHeartbeat := func(heartbeatState gossip.HeartbeatStateSnapshot) (string, int64, error) {
-		req := &pbproto.HeartbeatRequest{
-			NodeId:    string(heartbeatState.NodeID),
-			Timestamp: heartbeatState.Generation,
-		}
-
-		resp, err := client.Heartbeat(n.ctx, req)
+		ctx, cancel := context.WithTimeout(nodeCtx, n.config.RPCTimeout)
+		defer cancel()
+		resp, err := peer.SendHeartbeat(ctx, heartbeatState)
 		if err != nil {
 			return "", 0, err
 		}
 
-		return resp.NodeId, resp.Timestamp, nil
+		return string(resp.NodeID), resp.Generation, nil
 	}
 
 	// Start heartbeat sending
-	n.gossipState.Start(n.ctx, sendHeartbeat)
+	n.gossipState.Start(nodeCtx, sendHeartbeat)
 
 	return nil
 }
 
-// logf logs using the global logger (which handles both stdout and log buffer)
+// logf logs with the node's logger, tagged with its node ID
 func (n *Node) logf(format string, args ...interface{}) {
-	// Use logger with node ID as prefix
-	logger.Printf("[%s] %s", string(n.config.NodeID), fmt.Sprintf(format, args...))
+	n.log.Printf(format, args...)
 }
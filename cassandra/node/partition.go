@@ -0,0 +1,94 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// Partition simulates a network partition between two groups of managed nodes: every node
+// drops gossip to and from the nodes of the other group, while both groups keep gossiping
+// among themselves and with nodes in neither group. Partitions add up until Heal.
+func (m *Manager) Partition(groupA, groupB []gossip.NodeID) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, err := m.nodesByIDLocked(groupA)
+	if err != nil {
+		return err
+	}
+	b, err := m.nodesByIDLocked(groupB)
+	if err != nil {
+		return err
+	}
+	for _, node := range a {
+		for _, other := range b {
+			if node == other {
+				return fmt.Errorf("node %s is in both groups", node.config.NodeID)
+			}
+		}
+	}
+
+	for _, node := range a {
+		for _, other := range b {
+			m.filters[node].Block(other.config.NodeID, other.AdvertisedAddress())
+			m.filters[other].Block(node.config.NodeID, node.AdvertisedAddress())
+		}
+	}
+	logger.Infof("Partitioned %v from %v", groupA, groupB)
+	m.bus.Publish(Event{Type: EventPartitioned, Groups: [2][]gossip.NodeID{groupA, groupB}})
+	return nil
+}
+
+// Partitioned reports whether any managed node drops gossip from another because of Partition
+func (m *Manager) Partitioned() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, filter := range m.filters {
+		if len(filter.Blocked()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Heal undoes every Partition. Connections that failed during the partition are dropped,
+// so the nodes redial each other right away instead of waiting out their reconnect backoff.
+func (m *Manager) Heal() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	healed := false
+	for node, filter := range m.filters {
+		blocked := filter.Blocked()
+		if len(blocked) == 0 {
+			continue
+		}
+		filter.Clear()
+		healed = true
+
+		for _, nodeID := range blocked {
+			if index, ok := m.nodeMap[string(nodeID)]; ok {
+				node.closePeer(m.nodes[index].AdvertisedAddress())
+			}
+		}
+	}
+	if healed {
+		logger.Info("Partition healed")
+		m.bus.Publish(Event{Type: EventHealed})
+	}
+}
+
+// nodesByIDLocked returns the managed nodes with the given IDs. Caller must hold m.mu.
+func (m *Manager) nodesByIDLocked(nodeIDs []gossip.NodeID) ([]*Node, error) {
+	nodes := make([]*Node, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		index, ok := m.nodeMap[string(nodeID)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+		}
+		nodes = append(nodes, m.nodes[index])
+	}
+	return nodes, nil
+}
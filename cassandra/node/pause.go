@@ -0,0 +1,57 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Pause freezes the node without stopping it, like a long GC pause or a hung process: its
+// gossip loop skips every round and gossip messages from peers fail with
+// transport.ErrUnavailable (codes.Unavailable over gRPC), so the cluster suspects and
+// eventually convicts it. Admin RPCs keep working.
+func (n *Node) Pause() error {
+	if status := n.Status(); status != StatusRunning {
+		return fmt.Errorf("%w: cannot pause a %s node", ErrInvalidTransition, status)
+	}
+	if !n.paused.CompareAndSwap(false, true) {
+		return ErrNodePaused
+	}
+	n.logf("Paused: not gossiping or answering peers until resumed")
+	n.publish(Event{Type: EventNodePaused})
+	return nil
+}
+
+// Resume undoes Pause. The node gossips again from its next round, so the cluster soon
+// sees it alive again.
+func (n *Node) Resume() error {
+	if !n.paused.CompareAndSwap(true, false) {
+		return ErrNodeNotPaused
+	}
+	n.logf("Resumed")
+	n.publish(Event{Type: EventNodeResumed})
+	return nil
+}
+
+// Paused reports whether the node is paused
+func (n *Node) Paused() bool {
+	return n.paused.Load()
+}
+
+// PauseNode pauses the managed node with the given ID, see Node.Pause
+func (m *Manager) PauseNode(nodeID gossip.NodeID) error {
+	node, err := m.GetNode(nodeID)
+	if err != nil {
+		return err
+	}
+	return node.Pause()
+}
+
+// ResumeNode resumes the managed node with the given ID, see Node.Resume
+func (m *Manager) ResumeNode(nodeID gossip.NodeID) error {
+	node, err := m.GetNode(nodeID)
+	if err != nil {
+		return err
+	}
+	return node.Resume()
+}
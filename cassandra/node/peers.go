@@ -0,0 +1,343 @@
+package node
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// PeerState is the health of the outbound connection to a peer
+type PeerState int
+
+const (
+	PeerConnecting PeerState = iota // dialed, no exchange has succeeded yet
+	PeerConnected                   // the last exchange succeeded
+	PeerDown                        // the last exchange failed, waiting to redial
+	PeerIdle                        // known from gossip, never dialed
+)
+
+func (s PeerState) String() string {
+	switch s {
+	case PeerConnecting:
+		return "Connecting"
+	case PeerConnected:
+		return "Connected"
+	case PeerDown:
+		return "Down"
+	case PeerIdle:
+		return "Idle"
+	default:
+		return "Unknown"
+	}
+}
+
+// PeerConnection describes the outbound connection to one peer address
+type PeerConnection struct {
+	Address      string
+	State        PeerState
+	Failures     int       // consecutive failed exchanges
+	Errors       int       // failed exchanges since the connection was first dialed
+	LastExchange time.Time // last successful exchange, zero if none succeeded
+	NextAttempt  time.Time // earliest redial of a Down peer
+	LastError    error
+}
+
+// PeerInfo is what the node knows about one peer: its connection, if any, and its gossip
+type PeerInfo struct {
+	PeerConnection
+	NodeID gossip.NodeID // empty until gossip tells us which node serves Address
+	Alive  bool          // the failure detector's view, false while NodeID is unknown
+}
+
+// peerConn is a pooled connection. peer is nil while the connection is Down.
+type peerConn struct {
+	PeerConnection
+	peer transport.Peer
+
+	// Heartbeat gossip reported for this address when we gave up on it
+	lastHeartbeat    gossip.HeartbeatStateSnapshot
+	hasLastHeartbeat bool
+}
+
+// PeerConnections returns the state of every outbound peer connection, sorted by address
+func (n *Node) PeerConnections() []PeerConnection {
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	conns := make([]PeerConnection, 0, len(n.peers))
+	for _, conn := range n.peers {
+		conns = append(conns, conn.PeerConnection)
+	}
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].Address < conns[j].Address
+	})
+	return conns
+}
+
+// GetPeers returns every peer the node knows about, sorted by address: those it has a
+// connection to and those it only heard of through gossip (PeerIdle).
+func (n *Node) GetPeers() []PeerInfo {
+	peers := make(map[string]*PeerInfo)
+	for _, conn := range n.PeerConnections() {
+		peers[conn.Address] = &PeerInfo{PeerConnection: conn}
+	}
+
+	for nodeID, state := range n.GetGossipState().GetStateByNode() {
+		if nodeID == n.config.NodeID {
+			continue
+		}
+		addr, ok := state.GetApplicationState(gossip.AppHeartbeat)
+		if !ok {
+			continue
+		}
+		peer, ok := peers[addr.Value]
+		if !ok {
+			peer = &PeerInfo{PeerConnection: PeerConnection{Address: addr.Value, State: PeerIdle}}
+			peers[addr.Value] = peer
+		}
+		peer.NodeID = nodeID
+		peer.Alive = state.IsAlive()
+	}
+
+	infos := make([]PeerInfo, 0, len(peers))
+	for _, peer := range peers {
+		infos = append(infos, *peer)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Address < infos[j].Address
+	})
+	return infos
+}
+
+// connectToPeers dials every configured seed, and every peer saved by a previous run,
+// so the first gossip rounds have somewhere to go
+func (n *Node) connectToPeers() {
+	for _, addr := range n.savedPeers {
+		if n.isSelf(addr) || n.isSeed(addr) {
+			continue
+		}
+		if _, err := n.getPeer(addr); err != nil {
+			n.logf("Failed to connect to saved peer %s: %v", addr, err)
+		}
+	}
+	for _, seed := range n.config.Seeds {
+		if n.isSelf(seed) {
+			continue
+		}
+		if _, err := n.getPeer(seed); err != nil {
+			n.logf("Failed to connect to seed %s: %v", seed, err)
+		}
+	}
+}
+
+// getPeer returns the connection to addr, dialing it on first use.
+// A Down peer is only redialed once its backoff has expired, and not at all
+// once MaxReconnectAttempts consecutive exchanges have failed.
+func (n *Node) getPeer(addr string) (transport.Peer, error) {
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	conn, ok := n.peers[addr]
+	if !ok {
+		conn = &peerConn{PeerConnection: PeerConnection{Address: addr, State: PeerConnecting}}
+		n.peers[addr] = conn
+	}
+	if conn.peer != nil {
+		return conn.peer, nil
+	}
+
+	if n.retriesExhausted(conn) {
+		return nil, ErrPeerRetriesExhausted
+	}
+	if time.Now().Before(conn.NextAttempt) {
+		return nil, ErrPeerBackoff
+	}
+
+	peer, err := n.transport.Dial(addr)
+	if err != nil {
+		n.markPeerFailedLocked(conn, err)
+		return nil, err
+	}
+	conn.peer = peer
+	conn.State = PeerConnecting
+	return peer, nil
+}
+
+// reportPeer records the outcome of an exchange with addr. A failure closes the
+// connection and schedules a redial with exponential backoff.
+func (n *Node) reportPeer(addr string, err error) {
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	conn, ok := n.peers[addr]
+	if !ok {
+		return
+	}
+
+	if err != nil {
+		n.markPeerFailedLocked(conn, err)
+		return
+	}
+
+	if conn.Failures > 0 {
+		n.logf("Reconnected to %s after %d failed attempts", addr, conn.Failures)
+		n.publish(Event{Type: EventConnectionUp, Address: addr})
+	}
+	conn.State = PeerConnected
+	conn.LastExchange = time.Now()
+	conn.Failures = 0
+	conn.NextAttempt = time.Time{}
+	conn.LastError = nil
+}
+
+func (n *Node) markPeerFailedLocked(conn *peerConn, err error) {
+	if conn.peer != nil {
+		conn.peer.Close()
+		conn.peer = nil
+	}
+
+	conn.Failures++
+	conn.Errors++
+	conn.LastError = err
+	conn.State = PeerDown
+	conn.NextAttempt = time.Now().Add(n.reconnectBackoff(conn.Failures))
+
+	if n.retriesExhausted(conn) {
+		n.logf("Giving up on %s after %d failed attempts: %v", conn.Address, conn.Failures, err)
+	} else if conn.Failures == 1 {
+		n.logf("Connection to %s is down, reconnecting with backoff: %v", conn.Address, err)
+	}
+	if conn.Failures == 1 {
+		n.publish(Event{Type: EventConnectionDown, Address: conn.Address, Err: err})
+	}
+}
+
+// reconnectBackoff doubles ReconnectBackoff for every consecutive failure, up to MaxReconnectBackoff
+func (n *Node) reconnectBackoff(failures int) time.Duration {
+	backoff := n.config.ReconnectBackoff
+	for i := 1; i < failures && backoff < n.config.MaxReconnectBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > n.config.MaxReconnectBackoff {
+		backoff = n.config.MaxReconnectBackoff
+	}
+	return backoff
+}
+
+func (n *Node) retriesExhausted(conn *peerConn) bool {
+	return n.config.MaxReconnectAttempts > 0 && conn.Failures >= n.config.MaxReconnectAttempts
+}
+
+// revivePeers lets us retry peers we gave up on once gossip from other nodes
+// carries a newer heartbeat for them, i.e. someone else can reach them
+func (n *Node) revivePeers(gossipState *gossip.GossipState) {
+	heartbeats := make(map[string]gossip.HeartbeatStateSnapshot)
+	for _, state := range gossipState.GetStateByNode() {
+		if addr, ok := state.GetApplicationState(gossip.AppHeartbeat); ok {
+			heartbeats[addr.Value] = state.HeartbeatState
+		}
+	}
+
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	for addr, conn := range n.peers {
+		if !n.retriesExhausted(conn) {
+			conn.hasLastHeartbeat = false
+			continue
+		}
+		heartbeat, ok := heartbeats[addr]
+		if !ok {
+			continue
+		}
+		if !conn.hasLastHeartbeat {
+			conn.lastHeartbeat = heartbeat
+			conn.hasLastHeartbeat = true
+			continue
+		}
+		if heartbeat.Generation == conn.lastHeartbeat.Generation && heartbeat.Version <= conn.lastHeartbeat.Version {
+			continue
+		}
+		n.logf("%s is alive according to gossip, retrying", addr)
+		conn.Failures = 0
+		conn.NextAttempt = time.Time{}
+		conn.hasLastHeartbeat = false
+	}
+}
+
+// checkPeers is the connection health check: every Down peer whose backoff has
+// expired is redialed and probed with a heartbeat, so it comes back as soon as it is reachable.
+func (n *Node) checkPeers(ctx context.Context, gossipState *gossip.GossipState) {
+	n.peersMu.Lock()
+	var due []string
+	now := time.Now()
+	for addr, conn := range n.peers {
+		if conn.State == PeerDown && !n.retriesExhausted(conn) && !now.Before(conn.NextAttempt) {
+			due = append(due, addr)
+		}
+	}
+	n.peersMu.Unlock()
+
+	for _, addr := range due {
+		go func(addr string) {
+			if ctx.Err() != nil {
+				return
+			}
+			peer, err := n.getPeer(addr)
+			if err != nil {
+				return
+			}
+			probeCtx, cancel := context.WithTimeout(ctx, n.config.RPCTimeout)
+			defer cancel()
+			_, err = peer.SendHeartbeat(probeCtx, gossipState.LocalHeartbeat())
+			n.reportPeer(addr, err)
+		}(addr)
+	}
+}
+
+// markSelf stops gossiping with addr, which turned out to be this node under another address
+func (n *Node) markSelf(addr string) {
+	n.closePeer(addr)
+
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+	if _, ok := n.selfAddrs[addr]; !ok {
+		n.selfAddrs[addr] = struct{}{}
+		n.logf("%s is this node, no longer gossiping with it", addr)
+	}
+}
+
+// closePeer closes and forgets the connection to addr, if any
+func (n *Node) closePeer(addr string) {
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	conn, ok := n.peers[addr]
+	if !ok {
+		return
+	}
+	if conn.peer != nil {
+		if err := conn.peer.Close(); err != nil {
+			n.logf("Error closing connection to %s: %v", addr, err)
+		}
+	}
+	delete(n.peers, addr)
+}
+
+// closePeers closes every outbound peer connection
+func (n *Node) closePeers() {
+	n.peersMu.Lock()
+	defer n.peersMu.Unlock()
+
+	for addr, conn := range n.peers {
+		if conn.peer != nil {
+			if err := conn.peer.Close(); err != nil {
+				n.logf("Error closing connection to %s: %v", addr, err)
+			}
+		}
+		delete(n.peers, addr)
+	}
+}
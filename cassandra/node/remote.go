@@ -0,0 +1,312 @@
+package node
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+const (
+	// remoteRPCTimeout bounds the admin RPCs sent to attached nodes
+	remoteRPCTimeout = 5 * time.Second
+	// remoteRewatchInterval is how long to wait before watching an attached node again
+	// after its stream broke
+	remoteRewatchInterval = time.Second
+	// remoteLogTail is how many of its latest log entries an attached node sends first, see FollowLogs
+	remoteLogTail = 100
+)
+
+// RemoteNode is a node running in another process (e.g. started with cassandra start) that the
+// Manager is attached to, see Manager.Attach. It is managed through the node's AdminService and
+// follows the node's gossip state through WatchClusterState, and its log through Logs (see FollowLogs).
+type RemoteNode struct {
+	addr   string
+	nodeID gossip.NodeID
+	client *transport.AdminClient
+	bus    *EventBus
+	ctx    context.Context // done once detached
+	cancel context.CancelFunc
+	loops  sync.WaitGroup // the watch and log loops
+
+	mu        sync.RWMutex
+	endpoints map[gossip.NodeID]gossip.StateUpdate // what the node knows, as of the last update
+	connected bool
+	err       error // why the last watch failed
+}
+
+// NodeID returns the remote node's ID
+func (r *RemoteNode) NodeID() gossip.NodeID {
+	return r.nodeID
+}
+
+// Address returns the address the Manager attached to
+func (r *RemoteNode) Address() string {
+	return r.addr
+}
+
+// Connected reports whether the remote node's state is being watched. While it isn't, the
+// Manager keeps trying to reconnect.
+func (r *RemoteNode) Connected() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.connected
+}
+
+// Err returns why the remote node couldn't be watched last time, or nil
+func (r *RemoteNode) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.err
+}
+
+// Endpoints returns what the remote node knows about every endpoint, including itself,
+// sorted by node ID. Endpoints it removed are only forgotten when the watch reconnects.
+func (r *RemoteNode) Endpoints() []gossip.EndpointInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoints := make([]gossip.EndpointInfo, 0, len(r.endpoints))
+	for nodeID, update := range r.endpoints {
+		endpoints = append(endpoints, gossip.EndpointInfo{
+			State: update.State,
+			Alive: update.Alive,
+			Local: nodeID == r.nodeID,
+		})
+	}
+	slices.SortFunc(endpoints, func(a, b gossip.EndpointInfo) int {
+		return cmp.Compare(a.State.HeartbeatState.NodeID, b.State.HeartbeatState.NodeID)
+	})
+	return endpoints
+}
+
+// ClusterState asks the remote node for its current cluster state
+func (r *RemoteNode) ClusterState() (transport.ClusterState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRPCTimeout)
+	defer cancel()
+	return r.client.GetClusterState(ctx)
+}
+
+// SendGossipRound makes the remote node run one gossip round now
+func (r *RemoteNode) SendGossipRound() error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRPCTimeout)
+	defer cancel()
+	return r.client.TriggerGossipRound(ctx)
+}
+
+// SetAppState sets one of the remote node's application states
+func (r *RemoteNode) SetAppState(key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRPCTimeout)
+	defer cancel()
+	return r.client.SetAppState(ctx, key, value)
+}
+
+// Decommission makes the remote node leave the cluster, see Node.Decommission. It returns
+// once the node started leaving.
+func (r *RemoteNode) Decommission() error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRPCTimeout)
+	defer cancel()
+	_, err := r.client.Decommission(ctx)
+	return err
+}
+
+// FollowLogs copies the remote node's log into buffer, starting with its latest entries, until
+// the node is detached. The process of the node serves the log of all its nodes, so entries of
+// other nodes it runs are copied too. The stream is reopened when it breaks; entries logged
+// while it was broken are copied too, as far as the node still has them.
+func (r *RemoteNode) FollowLogs(buffer *logger.LogBuffer) {
+	r.loops.Add(1)
+	go func() {
+		defer r.loops.Done()
+		r.followLogs(buffer)
+	}()
+}
+
+// followLogs copies log entries into buffer until r.ctx is done. A reopened stream starts with
+// entries copied before, which are skipped: those older than the last one copied, and those of
+// its millisecond, the resolution of the stream's timestamps, that were copied already.
+func (r *RemoteNode) followLogs(buffer *logger.LogBuffer) {
+	var last time.Time
+	var atLast []logger.LogEntry // entries copied with the timestamp last
+	for {
+		entries, err := r.client.Logs(r.ctx, transport.LogsQuery{Tail: remoteLogTail, Follow: true})
+		if err == nil {
+			sent := slices.Clone(atLast)
+			for entry := range entries {
+				if entry.Timestamp.Before(last) {
+					continue
+				}
+				if i := slices.IndexFunc(sent, entry.Equal); i >= 0 {
+					sent = slices.Delete(sent, i, i+1)
+					continue
+				}
+				if entry.Timestamp.After(last) {
+					last = entry.Timestamp
+					atLast = atLast[:0]
+				}
+				atLast = append(atLast, entry)
+				buffer.AddEntry(entry)
+			}
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(remoteRewatchInterval):
+		}
+	}
+}
+
+// watch follows the remote node's state until ctx is done, reconnecting when the stream breaks
+func (r *RemoteNode) watch(ctx context.Context) {
+	defer r.loops.Done()
+	for {
+		updates, err := r.client.WatchClusterState(ctx)
+		if err == nil {
+			r.setConnected(true, nil)
+			for update := range updates {
+				r.apply(update)
+			}
+			err = fmt.Errorf("cluster state stream to %s closed", r.addr)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		r.setConnected(false, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(remoteRewatchInterval):
+		}
+	}
+}
+
+// setConnected records whether the watch works. A new watch starts from a full snapshot,
+// so what the node knew before is forgotten.
+func (r *RemoteNode) setConnected(connected bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if connected && !r.connected {
+		clear(r.endpoints)
+	}
+	if connected != r.connected {
+		if connected {
+			logger.Infof("Attached node %s (%s) connected", r.nodeID, r.addr)
+		} else {
+			logger.Errorf("Attached node %s (%s) disconnected: %v", r.nodeID, r.addr, err)
+		}
+	}
+	r.connected = connected
+	r.err = err
+}
+
+// apply stores update, and publishes the peer events the Manager's own nodes would
+func (r *RemoteNode) apply(update gossip.StateUpdate) {
+	peer := update.State.HeartbeatState.NodeID
+
+	r.mu.Lock()
+	previous, known := r.endpoints[peer]
+	r.endpoints[peer] = update
+	r.mu.Unlock()
+
+	if peer == r.nodeID {
+		return
+	}
+	event := Event{NodeID: r.nodeID, Peer: peer}
+	switch {
+	case !known || update.State.HeartbeatState.Generation != previous.State.HeartbeatState.Generation:
+		event.Type = EventPeerDiscovered
+	case update.Alive && !previous.Alive:
+		event.Type = EventPeerUp
+	case !update.Alive && previous.Alive:
+		event.Type = EventPeerDown
+	default:
+		event.Type = EventStateMerged
+	}
+	r.bus.Publish(event)
+}
+
+// close stops watching the remote node and disconnects from it. The node keeps running.
+func (r *RemoteNode) close() error {
+	r.cancel()
+	r.loops.Wait()
+	return r.client.Close()
+}
+
+// Attach connects to a node running in another process, e.g. started with cassandra start, at
+// addr. The node is listed by RemoteNodes and its peers' comings and goings are published on
+// Bus like those of the Manager's own nodes. New nodes of the Manager use attached nodes as
+// seeds when there are not enough nodes of its own, so they join the same cluster.
+func (m *Manager) Attach(addr string) (*RemoteNode, error) {
+	client, err := transport.DialAdmin(addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRPCTimeout)
+	state, err := client.GetClusterState(ctx)
+	cancel()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to attach to %s: %w", addr, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.nodeIDTakenLocked(state.NodeID) {
+		client.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNodeExists, state.NodeID)
+	}
+
+	watchCtx, stop := context.WithCancel(context.Background())
+	remote := &RemoteNode{
+		addr:      addr,
+		nodeID:    state.NodeID,
+		client:    client,
+		bus:       m.bus,
+		ctx:       watchCtx,
+		cancel:    stop,
+		endpoints: make(map[gossip.NodeID]gossip.StateUpdate),
+	}
+	remote.loops.Add(1)
+	go remote.watch(watchCtx)
+	m.remotes = append(m.remotes, remote)
+	logger.Infof("Attached to node %s at %s", remote.nodeID, addr)
+	return remote, nil
+}
+
+// Detach disconnects from the attached node with the given ID. The node keeps running.
+func (m *Manager) Detach(nodeID gossip.NodeID) error {
+	m.mu.Lock()
+	index := slices.IndexFunc(m.remotes, func(r *RemoteNode) bool { return r.nodeID == nodeID })
+	if index < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+	remote := m.remotes[index]
+	m.remotes = slices.Delete(m.remotes, index, index+1)
+	m.mu.Unlock()
+
+	logger.Infof("Detached from node %s", nodeID)
+	return remote.close()
+}
+
+// RemoteNodes returns the attached nodes, in the order they were attached
+func (m *Manager) RemoteNodes() []*RemoteNode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.remotes)
+}
+
+// nodeIDTakenLocked reports whether a managed or attached node has nodeID. Caller must hold m.mu.
+func (m *Manager) nodeIDTakenLocked(nodeID gossip.NodeID) bool {
+	if _, ok := m.nodeMap[string(nodeID)]; ok {
+		return true
+	}
+	return slices.ContainsFunc(m.remotes, func(r *RemoteNode) bool { return r.nodeID == nodeID })
+}
@@ -0,0 +1,178 @@
+package node
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// Scan returns up to limit live keys of the cluster and their values, every one if limit is
+// 0, in token order, like a Cassandra range scan: every node is asked for the ranges it is a
+// replica of, and the newest versions are kept once every range has been read from as many
+// of its replicas as cl asks for. Unlike Get, it doesn't repair the replicas it finds stale.
+//
+// The coordinator holds every key of the cluster while it scans: it suits the small stores
+// of a test cluster.
+func (n *Node) Scan(ctx context.Context, cl storage.ConsistencyLevel, limit int) (transport.ScanResult, error) {
+	return n.scanKeys(ctx, transport.ScanRequest{Consistency: cl, Limit: limit})
+}
+
+// scanReply is the versions one replica streamed, or the error it failed with
+type scanReply struct {
+	replica  gossip.NodeID
+	versions []transport.KeyVersion
+	err      error
+}
+
+func (n *Node) scanKeys(ctx context.Context, req transport.ScanRequest) (transport.ScanResult, error) {
+	if status := n.Status(); status != StatusRunning {
+		return transport.ScanResult{}, fmt.Errorf("%w: a %s node serves no keys", ErrInvalidTransition, status)
+	}
+	ctx, traceID := n.startTrace(ctx, req.Trace, "SCAN at %s", req.Consistency)
+	result, err := n.scan(ctx, req.Consistency, req.Limit)
+	if err != nil {
+		return transport.ScanResult{}, traceError(ctx, traceID, err)
+	}
+	storage.TraceFrom(ctx).Record("Answering the client: %d keys", len(result.Entries))
+	result.TraceID = traceID
+	return result, nil
+}
+
+// scan reads every range of the ring, see Scan
+func (n *Node) scan(ctx context.Context, cl storage.ConsistencyLevel, limit int) (transport.ScanResult, error) {
+	current := n.Ring()
+	if current.Len() == 0 {
+		return transport.ScanResult{}, ErrNoKeyOwner
+	}
+
+	// The replicas of every range, and the ranges of every replica
+	trace := storage.TraceFrom(ctx)
+	blockFor := cl.BlockFor(n.strategy.TotalReplicas())
+	ranges := current.Ranges()
+	replicasOf := make([][]gossip.NodeID, len(ranges))
+	rangesOf := make(map[gossip.NodeID][]ring.Range)
+	for i, rng := range ranges {
+		replicasOf[i] = n.strategy.Replicas(current, rng.End)
+		if len(replicasOf[i]) < blockFor {
+			return transport.ScanResult{}, fmt.Errorf("%w: %s needs %d, %d available",
+				storage.ErrUnavailable, cl, blockFor, len(replicasOf[i]))
+		}
+		for _, replica := range replicasOf[i] {
+			rangesOf[replica] = append(rangesOf[replica], rng)
+		}
+	}
+
+	// Every replica streams all its ranges at once, bounded like the coordinator's requests
+	scanCtx, cancel := context.WithTimeout(ctx, n.config.RPCTimeout)
+	defer cancel() // stops the streams not needed
+	replies := make(chan scanReply, len(rangesOf))
+	for replica, replicaRanges := range rangesOf {
+		trace.Record("Streaming %d ranges from %s", len(replicaRanges), replica)
+		go func() {
+			start := time.Now()
+			versions, err := n.scanReplica(scanCtx, replica, replicaRanges)
+			switch {
+			case err != nil && errors.Is(scanCtx.Err(), context.Canceled) && ctx.Err() == nil:
+				trace.RecordFrom(replica, "Stopped after %v, not needed", time.Since(start).Round(time.Microsecond))
+			case err != nil:
+				trace.RecordFrom(replica, "Failed after %v: %v", time.Since(start).Round(time.Microsecond), err)
+			default:
+				trace.RecordFrom(replica, "Streamed %d versions in %v", len(versions), time.Since(start).Round(time.Microsecond))
+			}
+			replies <- scanReply{replica: replica, versions: versions, err: err}
+		}()
+	}
+
+	var answered []gossip.NodeID
+	newest := make(map[string]storage.Cell)
+	var lastErr error
+	for pending := len(rangesOf); pending > 0 && !scanned(replicasOf, answered, blockFor); pending-- {
+		reply := <-replies
+		if reply.err != nil {
+			lastErr = fmt.Errorf("%s: %w", reply.replica, reply.err)
+			continue
+		}
+		answered = append(answered, reply.replica)
+		for _, version := range reply.versions {
+			if cell, ok := newest[version.Key]; !ok || version.Cell.Newer(cell) {
+				newest[version.Key] = version.Cell
+			}
+		}
+	}
+	if !scanned(replicasOf, answered, blockFor) {
+		if err := ctx.Err(); err != nil {
+			lastErr = err
+		}
+		return transport.ScanResult{}, fmt.Errorf("%w: not every range read from %d replicas for %s, %v",
+			storage.ErrNotEnoughReplies, blockFor, cl, lastErr)
+	}
+
+	trace.Record("Every range read from %d of its replicas, enough for %s", blockFor, cl)
+	result := transport.ScanResult{Replicas: answered}
+	now := time.Now()
+	for key, cell := range newest {
+		if cell.Live(now) {
+			result.Entries = append(result.Entries, transport.KeyVersion{Key: key, Cell: cell})
+		}
+	}
+	slices.SortFunc(result.Entries, func(a, b transport.KeyVersion) int {
+		return cmp.Or(cmp.Compare(ring.KeyToken(a.Key), ring.KeyToken(b.Key)), cmp.Compare(a.Key, b.Key))
+	})
+	if limit > 0 && len(result.Entries) > limit {
+		result.Entries, result.Truncated = result.Entries[:limit], true
+	}
+	return result, nil
+}
+
+// scanned reports whether every range has blockFor of its replicas among answered
+func scanned(replicasOf [][]gossip.NodeID, answered []gossip.NodeID, blockFor int) bool {
+	for _, replicas := range replicasOf {
+		count := 0
+		for _, replica := range replicas {
+			if slices.Contains(answered, replica) {
+				count++
+			}
+		}
+		if count < blockFor {
+			return false
+		}
+	}
+	return true
+}
+
+// scanReplica returns the versions a replica holds in ranges, tombstones included
+func (n *Node) scanReplica(ctx context.Context, replica gossip.NodeID, ranges []ring.Range) ([]transport.KeyVersion, error) {
+	var versions []transport.KeyVersion
+	collect := func(version transport.KeyVersion) error {
+		versions = append(versions, version)
+		return nil
+	}
+	var err error
+	if replica == n.config.NodeID {
+		err = n.streamRanges(ctx, ranges, collect)
+	} else {
+		var peer transport.KVPeer
+		if peer, err = n.remoteReplica(replica).kvPeer(); err == nil {
+			err = peer.StreamRanges(ctx, ranges, collect)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (h *gossipHandler) ScanKeys(ctx context.Context, req transport.ScanRequest) (transport.ScanResult, error) {
+	if err := h.checkPaused(); err != nil {
+		return transport.ScanResult{}, err
+	}
+	return h.node.scanKeys(ctx, req)
+}
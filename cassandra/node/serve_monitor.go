@@ -0,0 +1,98 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// monitorServeErrors watches transports that can stop serving after Start returned
+// (transport.ServeErrorReporter) and rebinds them instead of silently serving nothing
+func (n *Node) monitorServeErrors(ctx context.Context, nodeTransport transport.Transport) {
+	reporter, ok := nodeTransport.(transport.ServeErrorReporter)
+	if !ok {
+		return
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case err := <-reporter.ServeErrors():
+			if ctx.Err() != nil {
+				// Stopping, Serve returning is expected
+				return
+			}
+			n.logf("Server on %s stopped serving: %v", n.config.GetAddress(), err)
+			n.rebind(ctx, nodeTransport, err)
+		}
+	}()
+}
+
+// rebind replaces a transport that stopped serving, retrying with backoff up to
+// RebindAttempts times before marking the node failed
+func (n *Node) rebind(ctx context.Context, broken transport.Transport, cause error) {
+	if err := broken.Stop(); err != nil {
+		n.logf("Error stopping transport: %v", err)
+	}
+
+	for attempt := 1; attempt <= n.config.RebindAttempts; attempt++ {
+		backoff := n.reconnectBackoff(attempt)
+		n.logf("Rebinding %s in %v (attempt %d/%d)", n.config.GetAddress(), backoff, attempt, n.config.RebindAttempts)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		nodeTransport, err := n.bindTransport()
+		if err != nil {
+			n.logf("Rebind failed: %v", err)
+			cause = err
+			continue
+		}
+
+		n.mu.Lock()
+		if ctx.Err() != nil {
+			// Stopped while we were rebinding
+			n.mu.Unlock()
+			nodeTransport.Stop()
+			return
+		}
+		n.peersMu.Lock()
+		n.transport = nodeTransport
+		n.peersMu.Unlock()
+		n.mu.Unlock()
+
+		if n.Status() == StatusRunning && !n.Decommissioning() {
+			n.setServing(true)
+		}
+		n.monitorServeErrors(ctx, nodeTransport)
+		n.logf("Rebound %s", n.config.GetAddress())
+		return
+	}
+
+	if ctx.Err() == nil {
+		n.fail(fmt.Errorf("%w: %v", ErrServerFailed, cause))
+	}
+}
+
+// fail marks the node failed and stops its background work. The node stays in the
+// Manager so the failure can be displayed; Stop still releases its resources.
+// A node that already failed, or is stopping, can't fail (again).
+func (n *Node) fail(err error) {
+	if n.transition(StatusFailed, err, StatusStarting, StatusRunning) != nil {
+		return
+	}
+
+	n.mu.RLock()
+	cancel, failed := n.cancel, n.failed
+	n.mu.RUnlock()
+
+	n.logf("Node failed: %v", err)
+	n.setServing(false)
+	cancel()
+	close(failed)
+}
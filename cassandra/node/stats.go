@@ -0,0 +1,26 @@
+package node
+
+import "sync/atomic"
+
+// Stats are counters of a node's gossip since it was created, across restarts
+type Stats struct {
+	GossipRounds int64 // rounds run by the gossip loop or SendGossipRound
+	SynFailures  int64 // SYNs that got no ACK back
+	StatesMerged int64 // new or newer states of peers merged, see EventPeerDiscovered and EventStateMerged
+}
+
+// stats holds the counters behind Stats
+type stats struct {
+	gossipRounds atomic.Int64
+	synFailures  atomic.Int64
+	statesMerged atomic.Int64
+}
+
+// Stats returns the node's gossip counters. Sample them to get rates.
+func (n *Node) Stats() Stats {
+	return Stats{
+		GossipRounds: n.stats.gossipRounds.Load(),
+		SynFailures:  n.stats.synFailures.Load(),
+		StatesMerged: n.stats.statesMerged.Load(),
+	}
+}
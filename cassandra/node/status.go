@@ -0,0 +1,134 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// statusEventBuffer is how many status events a watcher can fall behind before events are dropped
+const statusEventBuffer = 16
+
+// Status is a node's lifecycle state.
+//
+//	Created → Starting → Running → Stopping → Stopped
+//
+// A node that can't start, or stops serving later, is Failed; Stop still moves it to Stopped.
+type Status int
+
+const (
+	StatusCreated Status = iota
+	StatusStarting
+	StatusRunning
+	StatusStopping
+	StatusStopped
+	StatusFailed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusCreated:
+		return "created"
+	case StatusStarting:
+		return "starting"
+	case StatusRunning:
+		return "running"
+	case StatusStopping:
+		return "stopping"
+	case StatusStopped:
+		return "stopped"
+	case StatusFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// StatusEvent reports a node's lifecycle transition. Err is set when the node failed.
+type StatusEvent struct {
+	NodeID gossip.NodeID
+	From   Status
+	To     Status
+	Err    error
+	Time   time.Time
+}
+
+// Status returns the node's lifecycle state
+func (n *Node) Status() Status {
+	n.statusMu.Lock()
+	defer n.statusMu.Unlock()
+	return n.status
+}
+
+// Err returns why the node failed, or nil while it is healthy
+func (n *Node) Err() error {
+	n.statusMu.Lock()
+	defer n.statusMu.Unlock()
+	return n.failErr
+}
+
+// Failed returns a channel that is closed when the node fails (see Err for the reason).
+// Restart replaces the channel, so get it again after restarting.
+func (n *Node) Failed() <-chan struct{} {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.failed
+}
+
+// WatchStatus returns a channel that first receives the current status (From == To) and then
+// every transition. Events are dropped if the receiver falls behind. The channel is closed
+// once ctx is done.
+func (n *Node) WatchStatus(ctx context.Context) <-chan StatusEvent {
+	n.statusMu.Lock()
+	defer n.statusMu.Unlock()
+
+	events := make(chan StatusEvent, statusEventBuffer)
+	events <- StatusEvent{NodeID: n.config.NodeID, From: n.status, To: n.status, Err: n.failErr, Time: time.Now()}
+	n.statusWatchers[events] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		n.statusMu.Lock()
+		defer n.statusMu.Unlock()
+		delete(n.statusWatchers, events)
+		close(events)
+	}()
+
+	return events
+}
+
+// transition moves the node to status to if it is currently in one of from, and notifies watchers
+func (n *Node) transition(to Status, err error, from ...Status) error {
+	n.statusMu.Lock()
+	defer n.statusMu.Unlock()
+
+	current := n.status
+	allowed := false
+	for _, status := range from {
+		if current == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidTransition, current, to)
+	}
+
+	n.status = to
+	if err != nil || to == StatusStarting {
+		// A restart clears the previous failure
+		n.failErr = err
+	}
+
+	event := StatusEvent{NodeID: n.config.NodeID, From: current, To: to, Err: err, Time: time.Now()}
+	for events := range n.statusWatchers {
+		select {
+		case events <- event:
+		default:
+			// Slow watcher, it can still read the latest state from Status()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package node
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// initialRestartBackoff is how long the Manager waits before restarting a failed node
+	initialRestartBackoff = time.Second
+	// maxRestartBackoff caps the wait when a node keeps failing
+	maxRestartBackoff = 30 * time.Second
+	// restartBackoffReset is how long a node must run without failing to start over at
+	// initialRestartBackoff
+	restartBackoffReset = time.Minute
+)
+
+// SetAutoRestart turns the Manager's supervisor on or off. While it is on, a managed node that
+// fails (it can't start, or its server stops serving) is restarted with a new generation, after
+// a backoff that doubles while it keeps failing. Node.Restarts counts the restarts.
+func (m *Manager) SetAutoRestart(enabled bool) {
+	m.autoRestart.Store(enabled)
+}
+
+// AutoRestart reports whether failed nodes are restarted, see SetAutoRestart
+func (m *Manager) AutoRestart() bool {
+	return m.autoRestart.Load()
+}
+
+// restartBackoff tracks how long to wait before restarting a node that keeps failing
+type restartBackoff struct {
+	failures   int
+	lastFailed time.Time
+}
+
+// next returns the wait before restarting a node that failed at now
+func (b *restartBackoff) next(now time.Time) time.Duration {
+	if now.Sub(b.lastFailed) > restartBackoffReset {
+		b.failures = 0
+	}
+	b.lastFailed = now
+	b.failures++
+
+	backoff := initialRestartBackoff
+	for i := 1; i < b.failures && backoff < maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRestartBackoff)
+}
+
+// restartFailed restarts node after backoff, unless it was deleted, restarted or stopped by
+// then. If the restart fails, the node fails again and watch schedules the next attempt.
+func (m *Manager) restartFailed(ctx context.Context, node *Node, backoff time.Duration) {
+	node.logf("Restarting in %v", backoff)
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(backoff):
+	}
+	if !m.autoRestart.Load() || node.Status() != StatusFailed {
+		return
+	}
+	if err := node.Restart(); err != nil {
+		node.logf("Restart failed: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Topology is the set of nodes a Manager runs, as written by SaveTopology
+type Topology struct {
+	Nodes []NodeOverrides `yaml:"nodes"`
+}
+
+// Topology returns the managed nodes with the settings needed to create them again:
+// ID, port, cluster, seeds, intervals and manual gossip.
+func (m *Manager) Topology() Topology {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	topology := Topology{Nodes: make([]NodeOverrides, 0, len(m.nodes))}
+	for _, node := range m.nodes {
+		topology.Nodes = append(topology.Nodes, node.Overrides())
+	}
+	return topology
+}
+
+// Overrides returns the settings that create n again with Manager.CreateNodeWith, e.g. once
+// it was deleted: ID, port, cluster, seeds, intervals and manual gossip.
+func (n *Node) Overrides() NodeOverrides {
+	config := n.GetConfig()
+	port, _ := strconv.Atoi(config.Port)
+	return NodeOverrides{
+		NodeID:            config.NodeID,
+		Port:              port,
+		ClusterID:         config.ClusterID,
+		Seeds:             append([]string{}, config.Seeds...),
+		HeartbeatInterval: config.HeartbeatInterval,
+		GossipInterval:    config.GossipInterval,
+		ManualHeartbeat:   config.ManualHeartbeat,
+	}
+}
+
+// SaveTopology writes the managed nodes to path as YAML, so LoadTopology can recreate them
+func (m *Manager) SaveTopology(path string) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m.Topology()); err != nil {
+		return fmt.Errorf("failed to encode topology: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode topology: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write topology: %w", err)
+	}
+	return nil
+}
+
+// LoadTopology creates and starts the nodes of a file written by SaveTopology, in order and
+// with the same IDs, ports and seeds. Nodes listed without seeds get the Manager's.
+// On failure it returns the nodes that were started.
+func (m *Manager) LoadTopology(path string) ([]*Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology: %w", err)
+	}
+
+	var topology Topology
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&topology); err != nil {
+		return nil, fmt.Errorf("failed to parse topology %s: %w", path, err)
+	}
+
+	nodes := make([]*Node, 0, len(topology.Nodes))
+	for _, overrides := range topology.Nodes {
+		node, err := m.CreateNodeWith(overrides)
+		if err != nil {
+			return nodes, fmt.Errorf("failed to restore node %s: %w", overrides.NodeID, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
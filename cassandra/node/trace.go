@@ -0,0 +1,61 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// traceCapacity is how many traces of the requests it coordinated a node keeps, the oldest
+// being dropped first
+const traceCapacity = 1000
+
+// Trace returns the trace with the ID id of a KV request the node coordinated with tracing
+// on, like Cassandra's system_traces: the events of the request, from the node receiving it
+// to the replicas answering and the read repairs it triggered. The node keeps the traces of
+// its latest requests only.
+func (n *Node) Trace(id string) (transport.Trace, error) {
+	trace, ok := n.traces.Get(id)
+	if !ok {
+		return transport.Trace{}, fmt.Errorf("%w: %s", ErrTraceNotFound, id)
+	}
+	return transport.Trace{
+		ID:          trace.ID,
+		Request:     trace.Request,
+		Coordinator: trace.Coordinator,
+		Started:     trace.Started,
+		Events:      trace.Events(),
+	}, nil
+}
+
+// startTrace starts the trace of a request the node coordinates, if the client asked for one,
+// returning ctx carrying it, see storage.TraceFrom, and its ID. The node's log entries about
+// the request carry the ID too.
+func (n *Node) startTrace(ctx context.Context, traced bool, format string, args ...any) (context.Context, string) {
+	if !traced {
+		return ctx, ""
+	}
+	trace := storage.NewTrace(logger.NewTraceID(), fmt.Sprintf(format, args...), n.config.NodeID)
+	n.traces.Add(trace)
+	return logger.WithTraceID(storage.WithTrace(ctx, trace), trace.ID), trace.ID
+}
+
+// traceError records that a traced request failed with err, and names the trace in err so
+// the client can look it up
+func traceError(ctx context.Context, traceID string, err error) error {
+	if traceID == "" {
+		return err
+	}
+	storage.TraceFrom(ctx).Record("Failed: %v", err)
+	return fmt.Errorf("%w (trace %s)", err, traceID)
+}
+
+func (h *gossipHandler) GetTrace(ctx context.Context, id string) (transport.Trace, error) {
+	if err := h.checkPaused(); err != nil {
+		return transport.Trace{}, err
+	}
+	return h.node.Trace(id)
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+	"github.com/adamgarcia4/goLearning/cassandra/transport/inmem"
+)
+
+// TransportFactory creates the transport a node serves gossip on and dials peers with.
+// The handler must receive every inbound message.
+type TransportFactory func(config *Config, handler transport.GossipHandler) (transport.Transport, error)
+
+// NewGRPCTransport is the default TransportFactory, serving gossip over gRPC.
+func NewGRPCTransport(config *Config, handler transport.GossipHandler) (transport.Transport, error) {
+	return transport.NewGRPC(
+		config.GetAddress(),
+		string(config.NodeID),
+		handler,
+		transport.WithTLS(config.TLS),
+		transport.WithKeepalive(config.keepalive()),
+		transport.WithDialTimeout(config.DialTimeout),
+		transport.WithCompression(config.compression()),
+		transport.WithServerOptions(config.GRPCServerOptions...),
+		transport.WithRateLimit(config.RateLimit),
+		transport.WithMessageFilter(config.MessageFilter),
+		transport.WithFaultInjector(config.Faults),
+	)
+}
+
+// NewUDPTransport is a TransportFactory that gossips over UDP datagrams,
+// falling back to gRPC for heartbeats and payloads too large for a datagram.
+func NewUDPTransport(config *Config, handler transport.GossipHandler) (transport.Transport, error) {
+	return transport.NewUDP(
+		config.GetAddress(),
+		string(config.NodeID),
+		handler,
+		transport.WithTLS(config.TLS),
+		transport.WithKeepalive(config.keepalive()),
+		transport.WithDialTimeout(config.DialTimeout),
+		transport.WithCompression(config.compression()),
+		transport.WithServerOptions(config.GRPCServerOptions...),
+		transport.WithRateLimit(config.RateLimit),
+		transport.WithMessageFilter(config.MessageFilter),
+		transport.WithFaultInjector(config.Faults),
+	)
+}
+
+// Transport names accepted by TransportByName
+const (
+	TransportGRPC = "grpc"
+	TransportUDP  = "udp"
+)
+
+// TransportByName returns the TransportFactory for a transport name, as given on the command line.
+func TransportByName(name string) (TransportFactory, error) {
+	switch name {
+	case "", TransportGRPC:
+		return NewGRPCTransport, nil
+	case TransportUDP:
+		return NewUDPTransport, nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q (expected %s or %s)", name, TransportGRPC, TransportUDP)
+	}
+}
+
+// InMemoryTransport returns a TransportFactory that attaches nodes to network,
+// so many nodes can gossip inside one process without opening sockets.
+func InMemoryTransport(network *inmem.Network) TransportFactory {
+	return func(config *Config, handler transport.GossipHandler) (transport.Transport, error) {
+		t, err := network.NewTransport(config.GetAddress(), handler)
+		if err != nil {
+			return nil, err
+		}
+		t.SetMessageFilter(config.MessageFilter)
+		t.SetFaultInjector(config.Faults)
+		return t, nil
+	}
+}
+
+// rateLimited is implemented by transports that rate limit inbound gossip (transport.GRPC)
+type rateLimited interface {
+	RateLimiter() *transport.RateLimiter
+}
+
+// RateLimitStats returns how many inbound gossip messages were allowed and rejected.
+// ok is false if the node's transport doesn't rate limit.
+func (n *Node) RateLimitStats() (stats transport.RateLimitStats, ok bool) {
+	n.mu.RLock()
+	nodeTransport := n.transport
+	n.mu.RUnlock()
+
+	limited, ok := nodeTransport.(rateLimited)
+	if !ok || limited.RateLimiter() == nil {
+		return transport.RateLimitStats{}, false
+	}
+	return limited.RateLimiter().Stats(), true
+}
+
+// setServing updates the health status reported by transports that serve grpc.health.v1
+func (n *Node) setServing(serving bool) {
+	n.mu.RLock()
+	nodeTransport := n.transport
+	n.mu.RUnlock()
+
+	if reporter, ok := nodeTransport.(transport.HealthReporter); ok {
+		reporter.SetServing(serving)
+	}
+}
@@ -0,0 +1,287 @@
+// Package ring is the consistent-hash token ring keys are spread over, like Cassandra's
+// TokenMetadata. Every node picks tokens, positions on a ring spanning the int64 range, and
+// gossips them as its TOKENS application state. A key belongs to the node with the first
+// token at or after the key's own token, wrapping around past the highest one, so a node
+// joining or leaving only moves the keys next to its tokens.
+//
+// Every node rebuilds the ring from its gossip state (see FromEndpoints): nodes that agree
+// on the membership agree on every key's owner without coordinating.
+//
+// Nodes pick many random tokens (vnodes, Cassandra's num_tokens), so each owns many small
+// ranges scattered around the ring: the keys are spread evenly, and a node joining or
+// leaving moves a little of every other node's keys rather than half of one neighbour's.
+package ring
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Ring maps tokens to the nodes that own them. It is immutable, rebuild it when the
+// membership changes.
+type Ring struct {
+	tokens []Token // sorted
+	owners map[Token]gossip.NodeID
+}
+
+// New builds the ring of the given tokens of every node. A token claimed by several nodes
+// goes to the lowest node ID, so every node resolves the clash the same way.
+func New(tokensByNode map[gossip.NodeID][]Token) *Ring {
+	r := &Ring{owners: make(map[Token]gossip.NodeID)}
+	for nodeID, tokens := range tokensByNode {
+		for _, token := range tokens {
+			owner, claimed := r.owners[token]
+			if !claimed {
+				r.tokens = append(r.tokens, token)
+			}
+			if !claimed || nodeID < owner {
+				r.owners[token] = nodeID
+			}
+		}
+	}
+	sortTokens(r.tokens)
+	return r
+}
+
+// FromEndpoints builds the ring of the endpoints that gossip their tokens. Endpoints whose
+// TOKENS can't be parsed are left out.
+func FromEndpoints(endpoints []gossip.EndpointInfo) *Ring {
+	return New(EndpointTokens(endpoints))
+}
+
+// EndpointTokens returns the tokens every endpoint gossips, see FromEndpoints
+func EndpointTokens(endpoints []gossip.EndpointInfo) map[gossip.NodeID][]Token {
+	tokensByNode := make(map[gossip.NodeID][]Token, len(endpoints))
+	for _, endpoint := range endpoints {
+		state, ok := endpoint.State.ApplicationStates[gossip.AppTokens]
+		if !ok {
+			continue
+		}
+		tokens, err := ParseTokens(state.Value)
+		if err != nil || len(tokens) == 0 {
+			continue
+		}
+		tokensByNode[endpoint.State.HeartbeatState.NodeID] = tokens
+	}
+	return tokensByNode
+}
+
+// Members returns the endpoints keys are spread over: those the failure detector considers
+// UP whose STATUS is NORMAL, or not gossiped yet. A node leaving the cluster hands its
+// ranges over as soon as it announces it.
+func Members(endpoints []gossip.EndpointInfo) []gossip.EndpointInfo {
+	var members []gossip.EndpointInfo
+	for _, endpoint := range endpoints {
+		status := endpoint.State.ApplicationStates[gossip.AppStatus].Value
+		if endpoint.Alive && (status == "" || status == gossip.StatusNormal) {
+			members = append(members, endpoint)
+		}
+	}
+	return members
+}
+
+// Bootstrapping returns the endpoints the failure detector considers UP whose STATUS is BOOT:
+// joining the ring, they stream the ranges they take over before they are Members
+func Bootstrapping(endpoints []gossip.EndpointInfo) []gossip.EndpointInfo {
+	var joining []gossip.EndpointInfo
+	for _, endpoint := range endpoints {
+		if endpoint.Alive && endpoint.State.ApplicationStates[gossip.AppStatus].Value == gossip.StatusBoot {
+			joining = append(joining, endpoint)
+		}
+	}
+	return joining
+}
+
+// Owner returns the node that owns key, false if the ring is empty
+func (r *Ring) Owner(key string) (gossip.NodeID, bool) {
+	return r.TokenOwner(KeyToken(key))
+}
+
+// TokenOwner returns the node owning the first token at or after token, false if the ring
+// is empty
+func (r *Ring) TokenOwner(token Token) (gossip.NodeID, bool) {
+	if len(r.tokens) == 0 {
+		return "", false
+	}
+	return r.owners[r.tokens[r.search(token)]], true
+}
+
+// search returns the index of the first token at or after token. Caller must check the ring
+// isn't empty.
+func (r *Ring) search(token Token) int {
+	i := sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i] >= token })
+	if i == len(r.tokens) {
+		// Past the highest token, the ring wraps around to the lowest
+		i = 0
+	}
+	return i
+}
+
+// Replicas returns the n nodes holding copies of key, like Cassandra's SimpleStrategy: its
+// owner, then the owners of the next tokens around the ring, skipping nodes already picked.
+// Fewer if the ring has fewer than n nodes.
+func (r *Ring) Replicas(key string, n int) []gossip.NodeID {
+	return r.TokenReplicas(KeyToken(key), n)
+}
+
+// TokenReplicas returns the n nodes holding copies of the keys whose token is token, see
+// Replicas
+func (r *Ring) TokenReplicas(token Token, n int) []gossip.NodeID {
+	if len(r.tokens) == 0 {
+		return nil
+	}
+	start := r.search(token)
+	var replicas []gossip.NodeID
+	for i := 0; i < len(r.tokens) && len(replicas) < n; i++ {
+		owner := r.owners[r.tokens[(start+i)%len(r.tokens)]]
+		if !slices.Contains(replicas, owner) {
+			replicas = append(replicas, owner)
+		}
+	}
+	return replicas
+}
+
+// Tokens returns every token on the ring, sorted
+func (r *Ring) Tokens() []Token {
+	return slices.Clone(r.tokens)
+}
+
+// Nodes returns the nodes on the ring, sorted by ID
+func (r *Ring) Nodes() []gossip.NodeID {
+	var nodes []gossip.NodeID
+	for _, owner := range r.owners {
+		if !slices.Contains(nodes, owner) {
+			nodes = append(nodes, owner)
+		}
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// Range is the part of the ring a token owns: the tokens after Start, up to and including
+// End. The first range wraps around, starting at the highest token on the ring.
+type Range struct {
+	Start Token
+	End   Token
+	Owner gossip.NodeID
+}
+
+// Size returns how much of the ring the range covers, between 0 and 1. A ring of a single
+// token is one range covering all of it.
+func (r Range) Size() float64 {
+	if r.Start == r.End {
+		return 1
+	}
+	// Wraps around the int64 range as unsigned arithmetic, as the ring does
+	return float64(uint64(r.End)-uint64(r.Start)) / (1 << 64)
+}
+
+// Contains reports whether token is in the range
+func (r Range) Contains(token Token) bool {
+	if r.Start < r.End {
+		return r.Start < token && token <= r.End
+	}
+	// Wraps around, or covers the whole ring
+	return token > r.Start || token <= r.End
+}
+
+// Ranges returns the range of every token on the ring, in ring order
+func (r *Ring) Ranges() []Range {
+	ranges := make([]Range, len(r.tokens))
+	for i, token := range r.tokens {
+		start := r.tokens[(i+len(r.tokens)-1)%len(r.tokens)]
+		ranges[i] = Range{Start: start, End: token, Owner: r.owners[token]}
+	}
+	return ranges
+}
+
+// Ownership returns how much of the ring each node owns, between 0 and 1
+func (r *Ring) Ownership() map[gossip.NodeID]float64 {
+	owns := make(map[gossip.NodeID]float64)
+	for _, rng := range r.Ranges() {
+		owns[rng.Owner] += rng.Size()
+	}
+	return owns
+}
+
+// Transfer is a range a node joining the ring becomes a replica of, and the nodes holding it
+// before the node joins
+type Transfer struct {
+	Range   Range
+	Sources []gossip.NodeID // the range's replicas, those the joining node replaces first
+}
+
+// Joining returns the ranges node becomes a replica of, as strategy places them, by joining
+// the ring with tokens, like Cassandra's pending ranges: they are streamed to it from their
+// current replicas before it joins. Ranges nobody holds yet, on an empty ring, are left out.
+func (r *Ring) Joining(node gossip.NodeID, tokens []Token, strategy Strategy) []Transfer {
+	joined := r.join(node, tokens)
+	var transfers []Transfer
+	for _, rng := range joined.Ranges() {
+		// No token is strictly inside the range, on either ring: all of it has the same
+		// replicas before and after
+		after := strategy.Replicas(joined, rng.End)
+		before := strategy.Replicas(r, rng.End)
+		if !slices.Contains(after, node) || len(before) == 0 {
+			continue
+		}
+		sources := slices.DeleteFunc(slices.Clone(before), func(id gossip.NodeID) bool {
+			return slices.Contains(after, id)
+		})
+		for _, id := range before {
+			if !slices.Contains(sources, id) {
+				sources = append(sources, id)
+			}
+		}
+		transfers = append(transfers, Transfer{Range: rng, Sources: sources})
+	}
+	return transfers
+}
+
+// PendingReplicas returns the nodes of joining, each joining the ring with its tokens, that
+// become replicas of token as strategy places them, like Cassandra's pending endpoints: writes
+// reach them as well as the current replicas while they bootstrap, so the keys they stream
+// aren't missing the ones written meanwhile. Sorted by node ID.
+func (r *Ring) PendingReplicas(token Token, joining map[gossip.NodeID][]Token, strategy Strategy) []gossip.NodeID {
+	var pending []gossip.NodeID
+	for node, tokens := range joining {
+		if slices.Contains(strategy.Replicas(r.join(node, tokens), token), node) {
+			pending = append(pending, node)
+		}
+	}
+	slices.Sort(pending)
+	return pending
+}
+
+// join returns the ring with node joined with tokens
+func (r *Ring) join(node gossip.NodeID, tokens []Token) *Ring {
+	owners := make(map[Token]gossip.NodeID, len(r.owners)+len(tokens))
+	for token, owner := range r.owners {
+		owners[token] = owner
+	}
+	joined := &Ring{tokens: slices.Clone(r.tokens), owners: owners}
+	for _, token := range tokens {
+		// Resolve clashes like New
+		owner, claimed := owners[token]
+		if !claimed {
+			joined.tokens = append(joined.tokens, token)
+		}
+		if !claimed || node < owner {
+			owners[token] = node
+		}
+	}
+	sortTokens(joined.tokens)
+	return joined
+}
+
+// Len returns how many tokens are on the ring
+func (r *Ring) Len() int {
+	return len(r.tokens)
+}
+
+// sortTokens sorts tokens in ring order
+func sortTokens(tokens []Token) {
+	slices.Sort(tokens)
+}
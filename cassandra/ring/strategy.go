@@ -0,0 +1,131 @@
+package ring
+
+import (
+	"slices"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/snitch"
+)
+
+// Names of the replication strategies, as configured
+const (
+	SimpleStrategyName          = "SimpleStrategy"
+	NetworkTopologyStrategyName = "NetworkTopologyStrategy"
+)
+
+// Strategy decides which nodes hold the copies of a key, like Cassandra's replication
+// strategies. Every node must use the same.
+type Strategy interface {
+	// Replicas returns the nodes of r holding copies of the keys whose token is token, the
+	// first replica first
+	Replicas(r *Ring, token Token) []gossip.NodeID
+	// TotalReplicas returns how many copies every key has, on a ring large enough
+	TotalReplicas() int
+}
+
+var (
+	_ Strategy = SimpleStrategy{}
+	_ Strategy = NetworkTopologyStrategy{}
+)
+
+// SimpleStrategy puts the copies of a key on its owner and the owners of the next tokens,
+// wherever they are, see Ring.TokenReplicas
+type SimpleStrategy struct {
+	ReplicationFactor int
+}
+
+func (s SimpleStrategy) Replicas(r *Ring, token Token) []gossip.NodeID {
+	return r.TokenReplicas(token, s.ReplicationFactor)
+}
+
+func (s SimpleStrategy) TotalReplicas() int {
+	return s.ReplicationFactor
+}
+
+// NetworkTopologyStrategy puts a number of copies of every key in each datacenter, like
+// Cassandra's: walking the ring from the key's token, it takes the first node of every rack
+// of the datacenter, then, once every rack has a copy, the nodes it skipped for being in a
+// rack that had one, in ring order. A datacenter with fewer nodes than its number of copies
+// gets one on each.
+type NetworkTopologyStrategy struct {
+	ReplicationFactors map[string]int // copies in each datacenter, none in the others
+	Locate             func(gossip.NodeID) snitch.Location
+}
+
+func (s NetworkTopologyStrategy) Replicas(r *Ring, token Token) []gossip.NodeID {
+	if len(r.tokens) == 0 {
+		return nil
+	}
+
+	// Where every node is, and how many replicas each datacenter can get
+	locations := make(map[gossip.NodeID]snitch.Location)
+	nodesInDC := make(map[string]int)
+	racksInDC := make(map[string]map[string]bool)
+	for _, node := range r.Nodes() {
+		location := s.Locate(node)
+		locations[node] = location
+		nodesInDC[location.DC]++
+		if racksInDC[location.DC] == nil {
+			racksInDC[location.DC] = make(map[string]bool)
+		}
+		racksInDC[location.DC][location.Rack] = true
+	}
+	want := make(map[string]int)
+	total := 0
+	for dc, factor := range s.ReplicationFactors {
+		want[dc] = min(factor, nodesInDC[dc])
+		total += want[dc]
+	}
+
+	var replicas []gossip.NodeID
+	picked := make(map[string]int)
+	racksSeen := make(map[string]map[string]bool)
+	skipped := make(map[string][]gossip.NodeID)
+	add := func(node gossip.NodeID, dc string) {
+		replicas = append(replicas, node)
+		picked[dc]++
+	}
+
+	start := r.search(token)
+	for i := 0; i < len(r.tokens) && len(replicas) < total; i++ {
+		node := r.owners[r.tokens[(start+i)%len(r.tokens)]]
+		location := locations[node]
+		dc := location.DC
+		if picked[dc] == want[dc] || slices.Contains(replicas, node) || slices.Contains(skipped[dc], node) {
+			continue
+		}
+		if racksSeen[dc] == nil {
+			racksSeen[dc] = make(map[string]bool)
+		}
+
+		switch {
+		case len(racksSeen[dc]) == len(racksInDC[dc]):
+			// Every rack has a copy already
+			add(node, dc)
+		case !racksSeen[dc][location.Rack]:
+			add(node, dc)
+			racksSeen[dc][location.Rack] = true
+			if len(racksSeen[dc]) == len(racksInDC[dc]) {
+				// The nodes skipped while looking for the other racks come next
+				for _, skippedNode := range skipped[dc] {
+					if picked[dc] == want[dc] {
+						break
+					}
+					add(skippedNode, dc)
+				}
+				skipped[dc] = nil
+			}
+		default:
+			skipped[dc] = append(skipped[dc], node)
+		}
+	}
+	return replicas
+}
+
+func (s NetworkTopologyStrategy) TotalReplicas() int {
+	total := 0
+	for _, factor := range s.ReplicationFactors {
+		total += factor
+	}
+	return total
+}
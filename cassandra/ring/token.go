@@ -0,0 +1,73 @@
+package ring
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Token is a position on the ring. Tokens span the whole int64 range, like the tokens of
+// Cassandra's Murmur3Partitioner.
+type Token int64
+
+// KeyToken returns the position of key on the ring
+func KeyToken(key string) Token {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return Token(mix(h.Sum64()))
+}
+
+// mix spreads the bits of an FNV hash, whose high bits change little between keys that only
+// differ at the end (splitmix64's finalizer)
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// RandomTokens returns n distinct random tokens, sorted, for a node joining the ring
+func RandomTokens(n int) []Token {
+	seen := make(map[Token]bool, n)
+	tokens := make([]Token, 0, n)
+	for len(tokens) < n {
+		token := Token(rand.Uint64()) // uniform over the whole ring
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	sortTokens(tokens)
+	return tokens
+}
+
+// FormatTokens renders tokens as the value of the TOKENS application state, e.g.
+// "-3074457345618258603,3074457345618258602"
+func FormatTokens(tokens []Token) string {
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		parts[i] = strconv.FormatInt(int64(token), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseTokens parses the value of a TOKENS application state, see FormatTokens
+func ParseTokens(value string) ([]Token, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	tokens := make([]Token, len(parts))
+	for i, part := range parts {
+		token, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q: %w", part, err)
+		}
+		tokens[i] = Token(token)
+	}
+	return tokens, nil
+}
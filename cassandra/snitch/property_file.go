@@ -0,0 +1,93 @@
+package snitch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// defaultKey is the line of a topology file giving the location of the nodes it doesn't list
+const defaultKey = "default"
+
+// PropertyFile reads where every node is from a topology file shared by every node, like
+// Cassandra's PropertyFileSnitch and its cassandra-topology.properties. Each line maps a node,
+// by ID or by the address it gossips, to a datacenter and rack:
+//
+//	# node=DC:RACK
+//	node-1=dc1:rack1
+//	127.0.0.1:50052=dc1:rack2
+//	default=dc2:rack1
+//
+// Nodes the file doesn't list are in the default location, or DefaultDC and DefaultRack
+// without one. The locations nodes gossip aren't used.
+type PropertyFile struct {
+	local     Location
+	locations map[string]Location // by node ID or address
+	fallback  Location
+}
+
+// LoadPropertyFile reads the topology file at path, for the node self that gossips addr
+func LoadPropertyFile(path string, self gossip.NodeID, addr string) (*PropertyFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%s needs a topology file", PropertyFileName)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %w", err)
+	}
+
+	s := &PropertyFile{
+		locations: make(map[string]Location),
+		fallback:  Location{DC: DefaultDC, Rack: DefaultRack},
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		dc, rack, hasRack := strings.Cut(strings.TrimSpace(value), ":")
+		key, dc, rack = strings.TrimSpace(key), strings.TrimSpace(dc), strings.TrimSpace(rack)
+		if !ok || !hasRack || key == "" || dc == "" || rack == "" {
+			return nil, fmt.Errorf("invalid topology file %s, line %d: want node=DC:RACK", path, lineNumber)
+		}
+		if key == defaultKey {
+			s.fallback = Location{DC: dc, Rack: rack}
+		} else {
+			s.locations[key] = Location{DC: dc, Rack: rack}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %w", err)
+	}
+	s.local = s.lookup(self, addr)
+	return s, nil
+}
+
+func (*PropertyFile) Name() string {
+	return PropertyFileName
+}
+
+func (s *PropertyFile) Local() Location {
+	return s.local
+}
+
+func (s *PropertyFile) Locate(node gossip.NodeID, states map[gossip.AppStateKey]gossip.AppState) Location {
+	return s.lookup(node, states[gossip.AppHeartbeat].Value)
+}
+
+// lookup returns the location of the node, by ID then by address
+func (s *PropertyFile) lookup(node gossip.NodeID, addr string) Location {
+	if location, ok := s.locations[string(node)]; ok {
+		return location
+	}
+	if location, ok := s.locations[addr]; ok && addr != "" {
+		return location
+	}
+	return s.fallback
+}
@@ -0,0 +1,113 @@
+// Package snitch tells which datacenter and rack every node is in, like Cassandra's endpoint
+// snitches. Replication strategies use it to spread the replicas of a key over racks and
+// datacenters (see ring.NetworkTopologyStrategy), so that losing a rack, or a datacenter,
+// doesn't lose every copy of a key.
+//
+// Every node gossips its own datacenter and rack as its DC and RACK application states,
+// whatever its snitch. Nodes must agree on where every node is, so they must all use the same
+// snitch, and the same topology file for a PropertyFile snitch.
+package snitch
+
+import (
+	"fmt"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Names of the snitches, as configured
+const (
+	SimpleName                = "SimpleSnitch"
+	PropertyFileName          = "PropertyFileSnitch"
+	GossipingPropertyFileName = "GossipingPropertyFileSnitch"
+)
+
+// Where nodes are when nothing says otherwise, Cassandra's defaults
+const (
+	DefaultDC   = "datacenter1"
+	DefaultRack = "rack1"
+)
+
+// Location is the datacenter and rack of a node
+type Location struct {
+	DC   string
+	Rack string
+}
+
+func (l Location) String() string {
+	return l.DC + ":" + l.Rack
+}
+
+// Snitch tells where nodes are
+type Snitch interface {
+	// Name returns the snitch's name, e.g. SimpleName
+	Name() string
+	// Local returns the location of the node the snitch runs on
+	Local() Location
+	// Locate returns the location of another node, given the application states it gossips
+	Locate(node gossip.NodeID, states map[gossip.AppStateKey]gossip.AppState) Location
+}
+
+var (
+	_ Snitch = Simple{}
+	_ Snitch = (*PropertyFile)(nil)
+	_ Snitch = GossipingPropertyFile{}
+)
+
+// Simple puts every node in the same datacenter and rack, like Cassandra's SimpleSnitch. It
+// suits clusters in a single datacenter, with SimpleStrategy.
+type Simple struct{}
+
+func (Simple) Name() string {
+	return SimpleName
+}
+
+func (Simple) Local() Location {
+	return Location{DC: DefaultDC, Rack: DefaultRack}
+}
+
+func (Simple) Locate(gossip.NodeID, map[gossip.AppStateKey]gossip.AppState) Location {
+	return Location{DC: DefaultDC, Rack: DefaultRack}
+}
+
+// GossipingPropertyFile is configured with the location of its own node, like Cassandra's
+// GossipingPropertyFileSnitch and its cassandra-rackdc.properties, and learns where the other
+// nodes are from what they gossip. Nodes that gossip no location are in DefaultDC and
+// DefaultRack.
+type GossipingPropertyFile struct {
+	Location Location
+}
+
+func (GossipingPropertyFile) Name() string {
+	return GossipingPropertyFileName
+}
+
+func (s GossipingPropertyFile) Local() Location {
+	return s.Location
+}
+
+func (GossipingPropertyFile) Locate(_ gossip.NodeID, states map[gossip.AppStateKey]gossip.AppState) Location {
+	location := Location{DC: DefaultDC, Rack: DefaultRack}
+	if dc, ok := states[gossip.AppDC]; ok && dc.Value != "" {
+		location.DC = dc.Value
+	}
+	if rack, ok := states[gossip.AppRack]; ok && rack.Value != "" {
+		location.Rack = rack.Value
+	}
+	return location
+}
+
+// New returns the snitch called name. local is the location of a GossipingPropertyFile
+// snitch's node, and topologyFile the file a PropertyFile snitch reads, see LoadPropertyFile.
+func New(name string, local Location, topologyFile string, self gossip.NodeID, addr string) (Snitch, error) {
+	switch name {
+	case "", SimpleName:
+		return Simple{}, nil
+	case GossipingPropertyFileName:
+		return GossipingPropertyFile{Location: local}, nil
+	case PropertyFileName:
+		return LoadPropertyFile(topologyFile, self, addr)
+	default:
+		return nil, fmt.Errorf("unknown snitch %q, want %s, %s or %s",
+			name, SimpleName, PropertyFileName, GossipingPropertyFileName)
+	}
+}
@@ -0,0 +1,362 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncPolicy is when the commit log is synced to disk, like Cassandra's commitlog_sync.
+// Writes reach the file before they are acknowledged either way, so they survive the
+// process crashing; the policy decides what an OS crash or power loss can lose.
+type SyncPolicy string
+
+const (
+	// SyncPeriodic syncs every sync period: a crash of the machine loses at most the writes
+	// of the last period
+	SyncPeriodic SyncPolicy = "periodic"
+	// SyncBatch syncs before acknowledging every write, which waits for the disk
+	SyncBatch SyncPolicy = "batch"
+)
+
+// Validate checks the policy is known, and has a period if it is periodic
+func (p SyncPolicy) Validate(period time.Duration) error {
+	switch p {
+	case SyncBatch:
+		return nil
+	case SyncPeriodic:
+		if period <= 0 {
+			return errors.New("periodic commit log sync needs a period greater than 0")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown commit log sync %q, must be periodic or batch", p)
+	}
+}
+
+// ReplayStats is what a Store replayed from its commit log when it was opened
+type ReplayStats struct {
+	Segments int   // commit log files read
+	Entries  int   // writes replayed
+	Skipped  int64 // bytes dropped from the end of segments, torn by a crash mid-write
+}
+
+const (
+	segmentPrefix = "commitlog-"
+	segmentSuffix = ".log"
+
+	frameHeaderSize = 8 // payload length and CRC, both uint32
+
+	opPut    byte = 1
+	opDelete byte = 2
+	opPutTTL byte = 3 // a put with a TTL, after the timestamp
+)
+
+// crcTable is CRC-32C, which CPUs compute in hardware
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// commitLog is an append-only log of the writes a Store applies, in segment files named
+// commitlog-000001.log, ... Every entry is a frame: the length of its payload, the CRC of
+// the payload, and the payload, so a write torn by a crash is detected and dropped on
+// replay. Each time the log is opened it starts a new segment after the replayed ones, and
+// a flush starts one too, see rotate.
+type commitLog struct {
+	mu     sync.Mutex
+	dir    string
+	seq    int // of the segment appended to
+	file   *os.File
+	policy SyncPolicy
+	dirty  bool // written since the last sync
+	closed bool
+
+	stop chan struct{} // closed by close, stops the periodic sync
+	done chan struct{} // closed when the periodic sync has stopped
+}
+
+// openCommitLog replays the segments in dir, oldest first, and opens a new one to append to
+func openCommitLog(dir string, policy SyncPolicy, period time.Duration, replay func(entry)) (*commitLog, ReplayStats, error) {
+	var stats ReplayStats
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, stats, fmt.Errorf("failed to create commit log directory: %w", err)
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	var last int
+	for _, seq := range segments {
+		path := filepath.Join(dir, segmentName(seq))
+		entries, skipped, err := replaySegment(path, replay)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to replay %s: %w", path, err)
+		}
+		stats.Segments++
+		stats.Entries += entries
+		stats.Skipped += skipped
+		last = seq
+	}
+
+	file, err := createSegment(dir, last+1)
+	if err != nil {
+		return nil, stats, err
+	}
+	l := &commitLog{dir: dir, seq: last + 1, file: file, policy: policy, stop: make(chan struct{}), done: make(chan struct{})}
+	if policy == SyncPeriodic {
+		go l.syncEvery(period)
+	} else {
+		close(l.done)
+	}
+	return l, stats, nil
+}
+
+// listSegments returns the sequence numbers of the segments in dir, sorted
+func listSegments(dir string) ([]int, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit log segments: %w", err)
+	}
+	var segments []int
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		var seq int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, segmentPrefix), "%d"+segmentSuffix, &seq); err == nil {
+			segments = append(segments, seq)
+		}
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+func segmentName(seq int) string {
+	return fmt.Sprintf("%s%06d%s", segmentPrefix, seq, segmentSuffix)
+}
+
+func createSegment(dir string, seq int) (*os.File, error) {
+	file, err := os.OpenFile(filepath.Join(dir, segmentName(seq)), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create commit log segment: %w", err)
+	}
+	return file, nil
+}
+
+// replaySegment passes every intact entry of the segment at path to replay. Reading stops
+// at the first torn or corrupt frame, whose bytes and the rest are reported as skipped.
+func replaySegment(path string, replay func(entry)) (entries int, skipped int64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r := bufio.NewReader(file)
+	var offset int64
+	header := make([]byte, frameHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return entries, 0, nil
+			}
+			break // torn header
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if int64(length) > info.Size()-offset-frameHeaderSize {
+			break // torn payload, or a corrupt length
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+			break
+		}
+		entry, err := decodeEntry(payload)
+		if err != nil {
+			break
+		}
+		replay(entry)
+		entries++
+		offset += frameHeaderSize + int64(length)
+	}
+	return entries, info.Size() - offset, nil
+}
+
+// append writes e to the log, and syncs it first with SyncBatch
+func (l *commitLog) append(e entry) error {
+	payload := encodeEntry(e)
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(payload, crcTable))
+	frame = append(frame, payload...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	if _, err := l.file.Write(frame); err != nil {
+		return err
+	}
+	if l.policy == SyncBatch {
+		return l.file.Sync()
+	}
+	l.dirty = true
+	return nil
+}
+
+// rotate syncs and closes the segment appended to and starts the next one. It returns the
+// sequence of the closed segment: the writes appended before rotate are in it or older ones.
+func (l *commitLog) rotate() (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return 0, ErrClosed
+	}
+	file, err := createSegment(l.dir, l.seq+1)
+	if err != nil {
+		return 0, err
+	}
+	err = errors.Join(l.file.Sync(), l.file.Close())
+	l.file = file
+	l.seq++
+	l.dirty = false
+	return l.seq - 1, err
+}
+
+// discard removes the segments up to seq, once their writes are all in SSTables
+func (l *commitLog) discard(seq int) error {
+	segments, err := listSegments(l.dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, s := range segments {
+		if s <= seq {
+			errs = append(errs, os.Remove(filepath.Join(l.dir, segmentName(s))))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncEvery syncs the log every period until it is closed
+func (l *commitLog) syncEvery(period time.Duration) {
+	defer close(l.done)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			if l.dirty && !l.closed {
+				l.file.Sync()
+				l.dirty = false
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// close syncs and closes the log. Appending to it afterwards fails with ErrClosed.
+func (l *commitLog) close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.stop)
+	err := errors.Join(l.file.Sync(), l.file.Close())
+	l.mu.Unlock()
+	<-l.done
+	return err
+}
+
+// encodeEntry encodes e as op, timestamp, TTL in microseconds for opPutTTL, key and value,
+// lengths as uvarints. SSTables store their entries the same way.
+func encodeEntry(e entry) []byte {
+	op := opPut
+	switch {
+	case e.cell.Deleted:
+		op = opDelete
+	case e.cell.TTL > 0:
+		op = opPutTTL
+	}
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64*4+len(e.key)+len(e.cell.Value))
+	buf = append(buf, op)
+	buf = binary.AppendVarint(buf, e.cell.Timestamp)
+	if op == opPutTTL {
+		buf = binary.AppendUvarint(buf, uint64(e.cell.TTL.Microseconds()))
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(e.key)))
+	buf = append(buf, e.key...)
+	buf = binary.AppendUvarint(buf, uint64(len(e.cell.Value)))
+	return append(buf, e.cell.Value...)
+}
+
+var errCorruptEntry = errors.New("corrupt entry")
+
+func decodeEntry(buf []byte) (entry, error) {
+	if len(buf) == 0 || buf[0] < opPut || buf[0] > opPutTTL {
+		return entry{}, errCorruptEntry
+	}
+	op := buf[0]
+	e := entry{cell: Cell{Deleted: op == opDelete}}
+	buf = buf[1:]
+
+	timestamp, n := binary.Varint(buf)
+	if n <= 0 {
+		return entry{}, errCorruptEntry
+	}
+	e.cell.Timestamp = timestamp
+	buf = buf[n:]
+	if op == opPutTTL {
+		ttl, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return entry{}, errCorruptEntry
+		}
+		e.cell.TTL = time.Duration(ttl) * time.Microsecond
+		buf = buf[n:]
+	}
+
+	key, buf, ok := readBytes(buf)
+	if !ok {
+		return entry{}, errCorruptEntry
+	}
+	e.key = string(key)
+	value, buf, ok := readBytes(buf)
+	if !ok || len(buf) != 0 {
+		return entry{}, errCorruptEntry
+	}
+	if !e.cell.Deleted {
+		e.cell.Value = value
+	}
+	return e, nil
+}
+
+// readBytes reads a uvarint length and that many bytes from buf
+func readBytes(buf []byte) ([]byte, []byte, bool) {
+	length, n := binary.Uvarint(buf)
+	if n <= 0 || length > uint64(len(buf)-n) {
+		return nil, nil, false
+	}
+	buf = buf[n:]
+	return buf[:length], buf[length:], true
+}
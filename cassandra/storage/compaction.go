@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"cmp"
+	"errors"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultCompactionThreshold is how many SSTables of similar size are merged by default,
+	// Cassandra's min_threshold
+	DefaultCompactionThreshold = 4
+	// maxCompaction is the most SSTables merged at once, Cassandra's max_threshold
+	maxCompaction = 32
+)
+
+// compact merges SSTables of similar size while there are enough of them, like Cassandra's
+// size-tiered compaction: flushes write tables of about the same size, which are merged into
+// one about as large as all of them, which is merged with the tables as large, and so on. A
+// key is then in a few tables, and the versions its writes replaced are dropped, as are
+// tombstones older than gc_grace, see purgeable.
+func (s *Store) compact() error {
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		default:
+		}
+
+		s.mu.RLock()
+		bucket := pickBucket(s.tables, s.compactionThreshold)
+		s.mu.RUnlock()
+		if bucket == nil {
+			return nil
+		}
+		if err := s.compactTables(bucket); err != nil {
+			return err
+		}
+	}
+}
+
+// pickBucket groups tables in buckets of tables within half and one and a half times the
+// average size of the bucket, and returns the bucket of the smallest tables of those with at
+// least threshold of them, nil if none has
+func pickBucket(tables []*sstable, threshold int) []*sstable {
+	bySize := slices.SortedFunc(slices.Values(tables), func(a, b *sstable) int {
+		return cmp.Compare(a.size, b.size)
+	})
+
+	var buckets [][]*sstable
+	var totals []int64
+	for _, t := range bySize {
+		placed := false
+		for i, bucket := range buckets {
+			average := totals[i] / int64(len(bucket))
+			if t.size >= average/2 && t.size <= average*3/2 {
+				buckets[i] = append(bucket, t)
+				totals[i] += t.size
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			buckets = append(buckets, []*sstable{t})
+			totals = append(totals, t.size)
+		}
+	}
+
+	for _, bucket := range buckets {
+		if len(bucket) >= threshold {
+			return bucket[:min(len(bucket), maxCompaction)]
+		}
+	}
+	return nil
+}
+
+// compactTables merges tables into a new one, which replaces them
+func (s *Store) compactTables(tables []*sstable) error {
+	gen := s.nextGen
+	s.nextGen++
+	w, err := createSSTable(s.sstablePath(gen))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	purge := func(e entry) bool {
+		return e.cell.purgeable(now, s.gcGrace) && !s.heldOutside(e.key, tables)
+	}
+	dropped, purged, err := mergeTables(tables, purge, w.add)
+	if err != nil {
+		return w.fail(err)
+	}
+	if err := w.finish(); err != nil {
+		return err
+	}
+	merged, err := openSSTable(s.sstablePath(gen), gen)
+	if err != nil {
+		return err
+	}
+
+	var before int64
+	s.mu.Lock()
+	s.tables = slices.DeleteFunc(s.tables, func(t *sstable) bool {
+		return slices.Contains(tables, t)
+	})
+	s.tables = append(s.tables, merged)
+	s.mu.Unlock()
+
+	// Reads hold the lock while they read tables: none started before it is still reading the
+	// old ones, and those after read the merged one
+	var errs []error
+	for _, t := range tables {
+		before += t.size
+		errs = append(errs, t.close(), os.Remove(t.path))
+	}
+	s.compactions.Add(1)
+	s.log.Printf("Compacted %d SSTables (%d bytes) into %s (%d bytes), %d versions dropped, %d tombstones purged",
+		len(tables), before, sstableName(gen), merged.size, dropped, purged)
+	return errors.Join(errs...)
+}
+
+// heldOutside reports whether a memtable, or an SSTable other than tables, holds a version of
+// key. A tombstone can only be purged if none does: the versions it hides would come back.
+func (s *Store) heldOutside(key string, tables []*sstable) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, m := range s.memtables() {
+		if _, ok := m.get(key); ok {
+			return true
+		}
+	}
+	for _, t := range s.tables {
+		if !slices.Contains(tables, t) && t.has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTables passes add the newest version of every key of tables, in key order, unless
+// purge drops it. It returns how many older versions it dropped, and how many it purged.
+func mergeTables(tables []*sstable, purge func(entry) bool, add func(entry) error) (dropped, purged int, err error) {
+	next := make([]int, len(tables)) // the entry of each table to merge next
+	for {
+		key, found := "", false
+		for i, t := range tables {
+			if next[i] < len(t.keys) && (!found || t.keys[next[i]] < key) {
+				key, found = t.keys[next[i]], true
+			}
+		}
+		if !found {
+			return dropped, purged, nil
+		}
+
+		var newest entry
+		versions := 0
+		for i, t := range tables {
+			if next[i] == len(t.keys) || t.keys[next[i]] != key {
+				continue
+			}
+			e, err := t.entry(next[i])
+			if err != nil {
+				return dropped, purged, err
+			}
+			next[i]++
+			if versions == 0 || e.cell.Newer(newest.cell) {
+				newest = e
+			}
+			versions++
+		}
+		dropped += versions - 1
+		if purge(newest) {
+			purged++
+			continue
+		}
+		if err := add(newest); err != nil {
+			return dropped, purged, err
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConsistencyLevel is how many replicas of a key must answer a request before the
+// coordinator answers the client, like Cassandra's consistency levels. Reading and writing
+// at QUORUM, or writing at ALL and reading at ONE, always reads the latest write.
+type ConsistencyLevel int
+
+const (
+	One    ConsistencyLevel = iota // the first replica to answer
+	Quorum                         // a majority of the replication factor
+	All                            // every replica
+)
+
+// DefaultConsistencyLevel is used by requests that don't ask for one
+const DefaultConsistencyLevel = One
+
+var consistencyLevelNames = map[ConsistencyLevel]string{
+	One:    "ONE",
+	Quorum: "QUORUM",
+	All:    "ALL",
+}
+
+func (cl ConsistencyLevel) String() string {
+	if name, ok := consistencyLevelNames[cl]; ok {
+		return name
+	}
+	return fmt.Sprintf("ConsistencyLevel(%d)", int(cl))
+}
+
+// ParseConsistencyLevel parses ONE, QUORUM or ALL, in any case
+func ParseConsistencyLevel(s string) (ConsistencyLevel, error) {
+	for cl, name := range consistencyLevelNames {
+		if strings.EqualFold(s, name) {
+			return cl, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid consistency level %q, must be ONE, QUORUM or ALL", s)
+}
+
+// BlockFor returns how many replicas must answer at this level when keys have
+// replicationFactor replicas. Like Cassandra it counts the replication factor, not the
+// replicas that are up: QUORUM of 3 needs 2 even if only 1 node is left.
+func (cl ConsistencyLevel) BlockFor(replicationFactor int) int {
+	switch cl {
+	case Quorum:
+		return replicationFactor/2 + 1
+	case All:
+		return replicationFactor
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+var (
+	// ErrUnavailable is returned, without sending anything, when a key has fewer replicas
+	// than the consistency level needs
+	ErrUnavailable = errors.New("not enough replicas for the consistency level")
+	// ErrNotEnoughReplies is returned when too many replicas failed or timed out for the
+	// consistency level. A write may still have been applied by the replicas that answered.
+	ErrNotEnoughReplies = errors.New("not enough replicas answered")
+)
+
+// Replica is a node holding a copy of a key, as the coordinator reaches it: the local Store
+// or a peer serving it
+type Replica interface {
+	Node() gossip.NodeID
+	Get(ctx context.Context, key string) (Cell, bool, error)
+	Put(ctx context.Context, key string, cell Cell) error
+	Delete(ctx context.Context, key string, timestamp int64) (bool, error)
+}
+
+// Reply is what one replica answered
+type Reply struct {
+	Replica gossip.NodeID
+	Cell    Cell
+	// Found is whether the replica holds a version of the key, for a Get, or whether it
+	// deleted a live value, for a Delete
+	Found bool
+}
+
+// Result is the outcome of a coordinated request
+type Result struct {
+	Cell     Cell            // the newest version read by a Get, maybe a tombstone
+	Found    bool            // whether that version is live, for a Get, or a replica deleted one, for a Delete
+	Replicas []gossip.NodeID // the replicas that answered before the coordinator did
+}
+
+// Coordinator sends a request to every replica of a key and answers as soon as as many
+// replicas as the consistency level needs have: the others still get the request, so a
+// write at ONE reaches every replica that is up.
+//
+// Reads are repaired, like Cassandra's read repair: the replies the coordinator waited for
+// are compared, the client gets the newest value, and replicas that answered an older value,
+// or none, are written the newest one in the background. Tombstones are compared and written
+// like values, so deletes are repaired too. With ReadRepairChance the
+// coordinator also waits for the replicas it didn't need and repairs them too, so keys that
+// are read converge on every replica even at ONE.
+type Coordinator struct {
+	ReplicationFactor int           // replicas every key has, see ConsistencyLevel.BlockFor
+	Timeout           time.Duration // bound on each replica request, which outlives the client's
+	ReadRepairChance  float64       // share of reads, 0 to 1, compared on every replica
+
+	repairs readRepairStats // see ReadRepairStats
+}
+
+// ReadRepairStats counts the reads a Coordinator compared and repaired
+type ReadRepairStats struct {
+	Reads      int64 // reads coordinated
+	Checked    int64 // reads compared on every replica, see Coordinator.ReadRepairChance
+	Mismatches int64 // reads whose replicas disagreed
+	Repaired   int64 // stale replicas written the newest value
+	Failed     int64 // write-backs that failed, the replica is left stale
+}
+
+// readRepairStats holds the counters behind ReadRepairStats
+type readRepairStats struct {
+	reads      atomic.Int64
+	checked    atomic.Int64
+	mismatches atomic.Int64
+	repaired   atomic.Int64
+	failed     atomic.Int64
+}
+
+// ReadRepairStats returns the coordinator's read repair counters. Sample them to get rates.
+func (c *Coordinator) ReadRepairStats() ReadRepairStats {
+	return ReadRepairStats{
+		Reads:      c.repairs.reads.Load(),
+		Checked:    c.repairs.checked.Load(),
+		Mismatches: c.repairs.mismatches.Load(),
+		Repaired:   c.repairs.repaired.Load(),
+		Failed:     c.repairs.failed.Load(),
+	}
+}
+
+// Get reads key from its replicas and returns the newest value they hold, repairing the
+// replicas that hold an older one
+func (c *Coordinator) Get(ctx context.Context, key string, replicas []Replica, cl ConsistencyLevel) (Result, error) {
+	c.repairs.reads.Add(1)
+	byNode := make(map[gossip.NodeID]Replica, len(replicas))
+	for _, replica := range replicas {
+		byNode[replica.Node()] = replica
+	}
+
+	blockFor := cl.BlockFor(c.ReplicationFactor)
+	answered, late, pending, err := c.run(ctx, replicas, blockFor, cl, func(ctx context.Context, replica Replica) (Reply, error) {
+		cell, found, err := replica.Get(ctx, key)
+		return Reply{Cell: cell, Found: found}, err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	result := merge(answered)
+	result.Found = result.Found && result.Cell.Live(time.Now())
+
+	trace := TraceFrom(ctx)
+	if pending > 0 && rand.Float64() < c.ReadRepairChance {
+		c.repairs.checked.Add(1)
+		trace.Record("Read repair chance: comparing the %d other replicas too", pending)
+		go func() {
+			// Every replica request is bounded by Timeout already: this only guards the wait
+			timeout := time.NewTimer(c.Timeout)
+			defer timeout.Stop()
+		wait:
+			for ; pending > 0; pending-- {
+				select {
+				case reply := <-late:
+					if reply.err == nil {
+						answered = append(answered, reply.Reply)
+					}
+				case <-timeout.C:
+					trace.Record("Gave up waiting for %d late replicas", pending)
+					break wait
+				}
+			}
+			c.repair(trace, key, byNode, answered)
+		}()
+	} else {
+		go c.repair(trace, key, byNode, answered)
+	}
+	return result, nil
+}
+
+// Put writes cell to the replicas of key, and to its pending replicas: nodes bootstrapping
+// into the ring that become replicas of key once they join. Like Cassandra, cl waits for one
+// more reply for every pending replica, so the write still reaches as many replicas as cl
+// asks for if one of them finishes joining and takes over from a current replica.
+func (c *Coordinator) Put(ctx context.Context, key string, cell Cell, replicas, pending []Replica, cl ConsistencyLevel) (Result, error) {
+	blockFor := cl.BlockFor(c.ReplicationFactor) + len(pending)
+	answered, _, _, err := c.run(ctx, slices.Concat(replicas, pending), blockFor, cl, func(ctx context.Context, replica Replica) (Reply, error) {
+		return Reply{}, replica.Put(ctx, key, cell)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return merge(answered), nil
+}
+
+// Delete writes a tombstone for key at timestamp to its replicas and its pending replicas,
+// see Put
+func (c *Coordinator) Delete(ctx context.Context, key string, timestamp int64, replicas, pending []Replica, cl ConsistencyLevel) (Result, error) {
+	blockFor := cl.BlockFor(c.ReplicationFactor) + len(pending)
+	answered, _, _, err := c.run(ctx, slices.Concat(replicas, pending), blockFor, cl, func(ctx context.Context, replica Replica) (Reply, error) {
+		found, err := replica.Delete(ctx, key, timestamp)
+		return Reply{Found: found}, err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return merge(answered), nil
+}
+
+// replicaReply is a Reply or the error a replica failed with
+type replicaReply struct {
+	Reply
+	err error
+}
+
+// run sends request to every replica and returns the replies of the first ones to answer,
+// blockFor of them, as cl needs. The replies of the others, pending of them, arrive on late,
+// whose every send is buffered: leaving them unread doesn't block the replicas. Every reply is
+// recorded in the Trace of ctx, if the request is traced, even those that arrive late.
+func (c *Coordinator) run(ctx context.Context, replicas []Replica, blockFor int, cl ConsistencyLevel,
+	request func(context.Context, Replica) (Reply, error)) (answered []Reply, late <-chan replicaReply, pending int, err error) {
+	trace := TraceFrom(ctx)
+	if len(replicas) < blockFor {
+		trace.Record("Unavailable: %s needs %d replicas, %d available", cl, blockFor, len(replicas))
+		return nil, nil, 0, fmt.Errorf("%w: %s needs %d, %d available", ErrUnavailable, cl, blockFor, len(replicas))
+	}
+
+	replies := make(chan replicaReply, len(replicas))
+	for _, replica := range replicas {
+		trace.Record("Sending request to %s", replica.Node())
+		go func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.Timeout)
+			defer cancel()
+			start := time.Now()
+			reply, err := request(ctx, replica)
+			if err != nil {
+				trace.RecordFrom(replica.Node(), "Failed after %v: %v", time.Since(start).Round(time.Microsecond), err)
+			} else {
+				trace.RecordFrom(replica.Node(), "Answered in %v", time.Since(start).Round(time.Microsecond))
+			}
+			reply.Replica = replica.Node()
+			replies <- replicaReply{Reply: reply, err: err}
+		}()
+	}
+
+	var lastErr error
+	for pending = len(replicas); pending > 0; {
+		select {
+		case <-ctx.Done():
+			trace.Record("Gave up after %d of %d replies for %s: %v", len(answered), blockFor, cl, ctx.Err())
+			return nil, nil, 0, fmt.Errorf("%w: %d of %d for %s: %v", ErrNotEnoughReplies, len(answered), blockFor, cl, ctx.Err())
+		case reply := <-replies:
+			pending--
+			if reply.err != nil {
+				lastErr = fmt.Errorf("%s: %w", reply.Replica, reply.err)
+				continue
+			}
+			answered = append(answered, reply.Reply)
+			if len(answered) == blockFor {
+				trace.Record("%d of %d replicas answered, enough for %s", blockFor, len(replicas), cl)
+				return answered, replies, pending, nil
+			}
+		}
+	}
+	trace.Record("Only %d of %d replicas answered, not enough for %s", len(answered), blockFor, cl)
+	return nil, nil, 0, fmt.Errorf("%w: %d of %d for %s, %v", ErrNotEnoughReplies, len(answered), blockFor, cl, lastErr)
+}
+
+// repair writes the newest of replies, a value or a tombstone, back to the replicas that
+// answered an older one, or none, recording it in trace
+func (c *Coordinator) repair(trace *Trace, key string, replicas map[gossip.NodeID]Replica, replies []Reply) {
+	newest := merge(replies)
+	if !newest.Found {
+		return
+	}
+	var stale []Replica
+	for _, reply := range replies {
+		if !reply.Found || newest.Cell.Newer(reply.Cell) {
+			stale = append(stale, replicas[reply.Replica])
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	c.repairs.mismatches.Add(1)
+	for _, replica := range stale {
+		trace.Record("Mismatch: repairing %s, which answered an older version or none", replica.Node())
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+			defer cancel()
+			if err := replica.Put(ctx, key, newest.Cell); err != nil {
+				c.repairs.failed.Add(1)
+				trace.RecordFrom(replica.Node(), "Failed to repair: %v", err)
+				return
+			}
+			c.repairs.repaired.Add(1)
+			trace.RecordFrom(replica.Node(), "Repaired")
+		}()
+	}
+}
+
+// merge combines the replies of replicas, keeping the newest version read
+func merge(replies []Reply) Result {
+	var result Result
+	for _, reply := range replies {
+		result.Replicas = append(result.Replicas, reply.Replica)
+		if reply.Found && (!result.Found || reply.Cell.Newer(result.Cell)) {
+			result.Cell = reply.Cell
+		}
+		result.Found = result.Found || reply.Found
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// entryOverhead is roughly what a memtable spends on a key besides its key and value bytes
+const entryOverhead = 48
+
+// entry is a version of a key: a value, or a tombstone
+type entry struct {
+	key  string
+	cell Cell
+}
+
+// memtable holds the latest writes of a Store in memory until they are flushed to an
+// SSTable. It is split in partitions so requests for different keys rarely wait on each
+// other.
+type memtable struct {
+	partitions []*partition
+	size       atomic.Int64 // approximate bytes held, see entryOverhead
+}
+
+// partition holds the keys that hash to it
+type partition struct {
+	mu   sync.RWMutex
+	data map[string]Cell
+}
+
+func newMemtable(partitions int) *memtable {
+	m := &memtable{partitions: make([]*partition, partitions)}
+	for i := range m.partitions {
+		m.partitions[i] = &partition{data: make(map[string]Cell)}
+	}
+	return m
+}
+
+// partition returns the partition of key
+func (m *memtable) partition(key string) *partition {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return m.partitions[h.Sum32()%uint32(len(m.partitions))]
+}
+
+// get returns a copy of the version of key
+func (m *memtable) get(key string) (Cell, bool) {
+	p := m.partition(key)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	cell, ok := p.data[key]
+	if !ok {
+		return Cell{}, false
+	}
+	return cell.clone(), true
+}
+
+// put keeps a copy of cell for key, unless the memtable holds a newer version
+func (m *memtable) put(key string, cell Cell) {
+	p := m.partition(key)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current, ok := p.data[key]
+	if ok && !cell.Newer(current) {
+		return
+	}
+	if ok {
+		m.size.Add(int64(len(cell.Value) - len(current.Value)))
+	} else {
+		m.size.Add(int64(len(key) + len(cell.Value) + entryOverhead))
+	}
+	p.data[key] = cell.clone()
+}
+
+// len returns how many keys the memtable holds a version of
+func (m *memtable) len() int {
+	n := 0
+	for _, p := range m.partitions {
+		p.mu.RLock()
+		n += len(p.data)
+		p.mu.RUnlock()
+	}
+	return n
+}
+
+// keys returns the keys the memtable holds a version of, in no particular order
+func (m *memtable) keys() []string {
+	var keys []string
+	for _, p := range m.partitions {
+		p.mu.RLock()
+		for key := range p.data {
+			keys = append(keys, key)
+		}
+		p.mu.RUnlock()
+	}
+	return keys
+}
+
+// sorted returns the versions the memtable holds, sorted by key, to write an SSTable
+func (m *memtable) sorted() []entry {
+	var entries []entry
+	for _, p := range m.partitions {
+		p.mu.RLock()
+		for key, cell := range p.data {
+			entries = append(entries, entry{key: key, cell: cell})
+		}
+		p.mu.RUnlock()
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return entries
+}
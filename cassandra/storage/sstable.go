@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	sstablePrefix = "sstable-"
+	sstableSuffix = ".db"
+	tmpSuffix     = ".tmp"
+
+	footerSize   = 24                 // index offset u64, entry count u32, CRC u32, magic u64
+	sstableMagic = 0x53535441424c4531 // "SSTABLE1"
+)
+
+var errCorruptSSTable = errors.New("corrupt SSTable")
+
+// sstable is an immutable file of versions of keys, sorted by key, written by a flush or a
+// compaction. The file is the entries, encoded like the commit log's payloads, then an index
+// of every key with the offset and length of its entry, then a footer:
+//
+//	[index offset u64][entry count u32][CRC-32C of the entries and index u32][magic u64]
+//
+// The index is kept in memory, so a read is a binary search and one read of the file.
+type sstable struct {
+	gen   int // generation, in the file name: higher for newer tables
+	path  string
+	file  *os.File
+	size  int64 // bytes on disk
+	keys  []string
+	spans []span // of the entry of each key
+}
+
+// span is where an entry is in a table file
+type span struct {
+	offset int64
+	length int64
+}
+
+func sstableName(gen int) string {
+	return fmt.Sprintf("%s%06d%s", sstablePrefix, gen, sstableSuffix)
+}
+
+// listSSTables returns the generations of the tables in dir, sorted, removing the temporary
+// files of flushes and compactions a crash interrupted
+func listSSTables(dir string) ([]int, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSTables: %w", err)
+	}
+	var gens []int
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, sstablePrefix) {
+			continue
+		}
+		if strings.HasSuffix(name, tmpSuffix) {
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		var gen int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, sstablePrefix), "%d"+sstableSuffix, &gen); err == nil {
+			gens = append(gens, gen)
+		}
+	}
+	slices.Sort(gens)
+	return gens, nil
+}
+
+// openSSTable checks the table at path and loads its index
+func openSSTable(path string, gen int) (*sstable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < footerSize {
+		return nil, errCorruptSSTable
+	}
+	footer := data[len(data)-footerSize:]
+	indexOffset := binary.BigEndian.Uint64(footer[0:8])
+	count := binary.BigEndian.Uint32(footer[8:12])
+	body := data[:len(data)-footerSize]
+	if binary.BigEndian.Uint64(footer[16:24]) != sstableMagic ||
+		crc32.Checksum(body, crcTable) != binary.BigEndian.Uint32(footer[12:16]) ||
+		indexOffset > uint64(len(body)) {
+		return nil, errCorruptSSTable
+	}
+
+	t := &sstable{gen: gen, path: path, size: int64(len(data)), keys: make([]string, 0, count), spans: make([]span, 0, count)}
+	index := body[indexOffset:]
+	for range count {
+		key, rest, ok := readBytes(index)
+		if !ok {
+			return nil, errCorruptSSTable
+		}
+		offset, n := binary.Uvarint(rest)
+		if n <= 0 {
+			return nil, errCorruptSSTable
+		}
+		rest = rest[n:]
+		length, n := binary.Uvarint(rest)
+		if n <= 0 || offset+length > indexOffset {
+			return nil, errCorruptSSTable
+		}
+		index = rest[n:]
+		t.keys = append(t.keys, string(key))
+		t.spans = append(t.spans, span{offset: int64(offset), length: int64(length)})
+	}
+
+	if t.file, err = os.Open(path); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// get returns the version of key in the table
+func (t *sstable) get(key string) (Cell, bool, error) {
+	i, ok := slices.BinarySearch(t.keys, key)
+	if !ok {
+		return Cell{}, false, nil
+	}
+	e, err := t.entry(i)
+	return e.cell, err == nil, err
+}
+
+// entry reads the i-th entry of the table
+func (t *sstable) entry(i int) (entry, error) {
+	buf := make([]byte, t.spans[i].length)
+	if _, err := t.file.ReadAt(buf, t.spans[i].offset); err != nil {
+		return entry{}, fmt.Errorf("failed to read %s: %w", t.path, err)
+	}
+	e, err := decodeEntry(buf)
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to read %s: %w", t.path, errCorruptSSTable)
+	}
+	return e, nil
+}
+
+// has reports whether the table holds a version of key, without reading it
+func (t *sstable) has(key string) bool {
+	_, ok := slices.BinarySearch(t.keys, key)
+	return ok
+}
+
+func (t *sstable) close() error {
+	return t.file.Close()
+}
+
+// sstableWriter writes a table, entries in key order, to a temporary file renamed to the
+// table's name by finish: a table that exists is complete.
+type sstableWriter struct {
+	path  string
+	file  *os.File
+	w     *bufio.Writer
+	crc   hash.Hash32
+	out   io.Writer // w and crc
+	size  int64
+	count uint32
+	index []byte
+}
+
+func createSSTable(path string) (*sstableWriter, error) {
+	file, err := os.OpenFile(path+tmpSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSTable: %w", err)
+	}
+	w := &sstableWriter{path: path, file: file, w: bufio.NewWriter(file), crc: crc32.New(crcTable)}
+	w.out = io.MultiWriter(w.w, w.crc)
+	return w, nil
+}
+
+// add writes e, whose key must come after the previous one's
+func (w *sstableWriter) add(e entry) error {
+	payload := encodeEntry(e)
+	if _, err := w.out.Write(payload); err != nil {
+		return err
+	}
+	w.index = binary.AppendUvarint(w.index, uint64(len(e.key)))
+	w.index = append(w.index, e.key...)
+	w.index = binary.AppendUvarint(w.index, uint64(w.size))
+	w.index = binary.AppendUvarint(w.index, uint64(len(payload)))
+	w.size += int64(len(payload))
+	w.count++
+	return nil
+}
+
+// finish writes the index and footer, syncs the file and gives it its name
+func (w *sstableWriter) finish() error {
+	if _, err := w.out.Write(w.index); err != nil {
+		return w.fail(err)
+	}
+	footer := make([]byte, footerSize)
+	binary.BigEndian.PutUint64(footer[0:8], uint64(w.size))
+	binary.BigEndian.PutUint32(footer[8:12], w.count)
+	binary.BigEndian.PutUint32(footer[12:16], w.crc.Sum32())
+	binary.BigEndian.PutUint64(footer[16:24], sstableMagic)
+	if _, err := w.w.Write(footer); err != nil {
+		return w.fail(err)
+	}
+	if err := w.w.Flush(); err != nil {
+		return w.fail(err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return w.fail(err)
+	}
+	if err := w.file.Close(); err != nil {
+		return w.fail(err)
+	}
+	if err := os.Rename(w.path+tmpSuffix, w.path); err != nil {
+		return w.fail(err)
+	}
+	return syncDir(filepath.Dir(w.path))
+}
+
+// fail removes the temporary file of a table that couldn't be written
+func (w *sstableWriter) fail(err error) error {
+	w.file.Close()
+	os.Remove(w.path + tmpSuffix)
+	return fmt.Errorf("failed to write SSTable: %w", err)
+}
+
+// syncDir syncs dir, so the files created or renamed in it survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
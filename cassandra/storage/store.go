@@ -0,0 +1,498 @@
+// Package storage is the key-value store of a node, holding the keys it has a replica of.
+// Which nodes hold a key is decided by the token ring, see package ring, and requests are
+// sent to every one of them by a Coordinator.
+//
+// A Store opened on a directory (see Open) is a log-structured merge tree like Cassandra's:
+// every write is appended to a commit log, then applied to a memtable in memory. A memtable
+// that grows past a size is flushed to an SSTable, a sorted file that is never modified, and
+// SSTables of similar size are compacted into one in the background. Reads merge the newest
+// version of a key from the memtable and every SSTable. When the store is opened again it
+// replays the writes of the commit log that weren't flushed yet, so its keys survive
+// restarts.
+//
+// Deletes are writes too: a tombstone, a Cell with Deleted set, hides the older values of
+// its key wherever they are, and replicas exchange tombstones like values, so a replica
+// that missed a delete learns of it instead of bringing the key back. A value written with
+// a TTL turns into a tombstone once it expires. Compaction drops tombstones gc_grace after
+// the delete, see Options.GCGrace.
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+const (
+	// DefaultPartitions is how many partitions a memtable has by default
+	DefaultPartitions = 16
+	// DefaultFlushSize is the size of a memtable, in bytes, that is flushed by default
+	DefaultFlushSize = 1 << 20
+	// DefaultGCGrace is how long tombstones are kept by default, Cassandra's gc_grace_seconds
+	DefaultGCGrace = 10 * 24 * time.Hour
+)
+
+var (
+	// ErrEmptyKey is returned for a request without a key
+	ErrEmptyKey = errors.New("key must not be empty")
+	// ErrClosed is returned for a request to a closed Store
+	ErrClosed = errors.New("store is closed")
+)
+
+// Cell is the value of a key and when it was written, in microseconds since the epoch like
+// Cassandra's write timestamps, or the tombstone of a delete. Replicas keep the newest write.
+type Cell struct {
+	Value     []byte
+	Timestamp int64
+	TTL       time.Duration // how long after Timestamp the value expires, 0 for never
+	Deleted   bool          // a tombstone: the key was deleted at Timestamp
+}
+
+// Live reports whether c is a value that hasn't expired at now
+func (c Cell) Live(now time.Time) bool {
+	return !c.Deleted && (c.TTL == 0 || now.UnixMicro() < c.expires())
+}
+
+// expires returns when the value expires, in microseconds since the epoch
+func (c Cell) expires() int64 {
+	return c.Timestamp + c.TTL.Microseconds()
+}
+
+// purgeable reports whether c is a tombstone, or an expired value, older than gcGrace at now,
+// which compaction drops
+func (c Cell) purgeable(now time.Time, gcGrace time.Duration) bool {
+	if c.Live(now) {
+		return false
+	}
+	deletedAt := c.Timestamp
+	if !c.Deleted {
+		deletedAt = c.expires()
+	}
+	return now.UnixMicro() >= deletedAt+gcGrace.Microseconds()
+}
+
+// Store is a key-value store, safe for concurrent use: in memory only if created with
+// NewStore, or kept in a directory if opened with Open
+type Store struct {
+	partitions int // of each memtable
+
+	mu       sync.RWMutex // held by reads and writes, and exclusively to switch memtables or tables
+	memtable *memtable
+	flushing *memtable  // being written to an SSTable and still read, nil between flushes
+	tables   []*sstable // oldest first
+	closed   bool
+
+	// For a store opened with Open
+	dir                 string
+	commitLog           *commitLog
+	replayed            ReplayStats // see Replayed
+	flushSize           int64
+	compactionThreshold int
+	gcGrace             time.Duration
+	nextGen             int // of the next SSTable, only used by background and Close
+	log                 *logger.SubLogger
+
+	flushes     atomic.Int64
+	compactions atomic.Int64
+
+	flushRequests chan struct{} // see maybeFlush
+	stop          chan struct{} // closed by Close, stops background
+	done          chan struct{} // closed when background has stopped
+}
+
+// Options configure a Store opened with Open
+type Options struct {
+	Partitions int        // DefaultPartitions if not positive
+	Sync       SyncPolicy // when the commit log is synced, SyncPeriodic if empty
+	SyncPeriod time.Duration
+
+	// FlushSize is the size of the memtable, in bytes, that is flushed to an SSTable,
+	// DefaultFlushSize if not positive
+	FlushSize int64
+	// CompactionThreshold is how many SSTables of similar size are merged into one,
+	// DefaultCompactionThreshold if less than 2
+	CompactionThreshold int
+	// GCGrace is how long after a delete compaction keeps its tombstone, DefaultGCGrace if
+	// negative. Replicas that missed the delete must be repaired within it: once the
+	// tombstone is dropped, their older value comes back.
+	GCGrace time.Duration
+
+	// Log reports flushes and compactions, logger.Named("storage") if nil
+	Log *logger.SubLogger
+}
+
+// Stats describes what a Store holds, and the flushes and compactions since it was opened
+type Stats struct {
+	MemtableKeys  int   // keys written or deleted since the last flush
+	MemtableBytes int64 // approximate
+	SSTables      int
+	SSTableBytes  int64
+	Flushes       int64
+	Compactions   int64
+}
+
+// NewStore creates an empty store held in memory only, whose memtable has the given number
+// of partitions, DefaultPartitions if it isn't positive
+func NewStore(partitions int) *Store {
+	if partitions <= 0 {
+		partitions = DefaultPartitions
+	}
+	return &Store{partitions: partitions, memtable: newMemtable(partitions)}
+}
+
+// Open opens the store kept in dir: it loads the SSTables in dir/sstables and replays the
+// commit log in dir/commitlog, which every write is appended to from then on. Close it to
+// flush the memtable.
+func Open(dir string, opts Options) (*Store, error) {
+	if opts.Sync == "" {
+		opts.Sync = SyncPeriodic
+	}
+	if err := opts.Sync.Validate(opts.SyncPeriod); err != nil {
+		return nil, err
+	}
+	if opts.FlushSize <= 0 {
+		opts.FlushSize = DefaultFlushSize
+	}
+	if opts.CompactionThreshold < 2 {
+		opts.CompactionThreshold = DefaultCompactionThreshold
+	}
+	if opts.GCGrace < 0 {
+		opts.GCGrace = DefaultGCGrace
+	}
+	if opts.Log == nil {
+		opts.Log = logger.Named("storage")
+	}
+
+	s := NewStore(opts.Partitions)
+	s.dir = dir
+	s.flushSize = opts.FlushSize
+	s.compactionThreshold = opts.CompactionThreshold
+	s.gcGrace = opts.GCGrace
+	s.log = opts.Log
+	if err := s.loadSSTables(); err != nil {
+		return nil, err
+	}
+	commitLog, stats, err := openCommitLog(filepath.Join(dir, "commitlog"), opts.Sync, opts.SyncPeriod, s.apply)
+	if err != nil {
+		s.closeTables()
+		return nil, err
+	}
+	s.commitLog = commitLog
+	s.replayed = stats
+
+	s.flushRequests = make(chan struct{}, 1)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.maybeFlush()
+	go s.background()
+	return s, nil
+}
+
+// loadSSTables opens the SSTables in the store's directory
+func (s *Store) loadSSTables() error {
+	dir := filepath.Join(s.dir, "sstables")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create SSTable directory: %w", err)
+	}
+	gens, err := listSSTables(dir)
+	if err != nil {
+		return err
+	}
+	for _, gen := range gens {
+		table, err := openSSTable(s.sstablePath(gen), gen)
+		if err != nil {
+			s.closeTables()
+			return fmt.Errorf("failed to open %s: %w", sstableName(gen), err)
+		}
+		s.tables = append(s.tables, table)
+		s.nextGen = gen
+	}
+	s.nextGen++
+	return nil
+}
+
+func (s *Store) sstablePath(gen int) string {
+	return filepath.Join(s.dir, "sstables", sstableName(gen))
+}
+
+// Replayed returns what Open replayed from the commit log
+func (s *Store) Replayed() ReplayStats {
+	return s.replayed
+}
+
+// Close flushes the memtable to an SSTable, like nodetool drain, and closes the store's
+// files. Requests fail with ErrClosed afterwards.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	if s.commitLog == nil {
+		return nil
+	}
+
+	close(s.stop)
+	<-s.done
+	err := s.flush()
+	return errors.Join(err, s.commitLog.close(), s.closeTables())
+}
+
+func (s *Store) closeTables() error {
+	var errs []error
+	for _, table := range s.tables {
+		errs = append(errs, table.close())
+	}
+	return errors.Join(errs...)
+}
+
+// apply applies a write read back from the commit log
+func (s *Store) apply(e entry) {
+	s.memtable.put(e.key, e.cell)
+}
+
+// Get returns a copy of the newest version of key, false if it has none. The version may be
+// a tombstone or an expired value, see Cell.Live.
+func (s *Store) Get(key string) (Cell, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return Cell{}, false, ErrClosed
+	}
+	return s.newest(key)
+}
+
+// newest returns the newest version of key in the memtables and SSTables. Caller must hold
+// s.mu.
+func (s *Store) newest(key string) (Cell, bool, error) {
+	var cell Cell
+	found := false
+	for _, m := range s.memtables() {
+		if c, ok := m.get(key); ok && (!found || c.Newer(cell)) {
+			cell, found = c, true
+		}
+	}
+	for _, table := range s.tables {
+		c, ok, err := table.get(key)
+		if err != nil {
+			return Cell{}, false, err
+		}
+		if ok && (!found || c.Newer(cell)) {
+			cell, found = c, true
+		}
+	}
+	return cell, found, nil
+}
+
+// Keys returns the keys the store holds a version of, tombstones and expired values
+// included, sorted. Read their versions with Get, which may no longer find a key that
+// compaction purged meanwhile.
+func (s *Store) Keys() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	var keys []string
+	for _, m := range s.memtables() {
+		keys = append(keys, m.keys()...)
+	}
+	for _, table := range s.tables {
+		keys = append(keys, table.keys...)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
+
+// memtables returns the memtable written to and the one being flushed, if any. Caller must
+// hold s.mu.
+func (s *Store) memtables() []*memtable {
+	if s.flushing != nil {
+		return []*memtable{s.memtable, s.flushing}
+	}
+	return []*memtable{s.memtable}
+}
+
+// Put sets key to a copy of cell, unless the key holds a newer write: replicas receiving the
+// same writes in different orders end up with the same value. Ties go to a tombstone, then
+// to the greater value. The cell may be a tombstone, e.g. one read repair copies.
+func (s *Store) Put(key string, cell Cell) error {
+	if key == "" {
+		return ErrEmptyKey
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return s.write(entry{key: key, cell: cell})
+}
+
+// Newer reports whether c wins over other, the version replicas keep, see Put
+func (c Cell) Newer(other Cell) bool {
+	if c.Timestamp != other.Timestamp {
+		return c.Timestamp > other.Timestamp
+	}
+	if c.Deleted != other.Deleted {
+		return c.Deleted
+	}
+	return bytes.Compare(c.Value, other.Value) > 0
+}
+
+func (c Cell) clone() Cell {
+	c.Value = append([]byte{}, c.Value...)
+	return c
+}
+
+// Delete writes a tombstone for key at timestamp, which hides the values written before it,
+// reporting whether it hid a live one
+func (s *Store) Delete(key string, timestamp int64) (bool, error) {
+	if key == "" {
+		return false, ErrEmptyKey
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false, ErrClosed
+	}
+	tombstone := Cell{Timestamp: timestamp, Deleted: true}
+	cell, found, err := s.newest(key)
+	if err != nil {
+		return false, err
+	}
+	if err := s.write(entry{key: key, cell: tombstone}); err != nil {
+		return false, err
+	}
+	return found && cell.Live(time.Now()) && tombstone.Newer(cell), nil
+}
+
+// write appends e to the commit log, if the store has one, and applies it to the memtable.
+// Caller must hold s.mu.
+func (s *Store) write(e entry) error {
+	if s.commitLog != nil {
+		if err := s.commitLog.append(e); err != nil {
+			return fmt.Errorf("failed to write the commit log: %w", err)
+		}
+	}
+	s.memtable.put(e.key, e.cell)
+	s.maybeFlush()
+	return nil
+}
+
+// maybeFlush asks background to flush the memtable once it reaches the flush size. Caller
+// must hold s.mu.
+func (s *Store) maybeFlush() {
+	if s.commitLog == nil || s.memtable.size.Load() < s.flushSize {
+		return
+	}
+	select {
+	case s.flushRequests <- struct{}{}:
+	default: // already asked
+	}
+}
+
+// background flushes the memtable when asked and compacts the SSTables after, until Close
+func (s *Store) background() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.flushRequests:
+			if err := s.flush(); err != nil {
+				s.log.Errorf("Failed to flush the memtable: %v", err)
+				continue
+			}
+			if err := s.compact(); err != nil {
+				s.log.Errorf("Failed to compact SSTables: %v", err)
+			}
+		}
+	}
+}
+
+// flush writes the memtable to a new SSTable, then removes the commit log segments whose
+// writes it holds. Writes go to a new memtable meanwhile, and reads look in both.
+func (s *Store) flush() error {
+	s.mu.Lock()
+	if s.memtable.len() == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	sealed, err := s.commitLog.rotate()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	flushing := s.memtable
+	s.flushing, s.memtable = flushing, newMemtable(s.partitions)
+	s.mu.Unlock()
+
+	gen := s.nextGen
+	s.nextGen++
+	entries := flushing.sorted()
+	table, err := s.writeSSTable(gen, entries)
+
+	s.mu.Lock()
+	s.flushing = nil
+	if err != nil {
+		// Keep the writes in memory, and the commit log segments holding them
+		for _, e := range entries {
+			s.memtable.put(e.key, e.cell)
+		}
+		s.mu.Unlock()
+		return err
+	}
+	s.tables = append(s.tables, table)
+	s.mu.Unlock()
+
+	s.flushes.Add(1)
+	s.log.Printf("Flushed %d keys (%d bytes in memory) to %s (%d bytes)",
+		len(entries), flushing.size.Load(), sstableName(gen), table.size)
+	return s.commitLog.discard(sealed)
+}
+
+// writeSSTable writes entries, sorted by key, to the SSTable of generation gen and opens it
+func (s *Store) writeSSTable(gen int, entries []entry) (*sstable, error) {
+	w, err := createSSTable(s.sstablePath(gen))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := w.add(e); err != nil {
+			return nil, w.fail(err)
+		}
+	}
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	return openSSTable(s.sstablePath(gen), gen)
+}
+
+// Stats returns what the store holds, and the flushes and compactions it did
+func (s *Store) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := Stats{
+		SSTables:    len(s.tables),
+		Flushes:     s.flushes.Load(),
+		Compactions: s.compactions.Load(),
+	}
+	for _, m := range s.memtables() {
+		stats.MemtableKeys += m.len()
+		stats.MemtableBytes += m.size.Load()
+	}
+	for _, table := range s.tables {
+		stats.SSTableBytes += table.size
+	}
+	return stats
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Trace records what happens to one coordinated request, like Cassandra's query tracing: the
+// coordinator receiving it, sending it to every replica, their answers, and the read repairs
+// it triggers. Events can be recorded after the client has its answer, e.g. late replicas and
+// repairs. It is safe for concurrent use, and a nil Trace records nothing.
+type Trace struct {
+	ID          string
+	Request     string        // what was asked, e.g. "GET greeting at QUORUM"
+	Coordinator gossip.NodeID // the node that coordinated the request
+	Started     time.Time
+
+	mu     sync.Mutex
+	events []TraceEvent
+}
+
+// TraceEvent is one step of a traced request
+type TraceEvent struct {
+	Elapsed  time.Duration // since the coordinator received the request
+	Source   gossip.NodeID // the node the event is about
+	Activity string
+}
+
+// NewTrace starts the trace of a request the coordinator just received
+func NewTrace(id, request string, coordinator gossip.NodeID) *Trace {
+	t := &Trace{ID: id, Request: request, Coordinator: coordinator, Started: time.Now()}
+	t.Record("Received %s", request)
+	return t
+}
+
+// Record adds an event of the coordinator to the trace
+func (t *Trace) Record(format string, args ...any) {
+	if t == nil {
+		return
+	}
+	t.RecordFrom(t.Coordinator, format, args...)
+}
+
+// RecordFrom adds an event about source, e.g. a replica answering, to the trace
+func (t *Trace) RecordFrom(source gossip.NodeID, format string, args ...any) {
+	if t == nil {
+		return
+	}
+	event := TraceEvent{Elapsed: time.Since(t.Started), Source: source, Activity: fmt.Sprintf(format, args...)}
+	t.mu.Lock()
+	t.events = append(t.events, event)
+	t.mu.Unlock()
+}
+
+// Events returns the events recorded so far, oldest first
+func (t *Trace) Events() []TraceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.events)
+}
+
+// traceKey is the context key of the Trace of a request
+type traceKey struct{}
+
+// WithTrace returns a copy of ctx carrying t, which the Coordinator records the request's
+// events in
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// TraceFrom returns the Trace of ctx, nil if the request isn't traced
+func TraceFrom(ctx context.Context) *Trace {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+// TraceStore keeps the latest traces of a coordinator, forgetting the oldest ones beyond its
+// capacity
+type TraceStore struct {
+	mu       sync.Mutex
+	capacity int
+	traces   map[string]*Trace
+	order    []string // IDs, oldest first
+}
+
+// NewTraceStore returns a store of at most capacity traces
+func NewTraceStore(capacity int) *TraceStore {
+	return &TraceStore{capacity: capacity, traces: make(map[string]*Trace)}
+}
+
+// Add keeps t, forgetting the oldest trace if the store is full
+func (s *TraceStore) Add(t *Trace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.order) == s.capacity {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.traces[t.ID] = t
+	s.order = append(s.order, t.ID)
+}
+
+// Get returns the trace with the ID id, if the store still has it
+func (s *TraceStore) Get(id string) (*Trace, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.traces[id]
+	return t, ok
+}
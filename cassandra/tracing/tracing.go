@@ -0,0 +1,108 @@
+// Package tracing records OpenTelemetry spans of gossip exchanges. Until Setup is called the
+// spans go to OpenTelemetry's no-op tracer, so instrumented code costs next to nothing when
+// tracing isn't configured.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name of the spans
+const ServiceName = "cassandra"
+
+// tracer records the spans of the gossip code, through the provider set by Setup
+var tracer = otel.Tracer("github.com/adamgarcia4/goLearning/cassandra")
+
+// Span attributes
+const (
+	AttrNodeID   = attribute.Key("cassandra.node.id")
+	AttrPeer     = attribute.Key("cassandra.peer.address")
+	AttrDigests  = attribute.Key("cassandra.gossip.digests")
+	AttrStates   = attribute.Key("cassandra.gossip.states")
+	AttrRequests = attribute.Key("cassandra.gossip.requests")
+)
+
+// Configured reports whether endpoint, or else the standard OTEL_EXPORTER_OTLP_ENDPOINT and
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variables, say where to export spans
+func Configured(endpoint string) bool {
+	return endpoint != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// ValidateEndpoint checks that endpoint is an http:// (plaintext) or https:// URL of an OTLP
+// gRPC collector, e.g. http://localhost:4317. Empty is valid, it leaves the endpoint to the
+// environment.
+func ValidateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid OTLP endpoint %q: expected a URL like http://localhost:4317", endpoint)
+	}
+	return nil
+}
+
+// Setup exports spans over OTLP gRPC to endpoint, or to the collector of the environment
+// variables if it is empty, and propagates traces across gRPC calls in W3C trace context
+// headers. The returned function flushes the spans not yet exported and stops exporting.
+func Setup(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if err := ValidateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	var options []otlptracegrpc.Option
+	if endpoint != "" {
+		options = append(options, otlptracegrpc.WithEndpointURL(endpoint))
+	}
+	// Doesn't connect yet, an unreachable collector only fails the exports
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name, a child of the span in ctx if any, e.g.
+// tracing.Start(ctx, "gossip.merge", tracing.AttrStates.Int(len(states)))
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends span, marking it failed if err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the ID of the trace of the span in ctx, empty if it isn't recorded
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
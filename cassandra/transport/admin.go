@@ -0,0 +1,402 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+)
+
+// ClusterState is a node's view of the cluster, as returned by AdminHandler.GetClusterState.
+type ClusterState struct {
+	NodeID     gossip.NodeID
+	ClusterID  string
+	Endpoints  []gossip.EndpointInfo
+	Conflicts  []gossip.Conflict       // nodes sharing an ID or an address
+	Stats      gossip.Stats            // the node's gossip statistics
+	ReadRepair storage.ReadRepairStats // the read repairs of the node's KV coordinator
+	Storage    storage.Stats           // the node's KV store
+}
+
+// LogsQuery selects the log entries AdminHandler.Logs streams.
+type LogsQuery struct {
+	NodeID string // only entries logged by this node, "" for every entry
+	Tail   int    // how many of the latest entries to send first, negative for all
+	Follow bool   // keep streaming new entries until the context is done
+}
+
+// AdminHandler is implemented by whatever serves operator requests. If the GossipHandler
+// passed to NewGRPC also implements AdminHandler, the AdminService is served alongside gossip.
+type AdminHandler interface {
+	GetClusterState(ctx context.Context) (ClusterState, error)
+	RemoveNode(ctx context.Context, nodeID gossip.NodeID) error
+	SetAppState(ctx context.Context, key gossip.AppStateKey, value string) (gossip.AppState, error)
+	TriggerGossipRound(ctx context.Context) error
+	// Decommission starts leaving the cluster and returns how long each leave phase is gossiped.
+	Decommission(ctx context.Context) (time.Duration, error)
+	// Logs streams the entries selected by query. The channel is closed once they are sent,
+	// or when ctx is done if query.Follow is set.
+	Logs(ctx context.Context, query LogsQuery) (<-chan logger.LogEntry, error)
+}
+
+type AdminServiceServer struct {
+	gossipProtobuffer.UnimplementedAdminServiceServer
+	handler AdminHandler
+}
+
+// GetClusterState returns the node's view of every endpoint
+func (s *AdminServiceServer) GetClusterState(ctx context.Context, req *gossipProtobuffer.GetClusterStateRequest) (*gossipProtobuffer.GetClusterStateResponse, error) {
+	state, err := s.handler.GetClusterState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clusterStateToProto(state), nil
+}
+
+// RemoveNode forgets an endpoint
+func (s *AdminServiceServer) RemoveNode(ctx context.Context, req *gossipProtobuffer.RemoveNodeRequest) (*gossipProtobuffer.RemoveNodeResponse, error) {
+	if req.GetNodeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "node ID must be provided")
+	}
+	if err := s.handler.RemoveNode(ctx, gossip.NodeID(req.GetNodeId())); err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.RemoveNodeResponse{}, nil
+}
+
+// SetAppState sets a local application state
+func (s *AdminServiceServer) SetAppState(ctx context.Context, req *gossipProtobuffer.SetAppStateRequest) (*gossipProtobuffer.SetAppStateResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key must be provided")
+	}
+	state, err := s.handler.SetAppState(ctx, gossip.AppStateKey(req.GetKey()), req.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.SetAppStateResponse{
+		State: &gossipProtobuffer.VersionedValue{Value: state.Value, Version: state.Version},
+	}, nil
+}
+
+// TriggerGossipRound runs a gossip round now
+func (s *AdminServiceServer) TriggerGossipRound(ctx context.Context, req *gossipProtobuffer.TriggerGossipRoundRequest) (*gossipProtobuffer.TriggerGossipRoundResponse, error) {
+	if err := s.handler.TriggerGossipRound(ctx); err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.TriggerGossipRoundResponse{}, nil
+}
+
+// Decommission starts a graceful leave
+func (s *AdminServiceServer) Decommission(ctx context.Context, req *gossipProtobuffer.DecommissionRequest) (*gossipProtobuffer.DecommissionResponse, error) {
+	drain, err := s.handler.Decommission(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.DecommissionResponse{DrainMs: drain.Milliseconds()}, nil
+}
+
+// Logs streams log entries until the client goes away, or until the latest ones are sent
+// when not following
+func (s *AdminServiceServer) Logs(req *gossipProtobuffer.LogsRequest, stream grpc.ServerStreamingServer[gossipProtobuffer.LogEntry]) error {
+	entries, err := s.handler.Logs(stream.Context(), LogsQuery{
+		NodeID: req.GetNodeId(),
+		Tail:   int(req.GetTail()),
+		Follow: req.GetFollow(),
+	})
+	if err != nil {
+		return err
+	}
+	for entry := range entries {
+		err := stream.Send(&gossipProtobuffer.LogEntry{
+			TimestampMs: entry.Timestamp.UnixMilli(),
+			NodeId:      entry.NodeID,
+			Message:     entry.Text(), // the RPC has no fields, they are sent as text
+			Level:       entry.Level.String(),
+			Component:   entry.Component,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdminClient calls the AdminService of a running node.
+type AdminClient struct {
+	conn   *grpc.ClientConn
+	client gossipProtobuffer.AdminServiceClient
+	gossip gossipProtobuffer.GossipServiceClient
+}
+
+// DialAdmin connects to the AdminService at target. A nil tlsConfig means plaintext.
+func DialAdmin(target string, tlsConfig *TLSConfig) (*AdminClient, error) {
+	creds, err := tlsConfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client credentials: %w", err)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+
+	return &AdminClient{
+		conn:   conn,
+		client: gossipProtobuffer.NewAdminServiceClient(conn),
+		gossip: gossipProtobuffer.NewGossipServiceClient(conn),
+	}, nil
+}
+
+// GetClusterState returns the node's view of every endpoint, including itself.
+func (c *AdminClient) GetClusterState(ctx context.Context) (ClusterState, error) {
+	resp, err := c.client.GetClusterState(ctx, &gossipProtobuffer.GetClusterStateRequest{})
+	if err != nil {
+		return ClusterState{}, err
+	}
+	return clusterStateFromProto(resp), nil
+}
+
+// WatchClusterState streams the state of every endpoint the node knows, then every change,
+// until ctx is done or the connection breaks (the channel is closed then).
+func (c *AdminClient) WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error) {
+	return watchClusterState(ctx, c.gossip)
+}
+
+// RemoveNode makes the node forget nodeID.
+func (c *AdminClient) RemoveNode(ctx context.Context, nodeID gossip.NodeID) error {
+	_, err := c.client.RemoveNode(ctx, &gossipProtobuffer.RemoveNodeRequest{NodeId: string(nodeID)})
+	return err
+}
+
+// SetAppState sets an application state on the node and returns it with its new version.
+func (c *AdminClient) SetAppState(ctx context.Context, key gossip.AppStateKey, value string) (gossip.AppState, error) {
+	resp, err := c.client.SetAppState(ctx, &gossipProtobuffer.SetAppStateRequest{Key: string(key), Value: value})
+	if err != nil {
+		return gossip.AppState{}, err
+	}
+	return gossip.AppState{Value: resp.GetState().GetValue(), Version: resp.GetState().GetVersion()}, nil
+}
+
+// TriggerGossipRound makes the node run a gossip round now.
+func (c *AdminClient) TriggerGossipRound(ctx context.Context) error {
+	_, err := c.client.TriggerGossipRound(ctx, &gossipProtobuffer.TriggerGossipRoundRequest{})
+	return err
+}
+
+// Decommission makes the node leave the cluster and stop. It returns once the leave has started,
+// with how long each of STATUS LEAVING and LEFT is gossiped before the node stops.
+func (c *AdminClient) Decommission(ctx context.Context) (time.Duration, error) {
+	resp, err := c.client.Decommission(ctx, &gossipProtobuffer.DecommissionRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(resp.GetDrainMs()) * time.Millisecond, nil
+}
+
+// Logs streams the node's log entries selected by query. The channel is closed once they are
+// received, or, when following, once ctx is done or the connection breaks.
+func (c *AdminClient) Logs(ctx context.Context, query LogsQuery) (<-chan logger.LogEntry, error) {
+	stream, err := c.client.Logs(ctx, &gossipProtobuffer.LogsRequest{
+		NodeId: query.NodeID,
+		Tail:   int32(query.Tail),
+		Follow: query.Follow,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(chan logger.LogEntry)
+	go func() {
+		defer close(entries)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			// Nodes that don't send a level only log at info level
+			level, _ := logger.ParseLevel(resp.GetLevel())
+			entry := logger.LogEntry{
+				Timestamp: time.UnixMilli(resp.GetTimestampMs()),
+				NodeID:    resp.GetNodeId(),
+				Message:   resp.GetMessage(),
+				Level:     level,
+				Component: resp.GetComponent(),
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return entries, nil
+}
+
+// Close releases the connection.
+func (c *AdminClient) Close() error {
+	return c.conn.Close()
+}
+
+func clusterStateToProto(state ClusterState) *gossipProtobuffer.GetClusterStateResponse {
+	endpoints := make([]*gossipProtobuffer.EndpointStatus, 0, len(state.Endpoints))
+	for _, endpoint := range state.Endpoints {
+		endpoints = append(endpoints, &gossipProtobuffer.EndpointStatus{
+			State:           stateToProto(endpoint.State),
+			Alive:           endpoint.Alive,
+			Phi:             endpoint.Phi,
+			UpdateTimestamp: endpoint.UpdateTimestamp,
+			Local:           endpoint.Local,
+		})
+	}
+	conflicts := make([]*gossipProtobuffer.Conflict, 0, len(state.Conflicts))
+	for _, conflict := range state.Conflicts {
+		nodeIDs := make([]string, len(conflict.NodeIDs))
+		for i, id := range conflict.NodeIDs {
+			nodeIDs[i] = string(id)
+		}
+		conflicts = append(conflicts, &gossipProtobuffer.Conflict{
+			Kind:        conflict.Kind.String(),
+			NodeIds:     nodeIDs,
+			Addresses:   conflict.Addresses,
+			FirstSeenMs: conflict.FirstSeen.UnixMilli(),
+			LastSeenMs:  conflict.LastSeen.UnixMilli(),
+		})
+	}
+	return &gossipProtobuffer.GetClusterStateResponse{
+		NodeId:    string(state.NodeID),
+		ClusterId: state.ClusterID,
+		Endpoints: endpoints,
+		Conflicts: conflicts,
+		Stats: &gossipProtobuffer.GossipStats{
+			Rounds:              state.Stats.Rounds,
+			DigestsSent:         state.Stats.DigestsSent,
+			DigestsReceived:     state.Stats.DigestsReceived,
+			StatesPushed:        state.Stats.StatesPushed,
+			StatesPulled:        state.Stats.StatesPulled,
+			LastRoundDurationUs: state.Stats.LastRoundDuration.Microseconds(),
+			Convergence:         state.Stats.Convergence,
+			PeerLatency:         peerLatencyToProto(state.Stats.PeerLatency),
+		},
+		ReadRepair: &gossipProtobuffer.ReadRepairStats{
+			Reads:      state.ReadRepair.Reads,
+			Checked:    state.ReadRepair.Checked,
+			Mismatches: state.ReadRepair.Mismatches,
+			Repaired:   state.ReadRepair.Repaired,
+			Failed:     state.ReadRepair.Failed,
+		},
+		Storage: &gossipProtobuffer.StorageStats{
+			MemtableKeys:  int64(state.Storage.MemtableKeys),
+			MemtableBytes: state.Storage.MemtableBytes,
+			Sstables:      int64(state.Storage.SSTables),
+			SstableBytes:  state.Storage.SSTableBytes,
+			Flushes:       state.Storage.Flushes,
+			Compactions:   state.Storage.Compactions,
+		},
+	}
+}
+
+func peerLatencyToProto(latencies map[gossip.NodeID]gossip.LatencyHistogram) []*gossipProtobuffer.PeerLatency {
+	peers := make([]*gossipProtobuffer.PeerLatency, 0, len(latencies))
+	for id, h := range latencies {
+		peers = append(peers, &gossipProtobuffer.PeerLatency{
+			NodeId:  string(id),
+			Buckets: h.Buckets,
+			Count:   h.Count,
+			SumUs:   h.Sum.Microseconds(),
+			MaxUs:   h.Max.Microseconds(),
+		})
+	}
+	return peers
+}
+
+func peerLatencyFromProto(peers []*gossipProtobuffer.PeerLatency) map[gossip.NodeID]gossip.LatencyHistogram {
+	latencies := make(map[gossip.NodeID]gossip.LatencyHistogram, len(peers))
+	for _, peer := range peers {
+		latencies[gossip.NodeID(peer.GetNodeId())] = gossip.LatencyHistogram{
+			Buckets: peer.GetBuckets(),
+			Count:   peer.GetCount(),
+			Sum:     time.Duration(peer.GetSumUs()) * time.Microsecond,
+			Max:     time.Duration(peer.GetMaxUs()) * time.Microsecond,
+		}
+	}
+	return latencies
+}
+
+func clusterStateFromProto(resp *gossipProtobuffer.GetClusterStateResponse) ClusterState {
+	endpoints := make([]gossip.EndpointInfo, 0, len(resp.GetEndpoints()))
+	for _, endpoint := range resp.GetEndpoints() {
+		endpoints = append(endpoints, gossip.EndpointInfo{
+			State:           stateFromProto(endpoint.GetState()),
+			Alive:           endpoint.GetAlive(),
+			Phi:             endpoint.GetPhi(),
+			UpdateTimestamp: endpoint.GetUpdateTimestamp(),
+			Local:           endpoint.GetLocal(),
+		})
+	}
+	conflicts := make([]gossip.Conflict, 0, len(resp.GetConflicts()))
+	for _, conflict := range resp.GetConflicts() {
+		nodeIDs := make([]gossip.NodeID, len(conflict.GetNodeIds()))
+		for i, id := range conflict.GetNodeIds() {
+			nodeIDs[i] = gossip.NodeID(id)
+		}
+		conflicts = append(conflicts, gossip.Conflict{
+			Kind:      conflictKindFromProto(conflict.GetKind()),
+			NodeIDs:   nodeIDs,
+			Addresses: conflict.GetAddresses(),
+			FirstSeen: time.UnixMilli(conflict.GetFirstSeenMs()),
+			LastSeen:  time.UnixMilli(conflict.GetLastSeenMs()),
+		})
+	}
+	stats := resp.GetStats()
+	repairs := resp.GetReadRepair()
+	store := resp.GetStorage()
+	return ClusterState{
+		NodeID:    gossip.NodeID(resp.GetNodeId()),
+		ClusterID: resp.GetClusterId(),
+		Endpoints: endpoints,
+		Conflicts: conflicts,
+		Stats: gossip.Stats{
+			Rounds:            stats.GetRounds(),
+			DigestsSent:       stats.GetDigestsSent(),
+			DigestsReceived:   stats.GetDigestsReceived(),
+			StatesPushed:      stats.GetStatesPushed(),
+			StatesPulled:      stats.GetStatesPulled(),
+			LastRoundDuration: time.Duration(stats.GetLastRoundDurationUs()) * time.Microsecond,
+			Convergence:       stats.GetConvergence(),
+			PeerLatency:       peerLatencyFromProto(stats.GetPeerLatency()),
+		},
+		ReadRepair: storage.ReadRepairStats{
+			Reads:      repairs.GetReads(),
+			Checked:    repairs.GetChecked(),
+			Mismatches: repairs.GetMismatches(),
+			Repaired:   repairs.GetRepaired(),
+			Failed:     repairs.GetFailed(),
+		},
+		Storage: storage.Stats{
+			MemtableKeys:  int(store.GetMemtableKeys()),
+			MemtableBytes: store.GetMemtableBytes(),
+			SSTables:      int(store.GetSstables()),
+			SSTableBytes:  store.GetSstableBytes(),
+			Flushes:       store.GetFlushes(),
+			Compactions:   store.GetCompactions(),
+		},
+	}
+}
+
+// conflictKindFromProto parses a gossip.ConflictKind name; unknown kinds are 0
+func conflictKindFromProto(kind string) gossip.ConflictKind {
+	for _, known := range []gossip.ConflictKind{gossip.ConflictDuplicateNodeID, gossip.ConflictDuplicateAddress} {
+		if known.String() == kind {
+			return known
+		}
+	}
+	return 0
+}
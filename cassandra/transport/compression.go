@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultCompressionThreshold is the payload size (bytes) above which messages are compressed.
+// Small digests don't shrink enough to be worth the CPU.
+const DefaultCompressionThreshold = 1024
+
+// CompressionConfig enables compression of gossip messages.
+// Compression is applied per message, only when the encoded message is at least Threshold bytes.
+type CompressionConfig struct {
+	Algorithm string // registered gRPC compressor name, e.g. "gzip"
+	Threshold int    // minimum encoded size in bytes to compress
+}
+
+// Enabled reports whether compression is configured. A nil CompressionConfig means no compression.
+func (c *CompressionConfig) Enabled() bool {
+	return c != nil && c.Algorithm != ""
+}
+
+// Validate checks that the algorithm is registered with gRPC.
+func (c *CompressionConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if encoding.GetCompressor(c.Algorithm) == nil {
+		return fmt.Errorf("unsupported compression algorithm %q (supported: %s)", c.Algorithm, gzip.Name)
+	}
+	if c.Threshold < 0 {
+		return fmt.Errorf("compression threshold must not be negative")
+	}
+	return nil
+}
+
+// WithCompression compresses requests and responses larger than the configured threshold.
+func WithCompression(cfg *CompressionConfig) Option {
+	return func(g *GRPC) {
+		g.compression = cfg
+	}
+}
+
+// shouldCompress reports whether msg is large enough to compress.
+func (c *CompressionConfig) shouldCompress(msg proto.Message) bool {
+	return c.Enabled() && proto.Size(msg) >= c.Threshold
+}
+
+// callOptions returns the per-RPC options for sending msg.
+func (c *CompressionConfig) callOptions(msg proto.Message) []grpc.CallOption {
+	if !c.shouldCompress(msg) {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(c.Algorithm)}
+}
+
+// compressResponse asks gRPC to compress resp if it is large enough. Responses to
+// compressed requests are always compressed, since gRPC reuses the request's compressor.
+func (c *CompressionConfig) compressResponse(ctx context.Context, resp proto.Message) {
+	if c.shouldCompress(resp) {
+		// Fails only if the client doesn't accept the algorithm, then we just send it plain
+		grpc.SetSendCompressor(ctx, c.Algorithm)
+	}
+}
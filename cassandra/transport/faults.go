@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultInjector degrades the links to some peers, to show how the failure detector and
+// convergence behave on a slow or lossy network. It applies to outbound gossip messages
+// (heartbeats, SYN, ACK2); a lost message fails with codes.Unavailable.
+type FaultInjector interface {
+	// Fault returns how long to delay a message to target, and whether to drop it.
+	Fault(target string) (delay time.Duration, drop bool)
+}
+
+// WithFaultInjector delays and drops outbound gossip messages as faults says.
+func WithFaultInjector(faults FaultInjector) Option {
+	return func(g *GRPC) {
+		g.faults = faults
+	}
+}
+
+// linkFault is the degradation of the link to one peer
+type linkFault struct {
+	latency time.Duration
+	loss    float64 // fraction of messages dropped, 0 to 1
+}
+
+// LinkFaults is a FaultInjector with a fixed latency and loss rate per peer address.
+// It can be changed while the transport runs and is safe for concurrent use.
+type LinkFaults struct {
+	mu    sync.RWMutex
+	links map[string]linkFault
+}
+
+var _ FaultInjector = (*LinkFaults)(nil)
+
+// NewLinkFaults creates a LinkFaults that leaves every link alone
+func NewLinkFaults() *LinkFaults {
+	return &LinkFaults{links: make(map[string]linkFault)}
+}
+
+// SetLatency delays every message to target by latency (0 removes the delay)
+func (f *LinkFaults) SetLatency(target string, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	link := f.links[target]
+	link.latency = latency
+	f.set(target, link)
+}
+
+// SetLoss drops the given percentage (0 to 100) of the messages to target
+func (f *LinkFaults) SetLoss(target string, percent float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	link := f.links[target]
+	link.loss = min(max(percent, 0), 100) / 100
+	f.set(target, link)
+}
+
+// set stores link, forgetting links without faults. Caller must hold f.mu.
+func (f *LinkFaults) set(target string, link linkFault) {
+	if link == (linkFault{}) {
+		delete(f.links, target)
+		return
+	}
+	f.links[target] = link
+}
+
+// Clear removes every fault
+func (f *LinkFaults) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.links)
+}
+
+// Degraded reports whether any link has a fault
+func (f *LinkFaults) Degraded() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.links) > 0
+}
+
+func (f *LinkFaults) Fault(target string) (time.Duration, bool) {
+	f.mu.RLock()
+	link := f.links[target]
+	f.mu.RUnlock()
+	return link.latency, link.loss > 0 && rand.Float64() < link.loss
+}
+
+// injectFault waits out the delay faults adds to a message to target, and returns an error
+// if the message is lost or ctx is done first
+func injectFault(ctx context.Context, faults FaultInjector, target string) error {
+	if faults == nil {
+		return nil
+	}
+	delay, drop := faults.Fault(target)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if drop {
+		return status.Errorf(codes.Unavailable, "message to %s lost", target)
+	}
+	return nil
+}
+
+// faultInterceptor delays and drops RPCs to target as faults says
+func faultInterceptor(faults FaultInjector, target string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := injectFault(ctx, faults, target); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// MessageFilter decides which gossip messages a transport lets through, e.g. to simulate a
+// network partition. Outbound messages are matched by the address they are sent to, inbound
+// ones by the sender's node ID, since the source address of a connection isn't the address
+// the peer serves on. Dropped messages fail with codes.Unavailable, like an unreachable peer.
+type MessageFilter interface {
+	AllowSend(target string) bool
+	AllowReceive(sender gossip.NodeID) bool
+}
+
+// WithMessageFilter drops the gossip messages (heartbeats, SYN, ACK2) that filter doesn't allow.
+func WithMessageFilter(filter MessageFilter) Option {
+	return func(g *GRPC) {
+		g.filter = filter
+	}
+}
+
+// BlockList is a MessageFilter that drops messages to and from blocked peers. It can be
+// changed while the transport runs and is safe for concurrent use.
+type BlockList struct {
+	mu      sync.RWMutex
+	addrs   map[string]bool
+	nodeIDs map[gossip.NodeID]bool
+}
+
+var _ MessageFilter = (*BlockList)(nil)
+
+// NewBlockList creates a BlockList that allows everything
+func NewBlockList() *BlockList {
+	return &BlockList{
+		addrs:   make(map[string]bool),
+		nodeIDs: make(map[gossip.NodeID]bool),
+	}
+}
+
+// Block drops messages to addr and from nodeID
+func (b *BlockList) Block(nodeID gossip.NodeID, addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.addrs[addr] = true
+	b.nodeIDs[nodeID] = true
+}
+
+// Clear lets every message through again
+func (b *BlockList) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clear(b.addrs)
+	clear(b.nodeIDs)
+}
+
+// Blocked returns the blocked node IDs
+func (b *BlockList) Blocked() []gossip.NodeID {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	nodeIDs := make([]gossip.NodeID, 0, len(b.nodeIDs))
+	for nodeID := range b.nodeIDs {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs
+}
+
+func (b *BlockList) AllowSend(target string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return !b.addrs[target]
+}
+
+func (b *BlockList) AllowReceive(sender gossip.NodeID) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return !b.nodeIDs[sender]
+}
+
+// checkSend returns the error for a message to target that filter drops, or nil
+func checkSend(filter MessageFilter, target string) error {
+	if filter == nil || filter.AllowSend(target) {
+		return nil
+	}
+	return status.Errorf(codes.Unavailable, "message to %s dropped by filter", target)
+}
+
+// checkReceive returns the error for a message from sender that filter drops, or nil
+func checkReceive(filter MessageFilter, sender string) error {
+	if filter == nil || filter.AllowReceive(gossip.NodeID(sender)) {
+		return nil
+	}
+	return status.Errorf(codes.Unavailable, "message from %s dropped by filter", sender)
+}
+
+// filterInterceptor drops RPCs to target that filter doesn't allow, before they are sent
+func filterInterceptor(filter MessageFilter, target string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := checkSend(filter, target); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -2,28 +2,55 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1" // Import to register proto file descriptors for reflection
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-type GossipHandler interface {
-	HandleHeartbeat(remoteNodeID string, remoteGeneration int64, remoteVersion int64) (localNodeID string, localGeneration int64, localVersion int64, err error)
+// synRejections are the gossip errors a SYN can be rejected with that the initiator acts on
+var synRejections = []error{gossip.ErrSelfConnection, gossip.ErrDuplicateNodeID}
+
+// synRejected turns the message a peer rejected our SYN with back into the gossip error,
+// so the initiator can check it with errors.Is. Other messages are returned as is.
+func synRejected(target string, message string) error {
+	for _, rejection := range synRejections {
+		if strings.HasPrefix(message, rejection.Error()) {
+			return fmt.Errorf("%s rejected SYN: %w", target, rejection)
+		}
+	}
+	return fmt.Errorf("%s rejected SYN: %s", target, message)
 }
 
 type HeartbeatServiceServer struct {
 	gossipProtobuffer.UnimplementedHeartbeatServiceServer
 	handler GossipHandler
 	nodeID  string
+	filter  MessageFilter
 }
 
 // Heartbeat handles heartbeat requests
 func (s *HeartbeatServiceServer) Heartbeat(ctx context.Context, req *gossipProtobuffer.HeartbeatRequest) (*gossipProtobuffer.HeartbeatResponse, error) {
+	if err := checkReceive(s.filter, req.NodeId); err != nil {
+		return nil, err
+	}
+
+	// Older clients only sent their generation in the timestamp field
+	generation := req.Generation
+	if generation == 0 {
+		generation = req.Timestamp
+	}
+
 	// Convert proto → gossip types and call handler
-	localNodeID, _, _, err := s.handler.HandleHeartbeat(
+	localNodeID, localGeneration, localVersion, err := s.handler.HandleHeartbeat(
 		req.NodeId,
-		req.Timestamp,
-		0, // Version not in proto request, using 0 for now
+		generation,
+		req.Version,
 	)
 
 	if err != nil {
@@ -31,9 +58,194 @@ func (s *HeartbeatServiceServer) Heartbeat(ctx context.Context, req *gossipProto
 	}
 
 	// Convert gossip → proto types
-	// Note: proto response only has node_id and timestamp, so we use Generation as timestamp
 	return &gossipProtobuffer.HeartbeatResponse{
-		NodeId:    localNodeID,
-		Timestamp: time.Now().Unix(), // Using Generation as timestamp in response
+		NodeId:     localNodeID,
+		Timestamp:  time.Now().Unix(),
+		Generation: localGeneration,
+		Version:    localVersion,
 	}, nil
 }
+
+type GossipServiceServer struct {
+	gossipProtobuffer.UnimplementedGossipServiceServer
+	handler     GossipHandler
+	nodeID      string
+	compression *CompressionConfig
+	filter      MessageFilter
+}
+
+// Syn handles the first message of a gossip round and answers with an ACK
+func (s *GossipServiceServer) Syn(ctx context.Context, req *gossipProtobuffer.GossipDigestSyn) (*gossipProtobuffer.GossipDigestAck, error) {
+	if err := checkReceive(s.filter, req.GetSenderId()); err != nil {
+		return nil, err
+	}
+	version, err := negotiateVersion(req.GetProtocolVersion())
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	ack, err := s.handler.HandleSyn(ctx, synFromProto(req))
+	if err != nil {
+		return nil, err
+	}
+	resp := ackToProto(ack)
+	resp.ProtocolVersion = version
+	s.compression.compressResponse(ctx, resp)
+	return resp, nil
+}
+
+// Ack2 handles the final message of a gossip round
+func (s *GossipServiceServer) Ack2(ctx context.Context, req *gossipProtobuffer.GossipDigestAck2) (*gossipProtobuffer.GossipDigestAck2Response, error) {
+	if err := checkReceive(s.filter, req.GetSenderId()); err != nil {
+		return nil, err
+	}
+	if err := s.handler.HandleAck2(ctx, ack2FromProto(req)); err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.GossipDigestAck2Response{}, nil
+}
+
+// WatchClusterState streams state updates until the client goes away
+func (s *GossipServiceServer) WatchClusterState(req *gossipProtobuffer.WatchClusterStateRequest, stream grpc.ServerStreamingServer[gossipProtobuffer.ClusterStateUpdate]) error {
+	updates, err := s.handler.WatchClusterState(stream.Context())
+	if err != nil {
+		return err
+	}
+	for update := range updates {
+		if err := stream.Send(stateUpdateToProto(update)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Conversions between gossip and proto types
+
+func digestsToProto(digests []gossip.GossipDigest) []*gossipProtobuffer.GossipDigest {
+	out := make([]*gossipProtobuffer.GossipDigest, 0, len(digests))
+	for _, d := range digests {
+		out = append(out, &gossipProtobuffer.GossipDigest{
+			NodeId:     string(d.NodeID),
+			Generation: d.Generation,
+			MaxVersion: d.MaxVersion,
+		})
+	}
+	return out
+}
+
+func digestsFromProto(digests []*gossipProtobuffer.GossipDigest) []gossip.GossipDigest {
+	out := make([]gossip.GossipDigest, 0, len(digests))
+	for _, d := range digests {
+		out = append(out, gossip.GossipDigest{
+			NodeID:     gossip.NodeID(d.GetNodeId()),
+			Generation: d.GetGeneration(),
+			MaxVersion: d.GetMaxVersion(),
+		})
+	}
+	return out
+}
+
+func statesToProto(states []gossip.EndpointStateSnapshot) []*gossipProtobuffer.EndpointState {
+	out := make([]*gossipProtobuffer.EndpointState, 0, len(states))
+	for _, s := range states {
+		out = append(out, stateToProto(s))
+	}
+	return out
+}
+
+func stateToProto(s gossip.EndpointStateSnapshot) *gossipProtobuffer.EndpointState {
+	appStates := make(map[string]*gossipProtobuffer.VersionedValue, len(s.ApplicationStates))
+	for k, v := range s.ApplicationStates {
+		appStates[string(k)] = &gossipProtobuffer.VersionedValue{Value: v.Value, Version: v.Version}
+	}
+	return &gossipProtobuffer.EndpointState{
+		NodeId:            string(s.HeartbeatState.NodeID),
+		Generation:        s.HeartbeatState.Generation,
+		HeartbeatVersion:  s.HeartbeatState.Version,
+		ApplicationStates: appStates,
+	}
+}
+
+func statesFromProto(states []*gossipProtobuffer.EndpointState) []gossip.EndpointStateSnapshot {
+	out := make([]gossip.EndpointStateSnapshot, 0, len(states))
+	for _, s := range states {
+		out = append(out, stateFromProto(s))
+	}
+	return out
+}
+
+func stateFromProto(s *gossipProtobuffer.EndpointState) gossip.EndpointStateSnapshot {
+	appStates := make(map[gossip.AppStateKey]gossip.AppState, len(s.GetApplicationStates()))
+	for k, v := range s.GetApplicationStates() {
+		appStates[gossip.AppStateKey(k)] = gossip.AppState{Value: v.GetValue(), Version: v.GetVersion()}
+	}
+	return gossip.EndpointStateSnapshot{
+		HeartbeatState: gossip.HeartbeatStateSnapshot{
+			NodeID:     gossip.NodeID(s.GetNodeId()),
+			Generation: s.GetGeneration(),
+			Version:    s.GetHeartbeatVersion(),
+		},
+		ApplicationStates: appStates,
+	}
+}
+
+func synToProto(syn gossip.SynMessage) *gossipProtobuffer.GossipDigestSyn {
+	return &gossipProtobuffer.GossipDigestSyn{
+		ClusterId:     syn.ClusterID,
+		SenderId:      string(syn.SenderID),
+		SenderAddress: syn.SenderAddress,
+		Digests:       digestsToProto(syn.Digests),
+		// Only ProtocolVersion exists so far; encode per version here once another is added
+		ProtocolVersion: ProtocolVersion,
+	}
+}
+
+func synFromProto(syn *gossipProtobuffer.GossipDigestSyn) gossip.SynMessage {
+	return gossip.SynMessage{
+		ClusterID:     syn.GetClusterId(),
+		SenderID:      gossip.NodeID(syn.GetSenderId()),
+		SenderAddress: syn.GetSenderAddress(),
+		Digests:       digestsFromProto(syn.GetDigests()),
+	}
+}
+
+func ackToProto(ack gossip.AckMessage) *gossipProtobuffer.GossipDigestAck {
+	return &gossipProtobuffer.GossipDigestAck{
+		Requests: digestsToProto(ack.Requests),
+		States:   statesToProto(ack.States),
+	}
+}
+
+func ackFromProto(ack *gossipProtobuffer.GossipDigestAck) gossip.AckMessage {
+	return gossip.AckMessage{
+		Requests: digestsFromProto(ack.GetRequests()),
+		States:   statesFromProto(ack.GetStates()),
+	}
+}
+
+func ack2ToProto(ack2 gossip.Ack2Message) *gossipProtobuffer.GossipDigestAck2 {
+	return &gossipProtobuffer.GossipDigestAck2{
+		SenderId: string(ack2.SenderID),
+		States:   statesToProto(ack2.States),
+	}
+}
+
+func ack2FromProto(ack2 *gossipProtobuffer.GossipDigestAck2) gossip.Ack2Message {
+	return gossip.Ack2Message{
+		SenderID: gossip.NodeID(ack2.GetSenderId()),
+		States:   statesFromProto(ack2.GetStates()),
+	}
+}
+
+func stateUpdateToProto(update gossip.StateUpdate) *gossipProtobuffer.ClusterStateUpdate {
+	return &gossipProtobuffer.ClusterStateUpdate{
+		State: stateToProto(update.State),
+		Alive: update.Alive,
+	}
+}
+
+func stateUpdateFromProto(update *gossipProtobuffer.ClusterStateUpdate) gossip.StateUpdate {
+	return gossip.StateUpdate{
+		State: stateFromProto(update.GetState()),
+		Alive: update.GetAlive(),
+	}
+}
@@ -1,16 +1,26 @@
 package transport
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+// GRPC is the gRPC implementation of Transport.
 type GRPC struct {
 	addr          string
 	srv           *grpc.Server
@@ -21,10 +31,126 @@ type GRPC struct {
 	serveErrCh chan error // Channel to receive Serve() errors (for monitoring)
 	stopOnce   sync.Once  // Ensures Stop() is idempotent and thread-safe
 	stopErr    error      // Captured error from lis.Close()
+	tls        *TLSConfig // nil means plaintext
+
+	keepalive   *KeepaliveConfig   // nil means gRPC defaults (no client pings)
+	dialTimeout time.Duration      // 0 means gRPC's default connect timeout
+	compression *CompressionConfig // nil means no compression
+
+	interceptors    []grpc.UnaryServerInterceptor // nil means defaultInterceptors
+	extraServerOpts []grpc.ServerOption           // extra options, applied after ours
+	metrics         *RPCMetrics                   // filled by the default MetricsInterceptor
+	rateLimiter     *RateLimiter                  // nil means inbound gossip is not limited
+	health          *health.Server                // grpc.health.v1, NOT_SERVING until SetServing(true)
+	filter          MessageFilter                 // nil lets every message through
+	faults          FaultInjector                 // nil leaves links to peers alone
+}
+
+// KeepaliveConfig controls keepalive pings on server and client connections,
+// so a peer that silently disappears is detected instead of hanging RPCs.
+type KeepaliveConfig struct {
+	Time    time.Duration // ping after this long without activity (gRPC enforces at least 10s on clients)
+	Timeout time.Duration // close the connection if a ping isn't acknowledged within this
+}
+
+// Option configures optional behaviour of the gRPC transport.
+type Option func(*GRPC)
+
+// WithTLS secures the server with the given TLS configuration.
+// With RequireClientCert set, peers must present a certificate signed by the configured CA.
+func WithTLS(cfg *TLSConfig) Option {
+	return func(g *GRPC) {
+		g.tls = cfg
+	}
 }
 
-func (g *GRPC) setupTcp() (net.Listener, error) {
-	lis, err := net.Listen("tcp", g.addr)
+// ServerOption is an option of the gRPC server, e.g. grpc.MaxRecvMsgSize. Packages
+// configuring the transport, like node, use it without importing grpc themselves.
+type ServerOption = grpc.ServerOption
+
+// WithServerOptions passes extra options (max message sizes, connection limits,
+// stream interceptors, ...) to grpc.NewServer. They are applied after the transport's
+// own options, so options that replace a setting (e.g. grpc.Creds) take precedence.
+func WithServerOptions(opts ...ServerOption) Option {
+	return func(g *GRPC) {
+		g.extraServerOpts = append(g.extraServerOpts, opts...)
+	}
+}
+
+// WithKeepalive enables keepalive pings on the server and on connections dialed to peers.
+func WithKeepalive(cfg *KeepaliveConfig) Option {
+	return func(g *GRPC) {
+		g.keepalive = cfg
+	}
+}
+
+// WithDialTimeout bounds how long establishing a connection to a peer may take.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(g *GRPC) {
+		g.dialTimeout = timeout
+	}
+}
+
+func (g *GRPC) serverOptions(creds credentials.TransportCredentials) []grpc.ServerOption {
+	interceptors := g.interceptors
+	if interceptors == nil {
+		interceptors = g.defaultInterceptors()
+	}
+	opts := []grpc.ServerOption{
+		grpc.Creds(creds),
+		// The trace ID first, so every interceptor sees it, and handler errors mapped to codes last
+		grpc.ChainUnaryInterceptor(append(append([]grpc.UnaryServerInterceptor{traceServerInterceptor}, interceptors...),
+			unavailableServerInterceptor)...),
+		grpc.ChainStreamInterceptor(unavailableStreamInterceptor),
+		// Spans of the requests, children of the caller's (see the tracing package)
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	}
+	if g.keepalive != nil {
+		opts = append(opts,
+			grpc.KeepaliveParams(keepalive.ServerParameters{
+				Time:    g.keepalive.Time,
+				Timeout: g.keepalive.Timeout,
+			}),
+			// Peers ping as often as we do, don't punish them for it
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             g.keepalive.Time / 2,
+				PermitWithoutStream: true,
+			}),
+		)
+	}
+	return append(opts, g.extraServerOpts...)
+}
+
+// dialOptions are the options for connections to target, on top of transport credentials.
+func (g *GRPC) dialOptions(target string) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(traceClientInterceptor),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if g.filter != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(filterInterceptor(g.filter, target)))
+	}
+	if g.faults != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(faultInterceptor(g.faults, target)))
+	}
+	if g.keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                g.keepalive.Time,
+			Timeout:             g.keepalive.Timeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	if g.dialTimeout > 0 {
+		opts = append(opts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: g.dialTimeout,
+		}))
+	}
+	return opts
+}
+
+func (g *GRPC) setupListener() (net.Listener, error) {
+	lis, err := listen(g.addr)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen: %w", err)
@@ -41,8 +167,29 @@ func (g *GRPC) setupServices() error {
 	heartbeatServer := &HeartbeatServiceServer{
 		handler: g.gossipHandler,
 		nodeID:  g.nodeID,
+		filter:  g.filter,
 	}
 	gossipProtobuffer.RegisterHeartbeatServiceServer(g.srv, heartbeatServer)
+
+	gossipServer := &GossipServiceServer{
+		handler:     g.gossipHandler,
+		nodeID:      g.nodeID,
+		compression: g.compression,
+		filter:      g.filter,
+	}
+	gossipProtobuffer.RegisterGossipServiceServer(g.srv, gossipServer)
+
+	// Serve operator requests too if the handler supports them
+	if admin, ok := g.gossipHandler.(AdminHandler); ok {
+		gossipProtobuffer.RegisterAdminServiceServer(g.srv, &AdminServiceServer{handler: admin})
+	}
+	// And the key-value store
+	if kv, ok := g.gossipHandler.(KVHandler); ok {
+		gossipProtobuffer.RegisterKVServiceServer(g.srv, &KVServiceServer{handler: kv})
+	}
+
+	g.health = newHealthServer()
+	healthpb.RegisterHealthServer(g.srv, g.health)
 	return nil
 }
 
@@ -51,9 +198,9 @@ func (g *GRPC) setupServices() error {
 // The caller can check the return value to know if binding succeeded.
 func (g *GRPC) Start() error {
 	// Perform binding synchronously - this will return an error immediately if binding fails
-	lis, err := g.setupTcp()
+	lis, err := g.setupListener()
 	if err != nil {
-		return fmt.Errorf("failed to setup TCP: %w", err)
+		return fmt.Errorf("failed to setup listener: %w", err)
 	}
 	g.lis = lis
 
@@ -81,22 +228,69 @@ func (g *GRPC) Start() error {
 	return nil
 }
 
-// Stop gracefully stops the gRPC server.
+// Stop gracefully stops the gRPC server, waiting for in-flight RPCs and streams to finish.
 // It is idempotent and thread-safe, and returns any error from closing the listener.
 func (g *GRPC) Stop() error {
+	return g.StopContext(context.Background())
+}
+
+// StopContext stops the server like Stop, but once ctx is done it stops waiting for RPCs
+// and streams and closes every connection immediately.
+func (g *GRPC) StopContext(ctx context.Context) error {
 	g.stopOnce.Do(func() {
+		// Tell health watchers we're going away before the connections close
+		if g.health != nil {
+			g.health.Shutdown()
+		}
 		// Stop the gRPC server gracefully (this will unblock Serve())
 		if g.srv != nil {
-			g.srv.GracefulStop()
+			stopped := make(chan struct{})
+			go func() {
+				g.srv.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				g.srv.Stop()
+				<-stopped
+			}
 		}
-		// Close the listener and capture any error
+		// Close the listener and capture any error. GracefulStop usually closed it already.
 		if g.lis != nil {
-			g.stopErr = g.lis.Close()
+			if err := g.lis.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				g.stopErr = err
+			}
 		}
 	})
 	return g.stopErr
 }
 
+// Addr returns the address the server is bound to once started, so a configured
+// port of 0 resolves to the port the OS assigned. Before Start it is the configured address.
+func (g *GRPC) Addr() string {
+	if g.lis != nil && !IsUnixAddress(g.addr) {
+		return g.lis.Addr().String()
+	}
+	return g.addr
+}
+
+// Dial returns a Peer for target using the same TLS settings as the server.
+// The underlying connection is established lazily on the first RPC.
+func (g *GRPC) Dial(target string) (Peer, error) {
+	return dialGRPC(target, g.tls, g.compression, g.dialOptions(target)...)
+}
+
+// RateLimiter returns the inbound gossip rate limiter, or nil if rate limiting is disabled.
+func (g *GRPC) RateLimiter() *RateLimiter {
+	return g.rateLimiter
+}
+
+// Metrics returns the request counters recorded by the default interceptor chain.
+func (g *GRPC) Metrics() *RPCMetrics {
+	return g.metrics
+}
+
 // ServeErrors returns a receive-only channel that receives errors from the gRPC server's Serve() method.
 // Callers should read from this channel to detect post-bind Serve() failures that occur after Start() returns successfully.
 // The channel is buffered and initialized when the server is created, so it's safe to call this method
@@ -105,8 +299,8 @@ func (g *GRPC) ServeErrors() <-chan error {
 	return g.serveErrCh
 }
 
-func NewGRPC(addr string, nodeID string, gossipHandler GossipHandler) (*GRPC, error) {
-	if addr == "" || !strings.Contains(addr, ":") {
+func NewGRPC(addr string, nodeID string, gossipHandler GossipHandler, opts ...Option) (*GRPC, error) {
+	if addr == "" || (!IsUnixAddress(addr) && !strings.Contains(addr, ":")) {
 		return nil, fmt.Errorf("invalid address: %s", addr)
 	}
 
@@ -118,11 +312,33 @@ func NewGRPC(addr string, nodeID string, gossipHandler GossipHandler) (*GRPC, er
 		return nil, fmt.Errorf("gossip handler must be provided")
 	}
 
-	return &GRPC{
+	g := &GRPC{
 		addr:          addr,
-		srv:           grpc.NewServer(),
 		nodeID:        nodeID,
 		gossipHandler: gossipHandler,
 		serveErrCh:    make(chan error, 1), // Buffered channel for serve errors
-	}, nil
+		metrics:       NewRPCMetrics(),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if err := g.tls.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %w", err)
+	}
+	if err := g.compression.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid compression config: %w", err)
+	}
+	creds, err := g.tls.ServerCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server credentials: %w", err)
+	}
+	g.srv = grpc.NewServer(g.serverOptions(creds)...)
+
+	return g, nil
 }
+
+var (
+	_ Transport          = (*GRPC)(nil)
+	_ ServeErrorReporter = (*GRPC)(nil)
+)
@@ -0,0 +1,125 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// grpcPeer is a Peer backed by a gRPC client connection.
+type grpcPeer struct {
+	target    string
+	conn      *grpc.ClientConn
+	heartbeat gossipProtobuffer.HeartbeatServiceClient
+	gossip    gossipProtobuffer.GossipServiceClient
+	kv        gossipProtobuffer.KVServiceClient // see KVPeer
+
+	compression *CompressionConfig
+}
+
+func dialGRPC(target string, tlsConfig *TLSConfig, compression *CompressionConfig, opts ...grpc.DialOption) (*grpcPeer, error) {
+	creds, err := tlsConfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client credentials: %w", err)
+	}
+
+	opts = append(opts, grpc.WithTransportCredentials(creds))
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+
+	return &grpcPeer{
+		target:    target,
+		conn:      conn,
+		heartbeat: gossipProtobuffer.NewHeartbeatServiceClient(conn),
+		gossip:    gossipProtobuffer.NewGossipServiceClient(conn),
+		kv:        gossipProtobuffer.NewKVServiceClient(conn),
+
+		compression: compression,
+	}, nil
+}
+
+func (p *grpcPeer) Target() string {
+	return p.target
+}
+
+func (p *grpcPeer) SendHeartbeat(ctx context.Context, heartbeat gossip.HeartbeatStateSnapshot) (gossip.HeartbeatStateSnapshot, error) {
+	resp, err := p.heartbeat.Heartbeat(ctx, &gossipProtobuffer.HeartbeatRequest{
+		NodeId:     string(heartbeat.NodeID),
+		Timestamp:  heartbeat.Generation,
+		Generation: heartbeat.Generation,
+		Version:    heartbeat.Version,
+	})
+	if err != nil {
+		return gossip.HeartbeatStateSnapshot{}, err
+	}
+
+	return gossip.HeartbeatStateSnapshot{
+		NodeID:     gossip.NodeID(resp.GetNodeId()),
+		Generation: resp.GetGeneration(),
+		Version:    resp.GetVersion(),
+	}, nil
+}
+
+func (p *grpcPeer) SendSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error) {
+	req := synToProto(syn)
+	resp, err := p.gossip.Syn(ctx, req, p.compression.callOptions(req)...)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Code() == codes.Unknown {
+			// Returned by the handler rather than by gRPC
+			return gossip.AckMessage{}, synRejected(p.target, s.Message())
+		}
+		return gossip.AckMessage{}, err
+	}
+	if err := checkAckVersion(resp.GetProtocolVersion()); err != nil {
+		return gossip.AckMessage{}, fmt.Errorf("%s: %w", p.target, err)
+	}
+	return ackFromProto(resp), nil
+}
+
+func (p *grpcPeer) SendAck2(ctx context.Context, ack2 gossip.Ack2Message) error {
+	req := ack2ToProto(ack2)
+	_, err := p.gossip.Ack2(ctx, req, p.compression.callOptions(req)...)
+	return err
+}
+
+func (p *grpcPeer) WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error) {
+	return watchClusterState(ctx, p.gossip)
+}
+
+// watchClusterState streams the cluster state updates of the node client is connected to,
+// until ctx is done or the stream breaks
+func watchClusterState(ctx context.Context, client gossipProtobuffer.GossipServiceClient) (<-chan gossip.StateUpdate, error) {
+	stream, err := client.WatchClusterState(ctx, &gossipProtobuffer.WatchClusterStateRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan gossip.StateUpdate)
+	go func() {
+		defer close(updates)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case updates <- stateUpdateFromProto(resp):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
+
+func (p *grpcPeer) Close() error {
+	return p.conn.Close()
+}
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthReporter is implemented by transports that serve the standard gRPC health
+// service (grpc.health.v1.Health), so orchestrators can probe any node the same way.
+type HealthReporter interface {
+	// SetServing switches the reported status between SERVING and NOT_SERVING.
+	SetServing(serving bool)
+}
+
+// healthServices are the service names the health server reports on. "" is the whole server.
+var healthServices = []string{"", gossipProtobuffer.GossipService_ServiceDesc.ServiceName}
+
+// newHealthServer returns a health server that reports NOT_SERVING until SetServing(true)
+func newHealthServer() *health.Server {
+	srv := health.NewServer()
+	for _, service := range healthServices {
+		srv.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return srv
+}
+
+// SetServing reports the node as SERVING or NOT_SERVING. The server starts NOT_SERVING,
+// so the owner switches it once startup has completed.
+func (g *GRPC) SetServing(serving bool) {
+	if g.health == nil {
+		return
+	}
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	for _, service := range healthServices {
+		g.health.SetServingStatus(service, status)
+	}
+}
+
+// SetServing reports the node as SERVING or NOT_SERVING on the gRPC server running alongside.
+func (u *UDP) SetServing(serving bool) {
+	u.grpc.SetServing(serving)
+}
+
+// CheckHealth asks the node at target whether it is serving. A nil tlsConfig means plaintext.
+func CheckHealth(ctx context.Context, target string, tlsConfig *TLSConfig) (bool, error) {
+	creds, err := tlsConfig.ClientCredentials()
+	if err != nil {
+		return false, fmt.Errorf("failed to load client credentials: %w", err)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING, nil
+}
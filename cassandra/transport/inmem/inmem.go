@@ -0,0 +1,323 @@
+// Package inmem provides a Transport that routes gossip messages between nodes
+// in the same process over channels: no sockets, no ports.
+//
+// Every transport processes its inbound messages one at a time, in the order they
+// were delivered, which keeps multi-node simulations deterministic and cheap enough
+// to run hundreds of nodes in a single test.
+package inmem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+)
+
+// inboxSize bounds how many messages can be queued for a transport before senders block.
+const inboxSize = 64
+
+var (
+	// ErrAddressInUse is returned by Start when another transport is bound to the same address.
+	ErrAddressInUse = errors.New("address already in use")
+	// ErrUnreachable is returned when sending to an address nobody is bound to.
+	ErrUnreachable = errors.New("connection refused")
+	// ErrStopped is returned when the target transport stops before answering.
+	ErrStopped = errors.New("transport stopped")
+	// ErrFiltered is returned for messages dropped by a transport.MessageFilter.
+	ErrFiltered = errors.New("message dropped by filter")
+	// ErrLost is returned for messages dropped by a transport.FaultInjector.
+	ErrLost = errors.New("message lost")
+)
+
+// firstEphemeralPort is where ports are assigned from for transports started on port 0.
+const firstEphemeralPort = 40000
+
+// Network connects in-memory transports by address.
+type Network struct {
+	mu         sync.RWMutex
+	transports map[string]*Transport
+	nextPort   int // next port to try for port 0
+}
+
+// NewNetwork creates an empty network.
+func NewNetwork() *Network {
+	return &Network{
+		transports: make(map[string]*Transport),
+		nextPort:   firstEphemeralPort,
+	}
+}
+
+// NewTransport creates a transport for addr on this network. It is not reachable until Start.
+func (n *Network) NewTransport(addr string, handler transport.GossipHandler) (*Transport, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("address must be provided")
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("gossip handler must be provided")
+	}
+	return &Transport{
+		network: n,
+		addr:    addr,
+		handler: handler,
+		inbox:   make(chan envelope, inboxSize),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+func (n *Network) register(t *Transport) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Like a socket, port 0 means any free port
+	if host, port, err := net.SplitHostPort(t.addr); err == nil && port == "0" {
+		for {
+			addr := net.JoinHostPort(host, strconv.Itoa(n.nextPort))
+			n.nextPort++
+			if _, ok := n.transports[addr]; !ok {
+				t.addr = addr
+				break
+			}
+		}
+	}
+
+	if _, ok := n.transports[t.addr]; ok {
+		return fmt.Errorf("%w: %s", ErrAddressInUse, t.addr)
+	}
+	n.transports[t.addr] = t
+	return nil
+}
+
+func (n *Network) unregister(t *Transport) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.transports[t.addr] == t {
+		delete(n.transports, t.addr)
+	}
+}
+
+func (n *Network) lookup(addr string) (*Transport, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	t, ok := n.transports[addr]
+	return t, ok
+}
+
+// envelope is a single inbound message: call runs the handler, reply is closed once it has.
+type envelope struct {
+	call  func()
+	reply chan struct{}
+}
+
+// Transport is an in-memory implementation of transport.Transport.
+type Transport struct {
+	network *Network
+	addr    string
+	handler transport.GossipHandler
+	filter  transport.MessageFilter // nil lets every message through
+	faults  transport.FaultInjector // nil leaves links to peers alone
+
+	inbox    chan envelope
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ transport.Transport = (*Transport)(nil)
+
+// SetMessageFilter drops the messages to and from peers that filter doesn't allow,
+// see transport.WithMessageFilter. Set it before Start.
+func (t *Transport) SetMessageFilter(filter transport.MessageFilter) {
+	t.filter = filter
+}
+
+// SetFaultInjector delays and drops the gossip messages sent to peers as faults says,
+// see transport.WithFaultInjector. Set it before Start.
+func (t *Transport) SetFaultInjector(faults transport.FaultInjector) {
+	t.faults = faults
+}
+
+// Start registers the transport on the network and starts processing its inbox.
+func (t *Transport) Start() error {
+	if err := t.network.register(t); err != nil {
+		return err
+	}
+	go t.serve()
+	return nil
+}
+
+func (t *Transport) serve() {
+	for {
+		select {
+		case <-t.done:
+			return
+		case env := <-t.inbox:
+			env.call()
+			close(env.reply)
+		}
+	}
+}
+
+// Stop removes the transport from the network. It is idempotent.
+func (t *Transport) Stop() error {
+	t.stopOnce.Do(func() {
+		t.network.unregister(t)
+		close(t.done)
+	})
+	return nil
+}
+
+// Addr returns the address the transport is registered under. A port of 0 is resolved by Start.
+func (t *Transport) Addr() string {
+	return t.addr
+}
+
+// Dial returns a peer for target. Like a lazy gRPC connection, it succeeds even if
+// nothing is bound to target yet; sends fail with ErrUnreachable instead.
+func (t *Transport) Dial(target string) (transport.Peer, error) {
+	return &peer{network: t.network, target: target, filter: t.filter, faults: t.faults}, nil
+}
+
+// deliver queues call on the transport at target and waits for it to run.
+func (n *Network) deliver(ctx context.Context, target string, call func()) error {
+	t, ok := n.lookup(target)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnreachable, target)
+	}
+
+	env := envelope{call: call, reply: make(chan struct{})}
+	select {
+	case t.inbox <- env:
+	case <-t.done:
+		return fmt.Errorf("%w: %s", ErrStopped, target)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-env.reply:
+		return nil
+	case <-t.done:
+		return fmt.Errorf("%w: %s", ErrStopped, target)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// peer sends messages to the transport registered at target.
+type peer struct {
+	network *Network
+	target  string
+	filter  transport.MessageFilter // the sender's filter
+	faults  transport.FaultInjector // the sender's faults
+}
+
+func (p *peer) Target() string {
+	return p.target
+}
+
+func (p *peer) SendHeartbeat(ctx context.Context, heartbeat gossip.HeartbeatStateSnapshot) (gossip.HeartbeatStateSnapshot, error) {
+	var resp gossip.HeartbeatStateSnapshot
+	var handlerErr error
+	err := p.withHandler(ctx, heartbeat.NodeID, func(handler transport.GossipHandler) {
+		nodeID, generation, version, err := handler.HandleHeartbeat(string(heartbeat.NodeID), heartbeat.Generation, heartbeat.Version)
+		resp = gossip.HeartbeatStateSnapshot{NodeID: gossip.NodeID(nodeID), Generation: generation, Version: version}
+		handlerErr = err
+	})
+	if err != nil {
+		return gossip.HeartbeatStateSnapshot{}, err
+	}
+	return resp, handlerErr
+}
+
+func (p *peer) SendSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error) {
+	var ack gossip.AckMessage
+	var handlerErr error
+	err := p.withHandler(ctx, syn.SenderID, func(handler transport.GossipHandler) {
+		ack, handlerErr = handler.HandleSyn(ctx, syn)
+	})
+	if err != nil {
+		return gossip.AckMessage{}, err
+	}
+	return ack, handlerErr
+}
+
+func (p *peer) SendAck2(ctx context.Context, ack2 gossip.Ack2Message) error {
+	var handlerErr error
+	err := p.withHandler(ctx, ack2.SenderID, func(handler transport.GossipHandler) {
+		handlerErr = handler.HandleAck2(ctx, ack2)
+	})
+	if err != nil {
+		return err
+	}
+	return handlerErr
+}
+
+// WatchClusterState subscribes directly to the target's handler. Only the
+// subscription goes through the inbox, updates are streamed from the handler.
+func (p *peer) WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error) {
+	var updates <-chan gossip.StateUpdate
+	var handlerErr error
+	err := p.withHandler(ctx, "", func(handler transport.GossipHandler) {
+		updates, handlerErr = handler.WatchClusterState(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updates, handlerErr
+}
+
+// withHandler delivers fn to the target's inbox, passing it the target's handler.
+// Messages from sender that either side's filter drops are not delivered; an empty
+// sender is only checked by the sending side. Faults only apply to gossip messages,
+// which have a sender.
+func (p *peer) withHandler(ctx context.Context, sender gossip.NodeID, fn func(transport.GossipHandler)) error {
+	if p.filter != nil && !p.filter.AllowSend(p.target) {
+		return fmt.Errorf("%w: to %s", ErrFiltered, p.target)
+	}
+	if sender != "" {
+		if err := p.injectFault(ctx); err != nil {
+			return err
+		}
+	}
+	t, ok := p.network.lookup(p.target)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnreachable, p.target)
+	}
+	if sender != "" && t.filter != nil && !t.filter.AllowReceive(sender) {
+		return fmt.Errorf("%w: from %s", ErrFiltered, sender)
+	}
+	return p.network.deliver(ctx, p.target, func() {
+		fn(t.handler)
+	})
+}
+
+// injectFault waits out the delay the sender's faults add to a message to the target,
+// and returns ErrLost if they drop it
+func (p *peer) injectFault(ctx context.Context) error {
+	if p.faults == nil {
+		return nil
+	}
+	delay, drop := p.faults.Fault(p.target)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if drop {
+		return fmt.Errorf("%w: to %s", ErrLost, p.target)
+	}
+	return nil
+}
+
+func (p *peer) Close() error {
+	return nil
+}
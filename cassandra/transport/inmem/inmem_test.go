@@ -0,0 +1,182 @@
+package inmem_test
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/node"
+	"github.com/adamgarcia4/goLearning/cassandra/transport"
+	"github.com/adamgarcia4/goLearning/cassandra/transport/inmem"
+)
+
+const (
+	// maxRounds bounds the gossip rounds a test waits for the cluster to converge
+	maxRounds = 50
+	// roundInterval is the wait between rounds, longer than the nodes' reconnect backoff so
+	// peers cut off by a partition are redialed on the next round once it heals
+	roundInterval = 2 * time.Millisecond
+)
+
+// testNode is a node on the test network and the filter partitioning it
+type testNode struct {
+	*node.Node
+	filter *transport.BlockList
+}
+
+// startCluster starts count nodes on one in-memory network, the first one seeding the
+// others. They only gossip when runRounds runs a round.
+func startCluster(t *testing.T, count int) []testNode {
+	t.Helper()
+	network := inmem.NewNetwork()
+	var seeds []string
+	var nodes []testNode
+	for i := range count {
+		config := node.DefaultConfig(gossip.NodeID(fmt.Sprintf("node-%d", i+1)))
+		config.Address = "127.0.0.1"
+		config.Port = strconv.Itoa(50051 + i)
+		config.Transport = node.InMemoryTransport(network)
+		config.ManualHeartbeat = true
+		config.ReconnectBackoff = time.Millisecond
+		config.MaxReconnectBackoff = time.Millisecond
+		config.MaxReconnectAttempts = 0 // keep redialing across the partition
+		filter := transport.NewBlockList()
+		config.MessageFilter = filter
+		if i == 0 {
+			seeds = []string{config.GetAddress()}
+		}
+		config.Seeds = seeds
+
+		n, err := node.New(config)
+		if err != nil {
+			t.Fatalf("creating node %d: %v", i+1, err)
+		}
+		if err := n.Start(); err != nil {
+			t.Fatalf("starting node %d: %v", i+1, err)
+		}
+		t.Cleanup(func() { n.Stop() })
+		nodes = append(nodes, testNode{Node: n, filter: filter})
+	}
+	return nodes
+}
+
+// runRounds runs gossip rounds on every node at once until done holds for every node, and
+// returns the rounds it took, or maxRounds+1 if it never did
+func runRounds(t *testing.T, nodes []testNode, done func(testNode) bool) int {
+	t.Helper()
+	for round := 1; round <= maxRounds; round++ {
+		var wg sync.WaitGroup
+		for _, n := range nodes {
+			wg.Go(func() {
+				if err := n.SendGossipRound(); err != nil {
+					t.Errorf("%s: %v", n.GetConfig().NodeID, err)
+				}
+			})
+		}
+		wg.Wait()
+
+		converged := true
+		for _, n := range nodes {
+			converged = converged && done(n)
+		}
+		if converged {
+			return round
+		}
+		time.Sleep(roundInterval)
+	}
+	return maxRounds + 1
+}
+
+// knowsAll reports whether n knows every other of count nodes
+func knowsAll(count int) func(testNode) bool {
+	return func(n testNode) bool {
+		return len(n.GetGossipState().GetStateByNode()) == count-1
+	}
+}
+
+// hasAppState reports whether n, or what n knows about source, has key set to value
+func hasAppState(source gossip.NodeID, key gossip.AppStateKey, value string) func(testNode) bool {
+	return func(n testNode) bool {
+		if n.GetConfig().NodeID == source {
+			return true
+		}
+		state, ok := n.GetGossipState().GetEndpointState(source)
+		if !ok {
+			return false
+		}
+		appState, ok := state.GetApplicationState(key)
+		return ok && appState.Value == value
+	}
+}
+
+func TestClusterConverges(t *testing.T) {
+	const count = 20
+	nodes := startCluster(t, count)
+
+	if rounds := runRounds(t, nodes, knowsAll(count)); rounds > maxRounds {
+		t.Fatalf("%d nodes didn't all learn about each other within %d rounds", count, maxRounds)
+	}
+
+	source := nodes[count-1].GetConfig().NodeID
+	if _, err := nodes[count-1].SetAppState("LOAD", "42"); err != nil {
+		t.Fatal(err)
+	}
+	if rounds := runRounds(t, nodes, hasAppState(source, "LOAD", "42")); rounds > maxRounds {
+		t.Fatalf("LOAD=42 set on %s didn't reach every node within %d rounds", source, maxRounds)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	const count = 6
+	nodes := startCluster(t, count)
+	if rounds := runRounds(t, nodes, knowsAll(count)); rounds > maxRounds {
+		t.Fatalf("%d nodes didn't all learn about each other within %d rounds", count, maxRounds)
+	}
+
+	// Split the cluster in two halves that drop each other's messages
+	left, right := nodes[:count/2], nodes[count/2:]
+	for _, a := range left {
+		for _, b := range right {
+			a.filter.Block(b.GetConfig().NodeID, b.GetConfig().GetAddress())
+			b.filter.Block(a.GetConfig().NodeID, a.GetConfig().GetAddress())
+		}
+	}
+
+	source := left[0].GetConfig().NodeID
+	if _, err := left[0].SetAppState("LOAD", "42"); err != nil {
+		t.Fatal(err)
+	}
+	if rounds := runRounds(t, nodes, func(n testNode) bool {
+		return !isIn(n, left) || hasAppState(source, "LOAD", "42")(n)
+	}); rounds > maxRounds {
+		t.Fatalf("LOAD=42 set on %s didn't reach its side of the partition within %d rounds", source, maxRounds)
+	}
+	// A few more rounds give it every chance to leak across
+	runRounds(t, nodes, func(testNode) bool { return false })
+	for _, n := range right {
+		if hasAppState(source, "LOAD", "42")(n) {
+			t.Errorf("LOAD=42 set on %s crossed the partition to %s", source, n.GetConfig().NodeID)
+		}
+	}
+
+	// Healed, the other side catches up
+	for _, n := range nodes {
+		n.filter.Clear()
+	}
+	if rounds := runRounds(t, nodes, hasAppState(source, "LOAD", "42")); rounds > maxRounds {
+		t.Fatalf("LOAD=42 set on %s didn't reach every node within %d rounds of healing", source, maxRounds)
+	}
+}
+
+// isIn reports whether n is one of nodes
+func isIn(n testNode, nodes []testNode) bool {
+	for _, other := range nodes {
+		if other.Node == n.Node {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// LogMode selects which requests LoggingInterceptor logs.
+type LogMode int
+
+const (
+	LogErrors LogMode = iota // only failed requests (gossip runs every second, logging all of it is noisy)
+	LogAll                   // every request
+)
+
+// WithUnaryInterceptors replaces the default interceptor chain (logging, metrics, rate limiting, panic recovery).
+// Interceptors run in the order given, the first one outermost.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(g *GRPC) {
+		g.interceptors = interceptors
+	}
+}
+
+// defaultInterceptors logs failures, records metrics, applies the rate limit (if any) and
+// recovers panics. Recovery is innermost so the other interceptors see a panic as a
+// codes.Internal error, and rate limiting is inside metrics so rejections are counted.
+func (g *GRPC) defaultInterceptors() []grpc.UnaryServerInterceptor {
+	interceptors := []grpc.UnaryServerInterceptor{
+		LoggingInterceptor(g.nodeID, LogErrors),
+		MetricsInterceptor(g.metrics),
+	}
+	if g.rateLimiter != nil {
+		interceptors = append(interceptors, RateLimitInterceptor(g.rateLimiter))
+	}
+	return append(interceptors, RecoveryInterceptor(g.nodeID))
+}
+
+// LoggingInterceptor logs requests with their method, peer, code, duration and trace ID (see
+// logger.WithTraceID) as fields, as the transport component of the node.
+// Failed requests are logged as warnings.
+func LoggingInterceptor(nodeID string, mode LogMode) grpc.UnaryServerInterceptor {
+	log := logger.Named("transport").WithNode(nodeID)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		if code == codes.OK && mode != LogAll {
+			return resp, err
+		}
+
+		remote := "unknown"
+		if p, ok := peer.FromContext(ctx); ok {
+			remote = p.Addr.String()
+		}
+		request := log.WithContext(ctx).With("method", info.FullMethod, "peer", remote, "code", code, "duration", time.Since(start))
+		if err != nil {
+			request.With("error", status.Convert(err).Message()).Warnf("rpc")
+		} else {
+			request.Printf("rpc")
+		}
+		return resp, err
+	}
+}
+
+// unavailableServerInterceptor answers handler errors wrapping ErrUnavailable with
+// codes.Unavailable. It runs after the other interceptors, even those of
+// WithUnaryInterceptors, so they see the code the client gets.
+func unavailableServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, unavailableStatus(err)
+}
+
+// unavailableStreamInterceptor is unavailableServerInterceptor for streams
+func unavailableStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return unavailableStatus(handler(srv, ss))
+}
+
+// unavailableStatus turns err into a codes.Unavailable status if it wraps ErrUnavailable
+func unavailableStatus(err error) error {
+	if err != nil && errors.Is(err, ErrUnavailable) {
+		if _, ok := status.FromError(err); !ok {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+	return err
+}
+
+// RecoveryInterceptor turns a panicking handler into a codes.Internal error instead of crashing the node.
+func RecoveryInterceptor(nodeID string) grpc.UnaryServerInterceptor {
+	log := logger.Named("transport").WithNode(nodeID)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+				resp, err = nil, status.Errorf(codes.Internal, "internal error in %s", info.FullMethod)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// MetricsInterceptor counts requests, errors and latency per method into metrics.
+func MetricsInterceptor(metrics *RPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.record(info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// MethodStats are the counters for one RPC method.
+type MethodStats struct {
+	Method       string
+	Requests     int64
+	Errors       int64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AvgLatency returns the mean latency per request.
+func (s MethodStats) AvgLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Requests)
+}
+
+// RPCMetrics holds request counters for a server, keyed by full method name.
+type RPCMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// NewRPCMetrics creates an empty set of counters.
+func NewRPCMetrics() *RPCMetrics {
+	return &RPCMetrics{
+		methods: make(map[string]*MethodStats),
+	}
+}
+
+func (m *RPCMetrics) record(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{Method: method}
+		m.methods[method] = stats
+	}
+	stats.Requests++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
+
+// Snapshot returns a copy of the counters, sorted by method.
+func (m *RPCMetrics) Snapshot() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]MethodStats, 0, len(m.methods))
+	for _, stats := range m.methods {
+		snapshot = append(snapshot, *stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Method < snapshot[j].Method
+	})
+	return snapshot
+}
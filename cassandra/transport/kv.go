@@ -0,0 +1,530 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+	"github.com/adamgarcia4/goLearning/cassandra/storage"
+)
+
+const (
+	// StreamRanges sends the versions it streams in chunks of at most this many versions, or
+	// about this many bytes
+	streamChunkVersions = 256
+	streamChunkBytes    = 1 << 20
+)
+
+// KVRequest is a key-value request, as the KVService receives it
+type KVRequest struct {
+	Key         string
+	Value       []byte        // the value set by a put
+	TTL         time.Duration // how long after the put its value expires, 0 for never
+	Timestamp   int64         // of a forwarded put or delete, in microseconds since the epoch
+	Consistency storage.ConsistencyLevel
+	Forwarded   bool // sent by the coordinator to a replica, to be served locally
+	Trace       bool // record the request's events, see KVHandler.GetTrace
+}
+
+// KVResult is the outcome of a key-value request
+type KVResult struct {
+	Value     []byte          // the value read by a get
+	Found     bool            // whether the key was set, for a get or a delete, see Deleted
+	Timestamp int64           // when the value read by a get was written
+	TTL       time.Duration   // how long after Timestamp the value read by a get expires, 0 for never
+	Deleted   bool            // for a forwarded get, whether the replica holds a tombstone of the key
+	Owner     gossip.NodeID   // the node that served the request, the key's first replica for a coordinator
+	Replicas  []gossip.NodeID // the replicas that answered the coordinator in time
+	TraceID   string          // of the request's trace, when traced
+}
+
+// KeyVersion is the version of a key a replica holds, streamed by StreamRanges, or a live
+// key returned by a scan
+type KeyVersion struct {
+	Key  string
+	Cell storage.Cell
+}
+
+// ScanRequest is a scan of the live keys of the cluster
+type ScanRequest struct {
+	Consistency storage.ConsistencyLevel
+	Limit       int  // most keys returned, 0 for all of them
+	Trace       bool // record the scan's events, see KVHandler.GetTrace
+}
+
+// ScanResult is the outcome of a scan
+type ScanResult struct {
+	Entries   []KeyVersion    // the live keys and their values, in token order
+	Truncated bool            // the limit left keys out
+	Replicas  []gossip.NodeID // the replicas that answered the coordinator in time
+	TraceID   string          // of the scan's trace, when traced
+}
+
+// Trace is what happened to a request a node coordinated with tracing on, see
+// storage.Trace
+type Trace struct {
+	ID          string
+	Request     string
+	Coordinator gossip.NodeID
+	Started     time.Time
+	Events      []storage.TraceEvent
+}
+
+// KVHandler is implemented by whatever serves the key-value store. If the GossipHandler
+// passed to NewGRPC also implements KVHandler, the KVService is served alongside gossip.
+// The node taking a request coordinates it, sending it to every replica of its key;
+// forwarded requests are served locally, so nodes disagreeing on the replicas don't
+// forward them back and forth.
+type KVHandler interface {
+	GetKey(ctx context.Context, req KVRequest) (KVResult, error)
+	PutKey(ctx context.Context, req KVRequest) (KVResult, error)
+	DeleteKey(ctx context.Context, req KVRequest) (KVResult, error)
+	// ScanKeys returns up to req.Limit live keys of the cluster, every one if it is 0
+	ScanKeys(ctx context.Context, req ScanRequest) (ScanResult, error)
+	// GetTrace returns the trace with the ID id of a request the node coordinated
+	GetTrace(ctx context.Context, id string) (Trace, error)
+	// StreamRanges passes send the version of every key held in ranges, tombstones included
+	StreamRanges(ctx context.Context, ranges []ring.Range, send func(KeyVersion) error) error
+}
+
+// KVPeer is implemented by peers that forward key-value requests to the node they are
+// connected to, a replica of the key, see KVHandler
+type KVPeer interface {
+	ForwardGet(ctx context.Context, key string) (KVResult, error)
+	ForwardPut(ctx context.Context, key string, value []byte, timestamp int64, ttl time.Duration) (KVResult, error)
+	ForwardDelete(ctx context.Context, key string, timestamp int64) (KVResult, error)
+	// StreamRanges passes receive the version of every key the peer holds in ranges, until
+	// they are all streamed or receive fails
+	StreamRanges(ctx context.Context, ranges []ring.Range, receive func(KeyVersion) error) error
+}
+
+var (
+	_ KVPeer = (*grpcPeer)(nil)
+	_ KVPeer = (*udpPeer)(nil)
+)
+
+type KVServiceServer struct {
+	gossipProtobuffer.UnimplementedKVServiceServer
+	handler KVHandler
+}
+
+// Get returns the value of a key
+func (s *KVServiceServer) Get(ctx context.Context, req *gossipProtobuffer.GetRequest) (*gossipProtobuffer.GetResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key must be provided")
+	}
+	result, err := s.handler.GetKey(ctx, KVRequest{
+		Key:         req.GetKey(),
+		Consistency: consistencyFromProto(req.GetConsistency()),
+		Forwarded:   req.GetForwarded(),
+		Trace:       req.GetTrace(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.GetResponse{
+		Value:     result.Value,
+		Found:     result.Found,
+		Owner:     string(result.Owner),
+		Timestamp: result.Timestamp,
+		Replicas:  nodeIDsToProto(result.Replicas),
+		TtlMs:     result.TTL.Milliseconds(),
+		Deleted:   result.Deleted,
+		TraceId:   result.TraceID,
+	}, nil
+}
+
+// Put sets a key
+func (s *KVServiceServer) Put(ctx context.Context, req *gossipProtobuffer.PutRequest) (*gossipProtobuffer.PutResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key must be provided")
+	}
+	if req.GetTtlMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl must not be negative")
+	}
+	result, err := s.handler.PutKey(ctx, KVRequest{
+		Key:         req.GetKey(),
+		Value:       req.GetValue(),
+		TTL:         time.Duration(req.GetTtlMs()) * time.Millisecond,
+		Timestamp:   req.GetTimestamp(),
+		Consistency: consistencyFromProto(req.GetConsistency()),
+		Forwarded:   req.GetForwarded(),
+		Trace:       req.GetTrace(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.PutResponse{
+		Owner:    string(result.Owner),
+		Replicas: nodeIDsToProto(result.Replicas),
+		TraceId:  result.TraceID,
+	}, nil
+}
+
+// Delete removes a key
+func (s *KVServiceServer) Delete(ctx context.Context, req *gossipProtobuffer.DeleteRequest) (*gossipProtobuffer.DeleteResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key must be provided")
+	}
+	result, err := s.handler.DeleteKey(ctx, KVRequest{
+		Key:         req.GetKey(),
+		Timestamp:   req.GetTimestamp(),
+		Consistency: consistencyFromProto(req.GetConsistency()),
+		Forwarded:   req.GetForwarded(),
+		Trace:       req.GetTrace(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gossipProtobuffer.DeleteResponse{
+		Found:    result.Found,
+		Owner:    string(result.Owner),
+		Replicas: nodeIDsToProto(result.Replicas),
+		TraceId:  result.TraceID,
+	}, nil
+}
+
+// Scan returns the live keys of the cluster
+func (s *KVServiceServer) Scan(ctx context.Context, req *gossipProtobuffer.ScanRequest) (*gossipProtobuffer.ScanResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	result, err := s.handler.ScanKeys(ctx, ScanRequest{
+		Consistency: consistencyFromProto(req.GetConsistency()),
+		Limit:       int(req.GetLimit()),
+		Trace:       req.GetTrace(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &gossipProtobuffer.ScanResponse{
+		Truncated: result.Truncated,
+		Replicas:  nodeIDsToProto(result.Replicas),
+		TraceId:   result.TraceID,
+	}
+	for _, entry := range result.Entries {
+		resp.Entries = append(resp.Entries, keyVersionToProto(entry))
+	}
+	return resp, nil
+}
+
+// GetTrace returns the trace of a request the node coordinated
+func (s *KVServiceServer) GetTrace(ctx context.Context, req *gossipProtobuffer.GetTraceRequest) (*gossipProtobuffer.GetTraceResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "trace ID must be provided")
+	}
+	trace, err := s.handler.GetTrace(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	resp := &gossipProtobuffer.GetTraceResponse{
+		Id:          trace.ID,
+		Request:     trace.Request,
+		Coordinator: string(trace.Coordinator),
+		StartedAtMs: trace.Started.UnixMilli(),
+	}
+	for _, event := range trace.Events {
+		resp.Events = append(resp.Events, &gossipProtobuffer.TraceEvent{
+			ElapsedUs: event.Elapsed.Microseconds(),
+			Source:    string(event.Source),
+			Activity:  event.Activity,
+		})
+	}
+	return resp, nil
+}
+
+// StreamRanges streams the versions of the keys in token ranges, in chunks
+func (s *KVServiceServer) StreamRanges(req *gossipProtobuffer.StreamRangesRequest, stream grpc.ServerStreamingServer[gossipProtobuffer.StreamRangesChunk]) error {
+	ranges := make([]ring.Range, len(req.GetRanges()))
+	for i, r := range req.GetRanges() {
+		ranges[i] = ring.Range{Start: ring.Token(r.GetStart()), End: ring.Token(r.GetEnd())}
+	}
+
+	var chunk []*gossipProtobuffer.KeyVersion
+	size := 0
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		err := stream.Send(&gossipProtobuffer.StreamRangesChunk{Versions: chunk})
+		chunk, size = nil, 0
+		return err
+	}
+	err := s.handler.StreamRanges(stream.Context(), ranges, func(version KeyVersion) error {
+		chunk = append(chunk, keyVersionToProto(version))
+		size += len(version.Key) + len(version.Cell.Value)
+		if len(chunk) == streamChunkVersions || size >= streamChunkBytes {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// ForwardGet asks the peer, a replica of key, for its newest version of it, a tombstone
+// included
+func (p *grpcPeer) ForwardGet(ctx context.Context, key string) (KVResult, error) {
+	return kvGet(ctx, p.kv, KVRequest{Key: key, Forwarded: true})
+}
+
+// ForwardPut sets key on the peer, a replica of key, unless it holds a newer write
+func (p *grpcPeer) ForwardPut(ctx context.Context, key string, value []byte, timestamp int64, ttl time.Duration) (KVResult, error) {
+	return kvPut(ctx, p.kv, KVRequest{Key: key, Value: value, TTL: ttl, Timestamp: timestamp, Forwarded: true})
+}
+
+// ForwardDelete writes a tombstone for key at timestamp on the peer, a replica of key
+func (p *grpcPeer) ForwardDelete(ctx context.Context, key string, timestamp int64) (KVResult, error) {
+	return kvDelete(ctx, p.kv, KVRequest{Key: key, Timestamp: timestamp, Forwarded: true})
+}
+
+// StreamRanges streams the versions of the keys the peer holds in ranges
+func (p *grpcPeer) StreamRanges(ctx context.Context, ranges []ring.Range, receive func(KeyVersion) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the stream if receive fails
+	req := &gossipProtobuffer.StreamRangesRequest{}
+	for _, r := range ranges {
+		req.Ranges = append(req.Ranges, &gossipProtobuffer.TokenRange{Start: int64(r.Start), End: int64(r.End)})
+	}
+	stream, err := p.kv.StreamRanges(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, version := range chunk.GetVersions() {
+			if err := receive(keyVersionFromProto(version)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// KVClient calls the KVService of a running node, which forwards the requests for keys
+// it doesn't own.
+type KVClient struct {
+	// Trace asks the coordinator to trace every request, whose trace ID the results carry,
+	// see GetTrace
+	Trace bool
+
+	conn   *grpc.ClientConn
+	client gossipProtobuffer.KVServiceClient
+}
+
+// DialKV connects to the KVService at target. A nil tlsConfig means plaintext.
+func DialKV(target string, tlsConfig *TLSConfig) (*KVClient, error) {
+	creds, err := tlsConfig.ClientCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client credentials: %w", err)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+
+	return &KVClient{
+		conn:   conn,
+		client: gossipProtobuffer.NewKVServiceClient(conn),
+	}, nil
+}
+
+// Get returns the value of key, read from as many replicas as cl asks for
+func (c *KVClient) Get(ctx context.Context, key string, cl storage.ConsistencyLevel) (KVResult, error) {
+	return kvGet(ctx, c.client, KVRequest{Key: key, Consistency: cl, Trace: c.Trace})
+}
+
+// Put sets key to value, on as many replicas as cl asks for before returning. A ttl other
+// than 0 expires the value that long after it is written.
+func (c *KVClient) Put(ctx context.Context, key string, value []byte, ttl time.Duration, cl storage.ConsistencyLevel) (KVResult, error) {
+	return kvPut(ctx, c.client, KVRequest{Key: key, Value: value, TTL: ttl, Consistency: cl, Trace: c.Trace})
+}
+
+// Delete removes key, from as many replicas as cl asks for before returning
+func (c *KVClient) Delete(ctx context.Context, key string, cl storage.ConsistencyLevel) (KVResult, error) {
+	return kvDelete(ctx, c.client, KVRequest{Key: key, Consistency: cl, Trace: c.Trace})
+}
+
+// Scan returns up to limit live keys of the cluster and their values, every one if limit is
+// 0, in token order. Every token range is read from as many of its replicas as cl asks for.
+func (c *KVClient) Scan(ctx context.Context, cl storage.ConsistencyLevel, limit int) (ScanResult, error) {
+	resp, err := c.client.Scan(ctx, &gossipProtobuffer.ScanRequest{
+		Consistency: consistencyToProto(cl),
+		Limit:       int32(limit),
+		Trace:       c.Trace,
+	})
+	if err != nil {
+		return ScanResult{}, err
+	}
+	result := ScanResult{
+		Truncated: resp.GetTruncated(),
+		Replicas:  nodeIDsFromProto(resp.GetReplicas()),
+		TraceID:   resp.GetTraceId(),
+	}
+	for _, entry := range resp.GetEntries() {
+		result.Entries = append(result.Entries, keyVersionFromProto(entry))
+	}
+	return result, nil
+}
+
+// GetTrace returns the trace with the ID id of a request the node coordinated, if it still
+// keeps it
+func (c *KVClient) GetTrace(ctx context.Context, id string) (Trace, error) {
+	resp, err := c.client.GetTrace(ctx, &gossipProtobuffer.GetTraceRequest{Id: id})
+	if err != nil {
+		return Trace{}, err
+	}
+	trace := Trace{
+		ID:          resp.GetId(),
+		Request:     resp.GetRequest(),
+		Coordinator: gossip.NodeID(resp.GetCoordinator()),
+		Started:     time.UnixMilli(resp.GetStartedAtMs()),
+	}
+	for _, event := range resp.GetEvents() {
+		trace.Events = append(trace.Events, storage.TraceEvent{
+			Elapsed:  time.Duration(event.GetElapsedUs()) * time.Microsecond,
+			Source:   gossip.NodeID(event.GetSource()),
+			Activity: event.GetActivity(),
+		})
+	}
+	return trace, nil
+}
+
+// Close releases the connection.
+func (c *KVClient) Close() error {
+	return c.conn.Close()
+}
+
+func kvGet(ctx context.Context, client gossipProtobuffer.KVServiceClient, req KVRequest) (KVResult, error) {
+	resp, err := client.Get(ctx, &gossipProtobuffer.GetRequest{
+		Key:         req.Key,
+		Forwarded:   req.Forwarded,
+		Consistency: consistencyToProto(req.Consistency),
+		Trace:       req.Trace,
+	})
+	if err != nil {
+		return KVResult{}, err
+	}
+	return KVResult{
+		Value:     resp.GetValue(),
+		Found:     resp.GetFound(),
+		Timestamp: resp.GetTimestamp(),
+		TTL:       time.Duration(resp.GetTtlMs()) * time.Millisecond,
+		Deleted:   resp.GetDeleted(),
+		Owner:     gossip.NodeID(resp.GetOwner()),
+		Replicas:  nodeIDsFromProto(resp.GetReplicas()),
+		TraceID:   resp.GetTraceId(),
+	}, nil
+}
+
+func kvPut(ctx context.Context, client gossipProtobuffer.KVServiceClient, req KVRequest) (KVResult, error) {
+	resp, err := client.Put(ctx, &gossipProtobuffer.PutRequest{
+		Key:         req.Key,
+		Value:       req.Value,
+		Forwarded:   req.Forwarded,
+		Consistency: consistencyToProto(req.Consistency),
+		Timestamp:   req.Timestamp,
+		TtlMs:       req.TTL.Milliseconds(),
+		Trace:       req.Trace,
+	})
+	if err != nil {
+		return KVResult{}, err
+	}
+	return KVResult{
+		Owner:    gossip.NodeID(resp.GetOwner()),
+		Replicas: nodeIDsFromProto(resp.GetReplicas()),
+		TraceID:  resp.GetTraceId(),
+	}, nil
+}
+
+func kvDelete(ctx context.Context, client gossipProtobuffer.KVServiceClient, req KVRequest) (KVResult, error) {
+	resp, err := client.Delete(ctx, &gossipProtobuffer.DeleteRequest{
+		Key:         req.Key,
+		Forwarded:   req.Forwarded,
+		Consistency: consistencyToProto(req.Consistency),
+		Timestamp:   req.Timestamp,
+		Trace:       req.Trace,
+	})
+	if err != nil {
+		return KVResult{}, err
+	}
+	return KVResult{
+		Found:    resp.GetFound(),
+		Owner:    gossip.NodeID(resp.GetOwner()),
+		Replicas: nodeIDsFromProto(resp.GetReplicas()),
+		TraceID:  resp.GetTraceId(),
+	}, nil
+}
+
+var consistencyLevels = map[storage.ConsistencyLevel]gossipProtobuffer.ConsistencyLevel{
+	storage.One:    gossipProtobuffer.ConsistencyLevel_CONSISTENCY_LEVEL_ONE,
+	storage.Quorum: gossipProtobuffer.ConsistencyLevel_CONSISTENCY_LEVEL_QUORUM,
+	storage.All:    gossipProtobuffer.ConsistencyLevel_CONSISTENCY_LEVEL_ALL,
+}
+
+func consistencyToProto(cl storage.ConsistencyLevel) gossipProtobuffer.ConsistencyLevel {
+	return consistencyLevels[cl]
+}
+
+// consistencyFromProto converts a consistency level, storage.DefaultConsistencyLevel if
+// it isn't set
+func consistencyFromProto(cl gossipProtobuffer.ConsistencyLevel) storage.ConsistencyLevel {
+	for level, pb := range consistencyLevels {
+		if pb == cl {
+			return level
+		}
+	}
+	return storage.DefaultConsistencyLevel
+}
+
+func keyVersionToProto(version KeyVersion) *gossipProtobuffer.KeyVersion {
+	return &gossipProtobuffer.KeyVersion{
+		Key:       version.Key,
+		Value:     version.Cell.Value,
+		Timestamp: version.Cell.Timestamp,
+		TtlMs:     version.Cell.TTL.Milliseconds(),
+		Deleted:   version.Cell.Deleted,
+	}
+}
+
+func keyVersionFromProto(version *gossipProtobuffer.KeyVersion) KeyVersion {
+	return KeyVersion{Key: version.GetKey(), Cell: storage.Cell{
+		Value:     version.GetValue(),
+		Timestamp: version.GetTimestamp(),
+		TTL:       time.Duration(version.GetTtlMs()) * time.Millisecond,
+		Deleted:   version.GetDeleted(),
+	}}
+}
+
+func nodeIDsToProto(nodeIDs []gossip.NodeID) []string {
+	ids := make([]string, len(nodeIDs))
+	for i, id := range nodeIDs {
+		ids[i] = string(id)
+	}
+	return ids
+}
+
+func nodeIDsFromProto(ids []string) []gossip.NodeID {
+	nodeIDs := make([]gossip.NodeID, len(ids))
+	for i, id := range ids {
+		nodeIDs[i] = gossip.NodeID(id)
+	}
+	return nodeIDs
+}
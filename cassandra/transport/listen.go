@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// unixScheme prefixes unix domain socket addresses, e.g. unix:///tmp/node1.sock.
+// gRPC understands the same form as a dial target.
+const unixScheme = "unix://"
+
+// IsUnixAddress reports whether addr is a unix domain socket address (unix:///path).
+func IsUnixAddress(addr string) bool {
+	return strings.HasPrefix(addr, unixScheme)
+}
+
+// listen binds addr, which is either host:port or unix:///path.
+func listen(addr string) (net.Listener, error) {
+	if !IsUnixAddress(addr) {
+		return net.Listen("tcp", addr)
+	}
+
+	path := strings.TrimPrefix(addr, unixScheme)
+	if path == "" {
+		return nil, fmt.Errorf("unix socket path must be provided: %s", addr)
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// removeStaleSocket deletes a socket file left behind by a node that didn't shut down
+// cleanly. A socket that still accepts connections belongs to a live node and is kept,
+// so binding fails with "address already in use" like it would for a TCP port.
+func removeStaleSocket(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+)
+
+// maxTrackedPeers bounds the per-peer buckets kept in memory, and their rejection counts. Beyond
+// it, idle buckets are dropped, or the least recently limited one if no bucket is idle.
+const maxTrackedPeers = 1024
+
+// RateLimitConfig limits inbound gossip messages with token buckets.
+// A rate of 0 disables that limit. Burst defaults to the rate (rounded up) when unset.
+type RateLimitConfig struct {
+	GlobalRate  float64 // messages per second from all peers together
+	GlobalBurst int
+	PeerRate    float64 // messages per second from a single peer
+	PeerBurst   int
+}
+
+// Enabled reports whether any limit is configured. A nil RateLimitConfig means unlimited.
+func (c *RateLimitConfig) Enabled() bool {
+	return c != nil && (c.GlobalRate > 0 || c.PeerRate > 0)
+}
+
+// Validate checks that rates and bursts are not negative.
+func (c *RateLimitConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.GlobalRate < 0 || c.PeerRate < 0 || c.GlobalBurst < 0 || c.PeerBurst < 0 {
+		return fmt.Errorf("rate limits must not be negative")
+	}
+	return nil
+}
+
+// WithRateLimit rejects gossip messages beyond the configured rates with codes.ResourceExhausted.
+func WithRateLimit(cfg *RateLimitConfig) Option {
+	return func(g *GRPC) {
+		if cfg.Enabled() {
+			g.rateLimiter = NewRateLimiter(*cfg)
+		}
+	}
+}
+
+// tokenBucket refills at rate tokens per second up to burst; each message takes one token.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(burst)
+	if b <= 0 {
+		b = rate
+		if b < 1 {
+			b = 1
+		}
+	}
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RateLimitStats counts what the rate limiter let through and rejected.
+type RateLimitStats struct {
+	Allowed        int64
+	RejectedGlobal int64            // rejected by the global limit
+	RejectedPeer   int64            // rejected by a per-peer limit
+	RejectedByPeer map[string]int64 // per-peer rejections of the tracked peers, keyed by sender
+}
+
+// RateLimiter applies a global and a per-peer token bucket to inbound messages.
+type RateLimiter struct {
+	mu     sync.Mutex
+	cfg    RateLimitConfig
+	global *tokenBucket
+	peers  map[string]*tokenBucket
+	stats  RateLimitStats
+}
+
+// NewRateLimiter creates a rate limiter with full buckets.
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{
+		cfg:   cfg,
+		peers: make(map[string]*tokenBucket),
+		stats: RateLimitStats{RejectedByPeer: make(map[string]int64)},
+	}
+	if cfg.GlobalRate > 0 {
+		l.global = newTokenBucket(cfg.GlobalRate, cfg.GlobalBurst, time.Now())
+	}
+	return l
+}
+
+// Allow reports whether a message from sender may be processed now.
+func (l *RateLimiter) Allow(sender string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var bucket *tokenBucket
+	if l.cfg.PeerRate > 0 {
+		var ok bool
+		bucket, ok = l.peers[sender]
+		if !ok {
+			l.evictLocked(now)
+			bucket = newTokenBucket(l.cfg.PeerRate, l.cfg.PeerBurst, now)
+			l.peers[sender] = bucket
+		}
+		if !bucket.allow(now) {
+			l.stats.RejectedPeer++
+			l.stats.RejectedByPeer[sender]++
+			return false
+		}
+	}
+	if l.global != nil && !l.global.allow(now) {
+		if bucket != nil {
+			// The message wasn't processed, it doesn't count against the peer
+			bucket.tokens++
+		}
+		l.stats.RejectedGlobal++
+		return false
+	}
+	l.stats.Allowed++
+	return true
+}
+
+// evictLocked makes room for a new peer once too many are tracked: it drops the buckets that
+// have refilled completely (their peer has been quiet) or, if none has, the fullest one,
+// along with their rejection counts. Caller must hold l.mu.
+func (l *RateLimiter) evictLocked(now time.Time) {
+	if len(l.peers) < maxTrackedPeers {
+		return
+	}
+	fullest := ""
+	for sender, bucket := range l.peers {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.burst {
+			l.forgetLocked(sender)
+		} else if fullest == "" || bucket.tokens > l.peers[fullest].tokens {
+			fullest = sender
+		}
+	}
+	if len(l.peers) >= maxTrackedPeers {
+		l.forgetLocked(fullest)
+	}
+}
+
+// forgetLocked drops the bucket of sender and its rejection count. Caller must hold l.mu.
+func (l *RateLimiter) forgetLocked(sender string) {
+	delete(l.peers, sender)
+	delete(l.stats.RejectedByPeer, sender)
+}
+
+// Stats returns a copy of the counters.
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.RejectedByPeer = make(map[string]int64, len(l.stats.RejectedByPeer))
+	for sender, count := range l.stats.RejectedByPeer {
+		stats.RejectedByPeer[sender] = count
+	}
+	return stats
+}
+
+// RateLimitInterceptor rejects GossipService requests beyond the limiter's rates with
+// codes.ResourceExhausted. Other services (e.g. AdminService) are not limited.
+func RateLimitInterceptor(limiter *RateLimiter) grpc.UnaryServerInterceptor {
+	prefix := "/" + gossipProtobuffer.GossipService_ServiceDesc.ServiceName + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		if !limiter.Allow(senderOf(ctx, req)) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// senderOf identifies who sent a gossip message by the remote address of its connection,
+// which the peer can't pick the way it picks the node ID it claims: a peer sending under
+// ever new IDs still drains a single bucket. The address includes the port, as local
+// clusters share an IP. The node ID is only used without an address.
+func senderOf(ctx context.Context, req any) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	if msg, ok := req.(interface{ GetSenderId() string }); ok && msg.GetSenderId() != "" {
+		return msg.GetSenderId()
+	}
+	return "unknown"
+}
@@ -0,0 +1,120 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TLSConfig holds the certificate material used to secure gRPC connections.
+// The same files are used for serving and for dialing peers, so with
+// RequireClientCert set every node must present a certificate signed by CAFile (mTLS).
+type TLSConfig struct {
+	CertFile          string // PEM certificate presented by this node
+	KeyFile           string // PEM private key for CertFile
+	CAFile            string // PEM CA bundle used to verify peers
+	RequireClientCert bool   // reject inbound connections without a valid client certificate
+	ServerName        string // optional override for the name verified in peer certificates
+}
+
+// Enabled reports whether TLS is configured. A nil TLSConfig means plaintext.
+func (c *TLSConfig) Enabled() bool {
+	return c != nil && (c.CertFile != "" || c.KeyFile != "" || c.CAFile != "")
+}
+
+// Validate checks that the configured files are consistent.
+func (c *TLSConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("TLS cert and key must be provided together")
+	}
+	if c.RequireClientCert && c.CAFile == "" {
+		return fmt.Errorf("TLS CA is required to verify client certificates")
+	}
+	return nil
+}
+
+// ServerCredentials returns the transport credentials for the gRPC server.
+// Plaintext credentials are returned when TLS is not enabled.
+func (c *TLSConfig) ServerCredentials() (credentials.TransportCredentials, error) {
+	if !c.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	if c.CertFile == "" {
+		return nil, fmt.Errorf("TLS cert and key are required to serve TLS")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	if c.RequireClientCert {
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// ClientCredentials returns the transport credentials used when dialing peers.
+// Plaintext credentials are returned when TLS is not enabled.
+func (c *TLSConfig) ClientCredentials() (credentials.TransportCredentials, error) {
+	if !c.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsCfg := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	// Present our own certificate so servers requiring client certs accept us
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in TLS CA %s", caFile)
+	}
+	return pool, nil
+}
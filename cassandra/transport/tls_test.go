@@ -0,0 +1,183 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// testCert is a certificate and key generated for a test, and the files they're written to
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert generates a certificate for name signed by parent, self-signed if parent is nil,
+// and writes it to dir
+func newTestCert(t *testing.T, dir, name string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &testCert{cert: cert, key: key,
+		certFile: filepath.Join(dir, name+".crt"), keyFile: filepath.Join(dir, name+".key")}
+	writePEM(t, c.certFile, "CERTIFICATE", der)
+	writePEM(t, c.keyFile, "EC PRIVATE KEY", keyDER)
+	return c
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects client to server over TCP and returns the error of the server's handshake
+func handshake(t *testing.T, server, client credentials.TransportCredentials) error {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = server.ServerHandshake(conn)
+		serverErr <- err
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if secure, _, err := client.ClientHandshake(ctx, "localhost", conn); err == nil {
+		// With TLS 1.3 the server verifies the client's certificate after the client is done:
+		// read, so a rejected client sees the alert rather than hanging
+		secure.SetReadDeadline(time.Now().Add(time.Second))
+		secure.Read(make([]byte, 1))
+		secure.Close()
+	}
+	return <-serverErr
+}
+
+func TestTLSConfigMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, dir, "ca", true, nil)
+	server := newTestCert(t, dir, "server", false, ca)
+	client := newTestCert(t, dir, "client", false, ca)
+	unsigned := newTestCert(t, dir, "unsigned", false, nil)
+	foreignCA := newTestCert(t, dir, "foreign-ca", true, nil)
+	foreign := newTestCert(t, dir, "foreign", false, foreignCA)
+
+	serverCreds, err := (&TLSConfig{CertFile: server.certFile, KeyFile: server.keyFile,
+		CAFile: ca.certFile, RequireClientCert: true}).ServerCredentials()
+	if err != nil {
+		t.Fatalf("ServerCredentials: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		client TLSConfig
+		accept bool
+	}{
+		{"CA-signed client", TLSConfig{CertFile: client.certFile, KeyFile: client.keyFile, CAFile: ca.certFile}, true},
+		{"no client certificate", TLSConfig{CAFile: ca.certFile}, false},
+		{"self-signed client", TLSConfig{CertFile: unsigned.certFile, KeyFile: unsigned.keyFile, CAFile: ca.certFile}, false},
+		{"client of another CA", TLSConfig{CertFile: foreign.certFile, KeyFile: foreign.keyFile, CAFile: ca.certFile}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCreds, err := tt.client.ClientCredentials()
+			if err != nil {
+				t.Fatalf("ClientCredentials: %v", err)
+			}
+			err = handshake(t, serverCreds, clientCreds)
+			if tt.accept && err != nil {
+				t.Errorf("server rejected the client: %v", err)
+			}
+			if !tt.accept && err == nil {
+				t.Error("server accepted the client")
+			}
+		})
+	}
+}
+
+func TestTLSConfigClientRejectsUnknownServer(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, dir, "ca", true, nil)
+	server := newTestCert(t, dir, "server", false, nil) // self-signed, unknown to the client
+
+	serverCreds, err := (&TLSConfig{CertFile: server.certFile, KeyFile: server.keyFile}).ServerCredentials()
+	if err != nil {
+		t.Fatalf("ServerCredentials: %v", err)
+	}
+	clientCreds, err := (&TLSConfig{CAFile: ca.certFile}).ClientCredentials()
+	if err != nil {
+		t.Fatalf("ClientCredentials: %v", err)
+	}
+	if err := handshake(t, serverCreds, clientCreds); err == nil {
+		t.Error("client accepted a server its CA didn't sign")
+	}
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/adamgarcia4/goLearning/cassandra/logger"
+)
+
+// traceIDMetadataKey is the gRPC metadata carrying the trace ID of a request (see
+// logger.WithTraceID), so the entries both nodes log for a gossip exchange share it
+const traceIDMetadataKey = "x-trace-id"
+
+// traceClientInterceptor sends the trace ID of the request's context along with it
+func traceClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := logger.TraceID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, traceIDMetadataKey, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// traceServerInterceptor puts the trace ID sent with a request in its context. It runs before
+// the other interceptors, even those of WithUnaryInterceptors, so the logging interceptor logs
+// it too.
+func traceServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if ids := metadata.ValueFromIncomingContext(ctx, traceIDMetadataKey); len(ids) > 0 && ids[0] != "" {
+		ctx = logger.WithTraceID(ctx, ids[0])
+	}
+	return handler(ctx, req)
+}
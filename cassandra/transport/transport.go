@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+)
+
+// Transport is the network layer a node serves gossip on and dials peers with.
+// Implementations convert between gossip types and their own wire format, so the
+// node and gossip packages stay protocol-agnostic.
+type Transport interface {
+	// Start binds synchronously (returning any bind error) and then serves in the background.
+	Start() error
+	// Stop shuts the transport down. It must be idempotent.
+	Stop() error
+	// Addr returns the address the transport serves on. After Start it is the bound
+	// address, so a configured port of 0 resolves to the actual port.
+	Addr() string
+	// Dial returns a Peer for sending messages to target. Connections may be established lazily.
+	Dial(target string) (Peer, error)
+}
+
+// ServeErrorReporter is implemented by transports whose server can fail after Start returned.
+type ServeErrorReporter interface {
+	// ServeErrors receives errors that stopped the transport from serving.
+	ServeErrors() <-chan error
+}
+
+// ContextStopper is implemented by transports whose graceful Stop can wait on peers
+// (in-flight RPCs, open streams). StopContext gives up waiting once ctx is done.
+type ContextStopper interface {
+	StopContext(ctx context.Context) error
+}
+
+// Peer is an outbound connection to a single remote node.
+type Peer interface {
+	// Target returns the address this peer was dialed with.
+	Target() string
+	// SendHeartbeat sends a heartbeat and returns the remote node's heartbeat state.
+	SendHeartbeat(ctx context.Context, heartbeat gossip.HeartbeatStateSnapshot) (gossip.HeartbeatStateSnapshot, error)
+	// SendSyn starts a gossip round and returns the remote node's ACK.
+	SendSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error)
+	// SendAck2 completes a gossip round.
+	SendAck2(ctx context.Context, ack2 gossip.Ack2Message) error
+	// WatchClusterState streams the remote node's view of the cluster until ctx is done
+	// or the connection fails, then closes the channel.
+	WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error)
+	// Close releases the connection.
+	Close() error
+}
+
+// ErrUnavailable is wrapped by handler errors that should fail like an unreachable peer, e.g.
+// a paused node's. The gRPC transport answers them with codes.Unavailable.
+var ErrUnavailable = errors.New("unavailable")
+
+// GossipHandler is implemented by whatever processes inbound gossip messages.
+type GossipHandler interface {
+	HandleHeartbeat(remoteNodeID string, remoteGeneration int64, remoteVersion int64) (localNodeID string, localGeneration int64, localVersion int64, err error)
+	HandleSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error)
+	HandleAck2(ctx context.Context, ack2 gossip.Ack2Message) error
+	// WatchClusterState subscribes to the local view of the cluster until ctx is done.
+	WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error)
+}
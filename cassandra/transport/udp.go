@@ -0,0 +1,460 @@
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	gossipProtobuffer "github.com/adamgarcia4/goLearning/cassandra/api/gossip/v1"
+	"github.com/adamgarcia4/goLearning/cassandra/gossip"
+	"github.com/adamgarcia4/goLearning/cassandra/ring"
+)
+
+/*
+*
+UDP datagram layout (big endian):
+
+	[0]     protocol version (udpVersion)
+	[1]     message tag (tagSyn, tagAck, ...)
+	[2:6]   request ID, echoed in the reply so late answers can be discarded
+	[6:10]  payload length
+	[10:]   payload: a marshalled gossip proto, or an error message for tagError
+
+Gossip tolerates lost messages (the next round repairs them), so SYN gets a single
+ACK reply and ACK2 is fire-and-forget. Heartbeats and anything that doesn't fit in
+one datagram go over the gRPC server that runs alongside on the same port.
+*/
+
+const (
+	udpVersion    byte = 1
+	udpHeaderSize      = 10
+
+	// MaxDatagramPayload keeps datagrams below a typical Ethernet MTU so they are never fragmented.
+	MaxDatagramPayload = 1400 - udpHeaderSize
+
+	udpReadBufferSize = 64 * 1024
+	udpReplyTimeout   = 2 * time.Second // used when the caller's context has no deadline
+
+	udpWorkers   = 4   // datagrams handled at once
+	udpQueueSize = 256 // datagrams waiting for a worker, beyond which they are dropped
+)
+
+// Datagram message tags
+const (
+	tagSyn      byte = iota + 1 // GossipDigestSyn
+	tagAck                      // GossipDigestAck
+	tagAck2                     // GossipDigestAck2
+	tagError                    // the handler rejected the SYN
+	tagTooLarge                 // the ACK doesn't fit in a datagram, retry over gRPC
+)
+
+// ErrDatagramTooLarge is returned when a payload doesn't fit in a single datagram.
+var ErrDatagramTooLarge = errors.New("payload too large for a datagram")
+
+// UDP is a Transport that sends SYN/ACK/ACK2 as datagrams and falls back to gRPC
+// for heartbeats and oversized payloads. TLS is not supported.
+type UDP struct {
+	grpc    *GRPC
+	handler GossipHandler
+	conn    *net.UDPConn
+
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// NewUDP creates a UDP transport for addr. The options configure the gRPC fallback.
+func NewUDP(addr string, nodeID string, gossipHandler GossipHandler, opts ...Option) (*UDP, error) {
+	if IsUnixAddress(addr) {
+		return nil, fmt.Errorf("unix socket addresses are not supported by the UDP transport")
+	}
+	g, err := NewGRPC(addr, nodeID, gossipHandler, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if g.tls.Enabled() {
+		return nil, fmt.Errorf("TLS is not supported by the UDP transport")
+	}
+
+	return &UDP{
+		grpc:    g,
+		handler: gossipHandler,
+	}, nil
+}
+
+// Start binds the gRPC fallback and then the UDP socket on the same port (so a
+// configured port of 0 gives both the same port), then serves both in the background.
+func (u *UDP) Start() error {
+	if err := u.grpc.Start(); err != nil {
+		return err
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", u.grpc.Addr())
+	if err != nil {
+		u.grpc.Stop()
+		return fmt.Errorf("invalid UDP address: %w", err)
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		u.grpc.Stop()
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	u.conn = conn
+	go u.serve()
+	return nil
+}
+
+// inboundDatagram is a datagram read from the socket, waiting for a worker
+type inboundDatagram struct {
+	data []byte
+	from *net.UDPAddr
+}
+
+// serve reads datagrams and hands them to a fixed pool of workers. When every worker is busy
+// and the queue is full, datagrams are dropped like the network would drop them: a flood costs
+// a bounded number of goroutines and buffers, and gossip repairs the loss on the next round.
+func (u *UDP) serve() {
+	queue := make(chan inboundDatagram, udpQueueSize)
+	defer close(queue)
+	for range udpWorkers {
+		go func() {
+			for d := range queue {
+				u.handleDatagram(d.data, d.from)
+			}
+		}()
+	}
+
+	buf := make([]byte, udpReadBufferSize)
+	for {
+		n, from, err := u.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+
+		datagram := make([]byte, n)
+		copy(datagram, buf[:n])
+		select {
+		case queue <- inboundDatagram{data: datagram, from: from}:
+		default:
+		}
+	}
+}
+
+func (u *UDP) handleDatagram(datagram []byte, from *net.UDPAddr) {
+	tag, requestID, payload, err := decodeDatagram(datagram)
+	if err != nil {
+		// Garbage or a different protocol version, nothing sensible to reply
+		return
+	}
+	if tag != tagSyn && tag != tagAck2 {
+		return
+	}
+	// Rate limit on the source address before spending any work on the payload
+	if !u.allow(from) {
+		if tag == tagSyn {
+			u.reply(from, tagError, requestID, []byte("rate limit exceeded"))
+		}
+		return
+	}
+
+	switch tag {
+	case tagSyn:
+		req := &gossipProtobuffer.GossipDigestSyn{}
+		if err := proto.Unmarshal(payload, req); err != nil {
+			u.reply(from, tagError, requestID, []byte(err.Error()))
+			return
+		}
+		if err := checkReceive(u.grpc.filter, req.GetSenderId()); err != nil {
+			// A partitioned peer doesn't hear from us at all
+			return
+		}
+		version, err := negotiateVersion(req.GetProtocolVersion())
+		if err != nil {
+			u.reply(from, tagError, requestID, []byte(err.Error()))
+			return
+		}
+
+		ack, err := u.handler.HandleSyn(context.Background(), synFromProto(req))
+		if err != nil {
+			u.reply(from, tagError, requestID, []byte(err.Error()))
+			return
+		}
+
+		resp := ackToProto(ack)
+		resp.ProtocolVersion = version
+		data, err := proto.Marshal(resp)
+		if err != nil {
+			u.reply(from, tagError, requestID, []byte(err.Error()))
+			return
+		}
+		if len(data) > MaxDatagramPayload {
+			u.reply(from, tagTooLarge, requestID, nil)
+			return
+		}
+		u.reply(from, tagAck, requestID, data)
+	case tagAck2:
+		req := &gossipProtobuffer.GossipDigestAck2{}
+		if err := proto.Unmarshal(payload, req); err != nil {
+			return
+		}
+		if checkReceive(u.grpc.filter, req.GetSenderId()) != nil {
+			return
+		}
+		u.handler.HandleAck2(context.Background(), ack2FromProto(req))
+	}
+}
+
+// allow applies the gRPC fallback's rate limiter to datagrams. Like senderOf for gRPC calls, it
+// keys the per-peer bucket by the source address rather than the node ID the datagram claims.
+// A peer's datagrams and its gRPC calls come from different ports, so they draw on separate
+// per-peer buckets but on the same global one.
+func (u *UDP) allow(from *net.UDPAddr) bool {
+	if u.grpc.rateLimiter == nil {
+		return true
+	}
+	return u.grpc.rateLimiter.Allow(from.String())
+}
+
+func (u *UDP) reply(to *net.UDPAddr, tag byte, requestID uint32, payload []byte) {
+	u.conn.WriteToUDP(encodeDatagram(tag, requestID, payload), to)
+}
+
+// Stop closes the UDP socket and stops the gRPC fallback. It is idempotent.
+func (u *UDP) Stop() error {
+	return u.StopContext(context.Background())
+}
+
+// StopContext is Stop, with the gRPC fallback stopped forcefully once ctx is done.
+func (u *UDP) StopContext(ctx context.Context) error {
+	u.stopOnce.Do(func() {
+		if u.conn != nil {
+			u.conn.Close()
+		}
+		u.stopErr = u.grpc.StopContext(ctx)
+	})
+	return u.stopErr
+}
+
+// RateLimiter returns the limiter shared by datagrams and the gRPC fallback, or nil.
+func (u *UDP) RateLimiter() *RateLimiter {
+	return u.grpc.RateLimiter()
+}
+
+// ServeErrors reports failures of the gRPC server serving heartbeats and fallback gossip.
+func (u *UDP) ServeErrors() <-chan error {
+	return u.grpc.ServeErrors()
+}
+
+// Addr returns the address the transport is bound to once started.
+func (u *UDP) Addr() string {
+	return u.grpc.Addr()
+}
+
+// Dial returns a Peer that sends gossip rounds to target over UDP.
+func (u *UDP) Dial(target string) (Peer, error) {
+	if IsUnixAddress(target) {
+		return nil, fmt.Errorf("cannot reach %s over UDP", target)
+	}
+	udpAddr, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UDP address %s: %w", target, err)
+	}
+
+	fallback, err := dialGRPC(target, nil, u.grpc.compression, u.grpc.dialOptions(target)...)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialUDP("udp", nil, udpAddr)
+	if err != nil {
+		fallback.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+
+	return &udpPeer{
+		target:   target,
+		conn:     conn,
+		fallback: fallback,
+		filter:   u.grpc.filter,
+		faults:   u.grpc.faults,
+	}, nil
+}
+
+var (
+	_ Transport          = (*UDP)(nil)
+	_ ServeErrorReporter = (*UDP)(nil)
+)
+
+// udpPeer sends gossip datagrams to a single node over a connected UDP socket.
+type udpPeer struct {
+	target   string
+	fallback *grpcPeer
+	filter   MessageFilter
+	faults   FaultInjector
+
+	mu     sync.Mutex // one SYN in flight at a time so replies can't interleave
+	conn   *net.UDPConn
+	nextID uint32
+}
+
+func (p *udpPeer) Target() string {
+	return p.target
+}
+
+func (p *udpPeer) SendHeartbeat(ctx context.Context, heartbeat gossip.HeartbeatStateSnapshot) (gossip.HeartbeatStateSnapshot, error) {
+	return p.fallback.SendHeartbeat(ctx, heartbeat)
+}
+
+func (p *udpPeer) SendSyn(ctx context.Context, syn gossip.SynMessage) (gossip.AckMessage, error) {
+	if err := checkSend(p.filter, p.target); err != nil {
+		return gossip.AckMessage{}, err
+	}
+	if err := injectFault(ctx, p.faults, p.target); err != nil {
+		return gossip.AckMessage{}, err
+	}
+	data, err := proto.Marshal(synToProto(syn))
+	if err != nil {
+		return gossip.AckMessage{}, err
+	}
+	if len(data) > MaxDatagramPayload {
+		return p.fallback.SendSyn(ctx, syn)
+	}
+
+	ack, err := p.roundTrip(ctx, data)
+	if errors.Is(err, ErrDatagramTooLarge) {
+		return p.fallback.SendSyn(ctx, syn)
+	}
+	return ack, err
+}
+
+// roundTrip sends a SYN datagram and waits for the matching ACK.
+func (p *udpPeer) roundTrip(ctx context.Context, syn []byte) (gossip.AckMessage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	requestID := p.nextID
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(udpReplyTimeout)
+	}
+	if err := p.conn.SetDeadline(deadline); err != nil {
+		return gossip.AckMessage{}, err
+	}
+
+	if _, err := p.conn.Write(encodeDatagram(tagSyn, requestID, syn)); err != nil {
+		return gossip.AckMessage{}, err
+	}
+
+	buf := make([]byte, udpReadBufferSize)
+	for {
+		n, err := p.conn.Read(buf)
+		if err != nil {
+			return gossip.AckMessage{}, fmt.Errorf("no ACK from %s: %w", p.target, err)
+		}
+
+		tag, replyID, payload, err := decodeDatagram(buf[:n])
+		if err != nil || replyID != requestID {
+			// Corrupt, or a late reply to an earlier SYN
+			continue
+		}
+
+		switch tag {
+		case tagAck:
+			resp := &gossipProtobuffer.GossipDigestAck{}
+			if err := proto.Unmarshal(payload, resp); err != nil {
+				return gossip.AckMessage{}, fmt.Errorf("invalid ACK from %s: %w", p.target, err)
+			}
+			if err := checkAckVersion(resp.GetProtocolVersion()); err != nil {
+				return gossip.AckMessage{}, fmt.Errorf("%s: %w", p.target, err)
+			}
+			return ackFromProto(resp), nil
+		case tagError:
+			return gossip.AckMessage{}, synRejected(p.target, string(payload))
+		case tagTooLarge:
+			return gossip.AckMessage{}, ErrDatagramTooLarge
+		}
+	}
+}
+
+func (p *udpPeer) SendAck2(ctx context.Context, ack2 gossip.Ack2Message) error {
+	if err := checkSend(p.filter, p.target); err != nil {
+		return err
+	}
+	if err := injectFault(ctx, p.faults, p.target); err != nil {
+		return err
+	}
+	data, err := proto.Marshal(ack2ToProto(ack2))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxDatagramPayload {
+		return p.fallback.SendAck2(ctx, ack2)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.conn.Write(encodeDatagram(tagAck2, 0, data))
+	return err
+}
+
+// WatchClusterState is a long-lived stream, so it always goes over gRPC.
+func (p *udpPeer) WatchClusterState(ctx context.Context) (<-chan gossip.StateUpdate, error) {
+	return p.fallback.WatchClusterState(ctx)
+}
+
+// ForwardGet, ForwardPut, ForwardDelete and StreamRanges always go over gRPC, see KVPeer
+func (p *udpPeer) ForwardGet(ctx context.Context, key string) (KVResult, error) {
+	return p.fallback.ForwardGet(ctx, key)
+}
+
+func (p *udpPeer) ForwardPut(ctx context.Context, key string, value []byte, timestamp int64, ttl time.Duration) (KVResult, error) {
+	return p.fallback.ForwardPut(ctx, key, value, timestamp, ttl)
+}
+
+func (p *udpPeer) ForwardDelete(ctx context.Context, key string, timestamp int64) (KVResult, error) {
+	return p.fallback.ForwardDelete(ctx, key, timestamp)
+}
+
+func (p *udpPeer) StreamRanges(ctx context.Context, ranges []ring.Range, receive func(KeyVersion) error) error {
+	return p.fallback.StreamRanges(ctx, ranges, receive)
+}
+
+func (p *udpPeer) Close() error {
+	p.conn.Close()
+	return p.fallback.Close()
+}
+
+func encodeDatagram(tag byte, requestID uint32, payload []byte) []byte {
+	datagram := make([]byte, udpHeaderSize+len(payload))
+	datagram[0] = udpVersion
+	datagram[1] = tag
+	binary.BigEndian.PutUint32(datagram[2:6], requestID)
+	binary.BigEndian.PutUint32(datagram[6:10], uint32(len(payload)))
+	copy(datagram[udpHeaderSize:], payload)
+	return datagram
+}
+
+func decodeDatagram(datagram []byte) (tag byte, requestID uint32, payload []byte, err error) {
+	if len(datagram) < udpHeaderSize {
+		return 0, 0, nil, fmt.Errorf("datagram too short: %d bytes", len(datagram))
+	}
+	if datagram[0] != udpVersion {
+		return 0, 0, nil, fmt.Errorf("unsupported datagram version %d", datagram[0])
+	}
+	length := binary.BigEndian.Uint32(datagram[6:10])
+	if int(length) != len(datagram)-udpHeaderSize {
+		return 0, 0, nil, fmt.Errorf("datagram length mismatch: header says %d, got %d", length, len(datagram)-udpHeaderSize)
+	}
+	return datagram[1], binary.BigEndian.Uint32(datagram[2:6]), datagram[udpHeaderSize:], nil
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Gossip wire protocol versions. See the compatibility policy on GossipDigestSyn in gossip.proto:
+// a node accepts any version from MinProtocolVersion up, answers in the lower of the two
+// versions, and a round then continues in that version.
+//
+// Bump ProtocolVersion when a message changes in a way older nodes can't ignore, and keep
+// MinProtocolVersion at the oldest version still encoded, so a cluster can be upgraded node by node.
+const (
+	ProtocolVersion    uint32 = 1
+	MinProtocolVersion uint32 = 1
+
+	// legacyProtocolVersion is what a missing version field means: the sender predates versioning
+	legacyProtocolVersion uint32 = 1
+)
+
+// ErrUnsupportedProtocol is returned when a peer speaks a protocol version this node no longer supports.
+var ErrUnsupportedProtocol = errors.New("unsupported gossip protocol version")
+
+// negotiateVersion returns the version to use with a peer that speaks remote.
+// Newer peers are downgraded to our version; peers older than MinProtocolVersion are rejected.
+func negotiateVersion(remote uint32) (uint32, error) {
+	if remote == 0 {
+		remote = legacyProtocolVersion
+	}
+	if remote < MinProtocolVersion {
+		return 0, fmt.Errorf("%w: peer speaks %d, minimum is %d", ErrUnsupportedProtocol, remote, MinProtocolVersion)
+	}
+	return min(remote, ProtocolVersion), nil
+}
+
+// checkAckVersion validates the version a responder chose for the round. It can't be newer
+// than what we sent, and can't be older than what we still understand.
+func checkAckVersion(version uint32) error {
+	if version == 0 {
+		version = legacyProtocolVersion
+	}
+	if version < MinProtocolVersion || version > ProtocolVersion {
+		return fmt.Errorf("%w: peer answered in %d, supported are %d to %d",
+			ErrUnsupportedProtocol, version, MinProtocolVersion, ProtocolVersion)
+	}
+	return nil
+}